@@ -0,0 +1,6728 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v5.27.0
+// source: messagebroker.proto
+
+package brokerpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type MessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageResponse) Reset() {
+	*x = MessageResponse{}
+	mi := &file_messagebroker_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageResponse) ProtoMessage() {}
+
+func (x *MessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageResponse.ProtoReflect.Descriptor instead.
+func (*MessageResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *MessageResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *MessageResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type RegisterRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterRequest) Reset() {
+	*x = RegisterRequest{}
+	mi := &file_messagebroker_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterRequest) ProtoMessage() {}
+
+func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterRequest.ProtoReflect.Descriptor instead.
+func (*RegisterRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RegisterRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type RegisteredClient struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// only returned once, the service just keeps a hash of it
+	Secret        string `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisteredClient) Reset() {
+	*x = RegisteredClient{}
+	mi := &file_messagebroker_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisteredClient) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisteredClient) ProtoMessage() {}
+
+func (x *RegisteredClient) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisteredClient.ProtoReflect.Descriptor instead.
+func (*RegisteredClient) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RegisteredClient) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RegisteredClient) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type RegisterResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Row           *RegisteredClient      `protobuf:"bytes,3,opt,name=row,proto3" json:"row,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterResponse) Reset() {
+	*x = RegisterResponse{}
+	mi := &file_messagebroker_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterResponse) ProtoMessage() {}
+
+func (x *RegisterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterResponse.ProtoReflect.Descriptor instead.
+func (*RegisterResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RegisterResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RegisterResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RegisterResponse) GetRow() *RegisteredClient {
+	if x != nil {
+		return x.Row
+	}
+	return nil
+}
+
+type AuthenticateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Secret        string                 `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthenticateRequest) Reset() {
+	*x = AuthenticateRequest{}
+	mi := &file_messagebroker_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthenticateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticateRequest) ProtoMessage() {}
+
+func (x *AuthenticateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticateRequest.ProtoReflect.Descriptor instead.
+func (*AuthenticateRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AuthenticateRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AuthenticateRequest) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type GetAuthenticatedClientRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAuthenticatedClientRequest) Reset() {
+	*x = GetAuthenticatedClientRequest{}
+	mi := &file_messagebroker_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAuthenticatedClientRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAuthenticatedClientRequest) ProtoMessage() {}
+
+func (x *GetAuthenticatedClientRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAuthenticatedClientRequest.ProtoReflect.Descriptor instead.
+func (*GetAuthenticatedClientRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{5}
+}
+
+type Client struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// empty unless the client is a member of an organization
+	OrganizationId string `protobuf:"bytes,3,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Client) Reset() {
+	*x = Client{}
+	mi := &file_messagebroker_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Client) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Client) ProtoMessage() {}
+
+func (x *Client) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Client.ProtoReflect.Descriptor instead.
+func (*Client) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Client) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Client) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Client) GetOrganizationId() string {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return ""
+}
+
+type AuthenticateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Data          *Client                `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthenticateResponse) Reset() {
+	*x = AuthenticateResponse{}
+	mi := &file_messagebroker_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthenticateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticateResponse) ProtoMessage() {}
+
+func (x *AuthenticateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticateResponse.ProtoReflect.Descriptor instead.
+func (*AuthenticateResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AuthenticateResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *AuthenticateResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *AuthenticateResponse) GetData() *Client {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// organizations keep their members and publishers apart from other tenants
+type Organization struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// ids of the members who manage the organization and its publishers
+	Admins        []string `protobuf:"bytes,3,rep,name=admins,proto3" json:"admins,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Organization) Reset() {
+	*x = Organization{}
+	mi := &file_messagebroker_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Organization) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Organization) ProtoMessage() {}
+
+func (x *Organization) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Organization.ProtoReflect.Descriptor instead.
+func (*Organization) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Organization) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Organization) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Organization) GetAdmins() []string {
+	if x != nil {
+		return x.Admins
+	}
+	return nil
+}
+
+type OrganizationMember struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Admin         bool                   `protobuf:"varint,3,opt,name=admin,proto3" json:"admin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrganizationMember) Reset() {
+	*x = OrganizationMember{}
+	mi := &file_messagebroker_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrganizationMember) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrganizationMember) ProtoMessage() {}
+
+func (x *OrganizationMember) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrganizationMember.ProtoReflect.Descriptor instead.
+func (*OrganizationMember) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *OrganizationMember) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *OrganizationMember) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *OrganizationMember) GetAdmin() bool {
+	if x != nil {
+		return x.Admin
+	}
+	return false
+}
+
+type CreateOrganizationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// name of the first admin, registered as a member of the organization
+	AdminName     string `protobuf:"bytes,2,opt,name=admin_name,json=adminName,proto3" json:"admin_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrganizationRequest) Reset() {
+	*x = CreateOrganizationRequest{}
+	mi := &file_messagebroker_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrganizationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrganizationRequest) ProtoMessage() {}
+
+func (x *CreateOrganizationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrganizationRequest.ProtoReflect.Descriptor instead.
+func (*CreateOrganizationRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateOrganizationRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateOrganizationRequest) GetAdminName() string {
+	if x != nil {
+		return x.AdminName
+	}
+	return ""
+}
+
+type CreateOrganizationResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Success      bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message      string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Organization *Organization          `protobuf:"bytes,3,opt,name=organization,proto3" json:"organization,omitempty"`
+	// credentials of the first admin
+	Row           *RegisteredClient `protobuf:"bytes,4,opt,name=row,proto3" json:"row,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrganizationResponse) Reset() {
+	*x = CreateOrganizationResponse{}
+	mi := &file_messagebroker_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrganizationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrganizationResponse) ProtoMessage() {}
+
+func (x *CreateOrganizationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrganizationResponse.ProtoReflect.Descriptor instead.
+func (*CreateOrganizationResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CreateOrganizationResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CreateOrganizationResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CreateOrganizationResponse) GetOrganization() *Organization {
+	if x != nil {
+		return x.Organization
+	}
+	return nil
+}
+
+func (x *CreateOrganizationResponse) GetRow() *RegisteredClient {
+	if x != nil {
+		return x.Row
+	}
+	return nil
+}
+
+type GetOrganizationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrganizationRequest) Reset() {
+	*x = GetOrganizationRequest{}
+	mi := &file_messagebroker_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrganizationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrganizationRequest) ProtoMessage() {}
+
+func (x *GetOrganizationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrganizationRequest.ProtoReflect.Descriptor instead.
+func (*GetOrganizationRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{12}
+}
+
+type OrganizationResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Success      bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message      string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Organization *Organization          `protobuf:"bytes,3,opt,name=organization,proto3" json:"organization,omitempty"`
+	// only returned by GetOrganization
+	Members       []*OrganizationMember `protobuf:"bytes,4,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrganizationResponse) Reset() {
+	*x = OrganizationResponse{}
+	mi := &file_messagebroker_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrganizationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrganizationResponse) ProtoMessage() {}
+
+func (x *OrganizationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrganizationResponse.ProtoReflect.Descriptor instead.
+func (*OrganizationResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *OrganizationResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *OrganizationResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *OrganizationResponse) GetOrganization() *Organization {
+	if x != nil {
+		return x.Organization
+	}
+	return nil
+}
+
+func (x *OrganizationResponse) GetMembers() []*OrganizationMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type AddOrganizationMemberRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddOrganizationMemberRequest) Reset() {
+	*x = AddOrganizationMemberRequest{}
+	mi := &file_messagebroker_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddOrganizationMemberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddOrganizationMemberRequest) ProtoMessage() {}
+
+func (x *AddOrganizationMemberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddOrganizationMemberRequest.ProtoReflect.Descriptor instead.
+func (*AddOrganizationMemberRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *AddOrganizationMemberRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type OrganizationAdminRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrganizationAdminRequest) Reset() {
+	*x = OrganizationAdminRequest{}
+	mi := &file_messagebroker_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrganizationAdminRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrganizationAdminRequest) ProtoMessage() {}
+
+func (x *OrganizationAdminRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrganizationAdminRequest.ProtoReflect.Descriptor instead.
+func (*OrganizationAdminRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *OrganizationAdminRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+type RotateSecretRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateSecretRequest) Reset() {
+	*x = RotateSecretRequest{}
+	mi := &file_messagebroker_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateSecretRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateSecretRequest) ProtoMessage() {}
+
+func (x *RotateSecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateSecretRequest.ProtoReflect.Descriptor instead.
+func (*RotateSecretRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{16}
+}
+
+type ClientSecret struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Secret        string                 `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClientSecret) Reset() {
+	*x = ClientSecret{}
+	mi := &file_messagebroker_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientSecret) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientSecret) ProtoMessage() {}
+
+func (x *ClientSecret) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientSecret.ProtoReflect.Descriptor instead.
+func (*ClientSecret) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ClientSecret) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ClientSecret) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type RotateSecretResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Data          *ClientSecret          `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateSecretResponse) Reset() {
+	*x = RotateSecretResponse{}
+	mi := &file_messagebroker_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateSecretResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateSecretResponse) ProtoMessage() {}
+
+func (x *RotateSecretResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateSecretResponse.ProtoReflect.Descriptor instead.
+func (*RotateSecretResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RotateSecretResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RotateSecretResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RotateSecretResponse) GetData() *ClientSecret {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type ResetClientSecretRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetClientSecretRequest) Reset() {
+	*x = ResetClientSecretRequest{}
+	mi := &file_messagebroker_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetClientSecretRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetClientSecretRequest) ProtoMessage() {}
+
+func (x *ResetClientSecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetClientSecretRequest.ProtoReflect.Descriptor instead.
+func (*ResetClientSecretRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ResetClientSecretRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+type RefreshTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RefreshToken  string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshTokenRequest) Reset() {
+	*x = RefreshTokenRequest{}
+	mi := &file_messagebroker_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshTokenRequest) ProtoMessage() {}
+
+func (x *RefreshTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshTokenRequest.ProtoReflect.Descriptor instead.
+func (*RefreshTokenRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RefreshTokenRequest) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+type Tokens struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken  string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	RefreshToken string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	// always Bearer
+	TokenType string `protobuf:"bytes,3,opt,name=token_type,json=tokenType,proto3" json:"token_type,omitempty"`
+	// seconds until the access token expires
+	ExpiresIn int64 `protobuf:"varint,4,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
+	// seconds until the refresh token expires
+	RefreshExpiresIn int64 `protobuf:"varint,5,opt,name=refresh_expires_in,json=refreshExpiresIn,proto3" json:"refresh_expires_in,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Tokens) Reset() {
+	*x = Tokens{}
+	mi := &file_messagebroker_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tokens) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tokens) ProtoMessage() {}
+
+func (x *Tokens) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tokens.ProtoReflect.Descriptor instead.
+func (*Tokens) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Tokens) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *Tokens) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *Tokens) GetTokenType() string {
+	if x != nil {
+		return x.TokenType
+	}
+	return ""
+}
+
+func (x *Tokens) GetExpiresIn() int64 {
+	if x != nil {
+		return x.ExpiresIn
+	}
+	return 0
+}
+
+func (x *Tokens) GetRefreshExpiresIn() int64 {
+	if x != nil {
+		return x.RefreshExpiresIn
+	}
+	return 0
+}
+
+type TokenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Data          *Tokens                `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TokenResponse) Reset() {
+	*x = TokenResponse{}
+	mi := &file_messagebroker_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenResponse) ProtoMessage() {}
+
+func (x *TokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenResponse.ProtoReflect.Descriptor instead.
+func (*TokenResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *TokenResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *TokenResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TokenResponse) GetData() *Tokens {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type APIKey struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// manage, publish:<publisher id> or subscribe:<subscription id>
+	Scopes []string `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// RFC 3339 times, expires_at is empty if the key doesn't expire
+	CreatedAt string `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt string `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// only returned when the key is created
+	Key           string `protobuf:"bytes,6,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *APIKey) Reset() {
+	*x = APIKey{}
+	mi := &file_messagebroker_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *APIKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*APIKey) ProtoMessage() {}
+
+func (x *APIKey) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use APIKey.ProtoReflect.Descriptor instead.
+func (*APIKey) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *APIKey) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *APIKey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *APIKey) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *APIKey) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *APIKey) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *APIKey) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type ListAPIKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAPIKeysRequest) Reset() {
+	*x = ListAPIKeysRequest{}
+	mi := &file_messagebroker_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAPIKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAPIKeysRequest) ProtoMessage() {}
+
+func (x *ListAPIKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAPIKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{24}
+}
+
+type ListAPIKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Keys          []*APIKey              `protobuf:"bytes,3,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAPIKeysResponse) Reset() {
+	*x = ListAPIKeysResponse{}
+	mi := &file_messagebroker_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAPIKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAPIKeysResponse) ProtoMessage() {}
+
+func (x *ListAPIKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAPIKeysResponse.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListAPIKeysResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListAPIKeysResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListAPIKeysResponse) GetKeys() []*APIKey {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type CreateAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes        []string               `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	ExpiresAt     string                 `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyRequest) Reset() {
+	*x = CreateAPIKeyRequest{}
+	mi := &file_messagebroker_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyRequest) ProtoMessage() {}
+
+func (x *CreateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *CreateAPIKeyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyRequest) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+type CreateAPIKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Key           *APIKey                `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyResponse) Reset() {
+	*x = CreateAPIKeyResponse{}
+	mi := &file_messagebroker_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyResponse) ProtoMessage() {}
+
+func (x *CreateAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CreateAPIKeyResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CreateAPIKeyResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyResponse) GetKey() *APIKey {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+type RevokeAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeyId         string                 `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAPIKeyRequest) Reset() {
+	*x = RevokeAPIKeyRequest{}
+	mi := &file_messagebroker_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAPIKeyRequest) ProtoMessage() {}
+
+func (x *RevokeAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RevokeAPIKeyRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+type Publisher struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// only returned when listing the publishers of the client's organization
+	OwnerId string `protobuf:"bytes,3,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	// content type payloads have to be, empty if they can be anything
+	ContentType string `protobuf:"bytes,4,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	// only returned when creating a publisher with its own limit
+	MaxPayloadBytes int32 `protobuf:"varint,5,opt,name=max_payload_bytes,json=maxPayloadBytes,proto3" json:"max_payload_bytes,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Publisher) Reset() {
+	*x = Publisher{}
+	mi := &file_messagebroker_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Publisher) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Publisher) ProtoMessage() {}
+
+func (x *Publisher) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Publisher.ProtoReflect.Descriptor instead.
+func (*Publisher) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *Publisher) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Publisher) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Publisher) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *Publisher) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *Publisher) GetMaxPayloadBytes() int32 {
+	if x != nil {
+		return x.MaxPayloadBytes
+	}
+	return 0
+}
+
+type ListPublishersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// list every publisher in the client's organization rather than just the client's
+	Organization  bool `protobuf:"varint,1,opt,name=organization,proto3" json:"organization,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPublishersRequest) Reset() {
+	*x = ListPublishersRequest{}
+	mi := &file_messagebroker_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPublishersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPublishersRequest) ProtoMessage() {}
+
+func (x *ListPublishersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPublishersRequest.ProtoReflect.Descriptor instead.
+func (*ListPublishersRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListPublishersRequest) GetOrganization() bool {
+	if x != nil {
+		return x.Organization
+	}
+	return false
+}
+
+type ListPublishersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Publishers    []*Publisher           `protobuf:"bytes,3,rep,name=publishers,proto3" json:"publishers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPublishersResponse) Reset() {
+	*x = ListPublishersResponse{}
+	mi := &file_messagebroker_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPublishersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPublishersResponse) ProtoMessage() {}
+
+func (x *ListPublishersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPublishersResponse.ProtoReflect.Descriptor instead.
+func (*ListPublishersResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListPublishersResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListPublishersResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListPublishersResponse) GetPublishers() []*Publisher {
+	if x != nil {
+		return x.Publishers
+	}
+	return nil
+}
+
+type CreatePublisherRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// content type payloads have to be, empty accepts anything
+	ContentType string `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	// largest payload, 0 uses the service's limit
+	MaxPayloadBytes int32 `protobuf:"varint,3,opt,name=max_payload_bytes,json=maxPayloadBytes,proto3" json:"max_payload_bytes,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CreatePublisherRequest) Reset() {
+	*x = CreatePublisherRequest{}
+	mi := &file_messagebroker_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePublisherRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePublisherRequest) ProtoMessage() {}
+
+func (x *CreatePublisherRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePublisherRequest.ProtoReflect.Descriptor instead.
+func (*CreatePublisherRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *CreatePublisherRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreatePublisherRequest) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *CreatePublisherRequest) GetMaxPayloadBytes() int32 {
+	if x != nil {
+		return x.MaxPayloadBytes
+	}
+	return 0
+}
+
+type CreatePublisherResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Row           *Publisher             `protobuf:"bytes,3,opt,name=row,proto3" json:"row,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePublisherResponse) Reset() {
+	*x = CreatePublisherResponse{}
+	mi := &file_messagebroker_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePublisherResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePublisherResponse) ProtoMessage() {}
+
+func (x *CreatePublisherResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePublisherResponse.ProtoReflect.Descriptor instead.
+func (*CreatePublisherResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *CreatePublisherResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CreatePublisherResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CreatePublisherResponse) GetRow() *Publisher {
+	if x != nil {
+		return x.Row
+	}
+	return nil
+}
+
+type DeletePublisherRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PublisherId   string                 `protobuf:"bytes,1,opt,name=publisher_id,json=publisherId,proto3" json:"publisher_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePublisherRequest) Reset() {
+	*x = DeletePublisherRequest{}
+	mi := &file_messagebroker_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePublisherRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePublisherRequest) ProtoMessage() {}
+
+func (x *DeletePublisherRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePublisherRequest.ProtoReflect.Descriptor instead.
+func (*DeletePublisherRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *DeletePublisherRequest) GetPublisherId() string {
+	if x != nil {
+		return x.PublisherId
+	}
+	return ""
+}
+
+type ListPublisherSubscribersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PublisherId   string                 `protobuf:"bytes,1,opt,name=publisher_id,json=publisherId,proto3" json:"publisher_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPublisherSubscribersRequest) Reset() {
+	*x = ListPublisherSubscribersRequest{}
+	mi := &file_messagebroker_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPublisherSubscribersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPublisherSubscribersRequest) ProtoMessage() {}
+
+func (x *ListPublisherSubscribersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPublisherSubscribersRequest.ProtoReflect.Descriptor instead.
+func (*ListPublisherSubscribersRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ListPublisherSubscribersRequest) GetPublisherId() string {
+	if x != nil {
+		return x.PublisherId
+	}
+	return ""
+}
+
+type ListPublisherSubscribersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Subscribers   []*Client              `protobuf:"bytes,3,rep,name=subscribers,proto3" json:"subscribers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPublisherSubscribersResponse) Reset() {
+	*x = ListPublisherSubscribersResponse{}
+	mi := &file_messagebroker_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPublisherSubscribersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPublisherSubscribersResponse) ProtoMessage() {}
+
+func (x *ListPublisherSubscribersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPublisherSubscribersResponse.ProtoReflect.Descriptor instead.
+func (*ListPublisherSubscribersResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ListPublisherSubscribersResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListPublisherSubscribersResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListPublisherSubscribersResponse) GetSubscribers() []*Client {
+	if x != nil {
+		return x.Subscribers
+	}
+	return nil
+}
+
+type RemoveSubscriberRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PublisherId   string                 `protobuf:"bytes,1,opt,name=publisher_id,json=publisherId,proto3" json:"publisher_id,omitempty"`
+	ClientId      string                 `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveSubscriberRequest) Reset() {
+	*x = RemoveSubscriberRequest{}
+	mi := &file_messagebroker_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveSubscriberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveSubscriberRequest) ProtoMessage() {}
+
+func (x *RemoveSubscriberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveSubscriberRequest.ProtoReflect.Descriptor instead.
+func (*RemoveSubscriberRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *RemoveSubscriberRequest) GetPublisherId() string {
+	if x != nil {
+		return x.PublisherId
+	}
+	return ""
+}
+
+func (x *RemoveSubscriberRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+type ListPublisherSubscriptionRequestsRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	PublisherId string                 `protobuf:"bytes,1,opt,name=publisher_id,json=publisherId,proto3" json:"publisher_id,omitempty"`
+	// pending, approved or denied, empty for every request
+	Status        string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPublisherSubscriptionRequestsRequest) Reset() {
+	*x = ListPublisherSubscriptionRequestsRequest{}
+	mi := &file_messagebroker_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPublisherSubscriptionRequestsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPublisherSubscriptionRequestsRequest) ProtoMessage() {}
+
+func (x *ListPublisherSubscriptionRequestsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPublisherSubscriptionRequestsRequest.ProtoReflect.Descriptor instead.
+func (*ListPublisherSubscriptionRequestsRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ListPublisherSubscriptionRequestsRequest) GetPublisherId() string {
+	if x != nil {
+		return x.PublisherId
+	}
+	return ""
+}
+
+func (x *ListPublisherSubscriptionRequestsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+// limits a publisher puts on the payloads published to it
+type PayloadPolicy struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 0 uses the service's limit
+	MaxBytes int32 `protobuf:"varint,1,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"`
+	// empty accepts anything
+	ContentType   string `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PayloadPolicy) Reset() {
+	*x = PayloadPolicy{}
+	mi := &file_messagebroker_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PayloadPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadPolicy) ProtoMessage() {}
+
+func (x *PayloadPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadPolicy.ProtoReflect.Descriptor instead.
+func (*PayloadPolicy) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *PayloadPolicy) GetMaxBytes() int32 {
+	if x != nil {
+		return x.MaxBytes
+	}
+	return 0
+}
+
+func (x *PayloadPolicy) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+type GetPublisherPayloadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PublisherId   string                 `protobuf:"bytes,1,opt,name=publisher_id,json=publisherId,proto3" json:"publisher_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPublisherPayloadRequest) Reset() {
+	*x = GetPublisherPayloadRequest{}
+	mi := &file_messagebroker_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPublisherPayloadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPublisherPayloadRequest) ProtoMessage() {}
+
+func (x *GetPublisherPayloadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPublisherPayloadRequest.ProtoReflect.Descriptor instead.
+func (*GetPublisherPayloadRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *GetPublisherPayloadRequest) GetPublisherId() string {
+	if x != nil {
+		return x.PublisherId
+	}
+	return ""
+}
+
+type SetPublisherPayloadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PublisherId   string                 `protobuf:"bytes,1,opt,name=publisher_id,json=publisherId,proto3" json:"publisher_id,omitempty"`
+	MaxBytes      int32                  `protobuf:"varint,2,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"`
+	ContentType   string                 `protobuf:"bytes,3,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPublisherPayloadRequest) Reset() {
+	*x = SetPublisherPayloadRequest{}
+	mi := &file_messagebroker_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPublisherPayloadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPublisherPayloadRequest) ProtoMessage() {}
+
+func (x *SetPublisherPayloadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPublisherPayloadRequest.ProtoReflect.Descriptor instead.
+func (*SetPublisherPayloadRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *SetPublisherPayloadRequest) GetPublisherId() string {
+	if x != nil {
+		return x.PublisherId
+	}
+	return ""
+}
+
+func (x *SetPublisherPayloadRequest) GetMaxBytes() int32 {
+	if x != nil {
+		return x.MaxBytes
+	}
+	return 0
+}
+
+func (x *SetPublisherPayloadRequest) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+type PublisherPayloadResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Payload *PayloadPolicy         `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	// limit payloads to the publisher are held to
+	MaxPayloadBytes int32 `protobuf:"varint,4,opt,name=max_payload_bytes,json=maxPayloadBytes,proto3" json:"max_payload_bytes,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PublisherPayloadResponse) Reset() {
+	*x = PublisherPayloadResponse{}
+	mi := &file_messagebroker_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublisherPayloadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublisherPayloadResponse) ProtoMessage() {}
+
+func (x *PublisherPayloadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublisherPayloadResponse.ProtoReflect.Descriptor instead.
+func (*PublisherPayloadResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *PublisherPayloadResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *PublisherPayloadResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *PublisherPayloadResponse) GetPayload() *PayloadPolicy {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *PublisherPayloadResponse) GetMaxPayloadBytes() int32 {
+	if x != nil {
+		return x.MaxPayloadBytes
+	}
+	return 0
+}
+
+// version of the JSON Schema payloads published to a publisher are validated against
+type Schema struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Version int32                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// the JSON Schema document
+	Schema string `protobuf:"bytes,2,opt,name=schema,proto3" json:"schema,omitempty"`
+	// backward, forward, full or none
+	Compatibility string `protobuf:"bytes,3,opt,name=compatibility,proto3" json:"compatibility,omitempty"`
+	// RFC 3339 time
+	CreatedAt     string `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Schema) Reset() {
+	*x = Schema{}
+	mi := &file_messagebroker_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Schema) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Schema) ProtoMessage() {}
+
+func (x *Schema) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Schema.ProtoReflect.Descriptor instead.
+func (*Schema) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *Schema) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Schema) GetSchema() string {
+	if x != nil {
+		return x.Schema
+	}
+	return ""
+}
+
+func (x *Schema) GetCompatibility() string {
+	if x != nil {
+		return x.Compatibility
+	}
+	return ""
+}
+
+func (x *Schema) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type ListSchemasRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PublisherId   string                 `protobuf:"bytes,1,opt,name=publisher_id,json=publisherId,proto3" json:"publisher_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSchemasRequest) Reset() {
+	*x = ListSchemasRequest{}
+	mi := &file_messagebroker_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSchemasRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSchemasRequest) ProtoMessage() {}
+
+func (x *ListSchemasRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSchemasRequest.ProtoReflect.Descriptor instead.
+func (*ListSchemasRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ListSchemasRequest) GetPublisherId() string {
+	if x != nil {
+		return x.PublisherId
+	}
+	return ""
+}
+
+type ListSchemasResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// oldest first
+	Schemas       []*Schema `protobuf:"bytes,3,rep,name=schemas,proto3" json:"schemas,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSchemasResponse) Reset() {
+	*x = ListSchemasResponse{}
+	mi := &file_messagebroker_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSchemasResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSchemasResponse) ProtoMessage() {}
+
+func (x *ListSchemasResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSchemasResponse.ProtoReflect.Descriptor instead.
+func (*ListSchemasResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ListSchemasResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListSchemasResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListSchemasResponse) GetSchemas() []*Schema {
+	if x != nil {
+		return x.Schemas
+	}
+	return nil
+}
+
+type RegisterSchemaRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	PublisherId string                 `protobuf:"bytes,1,opt,name=publisher_id,json=publisherId,proto3" json:"publisher_id,omitempty"`
+	Schema      string                 `protobuf:"bytes,2,opt,name=schema,proto3" json:"schema,omitempty"`
+	// rule the new version is checked against the latest with, empty keeps the latest version's
+	Compatibility string `protobuf:"bytes,3,opt,name=compatibility,proto3" json:"compatibility,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterSchemaRequest) Reset() {
+	*x = RegisterSchemaRequest{}
+	mi := &file_messagebroker_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterSchemaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterSchemaRequest) ProtoMessage() {}
+
+func (x *RegisterSchemaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterSchemaRequest.ProtoReflect.Descriptor instead.
+func (*RegisterSchemaRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *RegisterSchemaRequest) GetPublisherId() string {
+	if x != nil {
+		return x.PublisherId
+	}
+	return ""
+}
+
+func (x *RegisterSchemaRequest) GetSchema() string {
+	if x != nil {
+		return x.Schema
+	}
+	return ""
+}
+
+func (x *RegisterSchemaRequest) GetCompatibility() string {
+	if x != nil {
+		return x.Compatibility
+	}
+	return ""
+}
+
+type GetSchemaRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	PublisherId string                 `protobuf:"bytes,1,opt,name=publisher_id,json=publisherId,proto3" json:"publisher_id,omitempty"`
+	// 0 for the latest version
+	Version       int32 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSchemaRequest) Reset() {
+	*x = GetSchemaRequest{}
+	mi := &file_messagebroker_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSchemaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSchemaRequest) ProtoMessage() {}
+
+func (x *GetSchemaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSchemaRequest.ProtoReflect.Descriptor instead.
+func (*GetSchemaRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *GetSchemaRequest) GetPublisherId() string {
+	if x != nil {
+		return x.PublisherId
+	}
+	return ""
+}
+
+func (x *GetSchemaRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type SchemaResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Schema        *Schema                `protobuf:"bytes,3,opt,name=schema,proto3" json:"schema,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SchemaResponse) Reset() {
+	*x = SchemaResponse{}
+	mi := &file_messagebroker_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SchemaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SchemaResponse) ProtoMessage() {}
+
+func (x *SchemaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SchemaResponse.ProtoReflect.Descriptor instead.
+func (*SchemaResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *SchemaResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SchemaResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SchemaResponse) GetSchema() *Schema {
+	if x != nil {
+		return x.Schema
+	}
+	return nil
+}
+
+type PublisherACL struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Visibility string                 `protobuf:"bytes,1,opt,name=visibility,proto3" json:"visibility,omitempty"`
+	Clients    []string               `protobuf:"bytes,2,rep,name=clients,proto3" json:"clients,omitempty"`
+	Groups     []string               `protobuf:"bytes,3,rep,name=groups,proto3" json:"groups,omitempty"`
+	// clients whose subscription requests were approved, ignored by SetPublisherACL
+	Approved []string `protobuf:"bytes,4,rep,name=approved,proto3" json:"approved,omitempty"`
+	// organizations the publisher is shared with
+	Organizations []string `protobuf:"bytes,5,rep,name=organizations,proto3" json:"organizations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublisherACL) Reset() {
+	*x = PublisherACL{}
+	mi := &file_messagebroker_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublisherACL) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublisherACL) ProtoMessage() {}
+
+func (x *PublisherACL) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublisherACL.ProtoReflect.Descriptor instead.
+func (*PublisherACL) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *PublisherACL) GetVisibility() string {
+	if x != nil {
+		return x.Visibility
+	}
+	return ""
+}
+
+func (x *PublisherACL) GetClients() []string {
+	if x != nil {
+		return x.Clients
+	}
+	return nil
+}
+
+func (x *PublisherACL) GetGroups() []string {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+func (x *PublisherACL) GetApproved() []string {
+	if x != nil {
+		return x.Approved
+	}
+	return nil
+}
+
+func (x *PublisherACL) GetOrganizations() []string {
+	if x != nil {
+		return x.Organizations
+	}
+	return nil
+}
+
+type GetPublisherACLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PublisherId   string                 `protobuf:"bytes,1,opt,name=publisher_id,json=publisherId,proto3" json:"publisher_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPublisherACLRequest) Reset() {
+	*x = GetPublisherACLRequest{}
+	mi := &file_messagebroker_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPublisherACLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPublisherACLRequest) ProtoMessage() {}
+
+func (x *GetPublisherACLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPublisherACLRequest.ProtoReflect.Descriptor instead.
+func (*GetPublisherACLRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *GetPublisherACLRequest) GetPublisherId() string {
+	if x != nil {
+		return x.PublisherId
+	}
+	return ""
+}
+
+type SetPublisherACLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PublisherId   string                 `protobuf:"bytes,1,opt,name=publisher_id,json=publisherId,proto3" json:"publisher_id,omitempty"`
+	Visibility    string                 `protobuf:"bytes,2,opt,name=visibility,proto3" json:"visibility,omitempty"`
+	Clients       []string               `protobuf:"bytes,3,rep,name=clients,proto3" json:"clients,omitempty"`
+	Groups        []string               `protobuf:"bytes,4,rep,name=groups,proto3" json:"groups,omitempty"`
+	Organizations []string               `protobuf:"bytes,5,rep,name=organizations,proto3" json:"organizations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPublisherACLRequest) Reset() {
+	*x = SetPublisherACLRequest{}
+	mi := &file_messagebroker_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPublisherACLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPublisherACLRequest) ProtoMessage() {}
+
+func (x *SetPublisherACLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPublisherACLRequest.ProtoReflect.Descriptor instead.
+func (*SetPublisherACLRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *SetPublisherACLRequest) GetPublisherId() string {
+	if x != nil {
+		return x.PublisherId
+	}
+	return ""
+}
+
+func (x *SetPublisherACLRequest) GetVisibility() string {
+	if x != nil {
+		return x.Visibility
+	}
+	return ""
+}
+
+func (x *SetPublisherACLRequest) GetClients() []string {
+	if x != nil {
+		return x.Clients
+	}
+	return nil
+}
+
+func (x *SetPublisherACLRequest) GetGroups() []string {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+func (x *SetPublisherACLRequest) GetOrganizations() []string {
+	if x != nil {
+		return x.Organizations
+	}
+	return nil
+}
+
+type AllowClientRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PublisherId   string                 `protobuf:"bytes,1,opt,name=publisher_id,json=publisherId,proto3" json:"publisher_id,omitempty"`
+	ClientId      string                 `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AllowClientRequest) Reset() {
+	*x = AllowClientRequest{}
+	mi := &file_messagebroker_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AllowClientRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllowClientRequest) ProtoMessage() {}
+
+func (x *AllowClientRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllowClientRequest.ProtoReflect.Descriptor instead.
+func (*AllowClientRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *AllowClientRequest) GetPublisherId() string {
+	if x != nil {
+		return x.PublisherId
+	}
+	return ""
+}
+
+func (x *AllowClientRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+type AllowGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PublisherId   string                 `protobuf:"bytes,1,opt,name=publisher_id,json=publisherId,proto3" json:"publisher_id,omitempty"`
+	GroupId       string                 `protobuf:"bytes,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AllowGroupRequest) Reset() {
+	*x = AllowGroupRequest{}
+	mi := &file_messagebroker_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AllowGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllowGroupRequest) ProtoMessage() {}
+
+func (x *AllowGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllowGroupRequest.ProtoReflect.Descriptor instead.
+func (*AllowGroupRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *AllowGroupRequest) GetPublisherId() string {
+	if x != nil {
+		return x.PublisherId
+	}
+	return ""
+}
+
+func (x *AllowGroupRequest) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+type PublisherACLResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Acl     *PublisherACL          `protobuf:"bytes,3,opt,name=acl,proto3" json:"acl,omitempty"`
+	// subscriptions removed because the change took their access away
+	Revoked       int32 `protobuf:"varint,4,opt,name=revoked,proto3" json:"revoked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublisherACLResponse) Reset() {
+	*x = PublisherACLResponse{}
+	mi := &file_messagebroker_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublisherACLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublisherACLResponse) ProtoMessage() {}
+
+func (x *PublisherACLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublisherACLResponse.ProtoReflect.Descriptor instead.
+func (*PublisherACLResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *PublisherACLResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *PublisherACLResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *PublisherACLResponse) GetAcl() *PublisherACL {
+	if x != nil {
+		return x.Acl
+	}
+	return nil
+}
+
+func (x *PublisherACLResponse) GetRevoked() int32 {
+	if x != nil {
+		return x.Revoked
+	}
+	return 0
+}
+
+type Group struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Members       []string               `protobuf:"bytes,3,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Group) Reset() {
+	*x = Group{}
+	mi := &file_messagebroker_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Group) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Group) ProtoMessage() {}
+
+func (x *Group) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Group.ProtoReflect.Descriptor instead.
+func (*Group) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *Group) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Group) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Group) GetMembers() []string {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type ListGroupsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGroupsRequest) Reset() {
+	*x = ListGroupsRequest{}
+	mi := &file_messagebroker_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGroupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGroupsRequest) ProtoMessage() {}
+
+func (x *ListGroupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGroupsRequest.ProtoReflect.Descriptor instead.
+func (*ListGroupsRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{56}
+}
+
+type ListGroupsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Groups        []*Group               `protobuf:"bytes,3,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGroupsResponse) Reset() {
+	*x = ListGroupsResponse{}
+	mi := &file_messagebroker_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGroupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGroupsResponse) ProtoMessage() {}
+
+func (x *ListGroupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGroupsResponse.ProtoReflect.Descriptor instead.
+func (*ListGroupsResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ListGroupsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListGroupsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListGroupsResponse) GetGroups() []*Group {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+type CreateGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Members       []string               `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateGroupRequest) Reset() {
+	*x = CreateGroupRequest{}
+	mi := &file_messagebroker_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateGroupRequest) ProtoMessage() {}
+
+func (x *CreateGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateGroupRequest.ProtoReflect.Descriptor instead.
+func (*CreateGroupRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *CreateGroupRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateGroupRequest) GetMembers() []string {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type SetGroupMembersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupId       string                 `protobuf:"bytes,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	Members       []string               `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetGroupMembersRequest) Reset() {
+	*x = SetGroupMembersRequest{}
+	mi := &file_messagebroker_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetGroupMembersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetGroupMembersRequest) ProtoMessage() {}
+
+func (x *SetGroupMembersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetGroupMembersRequest.ProtoReflect.Descriptor instead.
+func (*SetGroupMembersRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *SetGroupMembersRequest) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *SetGroupMembersRequest) GetMembers() []string {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type DeleteGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupId       string                 `protobuf:"bytes,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteGroupRequest) Reset() {
+	*x = DeleteGroupRequest{}
+	mi := &file_messagebroker_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteGroupRequest) ProtoMessage() {}
+
+func (x *DeleteGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteGroupRequest.ProtoReflect.Descriptor instead.
+func (*DeleteGroupRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *DeleteGroupRequest) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+type GroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Group         *Group                 `protobuf:"bytes,3,opt,name=group,proto3" json:"group,omitempty"`
+	Revoked       int32                  `protobuf:"varint,4,opt,name=revoked,proto3" json:"revoked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupResponse) Reset() {
+	*x = GroupResponse{}
+	mi := &file_messagebroker_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupResponse) ProtoMessage() {}
+
+func (x *GroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupResponse.ProtoReflect.Descriptor instead.
+func (*GroupResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *GroupResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GroupResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GroupResponse) GetGroup() *Group {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+func (x *GroupResponse) GetRevoked() int32 {
+	if x != nil {
+		return x.Revoked
+	}
+	return 0
+}
+
+type PublishMessageRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	PublisherId string                 `protobuf:"bytes,1,opt,name=publisher_id,json=publisherId,proto3" json:"publisher_id,omitempty"`
+	// time to live in seconds, 0 to keep the message until it is deleted
+	Ttl           int64  `protobuf:"varint,2,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	Payload       string `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishMessageRequest) Reset() {
+	*x = PublishMessageRequest{}
+	mi := &file_messagebroker_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishMessageRequest) ProtoMessage() {}
+
+func (x *PublishMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishMessageRequest.ProtoReflect.Descriptor instead.
+func (*PublishMessageRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *PublishMessageRequest) GetPublisherId() string {
+	if x != nil {
+		return x.PublisherId
+	}
+	return ""
+}
+
+func (x *PublishMessageRequest) GetTtl() int64 {
+	if x != nil {
+		return x.Ttl
+	}
+	return 0
+}
+
+func (x *PublishMessageRequest) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+type SubscriptionPublisher struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	OwnerId       string                 `protobuf:"bytes,3,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscriptionPublisher) Reset() {
+	*x = SubscriptionPublisher{}
+	mi := &file_messagebroker_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionPublisher) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionPublisher) ProtoMessage() {}
+
+func (x *SubscriptionPublisher) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionPublisher.ProtoReflect.Descriptor instead.
+func (*SubscriptionPublisher) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *SubscriptionPublisher) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SubscriptionPublisher) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SubscriptionPublisher) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+type WebhookStatus struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Url   string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	// healthy, or unhealthy after repeated failed deliveries
+	Status              string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	ConsecutiveFailures int32  `protobuf:"varint,3,opt,name=consecutive_failures,json=consecutiveFailures,proto3" json:"consecutive_failures,omitempty"`
+	LastError           string `protobuf:"bytes,4,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	// RFC 3339 times, empty if they have never happened
+	LastSuccessAt string `protobuf:"bytes,5,opt,name=last_success_at,json=lastSuccessAt,proto3" json:"last_success_at,omitempty"`
+	LastFailureAt string `protobuf:"bytes,6,opt,name=last_failure_at,json=lastFailureAt,proto3" json:"last_failure_at,omitempty"`
+	NextAttemptAt string `protobuf:"bytes,7,opt,name=next_attempt_at,json=nextAttemptAt,proto3" json:"next_attempt_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookStatus) Reset() {
+	*x = WebhookStatus{}
+	mi := &file_messagebroker_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookStatus) ProtoMessage() {}
+
+func (x *WebhookStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookStatus.ProtoReflect.Descriptor instead.
+func (*WebhookStatus) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *WebhookStatus) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *WebhookStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WebhookStatus) GetConsecutiveFailures() int32 {
+	if x != nil {
+		return x.ConsecutiveFailures
+	}
+	return 0
+}
+
+func (x *WebhookStatus) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *WebhookStatus) GetLastSuccessAt() string {
+	if x != nil {
+		return x.LastSuccessAt
+	}
+	return ""
+}
+
+func (x *WebhookStatus) GetLastFailureAt() string {
+	if x != nil {
+		return x.LastFailureAt
+	}
+	return ""
+}
+
+func (x *WebhookStatus) GetNextAttemptAt() string {
+	if x != nil {
+		return x.NextAttemptAt
+	}
+	return ""
+}
+
+type Subscription struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Publisher *SubscriptionPublisher `protobuf:"bytes,2,opt,name=publisher,proto3" json:"publisher,omitempty"`
+	// stream or webhook
+	Type          string         `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Webhook       *WebhookStatus `protobuf:"bytes,4,opt,name=webhook,proto3" json:"webhook,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Subscription) Reset() {
+	*x = Subscription{}
+	mi := &file_messagebroker_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Subscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subscription) ProtoMessage() {}
+
+func (x *Subscription) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subscription.ProtoReflect.Descriptor instead.
+func (*Subscription) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *Subscription) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Subscription) GetPublisher() *SubscriptionPublisher {
+	if x != nil {
+		return x.Publisher
+	}
+	return nil
+}
+
+func (x *Subscription) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Subscription) GetWebhook() *WebhookStatus {
+	if x != nil {
+		return x.Webhook
+	}
+	return nil
+}
+
+type ListSubscriptionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionsRequest) Reset() {
+	*x = ListSubscriptionsRequest{}
+	mi := &file_messagebroker_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionsRequest) ProtoMessage() {}
+
+func (x *ListSubscriptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionsRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{66}
+}
+
+type ListSubscriptionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Subscriptions []*Subscription        `protobuf:"bytes,3,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionsResponse) Reset() {
+	*x = ListSubscriptionsResponse{}
+	mi := &file_messagebroker_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionsResponse) ProtoMessage() {}
+
+func (x *ListSubscriptionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionsResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *ListSubscriptionsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListSubscriptionsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListSubscriptionsResponse) GetSubscriptions() []*Subscription {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return nil
+}
+
+type SubscribeRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	PublisherId string                 `protobuf:"bytes,1,opt,name=publisher_id,json=publisherId,proto3" json:"publisher_id,omitempty"`
+	// stream (the default) or webhook
+	Type string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	// URL webhook deliveries are POSTed to
+	Url string `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	// secret webhook deliveries are signed with
+	Secret        string `protobuf:"bytes,4,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	mi := &file_messagebroker_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *SubscribeRequest) GetPublisherId() string {
+	if x != nil {
+		return x.PublisherId
+	}
+	return ""
+}
+
+func (x *SubscribeRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *SubscribeRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *SubscribeRequest) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type UnsubscribeRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId string                 `protobuf:"bytes,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UnsubscribeRequest) Reset() {
+	*x = UnsubscribeRequest{}
+	mi := &file_messagebroker_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnsubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnsubscribeRequest) ProtoMessage() {}
+
+func (x *UnsubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnsubscribeRequest.ProtoReflect.Descriptor instead.
+func (*UnsubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *UnsubscribeRequest) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+// request from a client to subscribe to a publisher it can't subscribe to directly
+type SubscriptionRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ClientId  string                 `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Publisher *SubscriptionPublisher `protobuf:"bytes,3,opt,name=publisher,proto3" json:"publisher,omitempty"`
+	Message   string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	// stream or webhook, the type of subscription added once the request is approved
+	Type       string `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"`
+	WebhookUrl string `protobuf:"bytes,6,opt,name=webhook_url,json=webhookUrl,proto3" json:"webhook_url,omitempty"`
+	// pending, approved or denied
+	Status string `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	Reason string `protobuf:"bytes,8,opt,name=reason,proto3" json:"reason,omitempty"`
+	// RFC 3339 times, decided_at is empty while the request is pending
+	CreatedAt     string `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	DecidedAt     string `protobuf:"bytes,10,opt,name=decided_at,json=decidedAt,proto3" json:"decided_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscriptionRequest) Reset() {
+	*x = SubscriptionRequest{}
+	mi := &file_messagebroker_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionRequest) ProtoMessage() {}
+
+func (x *SubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*SubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *SubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SubscriptionRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *SubscriptionRequest) GetPublisher() *SubscriptionPublisher {
+	if x != nil {
+		return x.Publisher
+	}
+	return nil
+}
+
+func (x *SubscriptionRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SubscriptionRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *SubscriptionRequest) GetWebhookUrl() string {
+	if x != nil {
+		return x.WebhookUrl
+	}
+	return ""
+}
+
+func (x *SubscriptionRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SubscriptionRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *SubscriptionRequest) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *SubscriptionRequest) GetDecidedAt() string {
+	if x != nil {
+		return x.DecidedAt
+	}
+	return ""
+}
+
+type ListSubscriptionRequestsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// pending, approved or denied, empty for every request
+	Status        string `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionRequestsRequest) Reset() {
+	*x = ListSubscriptionRequestsRequest{}
+	mi := &file_messagebroker_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionRequestsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionRequestsRequest) ProtoMessage() {}
+
+func (x *ListSubscriptionRequestsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionRequestsRequest.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionRequestsRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *ListSubscriptionRequestsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ListSubscriptionRequestsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Requests      []*SubscriptionRequest `protobuf:"bytes,3,rep,name=requests,proto3" json:"requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionRequestsResponse) Reset() {
+	*x = ListSubscriptionRequestsResponse{}
+	mi := &file_messagebroker_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionRequestsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionRequestsResponse) ProtoMessage() {}
+
+func (x *ListSubscriptionRequestsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionRequestsResponse.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionRequestsResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *ListSubscriptionRequestsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListSubscriptionRequestsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListSubscriptionRequestsResponse) GetRequests() []*SubscriptionRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+type CreateSubscriptionRequestRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	PublisherId string                 `protobuf:"bytes,1,opt,name=publisher_id,json=publisherId,proto3" json:"publisher_id,omitempty"`
+	// to the owner of the publisher
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// the subscription to add once the request is approved, as in SubscribeRequest
+	Type          string `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Url           string `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+	Secret        string `protobuf:"bytes,5,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSubscriptionRequestRequest) Reset() {
+	*x = CreateSubscriptionRequestRequest{}
+	mi := &file_messagebroker_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSubscriptionRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSubscriptionRequestRequest) ProtoMessage() {}
+
+func (x *CreateSubscriptionRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSubscriptionRequestRequest.ProtoReflect.Descriptor instead.
+func (*CreateSubscriptionRequestRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *CreateSubscriptionRequestRequest) GetPublisherId() string {
+	if x != nil {
+		return x.PublisherId
+	}
+	return ""
+}
+
+func (x *CreateSubscriptionRequestRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CreateSubscriptionRequestRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *CreateSubscriptionRequestRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CreateSubscriptionRequestRequest) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type DecideSubscriptionRequestRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	RequestId string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// optional, passed on to the client
+	Reason        string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecideSubscriptionRequestRequest) Reset() {
+	*x = DecideSubscriptionRequestRequest{}
+	mi := &file_messagebroker_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecideSubscriptionRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecideSubscriptionRequestRequest) ProtoMessage() {}
+
+func (x *DecideSubscriptionRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecideSubscriptionRequestRequest.ProtoReflect.Descriptor instead.
+func (*DecideSubscriptionRequestRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *DecideSubscriptionRequestRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *DecideSubscriptionRequestRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type SubscriptionRequestResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Request       *SubscriptionRequest   `protobuf:"bytes,3,opt,name=request,proto3" json:"request,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscriptionRequestResponse) Reset() {
+	*x = SubscriptionRequestResponse{}
+	mi := &file_messagebroker_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionRequestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionRequestResponse) ProtoMessage() {}
+
+func (x *SubscriptionRequestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionRequestResponse.ProtoReflect.Descriptor instead.
+func (*SubscriptionRequestResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *SubscriptionRequestResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SubscriptionRequestResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SubscriptionRequestResponse) GetRequest() *SubscriptionRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+type StreamRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Request:
+	//
+	//	*StreamRequest_Authenticate
+	//	*StreamRequest_ConfirmMessages
+	//	*StreamRequest_ListSessions
+	Request       isStreamRequest_Request `protobuf_oneof:"request"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamRequest) Reset() {
+	*x = StreamRequest{}
+	mi := &file_messagebroker_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRequest) ProtoMessage() {}
+
+func (x *StreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRequest.ProtoReflect.Descriptor instead.
+func (*StreamRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *StreamRequest) GetRequest() isStreamRequest_Request {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *StreamRequest) GetAuthenticate() *StreamAuthenticate {
+	if x != nil {
+		if x, ok := x.Request.(*StreamRequest_Authenticate); ok {
+			return x.Authenticate
+		}
+	}
+	return nil
+}
+
+func (x *StreamRequest) GetConfirmMessages() *ConfirmMessages {
+	if x != nil {
+		if x, ok := x.Request.(*StreamRequest_ConfirmMessages); ok {
+			return x.ConfirmMessages
+		}
+	}
+	return nil
+}
+
+func (x *StreamRequest) GetListSessions() *ListSessions {
+	if x != nil {
+		if x, ok := x.Request.(*StreamRequest_ListSessions); ok {
+			return x.ListSessions
+		}
+	}
+	return nil
+}
+
+type isStreamRequest_Request interface {
+	isStreamRequest_Request()
+}
+
+type StreamRequest_Authenticate struct {
+	Authenticate *StreamAuthenticate `protobuf:"bytes,1,opt,name=authenticate,proto3,oneof"`
+}
+
+type StreamRequest_ConfirmMessages struct {
+	ConfirmMessages *ConfirmMessages `protobuf:"bytes,2,opt,name=confirm_messages,json=confirmMessages,proto3,oneof"`
+}
+
+type StreamRequest_ListSessions struct {
+	ListSessions *ListSessions `protobuf:"bytes,3,opt,name=list_sessions,json=listSessions,proto3,oneof"`
+}
+
+func (*StreamRequest_Authenticate) isStreamRequest_Request() {}
+
+func (*StreamRequest_ConfirmMessages) isStreamRequest_Request() {}
+
+func (*StreamRequest_ListSessions) isStreamRequest_Request() {}
+
+// authenticate the stream, the equivalent of the websocket authentication response
+type StreamAuthenticate struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Secret string                 `protobuf:"bytes,4,opt,name=secret,proto3" json:"secret,omitempty"`
+	// authenticate with an API key instead of the id and secret
+	ApiKey string `protobuf:"bytes,5,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// authenticate with an access token from the publisher service
+	Token string `protobuf:"bytes,6,opt,name=token,proto3" json:"token,omitempty"`
+	// fanout or balanced, how messages are shared between the client's sessions
+	Delivery string `protobuf:"bytes,2,opt,name=delivery,proto3" json:"delivery,omitempty"`
+	// close any other sessions the client has open
+	SingleSession bool `protobuf:"varint,3,opt,name=single_session,json=singleSession,proto3" json:"single_session,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamAuthenticate) Reset() {
+	*x = StreamAuthenticate{}
+	mi := &file_messagebroker_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamAuthenticate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamAuthenticate) ProtoMessage() {}
+
+func (x *StreamAuthenticate) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamAuthenticate.ProtoReflect.Descriptor instead.
+func (*StreamAuthenticate) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *StreamAuthenticate) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *StreamAuthenticate) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *StreamAuthenticate) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *StreamAuthenticate) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *StreamAuthenticate) GetDelivery() string {
+	if x != nil {
+		return x.Delivery
+	}
+	return ""
+}
+
+func (x *StreamAuthenticate) GetSingleSession() bool {
+	if x != nil {
+		return x.SingleSession
+	}
+	return false
+}
+
+type ConfirmMessage struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SubscriptionId string                 `protobuf:"bytes,2,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ConfirmMessage) Reset() {
+	*x = ConfirmMessage{}
+	mi := &file_messagebroker_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmMessage) ProtoMessage() {}
+
+func (x *ConfirmMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmMessage.ProtoReflect.Descriptor instead.
+func (*ConfirmMessage) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *ConfirmMessage) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ConfirmMessage) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+type ConfirmMessages struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*ConfirmMessage      `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmMessages) Reset() {
+	*x = ConfirmMessages{}
+	mi := &file_messagebroker_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmMessages) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmMessages) ProtoMessage() {}
+
+func (x *ConfirmMessages) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmMessages.ProtoReflect.Descriptor instead.
+func (*ConfirmMessages) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *ConfirmMessages) GetMessages() []*ConfirmMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type ListSessions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessions) Reset() {
+	*x = ListSessions{}
+	mi := &file_messagebroker_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessions) ProtoMessage() {}
+
+func (x *ListSessions) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessions.ProtoReflect.Descriptor instead.
+func (*ListSessions) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{80}
+}
+
+type StreamResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Response:
+	//
+	//	*StreamResponse_Authentication
+	//	*StreamResponse_SessionStarted
+	//	*StreamResponse_Messages
+	//	*StreamResponse_MessagesConfirmed
+	//	*StreamResponse_Sessions
+	//	*StreamResponse_Notice
+	Response      isStreamResponse_Response `protobuf_oneof:"response"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamResponse) Reset() {
+	*x = StreamResponse{}
+	mi := &file_messagebroker_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamResponse) ProtoMessage() {}
+
+func (x *StreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamResponse.ProtoReflect.Descriptor instead.
+func (*StreamResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *StreamResponse) GetResponse() isStreamResponse_Response {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *StreamResponse) GetAuthentication() *AuthenticationResult {
+	if x != nil {
+		if x, ok := x.Response.(*StreamResponse_Authentication); ok {
+			return x.Authentication
+		}
+	}
+	return nil
+}
+
+func (x *StreamResponse) GetSessionStarted() *SessionStarted {
+	if x != nil {
+		if x, ok := x.Response.(*StreamResponse_SessionStarted); ok {
+			return x.SessionStarted
+		}
+	}
+	return nil
+}
+
+func (x *StreamResponse) GetMessages() *Messages {
+	if x != nil {
+		if x, ok := x.Response.(*StreamResponse_Messages); ok {
+			return x.Messages
+		}
+	}
+	return nil
+}
+
+func (x *StreamResponse) GetMessagesConfirmed() *MessagesConfirmed {
+	if x != nil {
+		if x, ok := x.Response.(*StreamResponse_MessagesConfirmed); ok {
+			return x.MessagesConfirmed
+		}
+	}
+	return nil
+}
+
+func (x *StreamResponse) GetSessions() *Sessions {
+	if x != nil {
+		if x, ok := x.Response.(*StreamResponse_Sessions); ok {
+			return x.Sessions
+		}
+	}
+	return nil
+}
+
+func (x *StreamResponse) GetNotice() *Notice {
+	if x != nil {
+		if x, ok := x.Response.(*StreamResponse_Notice); ok {
+			return x.Notice
+		}
+	}
+	return nil
+}
+
+type isStreamResponse_Response interface {
+	isStreamResponse_Response()
+}
+
+type StreamResponse_Authentication struct {
+	Authentication *AuthenticationResult `protobuf:"bytes,1,opt,name=authentication,proto3,oneof"`
+}
+
+type StreamResponse_SessionStarted struct {
+	SessionStarted *SessionStarted `protobuf:"bytes,2,opt,name=session_started,json=sessionStarted,proto3,oneof"`
+}
+
+type StreamResponse_Messages struct {
+	Messages *Messages `protobuf:"bytes,3,opt,name=messages,proto3,oneof"`
+}
+
+type StreamResponse_MessagesConfirmed struct {
+	MessagesConfirmed *MessagesConfirmed `protobuf:"bytes,4,opt,name=messages_confirmed,json=messagesConfirmed,proto3,oneof"`
+}
+
+type StreamResponse_Sessions struct {
+	Sessions *Sessions `protobuf:"bytes,5,opt,name=sessions,proto3,oneof"`
+}
+
+type StreamResponse_Notice struct {
+	Notice *Notice `protobuf:"bytes,6,opt,name=notice,proto3,oneof"`
+}
+
+func (*StreamResponse_Authentication) isStreamResponse_Response() {}
+
+func (*StreamResponse_SessionStarted) isStreamResponse_Response() {}
+
+func (*StreamResponse_Messages) isStreamResponse_Response() {}
+
+func (*StreamResponse_MessagesConfirmed) isStreamResponse_Response() {}
+
+func (*StreamResponse_Sessions) isStreamResponse_Response() {}
+
+func (*StreamResponse_Notice) isStreamResponse_Response() {}
+
+type AuthenticationResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Client        *Client                `protobuf:"bytes,3,opt,name=client,proto3" json:"client,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthenticationResult) Reset() {
+	*x = AuthenticationResult{}
+	mi := &file_messagebroker_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthenticationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticationResult) ProtoMessage() {}
+
+func (x *AuthenticationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticationResult.ProtoReflect.Descriptor instead.
+func (*AuthenticationResult) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *AuthenticationResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *AuthenticationResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *AuthenticationResult) GetClient() *Client {
+	if x != nil {
+		return x.Client
+	}
+	return nil
+}
+
+type SessionStarted struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Delivery      string                 `protobuf:"bytes,2,opt,name=delivery,proto3" json:"delivery,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionStarted) Reset() {
+	*x = SessionStarted{}
+	mi := &file_messagebroker_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionStarted) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionStarted) ProtoMessage() {}
+
+func (x *SessionStarted) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionStarted.ProtoReflect.Descriptor instead.
+func (*SessionStarted) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *SessionStarted) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionStarted) GetDelivery() string {
+	if x != nil {
+		return x.Delivery
+	}
+	return ""
+}
+
+type Message struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PublisherId    string                 `protobuf:"bytes,2,opt,name=publisher_id,json=publisherId,proto3" json:"publisher_id,omitempty"`
+	SubscriptionId string                 `protobuf:"bytes,3,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	Payload        string                 `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
+	// version of the publisher's schema the payload matched, 0 if it doesn't have one
+	SchemaVersion int32 `protobuf:"varint,5,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message) Reset() {
+	*x = Message{}
+	mi := &file_messagebroker_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message) ProtoMessage() {}
+
+func (x *Message) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *Message) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Message) GetPublisherId() string {
+	if x != nil {
+		return x.PublisherId
+	}
+	return ""
+}
+
+func (x *Message) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *Message) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *Message) GetSchemaVersion() int32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+type Messages struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*Message             `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Messages) Reset() {
+	*x = Messages{}
+	mi := &file_messagebroker_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Messages) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Messages) ProtoMessage() {}
+
+func (x *Messages) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Messages.ProtoReflect.Descriptor instead.
+func (*Messages) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *Messages) GetMessages() []*Message {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type MessagesConfirmed struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Confirmed     int32                  `protobuf:"varint,1,opt,name=confirmed,proto3" json:"confirmed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessagesConfirmed) Reset() {
+	*x = MessagesConfirmed{}
+	mi := &file_messagebroker_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessagesConfirmed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessagesConfirmed) ProtoMessage() {}
+
+func (x *MessagesConfirmed) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessagesConfirmed.ProtoReflect.Descriptor instead.
+func (*MessagesConfirmed) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *MessagesConfirmed) GetConfirmed() int32 {
+	if x != nil {
+		return x.Confirmed
+	}
+	return 0
+}
+
+type Session struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RemoteAddress string                 `protobuf:"bytes,2,opt,name=remote_address,json=remoteAddress,proto3" json:"remote_address,omitempty"`
+	ConnectedAt   int64                  `protobuf:"varint,3,opt,name=connected_at,json=connectedAt,proto3" json:"connected_at,omitempty"`
+	Current       bool                   `protobuf:"varint,4,opt,name=current,proto3" json:"current,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_messagebroker_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[87]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *Session) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Session) GetRemoteAddress() string {
+	if x != nil {
+		return x.RemoteAddress
+	}
+	return ""
+}
+
+func (x *Session) GetConnectedAt() int64 {
+	if x != nil {
+		return x.ConnectedAt
+	}
+	return 0
+}
+
+func (x *Session) GetCurrent() bool {
+	if x != nil {
+		return x.Current
+	}
+	return false
+}
+
+type Sessions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*Session             `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Sessions) Reset() {
+	*x = Sessions{}
+	mi := &file_messagebroker_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sessions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sessions) ProtoMessage() {}
+
+func (x *Sessions) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[88]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sessions.ProtoReflect.Descriptor instead.
+func (*Sessions) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{88}
+}
+
+func (x *Sessions) GetSessions() []*Session {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+// notices sent by the server such as server_shutting_down or session_replaced
+type Notice struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Action        string                 `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Data          map[string]string      `protobuf:"bytes,3,rep,name=data,proto3" json:"data,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Notice) Reset() {
+	*x = Notice{}
+	mi := &file_messagebroker_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Notice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Notice) ProtoMessage() {}
+
+func (x *Notice) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[89]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Notice.ProtoReflect.Descriptor instead.
+func (*Notice) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *Notice) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *Notice) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Notice) GetData() map[string]string {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// limits are 0 when they're off. in an override 0 falls back to the default and a negative
+// value turns the limit off for the client
+type RateLimits struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	PublishPerMinute  int32                  `protobuf:"varint,1,opt,name=publish_per_minute,json=publishPerMinute,proto3" json:"publish_per_minute,omitempty"`
+	PublishBurst      int32                  `protobuf:"varint,2,opt,name=publish_burst,json=publishBurst,proto3" json:"publish_burst,omitempty"`
+	RequestsPerMinute int32                  `protobuf:"varint,3,opt,name=requests_per_minute,json=requestsPerMinute,proto3" json:"requests_per_minute,omitempty"`
+	RequestsBurst     int32                  `protobuf:"varint,4,opt,name=requests_burst,json=requestsBurst,proto3" json:"requests_burst,omitempty"`
+	Connections       int32                  `protobuf:"varint,5,opt,name=connections,proto3" json:"connections,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *RateLimits) Reset() {
+	*x = RateLimits{}
+	mi := &file_messagebroker_proto_msgTypes[90]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateLimits) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLimits) ProtoMessage() {}
+
+func (x *RateLimits) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[90]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLimits.ProtoReflect.Descriptor instead.
+func (*RateLimits) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *RateLimits) GetPublishPerMinute() int32 {
+	if x != nil {
+		return x.PublishPerMinute
+	}
+	return 0
+}
+
+func (x *RateLimits) GetPublishBurst() int32 {
+	if x != nil {
+		return x.PublishBurst
+	}
+	return 0
+}
+
+func (x *RateLimits) GetRequestsPerMinute() int32 {
+	if x != nil {
+		return x.RequestsPerMinute
+	}
+	return 0
+}
+
+func (x *RateLimits) GetRequestsBurst() int32 {
+	if x != nil {
+		return x.RequestsBurst
+	}
+	return 0
+}
+
+func (x *RateLimits) GetConnections() int32 {
+	if x != nil {
+		return x.Connections
+	}
+	return 0
+}
+
+type GetRateLimitsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRateLimitsRequest) Reset() {
+	*x = GetRateLimitsRequest{}
+	mi := &file_messagebroker_proto_msgTypes[91]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRateLimitsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRateLimitsRequest) ProtoMessage() {}
+
+func (x *GetRateLimitsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[91]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRateLimitsRequest.ProtoReflect.Descriptor instead.
+func (*GetRateLimitsRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{91}
+}
+
+type ClientRateLimitsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientId      string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClientRateLimitsRequest) Reset() {
+	*x = ClientRateLimitsRequest{}
+	mi := &file_messagebroker_proto_msgTypes[92]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientRateLimitsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientRateLimitsRequest) ProtoMessage() {}
+
+func (x *ClientRateLimitsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[92]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientRateLimitsRequest.ProtoReflect.Descriptor instead.
+func (*ClientRateLimitsRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{92}
+}
+
+func (x *ClientRateLimitsRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+type SetClientRateLimitsRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	ClientId          string                 `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	PublishPerMinute  int32                  `protobuf:"varint,2,opt,name=publish_per_minute,json=publishPerMinute,proto3" json:"publish_per_minute,omitempty"`
+	PublishBurst      int32                  `protobuf:"varint,3,opt,name=publish_burst,json=publishBurst,proto3" json:"publish_burst,omitempty"`
+	RequestsPerMinute int32                  `protobuf:"varint,4,opt,name=requests_per_minute,json=requestsPerMinute,proto3" json:"requests_per_minute,omitempty"`
+	RequestsBurst     int32                  `protobuf:"varint,5,opt,name=requests_burst,json=requestsBurst,proto3" json:"requests_burst,omitempty"`
+	Connections       int32                  `protobuf:"varint,6,opt,name=connections,proto3" json:"connections,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SetClientRateLimitsRequest) Reset() {
+	*x = SetClientRateLimitsRequest{}
+	mi := &file_messagebroker_proto_msgTypes[93]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetClientRateLimitsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetClientRateLimitsRequest) ProtoMessage() {}
+
+func (x *SetClientRateLimitsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[93]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetClientRateLimitsRequest.ProtoReflect.Descriptor instead.
+func (*SetClientRateLimitsRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{93}
+}
+
+func (x *SetClientRateLimitsRequest) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *SetClientRateLimitsRequest) GetPublishPerMinute() int32 {
+	if x != nil {
+		return x.PublishPerMinute
+	}
+	return 0
+}
+
+func (x *SetClientRateLimitsRequest) GetPublishBurst() int32 {
+	if x != nil {
+		return x.PublishBurst
+	}
+	return 0
+}
+
+func (x *SetClientRateLimitsRequest) GetRequestsPerMinute() int32 {
+	if x != nil {
+		return x.RequestsPerMinute
+	}
+	return 0
+}
+
+func (x *SetClientRateLimitsRequest) GetRequestsBurst() int32 {
+	if x != nil {
+		return x.RequestsBurst
+	}
+	return 0
+}
+
+func (x *SetClientRateLimitsRequest) GetConnections() int32 {
+	if x != nil {
+		return x.Connections
+	}
+	return 0
+}
+
+type RateLimitsResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// limits applied to the client
+	Limits *RateLimits `protobuf:"bytes,3,opt,name=limits,proto3" json:"limits,omitempty"`
+	// the client's override of the defaults, unset if it doesn't have one
+	Override      *RateLimits `protobuf:"bytes,4,opt,name=override,proto3" json:"override,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateLimitsResponse) Reset() {
+	*x = RateLimitsResponse{}
+	mi := &file_messagebroker_proto_msgTypes[94]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateLimitsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLimitsResponse) ProtoMessage() {}
+
+func (x *RateLimitsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[94]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLimitsResponse.ProtoReflect.Descriptor instead.
+func (*RateLimitsResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{94}
+}
+
+func (x *RateLimitsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RateLimitsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RateLimitsResponse) GetLimits() *RateLimits {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+func (x *RateLimitsResponse) GetOverride() *RateLimits {
+	if x != nil {
+		return x.Override
+	}
+	return nil
+}
+
+// messages stored against a quota, max_messages and max_bytes are 0 when there isn't one
+type Usage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      int64                  `protobuf:"varint,1,opt,name=messages,proto3" json:"messages,omitempty"`
+	Bytes         int64                  `protobuf:"varint,2,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	MaxMessages   int64                  `protobuf:"varint,3,opt,name=max_messages,json=maxMessages,proto3" json:"max_messages,omitempty"`
+	MaxBytes      int64                  `protobuf:"varint,4,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Usage) Reset() {
+	*x = Usage{}
+	mi := &file_messagebroker_proto_msgTypes[95]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Usage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Usage) ProtoMessage() {}
+
+func (x *Usage) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[95]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Usage.ProtoReflect.Descriptor instead.
+func (*Usage) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{95}
+}
+
+func (x *Usage) GetMessages() int64 {
+	if x != nil {
+		return x.Messages
+	}
+	return 0
+}
+
+func (x *Usage) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *Usage) GetMaxMessages() int64 {
+	if x != nil {
+		return x.MaxMessages
+	}
+	return 0
+}
+
+func (x *Usage) GetMaxBytes() int64 {
+	if x != nil {
+		return x.MaxBytes
+	}
+	return 0
+}
+
+type PublisherUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Usage         *Usage                 `protobuf:"bytes,3,opt,name=usage,proto3" json:"usage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublisherUsage) Reset() {
+	*x = PublisherUsage{}
+	mi := &file_messagebroker_proto_msgTypes[96]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublisherUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublisherUsage) ProtoMessage() {}
+
+func (x *PublisherUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[96]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublisherUsage.ProtoReflect.Descriptor instead.
+func (*PublisherUsage) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{96}
+}
+
+func (x *PublisherUsage) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PublisherUsage) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PublisherUsage) GetUsage() *Usage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+type GetUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageRequest) Reset() {
+	*x = GetUsageRequest{}
+	mi := &file_messagebroker_proto_msgTypes[97]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageRequest) ProtoMessage() {}
+
+func (x *GetUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[97]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetUsageRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{97}
+}
+
+type UsageResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// of all of the client's publishers, against the client's quota
+	Usage         *Usage            `protobuf:"bytes,3,opt,name=usage,proto3" json:"usage,omitempty"`
+	Publishers    []*PublisherUsage `protobuf:"bytes,4,rep,name=publishers,proto3" json:"publishers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UsageResponse) Reset() {
+	*x = UsageResponse{}
+	mi := &file_messagebroker_proto_msgTypes[98]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsageResponse) ProtoMessage() {}
+
+func (x *UsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[98]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsageResponse.ProtoReflect.Descriptor instead.
+func (*UsageResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{98}
+}
+
+func (x *UsageResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *UsageResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *UsageResponse) GetUsage() *Usage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *UsageResponse) GetPublishers() []*PublisherUsage {
+	if x != nil {
+		return x.Publishers
+	}
+	return nil
+}
+
+type GetPublisherUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PublisherId   string                 `protobuf:"bytes,1,opt,name=publisher_id,json=publisherId,proto3" json:"publisher_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPublisherUsageRequest) Reset() {
+	*x = GetPublisherUsageRequest{}
+	mi := &file_messagebroker_proto_msgTypes[99]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPublisherUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPublisherUsageRequest) ProtoMessage() {}
+
+func (x *GetPublisherUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[99]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPublisherUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetPublisherUsageRequest) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{99}
+}
+
+func (x *GetPublisherUsageRequest) GetPublisherId() string {
+	if x != nil {
+		return x.PublisherId
+	}
+	return ""
+}
+
+type PublisherUsageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Usage         *Usage                 `protobuf:"bytes,3,opt,name=usage,proto3" json:"usage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublisherUsageResponse) Reset() {
+	*x = PublisherUsageResponse{}
+	mi := &file_messagebroker_proto_msgTypes[100]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublisherUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublisherUsageResponse) ProtoMessage() {}
+
+func (x *PublisherUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messagebroker_proto_msgTypes[100]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublisherUsageResponse.ProtoReflect.Descriptor instead.
+func (*PublisherUsageResponse) Descriptor() ([]byte, []int) {
+	return file_messagebroker_proto_rawDescGZIP(), []int{100}
+}
+
+func (x *PublisherUsageResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *PublisherUsageResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *PublisherUsageResponse) GetUsage() *Usage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+var File_messagebroker_proto protoreflect.FileDescriptor
+
+const file_messagebroker_proto_rawDesc = "" +
+	"\n" +
+	"\x13messagebroker.proto\x12\x10messagebroker.v1\"E\n" +
+	"\x0fMessageResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"%\n" +
+	"\x0fRegisterRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\":\n" +
+	"\x10RegisteredClient\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\"|\n" +
+	"\x10RegisterResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x124\n" +
+	"\x03row\x18\x03 \x01(\v2\".messagebroker.v1.RegisteredClientR\x03row\"=\n" +
+	"\x13AuthenticateRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\"\x1f\n" +
+	"\x1dGetAuthenticatedClientRequest\"U\n" +
+	"\x06Client\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12'\n" +
+	"\x0forganization_id\x18\x03 \x01(\tR\x0eorganizationId\"x\n" +
+	"\x14AuthenticateResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12,\n" +
+	"\x04data\x18\x03 \x01(\v2\x18.messagebroker.v1.ClientR\x04data\"J\n" +
+	"\fOrganization\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06admins\x18\x03 \x03(\tR\x06admins\"N\n" +
+	"\x12OrganizationMember\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05admin\x18\x03 \x01(\bR\x05admin\"N\n" +
+	"\x19CreateOrganizationRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"admin_name\x18\x02 \x01(\tR\tadminName\"\xca\x01\n" +
+	"\x1aCreateOrganizationResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12B\n" +
+	"\forganization\x18\x03 \x01(\v2\x1e.messagebroker.v1.OrganizationR\forganization\x124\n" +
+	"\x03row\x18\x04 \x01(\v2\".messagebroker.v1.RegisteredClientR\x03row\"\x18\n" +
+	"\x16GetOrganizationRequest\"\xce\x01\n" +
+	"\x14OrganizationResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12B\n" +
+	"\forganization\x18\x03 \x01(\v2\x1e.messagebroker.v1.OrganizationR\forganization\x12>\n" +
+	"\amembers\x18\x04 \x03(\v2$.messagebroker.v1.OrganizationMemberR\amembers\"2\n" +
+	"\x1cAddOrganizationMemberRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"7\n" +
+	"\x18OrganizationAdminRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\"\x15\n" +
+	"\x13RotateSecretRequest\"6\n" +
+	"\fClientSecret\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\"~\n" +
+	"\x14RotateSecretResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x122\n" +
+	"\x04data\x18\x03 \x01(\v2\x1e.messagebroker.v1.ClientSecretR\x04data\"7\n" +
+	"\x18ResetClientSecretRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\":\n" +
+	"\x13RefreshTokenRequest\x12#\n" +
+	"\rrefresh_token\x18\x01 \x01(\tR\frefreshToken\"\xbc\x01\n" +
+	"\x06Tokens\x12!\n" +
+	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\x12\x1d\n" +
+	"\n" +
+	"token_type\x18\x03 \x01(\tR\ttokenType\x12\x1d\n" +
+	"\n" +
+	"expires_in\x18\x04 \x01(\x03R\texpiresIn\x12,\n" +
+	"\x12refresh_expires_in\x18\x05 \x01(\x03R\x10refreshExpiresIn\"q\n" +
+	"\rTokenResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12,\n" +
+	"\x04data\x18\x03 \x01(\v2\x18.messagebroker.v1.TokensR\x04data\"\x94\x01\n" +
+	"\x06APIKey\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06scopes\x18\x03 \x03(\tR\x06scopes\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\tR\texpiresAt\x12\x10\n" +
+	"\x03key\x18\x06 \x01(\tR\x03key\"\x14\n" +
+	"\x12ListAPIKeysRequest\"w\n" +
+	"\x13ListAPIKeysResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12,\n" +
+	"\x04keys\x18\x03 \x03(\v2\x18.messagebroker.v1.APIKeyR\x04keys\"`\n" +
+	"\x13CreateAPIKeyRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06scopes\x18\x02 \x03(\tR\x06scopes\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\tR\texpiresAt\"v\n" +
+	"\x14CreateAPIKeyResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12*\n" +
+	"\x03key\x18\x03 \x01(\v2\x18.messagebroker.v1.APIKeyR\x03key\",\n" +
+	"\x13RevokeAPIKeyRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\"\x99\x01\n" +
+	"\tPublisher\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x19\n" +
+	"\bowner_id\x18\x03 \x01(\tR\aownerId\x12!\n" +
+	"\fcontent_type\x18\x04 \x01(\tR\vcontentType\x12*\n" +
+	"\x11max_payload_bytes\x18\x05 \x01(\x05R\x0fmaxPayloadBytes\";\n" +
+	"\x15ListPublishersRequest\x12\"\n" +
+	"\forganization\x18\x01 \x01(\bR\forganization\"\x89\x01\n" +
+	"\x16ListPublishersResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12;\n" +
+	"\n" +
+	"publishers\x18\x03 \x03(\v2\x1b.messagebroker.v1.PublisherR\n" +
+	"publishers\"{\n" +
+	"\x16CreatePublisherRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12*\n" +
+	"\x11max_payload_bytes\x18\x03 \x01(\x05R\x0fmaxPayloadBytes\"|\n" +
+	"\x17CreatePublisherResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12-\n" +
+	"\x03row\x18\x03 \x01(\v2\x1b.messagebroker.v1.PublisherR\x03row\";\n" +
+	"\x16DeletePublisherRequest\x12!\n" +
+	"\fpublisher_id\x18\x01 \x01(\tR\vpublisherId\"D\n" +
+	"\x1fListPublisherSubscribersRequest\x12!\n" +
+	"\fpublisher_id\x18\x01 \x01(\tR\vpublisherId\"\x92\x01\n" +
+	" ListPublisherSubscribersResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12:\n" +
+	"\vsubscribers\x18\x03 \x03(\v2\x18.messagebroker.v1.ClientR\vsubscribers\"Y\n" +
+	"\x17RemoveSubscriberRequest\x12!\n" +
+	"\fpublisher_id\x18\x01 \x01(\tR\vpublisherId\x12\x1b\n" +
+	"\tclient_id\x18\x02 \x01(\tR\bclientId\"e\n" +
+	"(ListPublisherSubscriptionRequestsRequest\x12!\n" +
+	"\fpublisher_id\x18\x01 \x01(\tR\vpublisherId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"O\n" +
+	"\rPayloadPolicy\x12\x1b\n" +
+	"\tmax_bytes\x18\x01 \x01(\x05R\bmaxBytes\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\"?\n" +
+	"\x1aGetPublisherPayloadRequest\x12!\n" +
+	"\fpublisher_id\x18\x01 \x01(\tR\vpublisherId\"\x7f\n" +
+	"\x1aSetPublisherPayloadRequest\x12!\n" +
+	"\fpublisher_id\x18\x01 \x01(\tR\vpublisherId\x12\x1b\n" +
+	"\tmax_bytes\x18\x02 \x01(\x05R\bmaxBytes\x12!\n" +
+	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\"\xb5\x01\n" +
+	"\x18PublisherPayloadResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x129\n" +
+	"\apayload\x18\x03 \x01(\v2\x1f.messagebroker.v1.PayloadPolicyR\apayload\x12*\n" +
+	"\x11max_payload_bytes\x18\x04 \x01(\x05R\x0fmaxPayloadBytes\"\x7f\n" +
+	"\x06Schema\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x05R\aversion\x12\x16\n" +
+	"\x06schema\x18\x02 \x01(\tR\x06schema\x12$\n" +
+	"\rcompatibility\x18\x03 \x01(\tR\rcompatibility\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\"7\n" +
+	"\x12ListSchemasRequest\x12!\n" +
+	"\fpublisher_id\x18\x01 \x01(\tR\vpublisherId\"}\n" +
+	"\x13ListSchemasResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x122\n" +
+	"\aschemas\x18\x03 \x03(\v2\x18.messagebroker.v1.SchemaR\aschemas\"x\n" +
+	"\x15RegisterSchemaRequest\x12!\n" +
+	"\fpublisher_id\x18\x01 \x01(\tR\vpublisherId\x12\x16\n" +
+	"\x06schema\x18\x02 \x01(\tR\x06schema\x12$\n" +
+	"\rcompatibility\x18\x03 \x01(\tR\rcompatibility\"O\n" +
+	"\x10GetSchemaRequest\x12!\n" +
+	"\fpublisher_id\x18\x01 \x01(\tR\vpublisherId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\"v\n" +
+	"\x0eSchemaResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x120\n" +
+	"\x06schema\x18\x03 \x01(\v2\x18.messagebroker.v1.SchemaR\x06schema\"\xa2\x01\n" +
+	"\fPublisherACL\x12\x1e\n" +
+	"\n" +
+	"visibility\x18\x01 \x01(\tR\n" +
+	"visibility\x12\x18\n" +
+	"\aclients\x18\x02 \x03(\tR\aclients\x12\x16\n" +
+	"\x06groups\x18\x03 \x03(\tR\x06groups\x12\x1a\n" +
+	"\bapproved\x18\x04 \x03(\tR\bapproved\x12$\n" +
+	"\rorganizations\x18\x05 \x03(\tR\rorganizations\";\n" +
+	"\x16GetPublisherACLRequest\x12!\n" +
+	"\fpublisher_id\x18\x01 \x01(\tR\vpublisherId\"\xb3\x01\n" +
+	"\x16SetPublisherACLRequest\x12!\n" +
+	"\fpublisher_id\x18\x01 \x01(\tR\vpublisherId\x12\x1e\n" +
+	"\n" +
+	"visibility\x18\x02 \x01(\tR\n" +
+	"visibility\x12\x18\n" +
+	"\aclients\x18\x03 \x03(\tR\aclients\x12\x16\n" +
+	"\x06groups\x18\x04 \x03(\tR\x06groups\x12$\n" +
+	"\rorganizations\x18\x05 \x03(\tR\rorganizations\"T\n" +
+	"\x12AllowClientRequest\x12!\n" +
+	"\fpublisher_id\x18\x01 \x01(\tR\vpublisherId\x12\x1b\n" +
+	"\tclient_id\x18\x02 \x01(\tR\bclientId\"Q\n" +
+	"\x11AllowGroupRequest\x12!\n" +
+	"\fpublisher_id\x18\x01 \x01(\tR\vpublisherId\x12\x19\n" +
+	"\bgroup_id\x18\x02 \x01(\tR\agroupId\"\x96\x01\n" +
+	"\x14PublisherACLResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x120\n" +
+	"\x03acl\x18\x03 \x01(\v2\x1e.messagebroker.v1.PublisherACLR\x03acl\x12\x18\n" +
+	"\arevoked\x18\x04 \x01(\x05R\arevoked\"E\n" +
+	"\x05Group\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\amembers\x18\x03 \x03(\tR\amembers\"\x13\n" +
+	"\x11ListGroupsRequest\"y\n" +
+	"\x12ListGroupsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12/\n" +
+	"\x06groups\x18\x03 \x03(\v2\x17.messagebroker.v1.GroupR\x06groups\"B\n" +
+	"\x12CreateGroupRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\amembers\x18\x02 \x03(\tR\amembers\"M\n" +
+	"\x16SetGroupMembersRequest\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\tR\agroupId\x12\x18\n" +
+	"\amembers\x18\x02 \x03(\tR\amembers\"/\n" +
+	"\x12DeleteGroupRequest\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\tR\agroupId\"\x8c\x01\n" +
+	"\rGroupResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12-\n" +
+	"\x05group\x18\x03 \x01(\v2\x17.messagebroker.v1.GroupR\x05group\x12\x18\n" +
+	"\arevoked\x18\x04 \x01(\x05R\arevoked\"f\n" +
+	"\x15PublishMessageRequest\x12!\n" +
+	"\fpublisher_id\x18\x01 \x01(\tR\vpublisherId\x12\x10\n" +
+	"\x03ttl\x18\x02 \x01(\x03R\x03ttl\x12\x18\n" +
+	"\apayload\x18\x03 \x01(\tR\apayload\"V\n" +
+	"\x15SubscriptionPublisher\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x19\n" +
+	"\bowner_id\x18\x03 \x01(\tR\aownerId\"\x83\x02\n" +
+	"\rWebhookStatus\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x121\n" +
+	"\x14consecutive_failures\x18\x03 \x01(\x05R\x13consecutiveFailures\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x04 \x01(\tR\tlastError\x12&\n" +
+	"\x0flast_success_at\x18\x05 \x01(\tR\rlastSuccessAt\x12&\n" +
+	"\x0flast_failure_at\x18\x06 \x01(\tR\rlastFailureAt\x12&\n" +
+	"\x0fnext_attempt_at\x18\a \x01(\tR\rnextAttemptAt\"\xb4\x01\n" +
+	"\fSubscription\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12E\n" +
+	"\tpublisher\x18\x02 \x01(\v2'.messagebroker.v1.SubscriptionPublisherR\tpublisher\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x129\n" +
+	"\awebhook\x18\x04 \x01(\v2\x1f.messagebroker.v1.WebhookStatusR\awebhook\"\x1a\n" +
+	"\x18ListSubscriptionsRequest\"\x95\x01\n" +
+	"\x19ListSubscriptionsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12D\n" +
+	"\rsubscriptions\x18\x03 \x03(\v2\x1e.messagebroker.v1.SubscriptionR\rsubscriptions\"s\n" +
+	"\x10SubscribeRequest\x12!\n" +
+	"\fpublisher_id\x18\x01 \x01(\tR\vpublisherId\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12\x16\n" +
+	"\x06secret\x18\x04 \x01(\tR\x06secret\"=\n" +
+	"\x12UnsubscribeRequest\x12'\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\tR\x0esubscriptionId\"\xc6\x02\n" +
+	"\x13SubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tclient_id\x18\x02 \x01(\tR\bclientId\x12E\n" +
+	"\tpublisher\x18\x03 \x01(\v2'.messagebroker.v1.SubscriptionPublisherR\tpublisher\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x12\x12\n" +
+	"\x04type\x18\x05 \x01(\tR\x04type\x12\x1f\n" +
+	"\vwebhook_url\x18\x06 \x01(\tR\n" +
+	"webhookUrl\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12\x16\n" +
+	"\x06reason\x18\b \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"decided_at\x18\n" +
+	" \x01(\tR\tdecidedAt\"9\n" +
+	"\x1fListSubscriptionRequestsRequest\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\"\x99\x01\n" +
+	" ListSubscriptionRequestsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12A\n" +
+	"\brequests\x18\x03 \x03(\v2%.messagebroker.v1.SubscriptionRequestR\brequests\"\x9d\x01\n" +
+	" CreateSubscriptionRequestRequest\x12!\n" +
+	"\fpublisher_id\x18\x01 \x01(\tR\vpublisherId\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x10\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\x12\x16\n" +
+	"\x06secret\x18\x05 \x01(\tR\x06secret\"Y\n" +
+	" DecideSubscriptionRequestRequest\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\x92\x01\n" +
+	"\x1bSubscriptionRequestResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12?\n" +
+	"\arequest\x18\x03 \x01(\v2%.messagebroker.v1.SubscriptionRequestR\arequest\"\xfd\x01\n" +
+	"\rStreamRequest\x12J\n" +
+	"\fauthenticate\x18\x01 \x01(\v2$.messagebroker.v1.StreamAuthenticateH\x00R\fauthenticate\x12N\n" +
+	"\x10confirm_messages\x18\x02 \x01(\v2!.messagebroker.v1.ConfirmMessagesH\x00R\x0fconfirmMessages\x12E\n" +
+	"\rlist_sessions\x18\x03 \x01(\v2\x1e.messagebroker.v1.ListSessionsH\x00R\flistSessionsB\t\n" +
+	"\arequest\"\xae\x01\n" +
+	"\x12StreamAuthenticate\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06secret\x18\x04 \x01(\tR\x06secret\x12\x17\n" +
+	"\aapi_key\x18\x05 \x01(\tR\x06apiKey\x12\x14\n" +
+	"\x05token\x18\x06 \x01(\tR\x05token\x12\x1a\n" +
+	"\bdelivery\x18\x02 \x01(\tR\bdelivery\x12%\n" +
+	"\x0esingle_session\x18\x03 \x01(\bR\rsingleSession\"I\n" +
+	"\x0eConfirmMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0fsubscription_id\x18\x02 \x01(\tR\x0esubscriptionId\"O\n" +
+	"\x0fConfirmMessages\x12<\n" +
+	"\bmessages\x18\x01 \x03(\v2 .messagebroker.v1.ConfirmMessageR\bmessages\"\x0e\n" +
+	"\fListSessions\"\xb9\x03\n" +
+	"\x0eStreamResponse\x12P\n" +
+	"\x0eauthentication\x18\x01 \x01(\v2&.messagebroker.v1.AuthenticationResultH\x00R\x0eauthentication\x12K\n" +
+	"\x0fsession_started\x18\x02 \x01(\v2 .messagebroker.v1.SessionStartedH\x00R\x0esessionStarted\x128\n" +
+	"\bmessages\x18\x03 \x01(\v2\x1a.messagebroker.v1.MessagesH\x00R\bmessages\x12T\n" +
+	"\x12messages_confirmed\x18\x04 \x01(\v2#.messagebroker.v1.MessagesConfirmedH\x00R\x11messagesConfirmed\x128\n" +
+	"\bsessions\x18\x05 \x01(\v2\x1a.messagebroker.v1.SessionsH\x00R\bsessions\x122\n" +
+	"\x06notice\x18\x06 \x01(\v2\x18.messagebroker.v1.NoticeH\x00R\x06noticeB\n" +
+	"\n" +
+	"\bresponse\"|\n" +
+	"\x14AuthenticationResult\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x120\n" +
+	"\x06client\x18\x03 \x01(\v2\x18.messagebroker.v1.ClientR\x06client\"K\n" +
+	"\x0eSessionStarted\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1a\n" +
+	"\bdelivery\x18\x02 \x01(\tR\bdelivery\"\xa6\x01\n" +
+	"\aMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\fpublisher_id\x18\x02 \x01(\tR\vpublisherId\x12'\n" +
+	"\x0fsubscription_id\x18\x03 \x01(\tR\x0esubscriptionId\x12\x18\n" +
+	"\apayload\x18\x04 \x01(\tR\apayload\x12%\n" +
+	"\x0eschema_version\x18\x05 \x01(\x05R\rschemaVersion\"A\n" +
+	"\bMessages\x125\n" +
+	"\bmessages\x18\x01 \x03(\v2\x19.messagebroker.v1.MessageR\bmessages\"1\n" +
+	"\x11MessagesConfirmed\x12\x1c\n" +
+	"\tconfirmed\x18\x01 \x01(\x05R\tconfirmed\"}\n" +
+	"\aSession\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12%\n" +
+	"\x0eremote_address\x18\x02 \x01(\tR\rremoteAddress\x12!\n" +
+	"\fconnected_at\x18\x03 \x01(\x03R\vconnectedAt\x12\x18\n" +
+	"\acurrent\x18\x04 \x01(\bR\acurrent\"A\n" +
+	"\bSessions\x125\n" +
+	"\bsessions\x18\x01 \x03(\v2\x19.messagebroker.v1.SessionR\bsessions\"\xab\x01\n" +
+	"\x06Notice\x12\x16\n" +
+	"\x06action\x18\x01 \x01(\tR\x06action\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x126\n" +
+	"\x04data\x18\x03 \x03(\v2\".messagebroker.v1.Notice.DataEntryR\x04data\x1a7\n" +
+	"\tDataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xd8\x01\n" +
+	"\n" +
+	"RateLimits\x12,\n" +
+	"\x12publish_per_minute\x18\x01 \x01(\x05R\x10publishPerMinute\x12#\n" +
+	"\rpublish_burst\x18\x02 \x01(\x05R\fpublishBurst\x12.\n" +
+	"\x13requests_per_minute\x18\x03 \x01(\x05R\x11requestsPerMinute\x12%\n" +
+	"\x0erequests_burst\x18\x04 \x01(\x05R\rrequestsBurst\x12 \n" +
+	"\vconnections\x18\x05 \x01(\x05R\vconnections\"\x16\n" +
+	"\x14GetRateLimitsRequest\"6\n" +
+	"\x17ClientRateLimitsRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\"\x85\x02\n" +
+	"\x1aSetClientRateLimitsRequest\x12\x1b\n" +
+	"\tclient_id\x18\x01 \x01(\tR\bclientId\x12,\n" +
+	"\x12publish_per_minute\x18\x02 \x01(\x05R\x10publishPerMinute\x12#\n" +
+	"\rpublish_burst\x18\x03 \x01(\x05R\fpublishBurst\x12.\n" +
+	"\x13requests_per_minute\x18\x04 \x01(\x05R\x11requestsPerMinute\x12%\n" +
+	"\x0erequests_burst\x18\x05 \x01(\x05R\rrequestsBurst\x12 \n" +
+	"\vconnections\x18\x06 \x01(\x05R\vconnections\"\xb8\x01\n" +
+	"\x12RateLimitsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x124\n" +
+	"\x06limits\x18\x03 \x01(\v2\x1c.messagebroker.v1.RateLimitsR\x06limits\x128\n" +
+	"\boverride\x18\x04 \x01(\v2\x1c.messagebroker.v1.RateLimitsR\boverride\"y\n" +
+	"\x05Usage\x12\x1a\n" +
+	"\bmessages\x18\x01 \x01(\x03R\bmessages\x12\x14\n" +
+	"\x05bytes\x18\x02 \x01(\x03R\x05bytes\x12!\n" +
+	"\fmax_messages\x18\x03 \x01(\x03R\vmaxMessages\x12\x1b\n" +
+	"\tmax_bytes\x18\x04 \x01(\x03R\bmaxBytes\"c\n" +
+	"\x0ePublisherUsage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12-\n" +
+	"\x05usage\x18\x03 \x01(\v2\x17.messagebroker.v1.UsageR\x05usage\"\x11\n" +
+	"\x0fGetUsageRequest\"\xb4\x01\n" +
+	"\rUsageResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12-\n" +
+	"\x05usage\x18\x03 \x01(\v2\x17.messagebroker.v1.UsageR\x05usage\x12@\n" +
+	"\n" +
+	"publishers\x18\x04 \x03(\v2 .messagebroker.v1.PublisherUsageR\n" +
+	"publishers\"=\n" +
+	"\x18GetPublisherUsageRequest\x12!\n" +
+	"\fpublisher_id\x18\x01 \x01(\tR\vpublisherId\"{\n" +
+	"\x16PublisherUsageResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12-\n" +
+	"\x05usage\x18\x03 \x01(\v2\x17.messagebroker.v1.UsageR\x05usage2\xf4(\n" +
+	"\n" +
+	"Management\x12Q\n" +
+	"\bRegister\x12!.messagebroker.v1.RegisterRequest\x1a\".messagebroker.v1.RegisterResponse\x12]\n" +
+	"\fAuthenticate\x12%.messagebroker.v1.AuthenticateRequest\x1a&.messagebroker.v1.AuthenticateResponse\x12q\n" +
+	"\x16GetAuthenticatedClient\x12/.messagebroker.v1.GetAuthenticatedClientRequest\x1a&.messagebroker.v1.AuthenticateResponse\x12]\n" +
+	"\fRotateSecret\x12%.messagebroker.v1.RotateSecretRequest\x1a&.messagebroker.v1.RotateSecretResponse\x12T\n" +
+	"\n" +
+	"IssueToken\x12%.messagebroker.v1.AuthenticateRequest\x1a\x1f.messagebroker.v1.TokenResponse\x12V\n" +
+	"\fRefreshToken\x12%.messagebroker.v1.RefreshTokenRequest\x1a\x1f.messagebroker.v1.TokenResponse\x12o\n" +
+	"\x12CreateOrganization\x12+.messagebroker.v1.CreateOrganizationRequest\x1a,.messagebroker.v1.CreateOrganizationResponse\x12c\n" +
+	"\x0fGetOrganization\x12(.messagebroker.v1.GetOrganizationRequest\x1a&.messagebroker.v1.OrganizationResponse\x12k\n" +
+	"\x15AddOrganizationMember\x12..messagebroker.v1.AddOrganizationMemberRequest\x1a\".messagebroker.v1.RegisterResponse\x12j\n" +
+	"\x14AddOrganizationAdmin\x12*.messagebroker.v1.OrganizationAdminRequest\x1a&.messagebroker.v1.OrganizationResponse\x12m\n" +
+	"\x17RemoveOrganizationAdmin\x12*.messagebroker.v1.OrganizationAdminRequest\x1a&.messagebroker.v1.OrganizationResponse\x12Z\n" +
+	"\vListAPIKeys\x12$.messagebroker.v1.ListAPIKeysRequest\x1a%.messagebroker.v1.ListAPIKeysResponse\x12]\n" +
+	"\fCreateAPIKey\x12%.messagebroker.v1.CreateAPIKeyRequest\x1a&.messagebroker.v1.CreateAPIKeyResponse\x12X\n" +
+	"\fRevokeAPIKey\x12%.messagebroker.v1.RevokeAPIKeyRequest\x1a!.messagebroker.v1.MessageResponse\x12c\n" +
+	"\x0eListPublishers\x12'.messagebroker.v1.ListPublishersRequest\x1a(.messagebroker.v1.ListPublishersResponse\x12f\n" +
+	"\x0fCreatePublisher\x12(.messagebroker.v1.CreatePublisherRequest\x1a).messagebroker.v1.CreatePublisherResponse\x12^\n" +
+	"\x0fDeletePublisher\x12(.messagebroker.v1.DeletePublisherRequest\x1a!.messagebroker.v1.MessageResponse\x12\x81\x01\n" +
+	"\x18ListPublisherSubscribers\x121.messagebroker.v1.ListPublisherSubscribersRequest\x1a2.messagebroker.v1.ListPublisherSubscribersResponse\x12`\n" +
+	"\x10RemoveSubscriber\x12).messagebroker.v1.RemoveSubscriberRequest\x1a!.messagebroker.v1.MessageResponse\x12\x93\x01\n" +
+	"!ListPublisherSubscriptionRequests\x12:.messagebroker.v1.ListPublisherSubscriptionRequestsRequest\x1a2.messagebroker.v1.ListSubscriptionRequestsResponse\x12o\n" +
+	"\x13GetPublisherPayload\x12,.messagebroker.v1.GetPublisherPayloadRequest\x1a*.messagebroker.v1.PublisherPayloadResponse\x12o\n" +
+	"\x13SetPublisherPayload\x12,.messagebroker.v1.SetPublisherPayloadRequest\x1a*.messagebroker.v1.PublisherPayloadResponse\x12Z\n" +
+	"\vListSchemas\x12$.messagebroker.v1.ListSchemasRequest\x1a%.messagebroker.v1.ListSchemasResponse\x12[\n" +
+	"\x0eRegisterSchema\x12'.messagebroker.v1.RegisterSchemaRequest\x1a .messagebroker.v1.SchemaResponse\x12Q\n" +
+	"\tGetSchema\x12\".messagebroker.v1.GetSchemaRequest\x1a .messagebroker.v1.SchemaResponse\x12c\n" +
+	"\x0fGetPublisherACL\x12(.messagebroker.v1.GetPublisherACLRequest\x1a&.messagebroker.v1.PublisherACLResponse\x12c\n" +
+	"\x0fSetPublisherACL\x12(.messagebroker.v1.SetPublisherACLRequest\x1a&.messagebroker.v1.PublisherACLResponse\x12[\n" +
+	"\vAllowClient\x12$.messagebroker.v1.AllowClientRequest\x1a&.messagebroker.v1.PublisherACLResponse\x12^\n" +
+	"\x0eDisallowClient\x12$.messagebroker.v1.AllowClientRequest\x1a&.messagebroker.v1.PublisherACLResponse\x12Y\n" +
+	"\n" +
+	"AllowGroup\x12#.messagebroker.v1.AllowGroupRequest\x1a&.messagebroker.v1.PublisherACLResponse\x12\\\n" +
+	"\rDisallowGroup\x12#.messagebroker.v1.AllowGroupRequest\x1a&.messagebroker.v1.PublisherACLResponse\x12W\n" +
+	"\n" +
+	"ListGroups\x12#.messagebroker.v1.ListGroupsRequest\x1a$.messagebroker.v1.ListGroupsResponse\x12T\n" +
+	"\vCreateGroup\x12$.messagebroker.v1.CreateGroupRequest\x1a\x1f.messagebroker.v1.GroupResponse\x12\\\n" +
+	"\x0fSetGroupMembers\x12(.messagebroker.v1.SetGroupMembersRequest\x1a\x1f.messagebroker.v1.GroupResponse\x12V\n" +
+	"\vDeleteGroup\x12$.messagebroker.v1.DeleteGroupRequest\x1a!.messagebroker.v1.MessageResponse\x12\\\n" +
+	"\x0ePublishMessage\x12'.messagebroker.v1.PublishMessageRequest\x1a!.messagebroker.v1.MessageResponse\x12l\n" +
+	"\x11ListSubscriptions\x12*.messagebroker.v1.ListSubscriptionsRequest\x1a+.messagebroker.v1.ListSubscriptionsResponse\x12R\n" +
+	"\tSubscribe\x12\".messagebroker.v1.SubscribeRequest\x1a!.messagebroker.v1.MessageResponse\x12V\n" +
+	"\vUnsubscribe\x12$.messagebroker.v1.UnsubscribeRequest\x1a!.messagebroker.v1.MessageResponse\x12\x81\x01\n" +
+	"\x18ListSubscriptionRequests\x121.messagebroker.v1.ListSubscriptionRequestsRequest\x1a2.messagebroker.v1.ListSubscriptionRequestsResponse\x12~\n" +
+	"\x19CreateSubscriptionRequest\x122.messagebroker.v1.CreateSubscriptionRequestRequest\x1a-.messagebroker.v1.SubscriptionRequestResponse\x12\x89\x01\n" +
+	" ListIncomingSubscriptionRequests\x121.messagebroker.v1.ListSubscriptionRequestsRequest\x1a2.messagebroker.v1.ListSubscriptionRequestsResponse\x12\x7f\n" +
+	"\x1aApproveSubscriptionRequest\x122.messagebroker.v1.DecideSubscriptionRequestRequest\x1a-.messagebroker.v1.SubscriptionRequestResponse\x12|\n" +
+	"\x17DenySubscriptionRequest\x122.messagebroker.v1.DecideSubscriptionRequestRequest\x1a-.messagebroker.v1.SubscriptionRequestResponse\x12]\n" +
+	"\rGetRateLimits\x12&.messagebroker.v1.GetRateLimitsRequest\x1a$.messagebroker.v1.RateLimitsResponse\x12f\n" +
+	"\x13GetClientRateLimits\x12).messagebroker.v1.ClientRateLimitsRequest\x1a$.messagebroker.v1.RateLimitsResponse\x12i\n" +
+	"\x13SetClientRateLimits\x12,.messagebroker.v1.SetClientRateLimitsRequest\x1a$.messagebroker.v1.RateLimitsResponse\x12h\n" +
+	"\x15ResetClientRateLimits\x12).messagebroker.v1.ClientRateLimitsRequest\x1a$.messagebroker.v1.RateLimitsResponse\x12g\n" +
+	"\x11ResetClientSecret\x12*.messagebroker.v1.ResetClientSecretRequest\x1a&.messagebroker.v1.RotateSecretResponse\x12N\n" +
+	"\bGetUsage\x12!.messagebroker.v1.GetUsageRequest\x1a\x1f.messagebroker.v1.UsageResponse\x12i\n" +
+	"\x11GetPublisherUsage\x12*.messagebroker.v1.GetPublisherUsageRequest\x1a(.messagebroker.v1.PublisherUsageResponse2Y\n" +
+	"\x06Broker\x12O\n" +
+	"\x06Stream\x12\x1f.messagebroker.v1.StreamRequest\x1a .messagebroker.v1.StreamResponse(\x010\x01B0Z.bezberr.com/messagebrokerapi/brokerpb;brokerpbb\x06proto3"
+
+var (
+	file_messagebroker_proto_rawDescOnce sync.Once
+	file_messagebroker_proto_rawDescData []byte
+)
+
+func file_messagebroker_proto_rawDescGZIP() []byte {
+	file_messagebroker_proto_rawDescOnce.Do(func() {
+		file_messagebroker_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_messagebroker_proto_rawDesc), len(file_messagebroker_proto_rawDesc)))
+	})
+	return file_messagebroker_proto_rawDescData
+}
+
+var file_messagebroker_proto_msgTypes = make([]protoimpl.MessageInfo, 102)
+var file_messagebroker_proto_goTypes = []any{
+	(*MessageResponse)(nil),                          // 0: messagebroker.v1.MessageResponse
+	(*RegisterRequest)(nil),                          // 1: messagebroker.v1.RegisterRequest
+	(*RegisteredClient)(nil),                         // 2: messagebroker.v1.RegisteredClient
+	(*RegisterResponse)(nil),                         // 3: messagebroker.v1.RegisterResponse
+	(*AuthenticateRequest)(nil),                      // 4: messagebroker.v1.AuthenticateRequest
+	(*GetAuthenticatedClientRequest)(nil),            // 5: messagebroker.v1.GetAuthenticatedClientRequest
+	(*Client)(nil),                                   // 6: messagebroker.v1.Client
+	(*AuthenticateResponse)(nil),                     // 7: messagebroker.v1.AuthenticateResponse
+	(*Organization)(nil),                             // 8: messagebroker.v1.Organization
+	(*OrganizationMember)(nil),                       // 9: messagebroker.v1.OrganizationMember
+	(*CreateOrganizationRequest)(nil),                // 10: messagebroker.v1.CreateOrganizationRequest
+	(*CreateOrganizationResponse)(nil),               // 11: messagebroker.v1.CreateOrganizationResponse
+	(*GetOrganizationRequest)(nil),                   // 12: messagebroker.v1.GetOrganizationRequest
+	(*OrganizationResponse)(nil),                     // 13: messagebroker.v1.OrganizationResponse
+	(*AddOrganizationMemberRequest)(nil),             // 14: messagebroker.v1.AddOrganizationMemberRequest
+	(*OrganizationAdminRequest)(nil),                 // 15: messagebroker.v1.OrganizationAdminRequest
+	(*RotateSecretRequest)(nil),                      // 16: messagebroker.v1.RotateSecretRequest
+	(*ClientSecret)(nil),                             // 17: messagebroker.v1.ClientSecret
+	(*RotateSecretResponse)(nil),                     // 18: messagebroker.v1.RotateSecretResponse
+	(*ResetClientSecretRequest)(nil),                 // 19: messagebroker.v1.ResetClientSecretRequest
+	(*RefreshTokenRequest)(nil),                      // 20: messagebroker.v1.RefreshTokenRequest
+	(*Tokens)(nil),                                   // 21: messagebroker.v1.Tokens
+	(*TokenResponse)(nil),                            // 22: messagebroker.v1.TokenResponse
+	(*APIKey)(nil),                                   // 23: messagebroker.v1.APIKey
+	(*ListAPIKeysRequest)(nil),                       // 24: messagebroker.v1.ListAPIKeysRequest
+	(*ListAPIKeysResponse)(nil),                      // 25: messagebroker.v1.ListAPIKeysResponse
+	(*CreateAPIKeyRequest)(nil),                      // 26: messagebroker.v1.CreateAPIKeyRequest
+	(*CreateAPIKeyResponse)(nil),                     // 27: messagebroker.v1.CreateAPIKeyResponse
+	(*RevokeAPIKeyRequest)(nil),                      // 28: messagebroker.v1.RevokeAPIKeyRequest
+	(*Publisher)(nil),                                // 29: messagebroker.v1.Publisher
+	(*ListPublishersRequest)(nil),                    // 30: messagebroker.v1.ListPublishersRequest
+	(*ListPublishersResponse)(nil),                   // 31: messagebroker.v1.ListPublishersResponse
+	(*CreatePublisherRequest)(nil),                   // 32: messagebroker.v1.CreatePublisherRequest
+	(*CreatePublisherResponse)(nil),                  // 33: messagebroker.v1.CreatePublisherResponse
+	(*DeletePublisherRequest)(nil),                   // 34: messagebroker.v1.DeletePublisherRequest
+	(*ListPublisherSubscribersRequest)(nil),          // 35: messagebroker.v1.ListPublisherSubscribersRequest
+	(*ListPublisherSubscribersResponse)(nil),         // 36: messagebroker.v1.ListPublisherSubscribersResponse
+	(*RemoveSubscriberRequest)(nil),                  // 37: messagebroker.v1.RemoveSubscriberRequest
+	(*ListPublisherSubscriptionRequestsRequest)(nil), // 38: messagebroker.v1.ListPublisherSubscriptionRequestsRequest
+	(*PayloadPolicy)(nil),                            // 39: messagebroker.v1.PayloadPolicy
+	(*GetPublisherPayloadRequest)(nil),               // 40: messagebroker.v1.GetPublisherPayloadRequest
+	(*SetPublisherPayloadRequest)(nil),               // 41: messagebroker.v1.SetPublisherPayloadRequest
+	(*PublisherPayloadResponse)(nil),                 // 42: messagebroker.v1.PublisherPayloadResponse
+	(*Schema)(nil),                                   // 43: messagebroker.v1.Schema
+	(*ListSchemasRequest)(nil),                       // 44: messagebroker.v1.ListSchemasRequest
+	(*ListSchemasResponse)(nil),                      // 45: messagebroker.v1.ListSchemasResponse
+	(*RegisterSchemaRequest)(nil),                    // 46: messagebroker.v1.RegisterSchemaRequest
+	(*GetSchemaRequest)(nil),                         // 47: messagebroker.v1.GetSchemaRequest
+	(*SchemaResponse)(nil),                           // 48: messagebroker.v1.SchemaResponse
+	(*PublisherACL)(nil),                             // 49: messagebroker.v1.PublisherACL
+	(*GetPublisherACLRequest)(nil),                   // 50: messagebroker.v1.GetPublisherACLRequest
+	(*SetPublisherACLRequest)(nil),                   // 51: messagebroker.v1.SetPublisherACLRequest
+	(*AllowClientRequest)(nil),                       // 52: messagebroker.v1.AllowClientRequest
+	(*AllowGroupRequest)(nil),                        // 53: messagebroker.v1.AllowGroupRequest
+	(*PublisherACLResponse)(nil),                     // 54: messagebroker.v1.PublisherACLResponse
+	(*Group)(nil),                                    // 55: messagebroker.v1.Group
+	(*ListGroupsRequest)(nil),                        // 56: messagebroker.v1.ListGroupsRequest
+	(*ListGroupsResponse)(nil),                       // 57: messagebroker.v1.ListGroupsResponse
+	(*CreateGroupRequest)(nil),                       // 58: messagebroker.v1.CreateGroupRequest
+	(*SetGroupMembersRequest)(nil),                   // 59: messagebroker.v1.SetGroupMembersRequest
+	(*DeleteGroupRequest)(nil),                       // 60: messagebroker.v1.DeleteGroupRequest
+	(*GroupResponse)(nil),                            // 61: messagebroker.v1.GroupResponse
+	(*PublishMessageRequest)(nil),                    // 62: messagebroker.v1.PublishMessageRequest
+	(*SubscriptionPublisher)(nil),                    // 63: messagebroker.v1.SubscriptionPublisher
+	(*WebhookStatus)(nil),                            // 64: messagebroker.v1.WebhookStatus
+	(*Subscription)(nil),                             // 65: messagebroker.v1.Subscription
+	(*ListSubscriptionsRequest)(nil),                 // 66: messagebroker.v1.ListSubscriptionsRequest
+	(*ListSubscriptionsResponse)(nil),                // 67: messagebroker.v1.ListSubscriptionsResponse
+	(*SubscribeRequest)(nil),                         // 68: messagebroker.v1.SubscribeRequest
+	(*UnsubscribeRequest)(nil),                       // 69: messagebroker.v1.UnsubscribeRequest
+	(*SubscriptionRequest)(nil),                      // 70: messagebroker.v1.SubscriptionRequest
+	(*ListSubscriptionRequestsRequest)(nil),          // 71: messagebroker.v1.ListSubscriptionRequestsRequest
+	(*ListSubscriptionRequestsResponse)(nil),         // 72: messagebroker.v1.ListSubscriptionRequestsResponse
+	(*CreateSubscriptionRequestRequest)(nil),         // 73: messagebroker.v1.CreateSubscriptionRequestRequest
+	(*DecideSubscriptionRequestRequest)(nil),         // 74: messagebroker.v1.DecideSubscriptionRequestRequest
+	(*SubscriptionRequestResponse)(nil),              // 75: messagebroker.v1.SubscriptionRequestResponse
+	(*StreamRequest)(nil),                            // 76: messagebroker.v1.StreamRequest
+	(*StreamAuthenticate)(nil),                       // 77: messagebroker.v1.StreamAuthenticate
+	(*ConfirmMessage)(nil),                           // 78: messagebroker.v1.ConfirmMessage
+	(*ConfirmMessages)(nil),                          // 79: messagebroker.v1.ConfirmMessages
+	(*ListSessions)(nil),                             // 80: messagebroker.v1.ListSessions
+	(*StreamResponse)(nil),                           // 81: messagebroker.v1.StreamResponse
+	(*AuthenticationResult)(nil),                     // 82: messagebroker.v1.AuthenticationResult
+	(*SessionStarted)(nil),                           // 83: messagebroker.v1.SessionStarted
+	(*Message)(nil),                                  // 84: messagebroker.v1.Message
+	(*Messages)(nil),                                 // 85: messagebroker.v1.Messages
+	(*MessagesConfirmed)(nil),                        // 86: messagebroker.v1.MessagesConfirmed
+	(*Session)(nil),                                  // 87: messagebroker.v1.Session
+	(*Sessions)(nil),                                 // 88: messagebroker.v1.Sessions
+	(*Notice)(nil),                                   // 89: messagebroker.v1.Notice
+	(*RateLimits)(nil),                               // 90: messagebroker.v1.RateLimits
+	(*GetRateLimitsRequest)(nil),                     // 91: messagebroker.v1.GetRateLimitsRequest
+	(*ClientRateLimitsRequest)(nil),                  // 92: messagebroker.v1.ClientRateLimitsRequest
+	(*SetClientRateLimitsRequest)(nil),               // 93: messagebroker.v1.SetClientRateLimitsRequest
+	(*RateLimitsResponse)(nil),                       // 94: messagebroker.v1.RateLimitsResponse
+	(*Usage)(nil),                                    // 95: messagebroker.v1.Usage
+	(*PublisherUsage)(nil),                           // 96: messagebroker.v1.PublisherUsage
+	(*GetUsageRequest)(nil),                          // 97: messagebroker.v1.GetUsageRequest
+	(*UsageResponse)(nil),                            // 98: messagebroker.v1.UsageResponse
+	(*GetPublisherUsageRequest)(nil),                 // 99: messagebroker.v1.GetPublisherUsageRequest
+	(*PublisherUsageResponse)(nil),                   // 100: messagebroker.v1.PublisherUsageResponse
+	nil,                                              // 101: messagebroker.v1.Notice.DataEntry
+}
+var file_messagebroker_proto_depIdxs = []int32{
+	2,   // 0: messagebroker.v1.RegisterResponse.row:type_name -> messagebroker.v1.RegisteredClient
+	6,   // 1: messagebroker.v1.AuthenticateResponse.data:type_name -> messagebroker.v1.Client
+	8,   // 2: messagebroker.v1.CreateOrganizationResponse.organization:type_name -> messagebroker.v1.Organization
+	2,   // 3: messagebroker.v1.CreateOrganizationResponse.row:type_name -> messagebroker.v1.RegisteredClient
+	8,   // 4: messagebroker.v1.OrganizationResponse.organization:type_name -> messagebroker.v1.Organization
+	9,   // 5: messagebroker.v1.OrganizationResponse.members:type_name -> messagebroker.v1.OrganizationMember
+	17,  // 6: messagebroker.v1.RotateSecretResponse.data:type_name -> messagebroker.v1.ClientSecret
+	21,  // 7: messagebroker.v1.TokenResponse.data:type_name -> messagebroker.v1.Tokens
+	23,  // 8: messagebroker.v1.ListAPIKeysResponse.keys:type_name -> messagebroker.v1.APIKey
+	23,  // 9: messagebroker.v1.CreateAPIKeyResponse.key:type_name -> messagebroker.v1.APIKey
+	29,  // 10: messagebroker.v1.ListPublishersResponse.publishers:type_name -> messagebroker.v1.Publisher
+	29,  // 11: messagebroker.v1.CreatePublisherResponse.row:type_name -> messagebroker.v1.Publisher
+	6,   // 12: messagebroker.v1.ListPublisherSubscribersResponse.subscribers:type_name -> messagebroker.v1.Client
+	39,  // 13: messagebroker.v1.PublisherPayloadResponse.payload:type_name -> messagebroker.v1.PayloadPolicy
+	43,  // 14: messagebroker.v1.ListSchemasResponse.schemas:type_name -> messagebroker.v1.Schema
+	43,  // 15: messagebroker.v1.SchemaResponse.schema:type_name -> messagebroker.v1.Schema
+	49,  // 16: messagebroker.v1.PublisherACLResponse.acl:type_name -> messagebroker.v1.PublisherACL
+	55,  // 17: messagebroker.v1.ListGroupsResponse.groups:type_name -> messagebroker.v1.Group
+	55,  // 18: messagebroker.v1.GroupResponse.group:type_name -> messagebroker.v1.Group
+	63,  // 19: messagebroker.v1.Subscription.publisher:type_name -> messagebroker.v1.SubscriptionPublisher
+	64,  // 20: messagebroker.v1.Subscription.webhook:type_name -> messagebroker.v1.WebhookStatus
+	65,  // 21: messagebroker.v1.ListSubscriptionsResponse.subscriptions:type_name -> messagebroker.v1.Subscription
+	63,  // 22: messagebroker.v1.SubscriptionRequest.publisher:type_name -> messagebroker.v1.SubscriptionPublisher
+	70,  // 23: messagebroker.v1.ListSubscriptionRequestsResponse.requests:type_name -> messagebroker.v1.SubscriptionRequest
+	70,  // 24: messagebroker.v1.SubscriptionRequestResponse.request:type_name -> messagebroker.v1.SubscriptionRequest
+	77,  // 25: messagebroker.v1.StreamRequest.authenticate:type_name -> messagebroker.v1.StreamAuthenticate
+	79,  // 26: messagebroker.v1.StreamRequest.confirm_messages:type_name -> messagebroker.v1.ConfirmMessages
+	80,  // 27: messagebroker.v1.StreamRequest.list_sessions:type_name -> messagebroker.v1.ListSessions
+	78,  // 28: messagebroker.v1.ConfirmMessages.messages:type_name -> messagebroker.v1.ConfirmMessage
+	82,  // 29: messagebroker.v1.StreamResponse.authentication:type_name -> messagebroker.v1.AuthenticationResult
+	83,  // 30: messagebroker.v1.StreamResponse.session_started:type_name -> messagebroker.v1.SessionStarted
+	85,  // 31: messagebroker.v1.StreamResponse.messages:type_name -> messagebroker.v1.Messages
+	86,  // 32: messagebroker.v1.StreamResponse.messages_confirmed:type_name -> messagebroker.v1.MessagesConfirmed
+	88,  // 33: messagebroker.v1.StreamResponse.sessions:type_name -> messagebroker.v1.Sessions
+	89,  // 34: messagebroker.v1.StreamResponse.notice:type_name -> messagebroker.v1.Notice
+	6,   // 35: messagebroker.v1.AuthenticationResult.client:type_name -> messagebroker.v1.Client
+	84,  // 36: messagebroker.v1.Messages.messages:type_name -> messagebroker.v1.Message
+	87,  // 37: messagebroker.v1.Sessions.sessions:type_name -> messagebroker.v1.Session
+	101, // 38: messagebroker.v1.Notice.data:type_name -> messagebroker.v1.Notice.DataEntry
+	90,  // 39: messagebroker.v1.RateLimitsResponse.limits:type_name -> messagebroker.v1.RateLimits
+	90,  // 40: messagebroker.v1.RateLimitsResponse.override:type_name -> messagebroker.v1.RateLimits
+	95,  // 41: messagebroker.v1.PublisherUsage.usage:type_name -> messagebroker.v1.Usage
+	95,  // 42: messagebroker.v1.UsageResponse.usage:type_name -> messagebroker.v1.Usage
+	96,  // 43: messagebroker.v1.UsageResponse.publishers:type_name -> messagebroker.v1.PublisherUsage
+	95,  // 44: messagebroker.v1.PublisherUsageResponse.usage:type_name -> messagebroker.v1.Usage
+	1,   // 45: messagebroker.v1.Management.Register:input_type -> messagebroker.v1.RegisterRequest
+	4,   // 46: messagebroker.v1.Management.Authenticate:input_type -> messagebroker.v1.AuthenticateRequest
+	5,   // 47: messagebroker.v1.Management.GetAuthenticatedClient:input_type -> messagebroker.v1.GetAuthenticatedClientRequest
+	16,  // 48: messagebroker.v1.Management.RotateSecret:input_type -> messagebroker.v1.RotateSecretRequest
+	4,   // 49: messagebroker.v1.Management.IssueToken:input_type -> messagebroker.v1.AuthenticateRequest
+	20,  // 50: messagebroker.v1.Management.RefreshToken:input_type -> messagebroker.v1.RefreshTokenRequest
+	10,  // 51: messagebroker.v1.Management.CreateOrganization:input_type -> messagebroker.v1.CreateOrganizationRequest
+	12,  // 52: messagebroker.v1.Management.GetOrganization:input_type -> messagebroker.v1.GetOrganizationRequest
+	14,  // 53: messagebroker.v1.Management.AddOrganizationMember:input_type -> messagebroker.v1.AddOrganizationMemberRequest
+	15,  // 54: messagebroker.v1.Management.AddOrganizationAdmin:input_type -> messagebroker.v1.OrganizationAdminRequest
+	15,  // 55: messagebroker.v1.Management.RemoveOrganizationAdmin:input_type -> messagebroker.v1.OrganizationAdminRequest
+	24,  // 56: messagebroker.v1.Management.ListAPIKeys:input_type -> messagebroker.v1.ListAPIKeysRequest
+	26,  // 57: messagebroker.v1.Management.CreateAPIKey:input_type -> messagebroker.v1.CreateAPIKeyRequest
+	28,  // 58: messagebroker.v1.Management.RevokeAPIKey:input_type -> messagebroker.v1.RevokeAPIKeyRequest
+	30,  // 59: messagebroker.v1.Management.ListPublishers:input_type -> messagebroker.v1.ListPublishersRequest
+	32,  // 60: messagebroker.v1.Management.CreatePublisher:input_type -> messagebroker.v1.CreatePublisherRequest
+	34,  // 61: messagebroker.v1.Management.DeletePublisher:input_type -> messagebroker.v1.DeletePublisherRequest
+	35,  // 62: messagebroker.v1.Management.ListPublisherSubscribers:input_type -> messagebroker.v1.ListPublisherSubscribersRequest
+	37,  // 63: messagebroker.v1.Management.RemoveSubscriber:input_type -> messagebroker.v1.RemoveSubscriberRequest
+	38,  // 64: messagebroker.v1.Management.ListPublisherSubscriptionRequests:input_type -> messagebroker.v1.ListPublisherSubscriptionRequestsRequest
+	40,  // 65: messagebroker.v1.Management.GetPublisherPayload:input_type -> messagebroker.v1.GetPublisherPayloadRequest
+	41,  // 66: messagebroker.v1.Management.SetPublisherPayload:input_type -> messagebroker.v1.SetPublisherPayloadRequest
+	44,  // 67: messagebroker.v1.Management.ListSchemas:input_type -> messagebroker.v1.ListSchemasRequest
+	46,  // 68: messagebroker.v1.Management.RegisterSchema:input_type -> messagebroker.v1.RegisterSchemaRequest
+	47,  // 69: messagebroker.v1.Management.GetSchema:input_type -> messagebroker.v1.GetSchemaRequest
+	50,  // 70: messagebroker.v1.Management.GetPublisherACL:input_type -> messagebroker.v1.GetPublisherACLRequest
+	51,  // 71: messagebroker.v1.Management.SetPublisherACL:input_type -> messagebroker.v1.SetPublisherACLRequest
+	52,  // 72: messagebroker.v1.Management.AllowClient:input_type -> messagebroker.v1.AllowClientRequest
+	52,  // 73: messagebroker.v1.Management.DisallowClient:input_type -> messagebroker.v1.AllowClientRequest
+	53,  // 74: messagebroker.v1.Management.AllowGroup:input_type -> messagebroker.v1.AllowGroupRequest
+	53,  // 75: messagebroker.v1.Management.DisallowGroup:input_type -> messagebroker.v1.AllowGroupRequest
+	56,  // 76: messagebroker.v1.Management.ListGroups:input_type -> messagebroker.v1.ListGroupsRequest
+	58,  // 77: messagebroker.v1.Management.CreateGroup:input_type -> messagebroker.v1.CreateGroupRequest
+	59,  // 78: messagebroker.v1.Management.SetGroupMembers:input_type -> messagebroker.v1.SetGroupMembersRequest
+	60,  // 79: messagebroker.v1.Management.DeleteGroup:input_type -> messagebroker.v1.DeleteGroupRequest
+	62,  // 80: messagebroker.v1.Management.PublishMessage:input_type -> messagebroker.v1.PublishMessageRequest
+	66,  // 81: messagebroker.v1.Management.ListSubscriptions:input_type -> messagebroker.v1.ListSubscriptionsRequest
+	68,  // 82: messagebroker.v1.Management.Subscribe:input_type -> messagebroker.v1.SubscribeRequest
+	69,  // 83: messagebroker.v1.Management.Unsubscribe:input_type -> messagebroker.v1.UnsubscribeRequest
+	71,  // 84: messagebroker.v1.Management.ListSubscriptionRequests:input_type -> messagebroker.v1.ListSubscriptionRequestsRequest
+	73,  // 85: messagebroker.v1.Management.CreateSubscriptionRequest:input_type -> messagebroker.v1.CreateSubscriptionRequestRequest
+	71,  // 86: messagebroker.v1.Management.ListIncomingSubscriptionRequests:input_type -> messagebroker.v1.ListSubscriptionRequestsRequest
+	74,  // 87: messagebroker.v1.Management.ApproveSubscriptionRequest:input_type -> messagebroker.v1.DecideSubscriptionRequestRequest
+	74,  // 88: messagebroker.v1.Management.DenySubscriptionRequest:input_type -> messagebroker.v1.DecideSubscriptionRequestRequest
+	91,  // 89: messagebroker.v1.Management.GetRateLimits:input_type -> messagebroker.v1.GetRateLimitsRequest
+	92,  // 90: messagebroker.v1.Management.GetClientRateLimits:input_type -> messagebroker.v1.ClientRateLimitsRequest
+	93,  // 91: messagebroker.v1.Management.SetClientRateLimits:input_type -> messagebroker.v1.SetClientRateLimitsRequest
+	92,  // 92: messagebroker.v1.Management.ResetClientRateLimits:input_type -> messagebroker.v1.ClientRateLimitsRequest
+	19,  // 93: messagebroker.v1.Management.ResetClientSecret:input_type -> messagebroker.v1.ResetClientSecretRequest
+	97,  // 94: messagebroker.v1.Management.GetUsage:input_type -> messagebroker.v1.GetUsageRequest
+	99,  // 95: messagebroker.v1.Management.GetPublisherUsage:input_type -> messagebroker.v1.GetPublisherUsageRequest
+	76,  // 96: messagebroker.v1.Broker.Stream:input_type -> messagebroker.v1.StreamRequest
+	3,   // 97: messagebroker.v1.Management.Register:output_type -> messagebroker.v1.RegisterResponse
+	7,   // 98: messagebroker.v1.Management.Authenticate:output_type -> messagebroker.v1.AuthenticateResponse
+	7,   // 99: messagebroker.v1.Management.GetAuthenticatedClient:output_type -> messagebroker.v1.AuthenticateResponse
+	18,  // 100: messagebroker.v1.Management.RotateSecret:output_type -> messagebroker.v1.RotateSecretResponse
+	22,  // 101: messagebroker.v1.Management.IssueToken:output_type -> messagebroker.v1.TokenResponse
+	22,  // 102: messagebroker.v1.Management.RefreshToken:output_type -> messagebroker.v1.TokenResponse
+	11,  // 103: messagebroker.v1.Management.CreateOrganization:output_type -> messagebroker.v1.CreateOrganizationResponse
+	13,  // 104: messagebroker.v1.Management.GetOrganization:output_type -> messagebroker.v1.OrganizationResponse
+	3,   // 105: messagebroker.v1.Management.AddOrganizationMember:output_type -> messagebroker.v1.RegisterResponse
+	13,  // 106: messagebroker.v1.Management.AddOrganizationAdmin:output_type -> messagebroker.v1.OrganizationResponse
+	13,  // 107: messagebroker.v1.Management.RemoveOrganizationAdmin:output_type -> messagebroker.v1.OrganizationResponse
+	25,  // 108: messagebroker.v1.Management.ListAPIKeys:output_type -> messagebroker.v1.ListAPIKeysResponse
+	27,  // 109: messagebroker.v1.Management.CreateAPIKey:output_type -> messagebroker.v1.CreateAPIKeyResponse
+	0,   // 110: messagebroker.v1.Management.RevokeAPIKey:output_type -> messagebroker.v1.MessageResponse
+	31,  // 111: messagebroker.v1.Management.ListPublishers:output_type -> messagebroker.v1.ListPublishersResponse
+	33,  // 112: messagebroker.v1.Management.CreatePublisher:output_type -> messagebroker.v1.CreatePublisherResponse
+	0,   // 113: messagebroker.v1.Management.DeletePublisher:output_type -> messagebroker.v1.MessageResponse
+	36,  // 114: messagebroker.v1.Management.ListPublisherSubscribers:output_type -> messagebroker.v1.ListPublisherSubscribersResponse
+	0,   // 115: messagebroker.v1.Management.RemoveSubscriber:output_type -> messagebroker.v1.MessageResponse
+	72,  // 116: messagebroker.v1.Management.ListPublisherSubscriptionRequests:output_type -> messagebroker.v1.ListSubscriptionRequestsResponse
+	42,  // 117: messagebroker.v1.Management.GetPublisherPayload:output_type -> messagebroker.v1.PublisherPayloadResponse
+	42,  // 118: messagebroker.v1.Management.SetPublisherPayload:output_type -> messagebroker.v1.PublisherPayloadResponse
+	45,  // 119: messagebroker.v1.Management.ListSchemas:output_type -> messagebroker.v1.ListSchemasResponse
+	48,  // 120: messagebroker.v1.Management.RegisterSchema:output_type -> messagebroker.v1.SchemaResponse
+	48,  // 121: messagebroker.v1.Management.GetSchema:output_type -> messagebroker.v1.SchemaResponse
+	54,  // 122: messagebroker.v1.Management.GetPublisherACL:output_type -> messagebroker.v1.PublisherACLResponse
+	54,  // 123: messagebroker.v1.Management.SetPublisherACL:output_type -> messagebroker.v1.PublisherACLResponse
+	54,  // 124: messagebroker.v1.Management.AllowClient:output_type -> messagebroker.v1.PublisherACLResponse
+	54,  // 125: messagebroker.v1.Management.DisallowClient:output_type -> messagebroker.v1.PublisherACLResponse
+	54,  // 126: messagebroker.v1.Management.AllowGroup:output_type -> messagebroker.v1.PublisherACLResponse
+	54,  // 127: messagebroker.v1.Management.DisallowGroup:output_type -> messagebroker.v1.PublisherACLResponse
+	57,  // 128: messagebroker.v1.Management.ListGroups:output_type -> messagebroker.v1.ListGroupsResponse
+	61,  // 129: messagebroker.v1.Management.CreateGroup:output_type -> messagebroker.v1.GroupResponse
+	61,  // 130: messagebroker.v1.Management.SetGroupMembers:output_type -> messagebroker.v1.GroupResponse
+	0,   // 131: messagebroker.v1.Management.DeleteGroup:output_type -> messagebroker.v1.MessageResponse
+	0,   // 132: messagebroker.v1.Management.PublishMessage:output_type -> messagebroker.v1.MessageResponse
+	67,  // 133: messagebroker.v1.Management.ListSubscriptions:output_type -> messagebroker.v1.ListSubscriptionsResponse
+	0,   // 134: messagebroker.v1.Management.Subscribe:output_type -> messagebroker.v1.MessageResponse
+	0,   // 135: messagebroker.v1.Management.Unsubscribe:output_type -> messagebroker.v1.MessageResponse
+	72,  // 136: messagebroker.v1.Management.ListSubscriptionRequests:output_type -> messagebroker.v1.ListSubscriptionRequestsResponse
+	75,  // 137: messagebroker.v1.Management.CreateSubscriptionRequest:output_type -> messagebroker.v1.SubscriptionRequestResponse
+	72,  // 138: messagebroker.v1.Management.ListIncomingSubscriptionRequests:output_type -> messagebroker.v1.ListSubscriptionRequestsResponse
+	75,  // 139: messagebroker.v1.Management.ApproveSubscriptionRequest:output_type -> messagebroker.v1.SubscriptionRequestResponse
+	75,  // 140: messagebroker.v1.Management.DenySubscriptionRequest:output_type -> messagebroker.v1.SubscriptionRequestResponse
+	94,  // 141: messagebroker.v1.Management.GetRateLimits:output_type -> messagebroker.v1.RateLimitsResponse
+	94,  // 142: messagebroker.v1.Management.GetClientRateLimits:output_type -> messagebroker.v1.RateLimitsResponse
+	94,  // 143: messagebroker.v1.Management.SetClientRateLimits:output_type -> messagebroker.v1.RateLimitsResponse
+	94,  // 144: messagebroker.v1.Management.ResetClientRateLimits:output_type -> messagebroker.v1.RateLimitsResponse
+	18,  // 145: messagebroker.v1.Management.ResetClientSecret:output_type -> messagebroker.v1.RotateSecretResponse
+	98,  // 146: messagebroker.v1.Management.GetUsage:output_type -> messagebroker.v1.UsageResponse
+	100, // 147: messagebroker.v1.Management.GetPublisherUsage:output_type -> messagebroker.v1.PublisherUsageResponse
+	81,  // 148: messagebroker.v1.Broker.Stream:output_type -> messagebroker.v1.StreamResponse
+	97,  // [97:149] is the sub-list for method output_type
+	45,  // [45:97] is the sub-list for method input_type
+	45,  // [45:45] is the sub-list for extension type_name
+	45,  // [45:45] is the sub-list for extension extendee
+	0,   // [0:45] is the sub-list for field type_name
+}
+
+func init() { file_messagebroker_proto_init() }
+func file_messagebroker_proto_init() {
+	if File_messagebroker_proto != nil {
+		return
+	}
+	file_messagebroker_proto_msgTypes[76].OneofWrappers = []any{
+		(*StreamRequest_Authenticate)(nil),
+		(*StreamRequest_ConfirmMessages)(nil),
+		(*StreamRequest_ListSessions)(nil),
+	}
+	file_messagebroker_proto_msgTypes[81].OneofWrappers = []any{
+		(*StreamResponse_Authentication)(nil),
+		(*StreamResponse_SessionStarted)(nil),
+		(*StreamResponse_Messages)(nil),
+		(*StreamResponse_MessagesConfirmed)(nil),
+		(*StreamResponse_Sessions)(nil),
+		(*StreamResponse_Notice)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_messagebroker_proto_rawDesc), len(file_messagebroker_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   102,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_messagebroker_proto_goTypes,
+		DependencyIndexes: file_messagebroker_proto_depIdxs,
+		MessageInfos:      file_messagebroker_proto_msgTypes,
+	}.Build()
+	File_messagebroker_proto = out.File
+	file_messagebroker_proto_goTypes = nil
+	file_messagebroker_proto_depIdxs = nil
+}
@@ -0,0 +1,2237 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.27.0
+// source: messagebroker.proto
+
+package brokerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Management_Register_FullMethodName                          = "/messagebroker.v1.Management/Register"
+	Management_Authenticate_FullMethodName                      = "/messagebroker.v1.Management/Authenticate"
+	Management_GetAuthenticatedClient_FullMethodName            = "/messagebroker.v1.Management/GetAuthenticatedClient"
+	Management_RotateSecret_FullMethodName                      = "/messagebroker.v1.Management/RotateSecret"
+	Management_IssueToken_FullMethodName                        = "/messagebroker.v1.Management/IssueToken"
+	Management_RefreshToken_FullMethodName                      = "/messagebroker.v1.Management/RefreshToken"
+	Management_CreateOrganization_FullMethodName                = "/messagebroker.v1.Management/CreateOrganization"
+	Management_GetOrganization_FullMethodName                   = "/messagebroker.v1.Management/GetOrganization"
+	Management_AddOrganizationMember_FullMethodName             = "/messagebroker.v1.Management/AddOrganizationMember"
+	Management_AddOrganizationAdmin_FullMethodName              = "/messagebroker.v1.Management/AddOrganizationAdmin"
+	Management_RemoveOrganizationAdmin_FullMethodName           = "/messagebroker.v1.Management/RemoveOrganizationAdmin"
+	Management_ListAPIKeys_FullMethodName                       = "/messagebroker.v1.Management/ListAPIKeys"
+	Management_CreateAPIKey_FullMethodName                      = "/messagebroker.v1.Management/CreateAPIKey"
+	Management_RevokeAPIKey_FullMethodName                      = "/messagebroker.v1.Management/RevokeAPIKey"
+	Management_ListPublishers_FullMethodName                    = "/messagebroker.v1.Management/ListPublishers"
+	Management_CreatePublisher_FullMethodName                   = "/messagebroker.v1.Management/CreatePublisher"
+	Management_DeletePublisher_FullMethodName                   = "/messagebroker.v1.Management/DeletePublisher"
+	Management_ListPublisherSubscribers_FullMethodName          = "/messagebroker.v1.Management/ListPublisherSubscribers"
+	Management_RemoveSubscriber_FullMethodName                  = "/messagebroker.v1.Management/RemoveSubscriber"
+	Management_ListPublisherSubscriptionRequests_FullMethodName = "/messagebroker.v1.Management/ListPublisherSubscriptionRequests"
+	Management_GetPublisherPayload_FullMethodName               = "/messagebroker.v1.Management/GetPublisherPayload"
+	Management_SetPublisherPayload_FullMethodName               = "/messagebroker.v1.Management/SetPublisherPayload"
+	Management_ListSchemas_FullMethodName                       = "/messagebroker.v1.Management/ListSchemas"
+	Management_RegisterSchema_FullMethodName                    = "/messagebroker.v1.Management/RegisterSchema"
+	Management_GetSchema_FullMethodName                         = "/messagebroker.v1.Management/GetSchema"
+	Management_GetPublisherACL_FullMethodName                   = "/messagebroker.v1.Management/GetPublisherACL"
+	Management_SetPublisherACL_FullMethodName                   = "/messagebroker.v1.Management/SetPublisherACL"
+	Management_AllowClient_FullMethodName                       = "/messagebroker.v1.Management/AllowClient"
+	Management_DisallowClient_FullMethodName                    = "/messagebroker.v1.Management/DisallowClient"
+	Management_AllowGroup_FullMethodName                        = "/messagebroker.v1.Management/AllowGroup"
+	Management_DisallowGroup_FullMethodName                     = "/messagebroker.v1.Management/DisallowGroup"
+	Management_ListGroups_FullMethodName                        = "/messagebroker.v1.Management/ListGroups"
+	Management_CreateGroup_FullMethodName                       = "/messagebroker.v1.Management/CreateGroup"
+	Management_SetGroupMembers_FullMethodName                   = "/messagebroker.v1.Management/SetGroupMembers"
+	Management_DeleteGroup_FullMethodName                       = "/messagebroker.v1.Management/DeleteGroup"
+	Management_PublishMessage_FullMethodName                    = "/messagebroker.v1.Management/PublishMessage"
+	Management_ListSubscriptions_FullMethodName                 = "/messagebroker.v1.Management/ListSubscriptions"
+	Management_Subscribe_FullMethodName                         = "/messagebroker.v1.Management/Subscribe"
+	Management_Unsubscribe_FullMethodName                       = "/messagebroker.v1.Management/Unsubscribe"
+	Management_ListSubscriptionRequests_FullMethodName          = "/messagebroker.v1.Management/ListSubscriptionRequests"
+	Management_CreateSubscriptionRequest_FullMethodName         = "/messagebroker.v1.Management/CreateSubscriptionRequest"
+	Management_ListIncomingSubscriptionRequests_FullMethodName  = "/messagebroker.v1.Management/ListIncomingSubscriptionRequests"
+	Management_ApproveSubscriptionRequest_FullMethodName        = "/messagebroker.v1.Management/ApproveSubscriptionRequest"
+	Management_DenySubscriptionRequest_FullMethodName           = "/messagebroker.v1.Management/DenySubscriptionRequest"
+	Management_GetRateLimits_FullMethodName                     = "/messagebroker.v1.Management/GetRateLimits"
+	Management_GetClientRateLimits_FullMethodName               = "/messagebroker.v1.Management/GetClientRateLimits"
+	Management_SetClientRateLimits_FullMethodName               = "/messagebroker.v1.Management/SetClientRateLimits"
+	Management_ResetClientRateLimits_FullMethodName             = "/messagebroker.v1.Management/ResetClientRateLimits"
+	Management_ResetClientSecret_FullMethodName                 = "/messagebroker.v1.Management/ResetClientSecret"
+	Management_GetUsage_FullMethodName                          = "/messagebroker.v1.Management/GetUsage"
+	Management_GetPublisherUsage_FullMethodName                 = "/messagebroker.v1.Management/GetPublisherUsage"
+)
+
+// ManagementClient is the client API for Management service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Management mirrors the REST routes of the publisher service. Apart from Register, Authenticate
+// and the token calls every call must carry the id and secret of the client in the "client-id"
+// and "client-secret" metadata, an access token in the "authorization" metadata as
+// "Bearer <token>", or an API key with the call's scope in the "api-key" metadata.
+type ManagementClient interface {
+	// POST /register
+	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
+	// POST /auth
+	Authenticate(ctx context.Context, in *AuthenticateRequest, opts ...grpc.CallOption) (*AuthenticateResponse, error)
+	// GET /auth
+	GetAuthenticatedClient(ctx context.Context, in *GetAuthenticatedClientRequest, opts ...grpc.CallOption) (*AuthenticateResponse, error)
+	// POST /auth/secret
+	RotateSecret(ctx context.Context, in *RotateSecretRequest, opts ...grpc.CallOption) (*RotateSecretResponse, error)
+	// POST /auth/token
+	IssueToken(ctx context.Context, in *AuthenticateRequest, opts ...grpc.CallOption) (*TokenResponse, error)
+	// POST /auth/token/refresh
+	RefreshToken(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*TokenResponse, error)
+	// POST /organizations
+	CreateOrganization(ctx context.Context, in *CreateOrganizationRequest, opts ...grpc.CallOption) (*CreateOrganizationResponse, error)
+	// GET /organization
+	GetOrganization(ctx context.Context, in *GetOrganizationRequest, opts ...grpc.CallOption) (*OrganizationResponse, error)
+	// POST /organization/members
+	AddOrganizationMember(ctx context.Context, in *AddOrganizationMemberRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
+	// POST /organization/admins
+	AddOrganizationAdmin(ctx context.Context, in *OrganizationAdminRequest, opts ...grpc.CallOption) (*OrganizationResponse, error)
+	// DELETE /organization/admins/{client_id}
+	RemoveOrganizationAdmin(ctx context.Context, in *OrganizationAdminRequest, opts ...grpc.CallOption) (*OrganizationResponse, error)
+	// GET /api-keys
+	ListAPIKeys(ctx context.Context, in *ListAPIKeysRequest, opts ...grpc.CallOption) (*ListAPIKeysResponse, error)
+	// POST /api-keys
+	CreateAPIKey(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*CreateAPIKeyResponse, error)
+	// DELETE /api-keys/{key_id}
+	RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*MessageResponse, error)
+	// GET /publishers
+	ListPublishers(ctx context.Context, in *ListPublishersRequest, opts ...grpc.CallOption) (*ListPublishersResponse, error)
+	// POST /publishers
+	CreatePublisher(ctx context.Context, in *CreatePublisherRequest, opts ...grpc.CallOption) (*CreatePublisherResponse, error)
+	// DELETE /publishers/{publisher_id}
+	DeletePublisher(ctx context.Context, in *DeletePublisherRequest, opts ...grpc.CallOption) (*MessageResponse, error)
+	// GET /publishers/{publisher_id}/subscribers
+	ListPublisherSubscribers(ctx context.Context, in *ListPublisherSubscribersRequest, opts ...grpc.CallOption) (*ListPublisherSubscribersResponse, error)
+	// DELETE /publishers/{publisher_id}/subscribers/{client_id}
+	RemoveSubscriber(ctx context.Context, in *RemoveSubscriberRequest, opts ...grpc.CallOption) (*MessageResponse, error)
+	// GET /publishers/{publisher_id}/subscription-requests
+	ListPublisherSubscriptionRequests(ctx context.Context, in *ListPublisherSubscriptionRequestsRequest, opts ...grpc.CallOption) (*ListSubscriptionRequestsResponse, error)
+	// GET /publishers/{publisher_id}/payload
+	GetPublisherPayload(ctx context.Context, in *GetPublisherPayloadRequest, opts ...grpc.CallOption) (*PublisherPayloadResponse, error)
+	// PUT /publishers/{publisher_id}/payload
+	SetPublisherPayload(ctx context.Context, in *SetPublisherPayloadRequest, opts ...grpc.CallOption) (*PublisherPayloadResponse, error)
+	// GET /publishers/{publisher_id}/schemas
+	ListSchemas(ctx context.Context, in *ListSchemasRequest, opts ...grpc.CallOption) (*ListSchemasResponse, error)
+	// POST /publishers/{publisher_id}/schemas
+	RegisterSchema(ctx context.Context, in *RegisterSchemaRequest, opts ...grpc.CallOption) (*SchemaResponse, error)
+	// GET /publishers/{publisher_id}/schemas/{version}
+	GetSchema(ctx context.Context, in *GetSchemaRequest, opts ...grpc.CallOption) (*SchemaResponse, error)
+	// GET /publishers/{publisher_id}/acl
+	GetPublisherACL(ctx context.Context, in *GetPublisherACLRequest, opts ...grpc.CallOption) (*PublisherACLResponse, error)
+	// PUT /publishers/{publisher_id}/acl
+	SetPublisherACL(ctx context.Context, in *SetPublisherACLRequest, opts ...grpc.CallOption) (*PublisherACLResponse, error)
+	// POST /publishers/{publisher_id}/acl/clients
+	AllowClient(ctx context.Context, in *AllowClientRequest, opts ...grpc.CallOption) (*PublisherACLResponse, error)
+	// DELETE /publishers/{publisher_id}/acl/clients/{client_id}
+	DisallowClient(ctx context.Context, in *AllowClientRequest, opts ...grpc.CallOption) (*PublisherACLResponse, error)
+	// POST /publishers/{publisher_id}/acl/groups
+	AllowGroup(ctx context.Context, in *AllowGroupRequest, opts ...grpc.CallOption) (*PublisherACLResponse, error)
+	// DELETE /publishers/{publisher_id}/acl/groups/{group_id}
+	DisallowGroup(ctx context.Context, in *AllowGroupRequest, opts ...grpc.CallOption) (*PublisherACLResponse, error)
+	// GET /groups
+	ListGroups(ctx context.Context, in *ListGroupsRequest, opts ...grpc.CallOption) (*ListGroupsResponse, error)
+	// POST /groups
+	CreateGroup(ctx context.Context, in *CreateGroupRequest, opts ...grpc.CallOption) (*GroupResponse, error)
+	// PUT /groups/{group_id}/members
+	SetGroupMembers(ctx context.Context, in *SetGroupMembersRequest, opts ...grpc.CallOption) (*GroupResponse, error)
+	// DELETE /groups/{group_id}
+	DeleteGroup(ctx context.Context, in *DeleteGroupRequest, opts ...grpc.CallOption) (*MessageResponse, error)
+	// POST /publishers/{publisher_id}/messages
+	PublishMessage(ctx context.Context, in *PublishMessageRequest, opts ...grpc.CallOption) (*MessageResponse, error)
+	// GET /subscriptions
+	ListSubscriptions(ctx context.Context, in *ListSubscriptionsRequest, opts ...grpc.CallOption) (*ListSubscriptionsResponse, error)
+	// POST /subscriptions
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (*MessageResponse, error)
+	// DELETE /subscriptions/{subscription_id}
+	Unsubscribe(ctx context.Context, in *UnsubscribeRequest, opts ...grpc.CallOption) (*MessageResponse, error)
+	// GET /subscription-requests
+	ListSubscriptionRequests(ctx context.Context, in *ListSubscriptionRequestsRequest, opts ...grpc.CallOption) (*ListSubscriptionRequestsResponse, error)
+	// POST /subscription-requests
+	CreateSubscriptionRequest(ctx context.Context, in *CreateSubscriptionRequestRequest, opts ...grpc.CallOption) (*SubscriptionRequestResponse, error)
+	// GET /subscription-requests/incoming
+	ListIncomingSubscriptionRequests(ctx context.Context, in *ListSubscriptionRequestsRequest, opts ...grpc.CallOption) (*ListSubscriptionRequestsResponse, error)
+	// POST /subscription-requests/{request_id}/approve
+	ApproveSubscriptionRequest(ctx context.Context, in *DecideSubscriptionRequestRequest, opts ...grpc.CallOption) (*SubscriptionRequestResponse, error)
+	// POST /subscription-requests/{request_id}/deny
+	DenySubscriptionRequest(ctx context.Context, in *DecideSubscriptionRequestRequest, opts ...grpc.CallOption) (*SubscriptionRequestResponse, error)
+	// GET /rate-limits
+	GetRateLimits(ctx context.Context, in *GetRateLimitsRequest, opts ...grpc.CallOption) (*RateLimitsResponse, error)
+	// GET /admin/clients/{client_id}/rate-limits, requires the admin-secret metadata
+	GetClientRateLimits(ctx context.Context, in *ClientRateLimitsRequest, opts ...grpc.CallOption) (*RateLimitsResponse, error)
+	// PUT /admin/clients/{client_id}/rate-limits, requires the admin-secret metadata
+	SetClientRateLimits(ctx context.Context, in *SetClientRateLimitsRequest, opts ...grpc.CallOption) (*RateLimitsResponse, error)
+	// DELETE /admin/clients/{client_id}/rate-limits, requires the admin-secret metadata
+	ResetClientRateLimits(ctx context.Context, in *ClientRateLimitsRequest, opts ...grpc.CallOption) (*RateLimitsResponse, error)
+	// POST /admin/clients/{client_id}/secret, requires the admin-secret metadata
+	ResetClientSecret(ctx context.Context, in *ResetClientSecretRequest, opts ...grpc.CallOption) (*RotateSecretResponse, error)
+	// GET /usage
+	GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*UsageResponse, error)
+	// GET /publishers/{publisher_id}/usage
+	GetPublisherUsage(ctx context.Context, in *GetPublisherUsageRequest, opts ...grpc.CallOption) (*PublisherUsageResponse, error)
+}
+
+type managementClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewManagementClient(cc grpc.ClientConnInterface) ManagementClient {
+	return &managementClient{cc}
+}
+
+func (c *managementClient) Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterResponse)
+	err := c.cc.Invoke(ctx, Management_Register_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) Authenticate(ctx context.Context, in *AuthenticateRequest, opts ...grpc.CallOption) (*AuthenticateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuthenticateResponse)
+	err := c.cc.Invoke(ctx, Management_Authenticate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) GetAuthenticatedClient(ctx context.Context, in *GetAuthenticatedClientRequest, opts ...grpc.CallOption) (*AuthenticateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuthenticateResponse)
+	err := c.cc.Invoke(ctx, Management_GetAuthenticatedClient_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) RotateSecret(ctx context.Context, in *RotateSecretRequest, opts ...grpc.CallOption) (*RotateSecretResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RotateSecretResponse)
+	err := c.cc.Invoke(ctx, Management_RotateSecret_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) IssueToken(ctx context.Context, in *AuthenticateRequest, opts ...grpc.CallOption) (*TokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TokenResponse)
+	err := c.cc.Invoke(ctx, Management_IssueToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) RefreshToken(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*TokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TokenResponse)
+	err := c.cc.Invoke(ctx, Management_RefreshToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) CreateOrganization(ctx context.Context, in *CreateOrganizationRequest, opts ...grpc.CallOption) (*CreateOrganizationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateOrganizationResponse)
+	err := c.cc.Invoke(ctx, Management_CreateOrganization_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) GetOrganization(ctx context.Context, in *GetOrganizationRequest, opts ...grpc.CallOption) (*OrganizationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrganizationResponse)
+	err := c.cc.Invoke(ctx, Management_GetOrganization_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) AddOrganizationMember(ctx context.Context, in *AddOrganizationMemberRequest, opts ...grpc.CallOption) (*RegisterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterResponse)
+	err := c.cc.Invoke(ctx, Management_AddOrganizationMember_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) AddOrganizationAdmin(ctx context.Context, in *OrganizationAdminRequest, opts ...grpc.CallOption) (*OrganizationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrganizationResponse)
+	err := c.cc.Invoke(ctx, Management_AddOrganizationAdmin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) RemoveOrganizationAdmin(ctx context.Context, in *OrganizationAdminRequest, opts ...grpc.CallOption) (*OrganizationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrganizationResponse)
+	err := c.cc.Invoke(ctx, Management_RemoveOrganizationAdmin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) ListAPIKeys(ctx context.Context, in *ListAPIKeysRequest, opts ...grpc.CallOption) (*ListAPIKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAPIKeysResponse)
+	err := c.cc.Invoke(ctx, Management_ListAPIKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) CreateAPIKey(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*CreateAPIKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateAPIKeyResponse)
+	err := c.cc.Invoke(ctx, Management_CreateAPIKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*MessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MessageResponse)
+	err := c.cc.Invoke(ctx, Management_RevokeAPIKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) ListPublishers(ctx context.Context, in *ListPublishersRequest, opts ...grpc.CallOption) (*ListPublishersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPublishersResponse)
+	err := c.cc.Invoke(ctx, Management_ListPublishers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) CreatePublisher(ctx context.Context, in *CreatePublisherRequest, opts ...grpc.CallOption) (*CreatePublisherResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreatePublisherResponse)
+	err := c.cc.Invoke(ctx, Management_CreatePublisher_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) DeletePublisher(ctx context.Context, in *DeletePublisherRequest, opts ...grpc.CallOption) (*MessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MessageResponse)
+	err := c.cc.Invoke(ctx, Management_DeletePublisher_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) ListPublisherSubscribers(ctx context.Context, in *ListPublisherSubscribersRequest, opts ...grpc.CallOption) (*ListPublisherSubscribersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPublisherSubscribersResponse)
+	err := c.cc.Invoke(ctx, Management_ListPublisherSubscribers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) RemoveSubscriber(ctx context.Context, in *RemoveSubscriberRequest, opts ...grpc.CallOption) (*MessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MessageResponse)
+	err := c.cc.Invoke(ctx, Management_RemoveSubscriber_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) ListPublisherSubscriptionRequests(ctx context.Context, in *ListPublisherSubscriptionRequestsRequest, opts ...grpc.CallOption) (*ListSubscriptionRequestsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSubscriptionRequestsResponse)
+	err := c.cc.Invoke(ctx, Management_ListPublisherSubscriptionRequests_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) GetPublisherPayload(ctx context.Context, in *GetPublisherPayloadRequest, opts ...grpc.CallOption) (*PublisherPayloadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PublisherPayloadResponse)
+	err := c.cc.Invoke(ctx, Management_GetPublisherPayload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) SetPublisherPayload(ctx context.Context, in *SetPublisherPayloadRequest, opts ...grpc.CallOption) (*PublisherPayloadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PublisherPayloadResponse)
+	err := c.cc.Invoke(ctx, Management_SetPublisherPayload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) ListSchemas(ctx context.Context, in *ListSchemasRequest, opts ...grpc.CallOption) (*ListSchemasResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSchemasResponse)
+	err := c.cc.Invoke(ctx, Management_ListSchemas_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) RegisterSchema(ctx context.Context, in *RegisterSchemaRequest, opts ...grpc.CallOption) (*SchemaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SchemaResponse)
+	err := c.cc.Invoke(ctx, Management_RegisterSchema_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) GetSchema(ctx context.Context, in *GetSchemaRequest, opts ...grpc.CallOption) (*SchemaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SchemaResponse)
+	err := c.cc.Invoke(ctx, Management_GetSchema_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) GetPublisherACL(ctx context.Context, in *GetPublisherACLRequest, opts ...grpc.CallOption) (*PublisherACLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PublisherACLResponse)
+	err := c.cc.Invoke(ctx, Management_GetPublisherACL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) SetPublisherACL(ctx context.Context, in *SetPublisherACLRequest, opts ...grpc.CallOption) (*PublisherACLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PublisherACLResponse)
+	err := c.cc.Invoke(ctx, Management_SetPublisherACL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) AllowClient(ctx context.Context, in *AllowClientRequest, opts ...grpc.CallOption) (*PublisherACLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PublisherACLResponse)
+	err := c.cc.Invoke(ctx, Management_AllowClient_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) DisallowClient(ctx context.Context, in *AllowClientRequest, opts ...grpc.CallOption) (*PublisherACLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PublisherACLResponse)
+	err := c.cc.Invoke(ctx, Management_DisallowClient_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) AllowGroup(ctx context.Context, in *AllowGroupRequest, opts ...grpc.CallOption) (*PublisherACLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PublisherACLResponse)
+	err := c.cc.Invoke(ctx, Management_AllowGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) DisallowGroup(ctx context.Context, in *AllowGroupRequest, opts ...grpc.CallOption) (*PublisherACLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PublisherACLResponse)
+	err := c.cc.Invoke(ctx, Management_DisallowGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) ListGroups(ctx context.Context, in *ListGroupsRequest, opts ...grpc.CallOption) (*ListGroupsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListGroupsResponse)
+	err := c.cc.Invoke(ctx, Management_ListGroups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) CreateGroup(ctx context.Context, in *CreateGroupRequest, opts ...grpc.CallOption) (*GroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GroupResponse)
+	err := c.cc.Invoke(ctx, Management_CreateGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) SetGroupMembers(ctx context.Context, in *SetGroupMembersRequest, opts ...grpc.CallOption) (*GroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GroupResponse)
+	err := c.cc.Invoke(ctx, Management_SetGroupMembers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) DeleteGroup(ctx context.Context, in *DeleteGroupRequest, opts ...grpc.CallOption) (*MessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MessageResponse)
+	err := c.cc.Invoke(ctx, Management_DeleteGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) PublishMessage(ctx context.Context, in *PublishMessageRequest, opts ...grpc.CallOption) (*MessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MessageResponse)
+	err := c.cc.Invoke(ctx, Management_PublishMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) ListSubscriptions(ctx context.Context, in *ListSubscriptionsRequest, opts ...grpc.CallOption) (*ListSubscriptionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSubscriptionsResponse)
+	err := c.cc.Invoke(ctx, Management_ListSubscriptions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (*MessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MessageResponse)
+	err := c.cc.Invoke(ctx, Management_Subscribe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) Unsubscribe(ctx context.Context, in *UnsubscribeRequest, opts ...grpc.CallOption) (*MessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MessageResponse)
+	err := c.cc.Invoke(ctx, Management_Unsubscribe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) ListSubscriptionRequests(ctx context.Context, in *ListSubscriptionRequestsRequest, opts ...grpc.CallOption) (*ListSubscriptionRequestsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSubscriptionRequestsResponse)
+	err := c.cc.Invoke(ctx, Management_ListSubscriptionRequests_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) CreateSubscriptionRequest(ctx context.Context, in *CreateSubscriptionRequestRequest, opts ...grpc.CallOption) (*SubscriptionRequestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubscriptionRequestResponse)
+	err := c.cc.Invoke(ctx, Management_CreateSubscriptionRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) ListIncomingSubscriptionRequests(ctx context.Context, in *ListSubscriptionRequestsRequest, opts ...grpc.CallOption) (*ListSubscriptionRequestsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSubscriptionRequestsResponse)
+	err := c.cc.Invoke(ctx, Management_ListIncomingSubscriptionRequests_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) ApproveSubscriptionRequest(ctx context.Context, in *DecideSubscriptionRequestRequest, opts ...grpc.CallOption) (*SubscriptionRequestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubscriptionRequestResponse)
+	err := c.cc.Invoke(ctx, Management_ApproveSubscriptionRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) DenySubscriptionRequest(ctx context.Context, in *DecideSubscriptionRequestRequest, opts ...grpc.CallOption) (*SubscriptionRequestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubscriptionRequestResponse)
+	err := c.cc.Invoke(ctx, Management_DenySubscriptionRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) GetRateLimits(ctx context.Context, in *GetRateLimitsRequest, opts ...grpc.CallOption) (*RateLimitsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RateLimitsResponse)
+	err := c.cc.Invoke(ctx, Management_GetRateLimits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) GetClientRateLimits(ctx context.Context, in *ClientRateLimitsRequest, opts ...grpc.CallOption) (*RateLimitsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RateLimitsResponse)
+	err := c.cc.Invoke(ctx, Management_GetClientRateLimits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) SetClientRateLimits(ctx context.Context, in *SetClientRateLimitsRequest, opts ...grpc.CallOption) (*RateLimitsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RateLimitsResponse)
+	err := c.cc.Invoke(ctx, Management_SetClientRateLimits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) ResetClientRateLimits(ctx context.Context, in *ClientRateLimitsRequest, opts ...grpc.CallOption) (*RateLimitsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RateLimitsResponse)
+	err := c.cc.Invoke(ctx, Management_ResetClientRateLimits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) ResetClientSecret(ctx context.Context, in *ResetClientSecretRequest, opts ...grpc.CallOption) (*RotateSecretResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RotateSecretResponse)
+	err := c.cc.Invoke(ctx, Management_ResetClientSecret_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*UsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UsageResponse)
+	err := c.cc.Invoke(ctx, Management_GetUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *managementClient) GetPublisherUsage(ctx context.Context, in *GetPublisherUsageRequest, opts ...grpc.CallOption) (*PublisherUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PublisherUsageResponse)
+	err := c.cc.Invoke(ctx, Management_GetPublisherUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ManagementServer is the server API for Management service.
+// All implementations must embed UnimplementedManagementServer
+// for forward compatibility.
+//
+// Management mirrors the REST routes of the publisher service. Apart from Register, Authenticate
+// and the token calls every call must carry the id and secret of the client in the "client-id"
+// and "client-secret" metadata, an access token in the "authorization" metadata as
+// "Bearer <token>", or an API key with the call's scope in the "api-key" metadata.
+type ManagementServer interface {
+	// POST /register
+	Register(context.Context, *RegisterRequest) (*RegisterResponse, error)
+	// POST /auth
+	Authenticate(context.Context, *AuthenticateRequest) (*AuthenticateResponse, error)
+	// GET /auth
+	GetAuthenticatedClient(context.Context, *GetAuthenticatedClientRequest) (*AuthenticateResponse, error)
+	// POST /auth/secret
+	RotateSecret(context.Context, *RotateSecretRequest) (*RotateSecretResponse, error)
+	// POST /auth/token
+	IssueToken(context.Context, *AuthenticateRequest) (*TokenResponse, error)
+	// POST /auth/token/refresh
+	RefreshToken(context.Context, *RefreshTokenRequest) (*TokenResponse, error)
+	// POST /organizations
+	CreateOrganization(context.Context, *CreateOrganizationRequest) (*CreateOrganizationResponse, error)
+	// GET /organization
+	GetOrganization(context.Context, *GetOrganizationRequest) (*OrganizationResponse, error)
+	// POST /organization/members
+	AddOrganizationMember(context.Context, *AddOrganizationMemberRequest) (*RegisterResponse, error)
+	// POST /organization/admins
+	AddOrganizationAdmin(context.Context, *OrganizationAdminRequest) (*OrganizationResponse, error)
+	// DELETE /organization/admins/{client_id}
+	RemoveOrganizationAdmin(context.Context, *OrganizationAdminRequest) (*OrganizationResponse, error)
+	// GET /api-keys
+	ListAPIKeys(context.Context, *ListAPIKeysRequest) (*ListAPIKeysResponse, error)
+	// POST /api-keys
+	CreateAPIKey(context.Context, *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error)
+	// DELETE /api-keys/{key_id}
+	RevokeAPIKey(context.Context, *RevokeAPIKeyRequest) (*MessageResponse, error)
+	// GET /publishers
+	ListPublishers(context.Context, *ListPublishersRequest) (*ListPublishersResponse, error)
+	// POST /publishers
+	CreatePublisher(context.Context, *CreatePublisherRequest) (*CreatePublisherResponse, error)
+	// DELETE /publishers/{publisher_id}
+	DeletePublisher(context.Context, *DeletePublisherRequest) (*MessageResponse, error)
+	// GET /publishers/{publisher_id}/subscribers
+	ListPublisherSubscribers(context.Context, *ListPublisherSubscribersRequest) (*ListPublisherSubscribersResponse, error)
+	// DELETE /publishers/{publisher_id}/subscribers/{client_id}
+	RemoveSubscriber(context.Context, *RemoveSubscriberRequest) (*MessageResponse, error)
+	// GET /publishers/{publisher_id}/subscription-requests
+	ListPublisherSubscriptionRequests(context.Context, *ListPublisherSubscriptionRequestsRequest) (*ListSubscriptionRequestsResponse, error)
+	// GET /publishers/{publisher_id}/payload
+	GetPublisherPayload(context.Context, *GetPublisherPayloadRequest) (*PublisherPayloadResponse, error)
+	// PUT /publishers/{publisher_id}/payload
+	SetPublisherPayload(context.Context, *SetPublisherPayloadRequest) (*PublisherPayloadResponse, error)
+	// GET /publishers/{publisher_id}/schemas
+	ListSchemas(context.Context, *ListSchemasRequest) (*ListSchemasResponse, error)
+	// POST /publishers/{publisher_id}/schemas
+	RegisterSchema(context.Context, *RegisterSchemaRequest) (*SchemaResponse, error)
+	// GET /publishers/{publisher_id}/schemas/{version}
+	GetSchema(context.Context, *GetSchemaRequest) (*SchemaResponse, error)
+	// GET /publishers/{publisher_id}/acl
+	GetPublisherACL(context.Context, *GetPublisherACLRequest) (*PublisherACLResponse, error)
+	// PUT /publishers/{publisher_id}/acl
+	SetPublisherACL(context.Context, *SetPublisherACLRequest) (*PublisherACLResponse, error)
+	// POST /publishers/{publisher_id}/acl/clients
+	AllowClient(context.Context, *AllowClientRequest) (*PublisherACLResponse, error)
+	// DELETE /publishers/{publisher_id}/acl/clients/{client_id}
+	DisallowClient(context.Context, *AllowClientRequest) (*PublisherACLResponse, error)
+	// POST /publishers/{publisher_id}/acl/groups
+	AllowGroup(context.Context, *AllowGroupRequest) (*PublisherACLResponse, error)
+	// DELETE /publishers/{publisher_id}/acl/groups/{group_id}
+	DisallowGroup(context.Context, *AllowGroupRequest) (*PublisherACLResponse, error)
+	// GET /groups
+	ListGroups(context.Context, *ListGroupsRequest) (*ListGroupsResponse, error)
+	// POST /groups
+	CreateGroup(context.Context, *CreateGroupRequest) (*GroupResponse, error)
+	// PUT /groups/{group_id}/members
+	SetGroupMembers(context.Context, *SetGroupMembersRequest) (*GroupResponse, error)
+	// DELETE /groups/{group_id}
+	DeleteGroup(context.Context, *DeleteGroupRequest) (*MessageResponse, error)
+	// POST /publishers/{publisher_id}/messages
+	PublishMessage(context.Context, *PublishMessageRequest) (*MessageResponse, error)
+	// GET /subscriptions
+	ListSubscriptions(context.Context, *ListSubscriptionsRequest) (*ListSubscriptionsResponse, error)
+	// POST /subscriptions
+	Subscribe(context.Context, *SubscribeRequest) (*MessageResponse, error)
+	// DELETE /subscriptions/{subscription_id}
+	Unsubscribe(context.Context, *UnsubscribeRequest) (*MessageResponse, error)
+	// GET /subscription-requests
+	ListSubscriptionRequests(context.Context, *ListSubscriptionRequestsRequest) (*ListSubscriptionRequestsResponse, error)
+	// POST /subscription-requests
+	CreateSubscriptionRequest(context.Context, *CreateSubscriptionRequestRequest) (*SubscriptionRequestResponse, error)
+	// GET /subscription-requests/incoming
+	ListIncomingSubscriptionRequests(context.Context, *ListSubscriptionRequestsRequest) (*ListSubscriptionRequestsResponse, error)
+	// POST /subscription-requests/{request_id}/approve
+	ApproveSubscriptionRequest(context.Context, *DecideSubscriptionRequestRequest) (*SubscriptionRequestResponse, error)
+	// POST /subscription-requests/{request_id}/deny
+	DenySubscriptionRequest(context.Context, *DecideSubscriptionRequestRequest) (*SubscriptionRequestResponse, error)
+	// GET /rate-limits
+	GetRateLimits(context.Context, *GetRateLimitsRequest) (*RateLimitsResponse, error)
+	// GET /admin/clients/{client_id}/rate-limits, requires the admin-secret metadata
+	GetClientRateLimits(context.Context, *ClientRateLimitsRequest) (*RateLimitsResponse, error)
+	// PUT /admin/clients/{client_id}/rate-limits, requires the admin-secret metadata
+	SetClientRateLimits(context.Context, *SetClientRateLimitsRequest) (*RateLimitsResponse, error)
+	// DELETE /admin/clients/{client_id}/rate-limits, requires the admin-secret metadata
+	ResetClientRateLimits(context.Context, *ClientRateLimitsRequest) (*RateLimitsResponse, error)
+	// POST /admin/clients/{client_id}/secret, requires the admin-secret metadata
+	ResetClientSecret(context.Context, *ResetClientSecretRequest) (*RotateSecretResponse, error)
+	// GET /usage
+	GetUsage(context.Context, *GetUsageRequest) (*UsageResponse, error)
+	// GET /publishers/{publisher_id}/usage
+	GetPublisherUsage(context.Context, *GetPublisherUsageRequest) (*PublisherUsageResponse, error)
+	mustEmbedUnimplementedManagementServer()
+}
+
+// UnimplementedManagementServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedManagementServer struct{}
+
+func (UnimplementedManagementServer) Register(context.Context, *RegisterRequest) (*RegisterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
+}
+func (UnimplementedManagementServer) Authenticate(context.Context, *AuthenticateRequest) (*AuthenticateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Authenticate not implemented")
+}
+func (UnimplementedManagementServer) GetAuthenticatedClient(context.Context, *GetAuthenticatedClientRequest) (*AuthenticateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAuthenticatedClient not implemented")
+}
+func (UnimplementedManagementServer) RotateSecret(context.Context, *RotateSecretRequest) (*RotateSecretResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateSecret not implemented")
+}
+func (UnimplementedManagementServer) IssueToken(context.Context, *AuthenticateRequest) (*TokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IssueToken not implemented")
+}
+func (UnimplementedManagementServer) RefreshToken(context.Context, *RefreshTokenRequest) (*TokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshToken not implemented")
+}
+func (UnimplementedManagementServer) CreateOrganization(context.Context, *CreateOrganizationRequest) (*CreateOrganizationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateOrganization not implemented")
+}
+func (UnimplementedManagementServer) GetOrganization(context.Context, *GetOrganizationRequest) (*OrganizationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrganization not implemented")
+}
+func (UnimplementedManagementServer) AddOrganizationMember(context.Context, *AddOrganizationMemberRequest) (*RegisterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddOrganizationMember not implemented")
+}
+func (UnimplementedManagementServer) AddOrganizationAdmin(context.Context, *OrganizationAdminRequest) (*OrganizationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddOrganizationAdmin not implemented")
+}
+func (UnimplementedManagementServer) RemoveOrganizationAdmin(context.Context, *OrganizationAdminRequest) (*OrganizationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveOrganizationAdmin not implemented")
+}
+func (UnimplementedManagementServer) ListAPIKeys(context.Context, *ListAPIKeysRequest) (*ListAPIKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAPIKeys not implemented")
+}
+func (UnimplementedManagementServer) CreateAPIKey(context.Context, *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAPIKey not implemented")
+}
+func (UnimplementedManagementServer) RevokeAPIKey(context.Context, *RevokeAPIKeyRequest) (*MessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAPIKey not implemented")
+}
+func (UnimplementedManagementServer) ListPublishers(context.Context, *ListPublishersRequest) (*ListPublishersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPublishers not implemented")
+}
+func (UnimplementedManagementServer) CreatePublisher(context.Context, *CreatePublisherRequest) (*CreatePublisherResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePublisher not implemented")
+}
+func (UnimplementedManagementServer) DeletePublisher(context.Context, *DeletePublisherRequest) (*MessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePublisher not implemented")
+}
+func (UnimplementedManagementServer) ListPublisherSubscribers(context.Context, *ListPublisherSubscribersRequest) (*ListPublisherSubscribersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPublisherSubscribers not implemented")
+}
+func (UnimplementedManagementServer) RemoveSubscriber(context.Context, *RemoveSubscriberRequest) (*MessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveSubscriber not implemented")
+}
+func (UnimplementedManagementServer) ListPublisherSubscriptionRequests(context.Context, *ListPublisherSubscriptionRequestsRequest) (*ListSubscriptionRequestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPublisherSubscriptionRequests not implemented")
+}
+func (UnimplementedManagementServer) GetPublisherPayload(context.Context, *GetPublisherPayloadRequest) (*PublisherPayloadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPublisherPayload not implemented")
+}
+func (UnimplementedManagementServer) SetPublisherPayload(context.Context, *SetPublisherPayloadRequest) (*PublisherPayloadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetPublisherPayload not implemented")
+}
+func (UnimplementedManagementServer) ListSchemas(context.Context, *ListSchemasRequest) (*ListSchemasResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSchemas not implemented")
+}
+func (UnimplementedManagementServer) RegisterSchema(context.Context, *RegisterSchemaRequest) (*SchemaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterSchema not implemented")
+}
+func (UnimplementedManagementServer) GetSchema(context.Context, *GetSchemaRequest) (*SchemaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSchema not implemented")
+}
+func (UnimplementedManagementServer) GetPublisherACL(context.Context, *GetPublisherACLRequest) (*PublisherACLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPublisherACL not implemented")
+}
+func (UnimplementedManagementServer) SetPublisherACL(context.Context, *SetPublisherACLRequest) (*PublisherACLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetPublisherACL not implemented")
+}
+func (UnimplementedManagementServer) AllowClient(context.Context, *AllowClientRequest) (*PublisherACLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AllowClient not implemented")
+}
+func (UnimplementedManagementServer) DisallowClient(context.Context, *AllowClientRequest) (*PublisherACLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisallowClient not implemented")
+}
+func (UnimplementedManagementServer) AllowGroup(context.Context, *AllowGroupRequest) (*PublisherACLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AllowGroup not implemented")
+}
+func (UnimplementedManagementServer) DisallowGroup(context.Context, *AllowGroupRequest) (*PublisherACLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisallowGroup not implemented")
+}
+func (UnimplementedManagementServer) ListGroups(context.Context, *ListGroupsRequest) (*ListGroupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGroups not implemented")
+}
+func (UnimplementedManagementServer) CreateGroup(context.Context, *CreateGroupRequest) (*GroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGroup not implemented")
+}
+func (UnimplementedManagementServer) SetGroupMembers(context.Context, *SetGroupMembersRequest) (*GroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetGroupMembers not implemented")
+}
+func (UnimplementedManagementServer) DeleteGroup(context.Context, *DeleteGroupRequest) (*MessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteGroup not implemented")
+}
+func (UnimplementedManagementServer) PublishMessage(context.Context, *PublishMessageRequest) (*MessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishMessage not implemented")
+}
+func (UnimplementedManagementServer) ListSubscriptions(context.Context, *ListSubscriptionsRequest) (*ListSubscriptionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSubscriptions not implemented")
+}
+func (UnimplementedManagementServer) Subscribe(context.Context, *SubscribeRequest) (*MessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedManagementServer) Unsubscribe(context.Context, *UnsubscribeRequest) (*MessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unsubscribe not implemented")
+}
+func (UnimplementedManagementServer) ListSubscriptionRequests(context.Context, *ListSubscriptionRequestsRequest) (*ListSubscriptionRequestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSubscriptionRequests not implemented")
+}
+func (UnimplementedManagementServer) CreateSubscriptionRequest(context.Context, *CreateSubscriptionRequestRequest) (*SubscriptionRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSubscriptionRequest not implemented")
+}
+func (UnimplementedManagementServer) ListIncomingSubscriptionRequests(context.Context, *ListSubscriptionRequestsRequest) (*ListSubscriptionRequestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListIncomingSubscriptionRequests not implemented")
+}
+func (UnimplementedManagementServer) ApproveSubscriptionRequest(context.Context, *DecideSubscriptionRequestRequest) (*SubscriptionRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveSubscriptionRequest not implemented")
+}
+func (UnimplementedManagementServer) DenySubscriptionRequest(context.Context, *DecideSubscriptionRequestRequest) (*SubscriptionRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DenySubscriptionRequest not implemented")
+}
+func (UnimplementedManagementServer) GetRateLimits(context.Context, *GetRateLimitsRequest) (*RateLimitsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRateLimits not implemented")
+}
+func (UnimplementedManagementServer) GetClientRateLimits(context.Context, *ClientRateLimitsRequest) (*RateLimitsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClientRateLimits not implemented")
+}
+func (UnimplementedManagementServer) SetClientRateLimits(context.Context, *SetClientRateLimitsRequest) (*RateLimitsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetClientRateLimits not implemented")
+}
+func (UnimplementedManagementServer) ResetClientRateLimits(context.Context, *ClientRateLimitsRequest) (*RateLimitsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetClientRateLimits not implemented")
+}
+func (UnimplementedManagementServer) ResetClientSecret(context.Context, *ResetClientSecretRequest) (*RotateSecretResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetClientSecret not implemented")
+}
+func (UnimplementedManagementServer) GetUsage(context.Context, *GetUsageRequest) (*UsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsage not implemented")
+}
+func (UnimplementedManagementServer) GetPublisherUsage(context.Context, *GetPublisherUsageRequest) (*PublisherUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPublisherUsage not implemented")
+}
+func (UnimplementedManagementServer) mustEmbedUnimplementedManagementServer() {}
+func (UnimplementedManagementServer) testEmbeddedByValue()                    {}
+
+// UnsafeManagementServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ManagementServer will
+// result in compilation errors.
+type UnsafeManagementServer interface {
+	mustEmbedUnimplementedManagementServer()
+}
+
+func RegisterManagementServer(s grpc.ServiceRegistrar, srv ManagementServer) {
+	// If the following call pancis, it indicates UnimplementedManagementServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Management_ServiceDesc, srv)
+}
+
+func _Management_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).Register(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_Register_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).Register(ctx, req.(*RegisterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_Authenticate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthenticateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).Authenticate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_Authenticate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).Authenticate(ctx, req.(*AuthenticateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_GetAuthenticatedClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAuthenticatedClientRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).GetAuthenticatedClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_GetAuthenticatedClient_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).GetAuthenticatedClient(ctx, req.(*GetAuthenticatedClientRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_RotateSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).RotateSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_RotateSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).RotateSecret(ctx, req.(*RotateSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_IssueToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthenticateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).IssueToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_IssueToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).IssueToken(ctx, req.(*AuthenticateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_RefreshToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).RefreshToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_RefreshToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).RefreshToken(ctx, req.(*RefreshTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_CreateOrganization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateOrganizationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).CreateOrganization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_CreateOrganization_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).CreateOrganization(ctx, req.(*CreateOrganizationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_GetOrganization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrganizationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).GetOrganization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_GetOrganization_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).GetOrganization(ctx, req.(*GetOrganizationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_AddOrganizationMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddOrganizationMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).AddOrganizationMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_AddOrganizationMember_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).AddOrganizationMember(ctx, req.(*AddOrganizationMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_AddOrganizationAdmin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrganizationAdminRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).AddOrganizationAdmin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_AddOrganizationAdmin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).AddOrganizationAdmin(ctx, req.(*OrganizationAdminRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_RemoveOrganizationAdmin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrganizationAdminRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).RemoveOrganizationAdmin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_RemoveOrganizationAdmin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).RemoveOrganizationAdmin(ctx, req.(*OrganizationAdminRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_ListAPIKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAPIKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).ListAPIKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_ListAPIKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).ListAPIKeys(ctx, req.(*ListAPIKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_CreateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).CreateAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_CreateAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).CreateAPIKey(ctx, req.(*CreateAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_RevokeAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).RevokeAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_RevokeAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).RevokeAPIKey(ctx, req.(*RevokeAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_ListPublishers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPublishersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).ListPublishers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_ListPublishers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).ListPublishers(ctx, req.(*ListPublishersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_CreatePublisher_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePublisherRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).CreatePublisher(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_CreatePublisher_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).CreatePublisher(ctx, req.(*CreatePublisherRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_DeletePublisher_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePublisherRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).DeletePublisher(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_DeletePublisher_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).DeletePublisher(ctx, req.(*DeletePublisherRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_ListPublisherSubscribers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPublisherSubscribersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).ListPublisherSubscribers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_ListPublisherSubscribers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).ListPublisherSubscribers(ctx, req.(*ListPublisherSubscribersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_RemoveSubscriber_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveSubscriberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).RemoveSubscriber(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_RemoveSubscriber_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).RemoveSubscriber(ctx, req.(*RemoveSubscriberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_ListPublisherSubscriptionRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPublisherSubscriptionRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).ListPublisherSubscriptionRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_ListPublisherSubscriptionRequests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).ListPublisherSubscriptionRequests(ctx, req.(*ListPublisherSubscriptionRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_GetPublisherPayload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPublisherPayloadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).GetPublisherPayload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_GetPublisherPayload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).GetPublisherPayload(ctx, req.(*GetPublisherPayloadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_SetPublisherPayload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPublisherPayloadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).SetPublisherPayload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_SetPublisherPayload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).SetPublisherPayload(ctx, req.(*SetPublisherPayloadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_ListSchemas_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSchemasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).ListSchemas(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_ListSchemas_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).ListSchemas(ctx, req.(*ListSchemasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_RegisterSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).RegisterSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_RegisterSchema_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).RegisterSchema(ctx, req.(*RegisterSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_GetSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).GetSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_GetSchema_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).GetSchema(ctx, req.(*GetSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_GetPublisherACL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPublisherACLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).GetPublisherACL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_GetPublisherACL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).GetPublisherACL(ctx, req.(*GetPublisherACLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_SetPublisherACL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPublisherACLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).SetPublisherACL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_SetPublisherACL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).SetPublisherACL(ctx, req.(*SetPublisherACLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_AllowClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AllowClientRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).AllowClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_AllowClient_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).AllowClient(ctx, req.(*AllowClientRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_DisallowClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AllowClientRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).DisallowClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_DisallowClient_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).DisallowClient(ctx, req.(*AllowClientRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_AllowGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AllowGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).AllowGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_AllowGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).AllowGroup(ctx, req.(*AllowGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_DisallowGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AllowGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).DisallowGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_DisallowGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).DisallowGroup(ctx, req.(*AllowGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_ListGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).ListGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_ListGroups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).ListGroups(ctx, req.(*ListGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_CreateGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).CreateGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_CreateGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).CreateGroup(ctx, req.(*CreateGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_SetGroupMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetGroupMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).SetGroupMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_SetGroupMembers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).SetGroupMembers(ctx, req.(*SetGroupMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_DeleteGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).DeleteGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_DeleteGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).DeleteGroup(ctx, req.(*DeleteGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_PublishMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).PublishMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_PublishMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).PublishMessage(ctx, req.(*PublishMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_ListSubscriptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSubscriptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).ListSubscriptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_ListSubscriptions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).ListSubscriptions(ctx, req.(*ListSubscriptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_Subscribe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubscribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).Subscribe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_Subscribe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).Subscribe(ctx, req.(*SubscribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_Unsubscribe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnsubscribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).Unsubscribe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_Unsubscribe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).Unsubscribe(ctx, req.(*UnsubscribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_ListSubscriptionRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSubscriptionRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).ListSubscriptionRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_ListSubscriptionRequests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).ListSubscriptionRequests(ctx, req.(*ListSubscriptionRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_CreateSubscriptionRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSubscriptionRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).CreateSubscriptionRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_CreateSubscriptionRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).CreateSubscriptionRequest(ctx, req.(*CreateSubscriptionRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_ListIncomingSubscriptionRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSubscriptionRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).ListIncomingSubscriptionRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_ListIncomingSubscriptionRequests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).ListIncomingSubscriptionRequests(ctx, req.(*ListSubscriptionRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_ApproveSubscriptionRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecideSubscriptionRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).ApproveSubscriptionRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_ApproveSubscriptionRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).ApproveSubscriptionRequest(ctx, req.(*DecideSubscriptionRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_DenySubscriptionRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecideSubscriptionRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).DenySubscriptionRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_DenySubscriptionRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).DenySubscriptionRequest(ctx, req.(*DecideSubscriptionRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_GetRateLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRateLimitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).GetRateLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_GetRateLimits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).GetRateLimits(ctx, req.(*GetRateLimitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_GetClientRateLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientRateLimitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).GetClientRateLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_GetClientRateLimits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).GetClientRateLimits(ctx, req.(*ClientRateLimitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_SetClientRateLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetClientRateLimitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).SetClientRateLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_SetClientRateLimits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).SetClientRateLimits(ctx, req.(*SetClientRateLimitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_ResetClientRateLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClientRateLimitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).ResetClientRateLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_ResetClientRateLimits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).ResetClientRateLimits(ctx, req.(*ClientRateLimitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_ResetClientSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetClientSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).ResetClientSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_ResetClientSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).ResetClientSecret(ctx, req.(*ResetClientSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_GetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).GetUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_GetUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).GetUsage(ctx, req.(*GetUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Management_GetPublisherUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPublisherUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ManagementServer).GetPublisherUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Management_GetPublisherUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ManagementServer).GetPublisherUsage(ctx, req.(*GetPublisherUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Management_ServiceDesc is the grpc.ServiceDesc for Management service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Management_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "messagebroker.v1.Management",
+	HandlerType: (*ManagementServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Register",
+			Handler:    _Management_Register_Handler,
+		},
+		{
+			MethodName: "Authenticate",
+			Handler:    _Management_Authenticate_Handler,
+		},
+		{
+			MethodName: "GetAuthenticatedClient",
+			Handler:    _Management_GetAuthenticatedClient_Handler,
+		},
+		{
+			MethodName: "RotateSecret",
+			Handler:    _Management_RotateSecret_Handler,
+		},
+		{
+			MethodName: "IssueToken",
+			Handler:    _Management_IssueToken_Handler,
+		},
+		{
+			MethodName: "RefreshToken",
+			Handler:    _Management_RefreshToken_Handler,
+		},
+		{
+			MethodName: "CreateOrganization",
+			Handler:    _Management_CreateOrganization_Handler,
+		},
+		{
+			MethodName: "GetOrganization",
+			Handler:    _Management_GetOrganization_Handler,
+		},
+		{
+			MethodName: "AddOrganizationMember",
+			Handler:    _Management_AddOrganizationMember_Handler,
+		},
+		{
+			MethodName: "AddOrganizationAdmin",
+			Handler:    _Management_AddOrganizationAdmin_Handler,
+		},
+		{
+			MethodName: "RemoveOrganizationAdmin",
+			Handler:    _Management_RemoveOrganizationAdmin_Handler,
+		},
+		{
+			MethodName: "ListAPIKeys",
+			Handler:    _Management_ListAPIKeys_Handler,
+		},
+		{
+			MethodName: "CreateAPIKey",
+			Handler:    _Management_CreateAPIKey_Handler,
+		},
+		{
+			MethodName: "RevokeAPIKey",
+			Handler:    _Management_RevokeAPIKey_Handler,
+		},
+		{
+			MethodName: "ListPublishers",
+			Handler:    _Management_ListPublishers_Handler,
+		},
+		{
+			MethodName: "CreatePublisher",
+			Handler:    _Management_CreatePublisher_Handler,
+		},
+		{
+			MethodName: "DeletePublisher",
+			Handler:    _Management_DeletePublisher_Handler,
+		},
+		{
+			MethodName: "ListPublisherSubscribers",
+			Handler:    _Management_ListPublisherSubscribers_Handler,
+		},
+		{
+			MethodName: "RemoveSubscriber",
+			Handler:    _Management_RemoveSubscriber_Handler,
+		},
+		{
+			MethodName: "ListPublisherSubscriptionRequests",
+			Handler:    _Management_ListPublisherSubscriptionRequests_Handler,
+		},
+		{
+			MethodName: "GetPublisherPayload",
+			Handler:    _Management_GetPublisherPayload_Handler,
+		},
+		{
+			MethodName: "SetPublisherPayload",
+			Handler:    _Management_SetPublisherPayload_Handler,
+		},
+		{
+			MethodName: "ListSchemas",
+			Handler:    _Management_ListSchemas_Handler,
+		},
+		{
+			MethodName: "RegisterSchema",
+			Handler:    _Management_RegisterSchema_Handler,
+		},
+		{
+			MethodName: "GetSchema",
+			Handler:    _Management_GetSchema_Handler,
+		},
+		{
+			MethodName: "GetPublisherACL",
+			Handler:    _Management_GetPublisherACL_Handler,
+		},
+		{
+			MethodName: "SetPublisherACL",
+			Handler:    _Management_SetPublisherACL_Handler,
+		},
+		{
+			MethodName: "AllowClient",
+			Handler:    _Management_AllowClient_Handler,
+		},
+		{
+			MethodName: "DisallowClient",
+			Handler:    _Management_DisallowClient_Handler,
+		},
+		{
+			MethodName: "AllowGroup",
+			Handler:    _Management_AllowGroup_Handler,
+		},
+		{
+			MethodName: "DisallowGroup",
+			Handler:    _Management_DisallowGroup_Handler,
+		},
+		{
+			MethodName: "ListGroups",
+			Handler:    _Management_ListGroups_Handler,
+		},
+		{
+			MethodName: "CreateGroup",
+			Handler:    _Management_CreateGroup_Handler,
+		},
+		{
+			MethodName: "SetGroupMembers",
+			Handler:    _Management_SetGroupMembers_Handler,
+		},
+		{
+			MethodName: "DeleteGroup",
+			Handler:    _Management_DeleteGroup_Handler,
+		},
+		{
+			MethodName: "PublishMessage",
+			Handler:    _Management_PublishMessage_Handler,
+		},
+		{
+			MethodName: "ListSubscriptions",
+			Handler:    _Management_ListSubscriptions_Handler,
+		},
+		{
+			MethodName: "Subscribe",
+			Handler:    _Management_Subscribe_Handler,
+		},
+		{
+			MethodName: "Unsubscribe",
+			Handler:    _Management_Unsubscribe_Handler,
+		},
+		{
+			MethodName: "ListSubscriptionRequests",
+			Handler:    _Management_ListSubscriptionRequests_Handler,
+		},
+		{
+			MethodName: "CreateSubscriptionRequest",
+			Handler:    _Management_CreateSubscriptionRequest_Handler,
+		},
+		{
+			MethodName: "ListIncomingSubscriptionRequests",
+			Handler:    _Management_ListIncomingSubscriptionRequests_Handler,
+		},
+		{
+			MethodName: "ApproveSubscriptionRequest",
+			Handler:    _Management_ApproveSubscriptionRequest_Handler,
+		},
+		{
+			MethodName: "DenySubscriptionRequest",
+			Handler:    _Management_DenySubscriptionRequest_Handler,
+		},
+		{
+			MethodName: "GetRateLimits",
+			Handler:    _Management_GetRateLimits_Handler,
+		},
+		{
+			MethodName: "GetClientRateLimits",
+			Handler:    _Management_GetClientRateLimits_Handler,
+		},
+		{
+			MethodName: "SetClientRateLimits",
+			Handler:    _Management_SetClientRateLimits_Handler,
+		},
+		{
+			MethodName: "ResetClientRateLimits",
+			Handler:    _Management_ResetClientRateLimits_Handler,
+		},
+		{
+			MethodName: "ResetClientSecret",
+			Handler:    _Management_ResetClientSecret_Handler,
+		},
+		{
+			MethodName: "GetUsage",
+			Handler:    _Management_GetUsage_Handler,
+		},
+		{
+			MethodName: "GetPublisherUsage",
+			Handler:    _Management_GetPublisherUsage_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "messagebroker.proto",
+}
+
+const (
+	Broker_Stream_FullMethodName = "/messagebroker.v1.Broker/Stream"
+)
+
+// BrokerClient is the client API for Broker service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Broker delivers the messages from a client's subscriptions, the equivalent of the websocket
+// on /ws. The first request on the stream must authenticate the client, after which the stream
+// is one of the client's sessions.
+type BrokerClient interface {
+	Stream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[StreamRequest, StreamResponse], error)
+}
+
+type brokerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBrokerClient(cc grpc.ClientConnInterface) BrokerClient {
+	return &brokerClient{cc}
+}
+
+func (c *brokerClient) Stream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[StreamRequest, StreamResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Broker_ServiceDesc.Streams[0], Broker_Stream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamRequest, StreamResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Broker_StreamClient = grpc.BidiStreamingClient[StreamRequest, StreamResponse]
+
+// BrokerServer is the server API for Broker service.
+// All implementations must embed UnimplementedBrokerServer
+// for forward compatibility.
+//
+// Broker delivers the messages from a client's subscriptions, the equivalent of the websocket
+// on /ws. The first request on the stream must authenticate the client, after which the stream
+// is one of the client's sessions.
+type BrokerServer interface {
+	Stream(grpc.BidiStreamingServer[StreamRequest, StreamResponse]) error
+	mustEmbedUnimplementedBrokerServer()
+}
+
+// UnimplementedBrokerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBrokerServer struct{}
+
+func (UnimplementedBrokerServer) Stream(grpc.BidiStreamingServer[StreamRequest, StreamResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Stream not implemented")
+}
+func (UnimplementedBrokerServer) mustEmbedUnimplementedBrokerServer() {}
+func (UnimplementedBrokerServer) testEmbeddedByValue()                {}
+
+// UnsafeBrokerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BrokerServer will
+// result in compilation errors.
+type UnsafeBrokerServer interface {
+	mustEmbedUnimplementedBrokerServer()
+}
+
+func RegisterBrokerServer(s grpc.ServiceRegistrar, srv BrokerServer) {
+	// If the following call pancis, it indicates UnimplementedBrokerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Broker_ServiceDesc, srv)
+}
+
+func _Broker_Stream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(BrokerServer).Stream(&grpc.GenericServerStream[StreamRequest, StreamResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Broker_StreamServer = grpc.BidiStreamingServer[StreamRequest, StreamResponse]
+
+// Broker_ServiceDesc is the grpc.ServiceDesc for Broker service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Broker_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "messagebroker.v1.Broker",
+	HandlerType: (*BrokerServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Stream",
+			Handler:       _Broker_Stream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "messagebroker.proto",
+}
@@ -0,0 +1,4 @@
+//Package messagebrokerapi holds the gRPC service definition for the message broker, the generated code is in brokerpb
+package messagebrokerapi
+
+//go:generate protoc --go_out=. --go_opt=module=bezberr.com/messagebrokerapi --go-grpc_out=. --go-grpc_opt=module=bezberr.com/messagebrokerapi messagebroker.proto
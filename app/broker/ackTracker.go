@@ -0,0 +1,119 @@
+package broker
+
+import (
+	"sync"
+	"time"
+)
+
+const ackTimeout = 30 * time.Second //how long to wait on acknowledgements before confirming what has been acknowledged
+
+//batch of messages from a subscription waiting on acknowledgements
+type ackBatch struct {
+	pending int
+	acked   []string
+	timer   *time.Timer
+}
+
+//tracks the batches sent to a session whose messages are acknowledged one at a time, e.g. MQTT PUBACKs or STOMP ACKs.
+//a batch is confirmed once every message in it has been settled, or the timeout passes, anything not acknowledged
+//is left to be sent again
+type ackTracker struct {
+	lock     sync.Mutex
+	batches  map[string]*ackBatch //batches waiting on acknowledgements by subscription id
+	messages map[string]string    //subscription id of each message waiting on an acknowledgement
+	confirm  func(subscriptionID string, messageIDs []string)
+}
+
+func newAckTracker(confirm func(subscriptionID string, messageIDs []string)) *ackTracker {
+	return &ackTracker{
+		batches:  make(map[string]*ackBatch),
+		messages: make(map[string]string),
+		confirm:  confirm,
+	}
+}
+
+//start tracking a batch of messages sent from a subscription, acked have already been settled
+func (tracker *ackTracker) track(subscriptionID string, pending []string, acked []string) {
+	if len(pending) == 0 {
+		go tracker.confirm(subscriptionID, acked)
+		return
+	}
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+	for _, id := range pending {
+		tracker.messages[id] = subscriptionID
+	}
+	tracker.batches[subscriptionID] = &ackBatch{
+		pending: len(pending),
+		acked:   acked,
+		timer: time.AfterFunc(ackTimeout, func() {
+			tracker.expire(subscriptionID)
+		}),
+	}
+}
+
+//whether a message is still waiting on an acknowledgement
+func (tracker *ackTracker) waiting(messageID string) bool {
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+	_, exists := tracker.messages[messageID]
+	return exists
+}
+
+//settle a message, if it wasn't acknowledged it will be sent again. returns false if the message wasn't waiting
+func (tracker *ackTracker) settle(messageID string, acknowledged bool) bool {
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+	subscriptionID, exists := tracker.messages[messageID]
+	if !exists {
+		return false
+	}
+	delete(tracker.messages, messageID)
+	batch := tracker.batches[subscriptionID]
+	if acknowledged {
+		batch.acked = append(batch.acked, messageID)
+	}
+	batch.pending--
+	if batch.pending == 0 {
+		batch.timer.Stop()
+		delete(tracker.batches, subscriptionID)
+		go tracker.confirm(subscriptionID, batch.acked)
+	}
+	return true
+}
+
+//the session didn't settle everything in time, confirm what was acknowledged so the rest can be sent again
+func (tracker *ackTracker) expire(subscriptionID string) {
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+	batch, exists := tracker.batches[subscriptionID]
+	if !exists {
+		return
+	}
+	delete(tracker.batches, subscriptionID)
+	for messageID, messageSubscription := range tracker.messages {
+		if messageSubscription == subscriptionID {
+			delete(tracker.messages, messageID)
+		}
+	}
+	go tracker.confirm(subscriptionID, batch.acked)
+}
+
+//confirm the acknowledged messages of a batch through the client's sessions, releasing the batch if none were
+func confirmSessionMessages(sessions *clientSessions, subscriptionID string, messageIDs []string) {
+	messages := []confirmMessageData{}
+	if len(messageIDs) == 0 {
+		//confirming nothing from the subscription releases the whole batch
+		messages = append(messages, confirmMessageData{SubscriptionID: subscriptionID})
+	}
+	for _, id := range messageIDs {
+		messages = append(messages, confirmMessageData{
+			Id:             id,
+			SubscriptionID: subscriptionID,
+		})
+	}
+	forwardConfirmation(sessions.subscriptionManager, &subscriptionManagerConfirmation{
+		messages:               messages,
+		numberConfirmedChannel: make(chan int, 1),
+	})
+}
@@ -0,0 +1,308 @@
+package broker
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/auth"
+)
+
+//response sent to and from the client during authentication
+type jsonAuthResponse struct {
+	Register      bool   `json:"register"`
+	Name          string `json:"name"`
+	UniqueId      string `json:"id"`
+	Secret        string `json:"secret,omitempty"`
+	APIKey        string `json:"api_key,omitempty"`        //authenticate with an API key instead of the id and secret
+	Token         string `json:"token,omitempty"`          //authenticate with an access token from the publisher service
+	Delivery      string `json:"delivery,omitempty"`       //fanout or balanced, how messages are shared between the client's sessions
+	SingleSession bool   `json:"single_session,omitempty"` //close any other sessions the client has open
+}
+
+//client along with its subscriptions, split by how they are consumed
+type brokerClient struct {
+	Id            string
+	Name          string
+	Subscriptions []storage.Subscription //subscriptions consumed by the client's sessions
+	Webhooks      []storage.Subscription //subscriptions pushed to a webhook
+	scope         keyScope               //set if the client authenticated with an API key
+	rateLimits    *storage.RateLimits    //the client's override of the default rate limits
+}
+
+//subscriptions a session which authenticated with an API key can consume and publishers it can publish to, nil for
+//sessions which authenticated with the client's secret and can use all of them
+type keyScope map[string]bool
+
+func newKeyScope(key *storage.APIKey) keyScope {
+	scope := keyScope{}
+	for _, granted := range key.Scopes {
+		if strings.HasPrefix(granted, auth.ScopeSubscribePrefix) || strings.HasPrefix(granted, auth.ScopePublishPrefix) {
+			scope[granted] = true
+		}
+	}
+	return scope
+}
+
+func (scope keyScope) allows(subscriptionID string) bool {
+	return scope == nil || scope[auth.ScopeSubscribePrefix+subscriptionID]
+}
+
+func (scope keyScope) allowsPublish(publisherID string) bool {
+	return scope == nil || scope[auth.ScopePublishPrefix+publisherID]
+}
+
+//whether the client is subscribed to a publisher, given the publisher id of each of its subscriptions, and whether
+//the scope lets the session consume the subscription. an API key can't make new subscriptions so it has to be
+//scoped to an existing one
+func (scope keyScope) subscribedTo(subscriptions map[string]string, publisherID string) (bool, bool) {
+	subscribed := false
+	scoped := scope == nil
+	for subscriptionID, subscribedPublisherID := range subscriptions {
+		if subscribedPublisherID == publisherID {
+			subscribed = true
+			scoped = scoped || scope.allows(subscriptionID)
+		}
+	}
+	return subscribed, scoped
+}
+
+//confirmations for the subscriptions the scope allows, the rest are dropped
+func (scope keyScope) filterConfirmations(messages []confirmMessageData) []confirmMessageData {
+	if scope == nil {
+		return messages
+	}
+	allowed := []confirmMessageData{}
+	for _, message := range messages {
+		if scope.allows(message.SubscriptionID) {
+			allowed = append(allowed, message)
+		}
+	}
+	return allowed
+}
+
+func requestAuthentication(client *clientConnection) (bool, error) {
+	successError := errorSuccess{
+		errorChannel:   make(chan error),
+		successChannel: make(chan bool),
+	}
+
+	//request to send message to client asking them to authenticate
+	go client.send(jsonCommunication{
+		Action:  "authenticate",
+		Message: "Please authenticate",
+	}, successError)
+
+	select {
+	case err := <-successError.errorChannel: //failed to request authentication
+		return false, err
+	case <-successError.successChannel: //success!
+		return true, nil
+	}
+}
+
+func getClientAuthenticationResponse(client *clientConnection, authTimeout time.Duration) (*jsonAuthResponse, error) {
+	var message string
+
+	//attempt to receive message from the client until the auth timeout
+	timeout := time.After(authTimeout)
+	select {
+	case message = <-client.receiveChannel:
+	case <-timeout:
+		client.send(jsonCommunication{
+			Action:  "authentication_failed",
+			Message: "Authentication timed out",
+		}, errorSuccess{})
+		return nil, errors.New("authentication timed out")
+	}
+	authResponse := jsonAuthResponse{}
+	err := json.Unmarshal([]byte(message), &authResponse) //parse response
+	if err != nil {
+		//failed parsing response
+		client.send(jsonCommunication{
+			Action:  "authentication_failed",
+			Message: "Failed authentication",
+		}, errorSuccess{})
+		return nil, err
+	}
+	return &authResponse, nil
+}
+
+//find a client along with its subscriptions
+func findClient(id string, store storage.Store) (*brokerClient, error) {
+	client, err := store.FindClient(id)
+	if err != nil {
+		return nil, err
+	}
+	return newBrokerClient(client), nil
+}
+
+//check the id and secret a client connects with through the server's authenticator, returning storage.ErrNotFound
+//if they're wrong
+func (access clientAccess) authenticateClient(id string, secret string) (*brokerClient, error) {
+	client, err := access.authenticate(id, secret)
+	if err != nil {
+		return nil, err
+	}
+	return newBrokerClient(client), nil
+}
+
+//check an API key through the store, the client's sessions are limited to the subscriptions it's scoped to. returns
+//storage.ErrNotFound if the key is wrong, has expired or been revoked
+func authenticateAPIKey(token string, store storage.Store) (*brokerClient, error) {
+	key, err := auth.AuthenticateAPIKey(store, token, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	client, err := findClient(key.ClientID, store)
+	if err != nil {
+		return nil, err
+	}
+	client.scope = newKeyScope(key)
+	return client, nil
+}
+
+//check an access token issued by the publisher service against the signing secret and the client's current
+//secret. returns storage.ErrNotFound if it's invalid, has expired, was issued before the client's secret was rotated
+//or tokens aren't enabled
+func (access clientAccess) authenticateToken(token string, store storage.Store) (*brokerClient, error) {
+	if access.tokens == nil {
+		return nil, storage.ErrNotFound
+	}
+	client, err := access.tokens.Authenticate(store, token, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return newBrokerClient(client), nil
+}
+
+//what a client can authenticate with, the first of the token, API key or id and secret which is set is checked
+type credentials struct {
+	id     string
+	secret string
+	apiKey string
+	token  string
+}
+
+func (access clientAccess) authenticateCredentials(supplied credentials, store storage.Store) (*brokerClient, error) {
+	if supplied.token != "" {
+		return access.authenticateToken(supplied.token, store)
+	}
+	if supplied.apiKey != "" {
+		return authenticateAPIKey(supplied.apiKey, store)
+	}
+	return access.authenticateClient(supplied.id, supplied.secret)
+}
+
+//split a client's subscriptions by how they're consumed
+func newBrokerClient(client *storage.Client) *brokerClient {
+	clientStruct := brokerClient{
+		Id:            client.ID,
+		Name:          client.Name,
+		Subscriptions: []storage.Subscription{},
+		rateLimits:    client.RateLimits,
+	}
+	for _, sub := range client.Subscriptions {
+		if sub.Type == storage.SubscriptionTypeWebhook {
+			clientStruct.Webhooks = append(clientStruct.Webhooks, sub)
+		} else {
+			clientStruct.Subscriptions = append(clientStruct.Subscriptions, sub)
+		}
+	}
+	return &clientStruct
+}
+
+//find the subscription with the given id amongst the client's subscriptions
+func (client *brokerClient) findSubscription(subscriptionID string) (storage.Subscription, bool) {
+	for _, sub := range client.Subscriptions {
+		if sub.ID == subscriptionID {
+			return sub, true
+		}
+	}
+	for _, sub := range client.Webhooks {
+		if sub.ID == subscriptionID {
+			return sub, true
+		}
+	}
+	return storage.Subscription{}, false
+}
+
+//authenticate a websocket connection, asking the client for its credentials unless a token was given when
+//connecting
+func authenticate(client *clientConnection, store storage.Store, access clientAccess, authTimeout time.Duration, connectAuth *jsonAuthResponse) (*brokerClient, error) {
+	authResponse := connectAuth
+	if authResponse == nil {
+		_, err := requestAuthentication(client)
+		if err != nil {
+			return nil, err
+		}
+
+		authResponse, err = getClientAuthenticationResponse(client, authTimeout)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	//credentials supplied so we're going to see if there is a valid client
+	clientStruct, err := access.authenticateCredentials(credentials{
+		id:     authResponse.UniqueId,
+		secret: authResponse.Secret,
+		apiKey: authResponse.APIKey,
+		token:  authResponse.Token,
+	}, store)
+	if errors.Is(err, storage.ErrNotFound) {
+
+		//not found
+		client.send(jsonCommunication{
+			Action:  "authentication_failed",
+			Message: "Incorrect credentials",
+		}, errorSuccess{}) //not supplying any channels for error/success since we don't really need to block here to check the response
+		return nil, errors.New("incorrect credentials")
+	} else if err != nil {
+
+		//db error
+		client.send(jsonCommunication{
+			Action:  "authentication_failed",
+			Message: "Error occurred",
+		}, errorSuccess{}) //not supplying any channels for error/success since we don't really need to block here to check the response
+		return nil, err
+	}
+
+	//client found
+
+	clientId := clientStruct.Id
+	clientName := clientStruct.Name
+
+	client.id = clientId
+	client.name = clientName
+	client.policy = sessionPolicy{
+		delivery:      authResponse.Delivery,
+		singleSession: authResponse.SingleSession && clientStruct.scope == nil,
+	}
+	client.scope = clientStruct.scope
+
+	//create response for the user with the clients ID and name
+	response := jsonAuthResponse{
+		UniqueId: clientId,
+		Name:     clientName,
+	}
+	successError := errorSuccess{
+		errorChannel:   make(chan error),
+		successChannel: make(chan bool),
+	}
+
+	//request to send success message to the clients
+	go client.send(jsonCommunication{
+		Action: "authentication_successful",
+		Data:   response,
+	}, successError) //this time we do want to check the response so we're supplying channels
+	select {
+	case <-successError.errorChannel: //failed to notify the front end :(
+		return nil, errors.New("failed to notify success")
+	case <-successError.successChannel: //success!
+		return clientStruct, nil
+	}
+
+}
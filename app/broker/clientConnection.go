@@ -1,8 +1,9 @@
-package main
+package broker
 
 import (
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -10,13 +11,14 @@ import (
 
 //struct for managing a client connection
 type clientConnection struct {
-	id                   string           //unique ID of the client
-	name                 string           //unique name of the client
+	sessionInfo
 	connection           *websocket.Conn  //websocket connection
 	sendChannel          chan sendRequest //channel used to send message requests to the send loop
 	sendClosedChannel    chan bool        //channel used to control exiting the send loop when the websocket connection closes
 	receiveClosedChannel chan bool        //channel used to control exiting the receive loop when the websocket connection closes
 	receiveChannel       chan string      //channel messages received in the receive loop are sent out on to be processed
+	closedChannel        chan bool        //closed once the connection has been closed
+	closeOnce            sync.Once
 	subscriptionManager  *subscriptionManager
 }
 
@@ -29,6 +31,7 @@ func (client *clientConnection) receiveLoop(managerChannels connectionManagerCha
 			managerChannels.lostConnection <- client
 			fmt.Println("lost connection")
 			client.close()
+			return
 		}
 
 		//got a message, send it out on the channel
@@ -96,8 +99,40 @@ func (client *clientConnection) send(message interface{}, customErrorSuccess err
 
 }
 
+//send a message out without waiting on the result, skipped if the connection has closed
+func (client *clientConnection) deliver(message interface{}) {
+	select {
+	case client.sendChannel <- sendRequest{message: message}:
+	case <-client.closedChannel:
+	case <-time.After(sessionQueueTimeout):
+		//not keeping up, disconnect it so anything it holds is delivered to the client's other sessions
+		go client.close()
+	}
+}
+
+//websocket sessions receive messages from all of the client's subscriptions, or those its API key is scoped to
+func (client *clientConnection) accepts(subscriptionID string) bool {
+	return client.scope.allows(subscriptionID)
+}
+
+func (client *clientConnection) deliverMessages(messages []jsonMessageItem) {
+	client.deliver(jsonCommunication{
+		Action: "messages",
+		Data:   messages,
+	})
+}
+
+func (client *clientConnection) notify(message jsonCommunication) {
+	client.deliver(message)
+}
+
 //close the websocket connection
 func (client *clientConnection) close() {
+	client.closeOnce.Do(client.closeConnection)
+}
+
+func (client *clientConnection) closeConnection() {
+	close(client.closedChannel)
 	client.connection.Close()
 	timeout := time.After(time.Second * 5)
 	select {
@@ -110,13 +145,4 @@ func (client *clientConnection) close() {
 	case client.sendClosedChannel <- true: //tell the send loop to stop
 	case <-timeout:
 	}
-
-	if client.subscriptionManager != nil {
-		timeout = time.After(time.Second * 5)
-		select {
-		case client.subscriptionManager.cancelManagerChannel <- true: //tell the sub manager to stop
-		case <-timeout:
-		}
-	}
-
 }
@@ -0,0 +1,448 @@
+package broker
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+)
+
+const (
+	deliveryFanOut   = "fanout"   //every session of the client receives every batch of messages
+	deliveryBalanced = "balanced" //batches of messages are handed to the client's sessions in turn
+
+	//longest a batch or notice waits for room in a session's queue before the session is disconnected as too slow,
+	//so one session that isn't keeping up doesn't hold up delivery to the client's other sessions
+	sessionQueueTimeout = 5 * time.Second
+)
+
+//options supplied by the client when authenticating which control how its sessions share messages
+type sessionPolicy struct {
+	delivery      string //how batches are delivered when the client has more than one session
+	singleSession bool   //disconnect any existing sessions when this one connects
+}
+
+//the delivery mode a session asked for, empty if it didn't say
+func requestedDelivery(policy sessionPolicy) string {
+	if policy.delivery == "" || policy.delivery == deliveryBalanced {
+		return policy.delivery
+	}
+	return deliveryFanOut
+}
+
+//returned when a session asks for a different delivery mode than the client's open sessions use
+type deliveryConflictError struct {
+	delivery string //mode of the open sessions
+}
+
+func (err *deliveryConflictError) Error() string {
+	return fmt.Sprintf("The client's open sessions use %s delivery, close them before changing it", err.delivery)
+}
+
+//details of a session returned to the client when listing its sessions
+type sessionDetails struct {
+	Id            string    `json:"id"`
+	RemoteAddress string    `json:"remote_address"`
+	ConnectedAt   time.Time `json:"connected_at"`
+	Current       bool      `json:"current"`
+}
+
+//a connection of a client which the messages from its subscriptions are delivered on, e.g. a websocket or an event stream
+type session interface {
+	info() *sessionInfo
+	accepts(subscriptionID string) bool         //whether the session is consuming the subscription
+	deliverMessages(messages []jsonMessageItem) //queue a batch of messages, closing the session if its queue stays full
+	notify(message jsonCommunication)           //queue a notice such as the server shutting down, the same way
+	close()
+}
+
+//details shared by every type of session
+type sessionInfo struct {
+	id            string          //unique ID of the client
+	name          string          //unique name of the client
+	sessionID     string          //unique ID of this session, a client can have several sessions open at once
+	remoteAddress string          //address the session was opened from
+	connectedAt   time.Time       //time the session was opened
+	policy        sessionPolicy   //session options requested by the client when authenticating
+	scope         keyScope        //subscriptions the session can consume if it authenticated with an API key
+	sessions      *clientSessions //the group of sessions open for the client
+	lost          bool            //the connection closed before the session was added, set by the connection manager
+}
+
+func (info *sessionInfo) info() *sessionInfo {
+	return info
+}
+
+//request to list the sessions of a client, current is the session asking
+type listSessionsRequest struct {
+	current         session
+	responseChannel chan []sessionDetails
+}
+
+//request to add a session to a client along with the subscriptions it consumes
+type addSessionRequest struct {
+	session       session
+	subscriptions []storage.Subscription
+}
+
+//request to remove a session from a client, responds with the number of sessions left open
+type removeSessionRequest struct {
+	session          session
+	remainingChannel chan int
+}
+
+//the latest batch handed out from a subscription and the sessions it went to
+type deliveredBatch struct {
+	messageIDs []string
+	sessions   []session //sessions the batch went to which are still open
+}
+
+//all of the open sessions for a single client, sharing a single subscription manager so that
+//each subscription is only being consumed once no matter how many sessions the client has open
+type clientSessions struct {
+	clientID             string
+	delivery             string
+	sessions             []session
+	nextSession          int                        //index of the session to receive the next batch when load balancing
+	subscriptionManager  *subscriptionManager       //manager running the subscriptions for all of the sessions
+	cluster              *clusterManager            //passed on to subscriptions added after the sessions started
+	settings             settings                   //passed on to the subscriptions
+	messagesChannel      chan []jsonMessageItem     //channel the subscription manager sends messages out on to be delivered to the sessions
+	started              map[string]bool            //subscriptions the subscription manager has been given
+	delivered            map[string]*deliveredBatch //latest batch from each subscription, released if its sessions all go
+	addSessionChannel    chan addSessionRequest     //add a newly authenticated session
+	subscribeChannel     chan storage.Subscription  //a subscription the client has just made
+	removeSessionChannel chan removeSessionRequest  //remove a closed session
+	listSessionsChannel  chan listSessionsRequest   //list the open sessions
+	noticeChannel        chan jsonCommunication     //notices for the client, e.g. a request to subscribe to one of its publishers
+	drainChannel         chan *drainRequest         //the server is shutting down
+	drainedChannel       chan bool                  //the subscription manager has finished draining
+	drainRequest         *drainRequest              //drain currently in progress
+	closeChannel         chan bool                  //stop the loop once there are no sessions left
+	stoppedChannel       chan bool                  //closed once the loop has stopped
+}
+
+func newClientSessions(clientID string, policy sessionPolicy) *clientSessions {
+	delivery := requestedDelivery(policy)
+	if delivery == "" {
+		delivery = deliveryFanOut
+	}
+	return &clientSessions{
+		clientID:             clientID,
+		delivery:             delivery,
+		sessions:             []session{},
+		messagesChannel:      make(chan []jsonMessageItem),
+		started:              make(map[string]bool),
+		delivered:            make(map[string]*deliveredBatch),
+		addSessionChannel:    make(chan addSessionRequest),
+		subscribeChannel:     make(chan storage.Subscription),
+		removeSessionChannel: make(chan removeSessionRequest),
+		listSessionsChannel:  make(chan listSessionsRequest),
+		noticeChannel:        make(chan jsonCommunication, noticeBuffer),
+		drainChannel:         make(chan *drainRequest),
+		drainedChannel:       make(chan bool, 1),
+		closeChannel:         make(chan bool),
+		stoppedChannel:       make(chan bool),
+	}
+}
+
+//refuse a session asking for a different delivery mode than the group's, the mode is set by the first session
+func (group *clientSessions) checkDelivery(policy sessionPolicy) error {
+	delivery := requestedDelivery(policy)
+	if delivery != "" && delivery != group.delivery {
+		return &deliveryConflictError{delivery: group.delivery}
+	}
+	return nil
+}
+
+//start the subscription manager shared by the sessions, subscriptions are added to it as sessions consuming them
+//are added
+func (group *clientSessions) startSubscriptions(store storage.Store, cluster *clusterManager, settings settings) {
+	group.cluster = cluster
+	group.settings = settings
+	group.subscriptionManager = &subscriptionManager{
+		subscriptions:             map[string]*subscription{},
+		newSubscriptionChannel:    make(chan *subscription),
+		confirmChannel:            make(chan *subscriptionManagerConfirmation),
+		removeSubscriptionChannel: make(chan string),
+		cancelReceiveChannel:      make(chan bool),
+		cancelManagerChannel:      make(chan bool),
+		drainChannel:              make(chan *drainRequest),
+		drainingChannel:           make(chan bool),
+		sendToClientChannel:       group.messagesChannel,
+	}
+
+	go group.subscriptionManager.managerLoop(store)
+}
+
+//start consuming the subscriptions which haven't been started already
+func (group *clientSessions) consume(subscriptions []storage.Subscription) {
+	for _, sub := range subscriptions {
+		if group.started[sub.ID] {
+			continue
+		}
+		group.started[sub.ID] = true
+		go group.addSubscription(sub)
+	}
+}
+
+//pass a subscription the client has just made on to the session loop, given up on if the loop has stopped
+func (group *clientSessions) subscribe(sub storage.Subscription) {
+	select {
+	case group.subscribeChannel <- sub:
+	case <-time.After(time.Second * 5):
+	}
+}
+
+//whether a subscription the client has just made should be consumed, event streams only consume the
+//subscription they were opened for so it's only needed if another type of session is open
+func (group *clientSessions) consumesNewSubscriptions() bool {
+	for _, session := range group.sessions {
+		if _, streaming := session.(*eventStream); !streaming {
+			return true
+		}
+	}
+	return false
+}
+
+//start consuming a subscription, e.g. one the client has just subscribed to. given up on if the subscriptions
+//have been stopped as the last session has gone
+func (group *clientSessions) addSubscription(sub storage.Subscription) {
+	newSubscription := &subscription{
+		id:                      sub.ID,
+		publisherID:             sub.PublisherID,
+		clientID:                group.clientID,
+		cluster:                 group.cluster,
+		pollInterval:            group.settings.pollInterval,
+		batchSize:               group.settings.batchSize,
+		cancelChannel:           make(chan bool),
+		messagesChannel:         make(chan []jsonMessageItem),
+		receiveConfirmedChannel: make(chan *subscriptionMessagesConfirmation),
+	}
+	select {
+	case group.subscriptionManager.newSubscriptionChannel <- newSubscription:
+	case <-time.After(time.Second * 5):
+	}
+}
+
+//release a batch no session is left to confirm so the subscription isn't left waiting on a confirmation
+func (group *clientSessions) release(subscriptionID string, messageIDs []string) {
+	messages := []confirmMessageData{}
+	for _, id := range messageIDs {
+		messages = append(messages, confirmMessageData{
+			Id:             id,
+			SubscriptionID: subscriptionID,
+		})
+	}
+	confirmation := subscriptionManagerConfirmation{
+		messages:               messages,
+		release:                true,
+		numberConfirmedChannel: make(chan int, 1),
+	}
+	forwardConfirmation(group.subscriptionManager, &confirmation)
+}
+
+//stop the subscriptions and the session loop once the last session has gone
+func (group *clientSessions) stop() {
+	timeout := time.After(time.Second * 5)
+	select {
+	case group.subscriptionManager.cancelManagerChannel <- true:
+	case <-timeout:
+	}
+	timeout = time.After(time.Second * 5)
+	select {
+	case group.closeChannel <- true:
+	case <-timeout:
+	}
+}
+
+//hand out a batch of messages to the sessions consuming each subscription
+func (group *clientSessions) deliver(messages []jsonMessageItem) {
+	subscriptionMessages := make(map[string][]jsonMessageItem)
+	subscriptionOrder := []string{}
+	for _, message := range messages {
+		if _, exists := subscriptionMessages[message.SubscriptionID]; !exists {
+			subscriptionOrder = append(subscriptionOrder, message.SubscriptionID)
+		}
+		subscriptionMessages[message.SubscriptionID] = append(subscriptionMessages[message.SubscriptionID], message)
+	}
+	delivered := make(map[string][]session)
+
+	if group.delivery == deliveryFanOut {
+		for _, session := range group.sessions {
+			sessionMessages := []jsonMessageItem{}
+			for _, message := range messages {
+				if session.accepts(message.SubscriptionID) {
+					sessionMessages = append(sessionMessages, message)
+				}
+			}
+			for _, subscriptionID := range subscriptionOrder {
+				if session.accepts(subscriptionID) {
+					delivered[subscriptionID] = append(delivered[subscriptionID], session)
+				}
+			}
+			if len(sessionMessages) > 0 {
+				session.deliverMessages(sessionMessages)
+			}
+		}
+	} else {
+		//load balancing, each subscription's messages go to the next session in turn which is consuming it
+		for _, subscriptionID := range subscriptionOrder {
+			for i := 0; i < len(group.sessions); i++ {
+				group.nextSession = group.nextSession % len(group.sessions)
+				session := group.sessions[group.nextSession]
+				group.nextSession++
+				if session.accepts(subscriptionID) {
+					session.deliverMessages(subscriptionMessages[subscriptionID])
+					delivered[subscriptionID] = append(delivered[subscriptionID], session)
+					break
+				}
+			}
+		}
+	}
+
+	for _, subscriptionID := range subscriptionOrder {
+		messageIDs := unconfirmedMessages(subscriptionMessages[subscriptionID], []string{})
+		if len(delivered[subscriptionID]) == 0 {
+			delete(group.delivered, subscriptionID)
+			go group.release(subscriptionID, messageIDs)
+			continue
+		}
+		group.delivered[subscriptionID] = &deliveredBatch{messageIDs: messageIDs, sessions: delivered[subscriptionID]}
+	}
+}
+
+func (group *clientSessions) addSession(newSession session) {
+	if newSession.info().policy.singleSession {
+		//the new session replaces any which were already open
+		for _, existing := range group.sessions {
+			existing.notify(jsonCommunication{
+				Action:  "session_replaced",
+				Message: "A new session has been opened for this client",
+				Data: map[string]string{
+					"session_id": newSession.info().sessionID,
+				},
+			})
+			go existing.close()
+		}
+	}
+	group.sessions = append(group.sessions, newSession)
+}
+
+//remove a closed session, releasing any batch it was the last session holding so the subscription can deliver
+//it to the sessions still open rather than waiting on a confirmation which won't come
+func (group *clientSessions) removeSession(closed session) int {
+	for i, existing := range group.sessions {
+		if existing == closed {
+			group.sessions = append(group.sessions[:i], group.sessions[i+1:]...)
+			break
+		}
+	}
+	for subscriptionID, batch := range group.delivered {
+		remaining := []session{}
+		for _, holder := range batch.sessions {
+			if holder != closed {
+				remaining = append(remaining, holder)
+			}
+		}
+		batch.sessions = remaining
+		if len(remaining) == 0 {
+			delete(group.delivered, subscriptionID)
+			if len(group.sessions) > 0 {
+				go group.release(subscriptionID, batch.messageIDs)
+			}
+		}
+	}
+	return len(group.sessions)
+}
+
+func (group *clientSessions) listSessions(current session) []sessionDetails {
+	details := []sessionDetails{}
+	for _, session := range group.sessions {
+		info := session.info()
+		details = append(details, sessionDetails{
+			Id:            info.sessionID,
+			RemoteAddress: info.remoteAddress,
+			ConnectedAt:   info.connectedAt,
+			Current:       session == current,
+		})
+	}
+	return details
+}
+
+//tell the sessions the server is going away then wait for the subscription manager to drain
+func (group *clientSessions) drain(request *drainRequest) {
+	group.drainRequest = request
+	for _, session := range group.sessions {
+		//spread the reconnects out so the clients don't all arrive at once
+		reconnectAfter := request.reconnectAfter + time.Duration(rand.Int63n(int64(request.reconnectAfter)+1))
+		session.notify(jsonCommunication{
+			Action:  "server_shutting_down",
+			Message: "The server is shutting down, please reconnect",
+			Data: map[string]int64{
+				"reconnect_after_ms": reconnectAfter.Milliseconds(),
+			},
+		})
+	}
+	go func(manager *subscriptionManager) {
+		managerRequest := drainRequest{
+			deadline:       request.deadline,
+			drainedChannel: make(chan bool, 1),
+		}
+		drained := false
+		timeout := time.After(time.Until(request.deadline))
+		select {
+		case manager.drainChannel <- &managerRequest:
+			select {
+			case drained = <-managerRequest.drainedChannel:
+			case <-timeout:
+			}
+		case <-timeout:
+		}
+		group.drainedChannel <- drained
+	}(group.subscriptionManager)
+}
+
+//loop running in a goroutine handling the sessions of a client and delivering messages to them
+func (group *clientSessions) loop() {
+	closed := false
+	for {
+		select {
+		case messages := <-group.messagesChannel: //messages from the subscription manager
+			group.deliver(messages)
+		case request := <-group.addSessionChannel:
+			group.addSession(request.session)
+			group.consume(request.subscriptions)
+		case sub := <-group.subscribeChannel:
+			if group.consumesNewSubscriptions() {
+				group.consume([]storage.Subscription{sub})
+			}
+		case request := <-group.removeSessionChannel:
+			request.remainingChannel <- group.removeSession(request.session)
+		case request := <-group.listSessionsChannel:
+			request.responseChannel <- group.listSessions(request.current)
+		case notice := <-group.noticeChannel:
+			group.notifySessions(notice)
+		case request := <-group.drainChannel:
+			group.drain(request)
+		case drained := <-group.drainedChannel: //finished waiting on confirmations, close the sessions
+			for _, session := range group.sessions {
+				go session.close()
+			}
+			group.drainRequest.drainedChannel <- drained
+			group.drainRequest = nil
+		case <-group.closeChannel:
+			closed = true
+			//the last session went while draining, there's nothing left to wait on
+			if group.drainRequest != nil {
+				group.drainRequest.drainedChannel <- true
+			}
+			close(group.stoppedChannel)
+			fmt.Println("client sessions stop")
+		}
+		if closed {
+			break
+		}
+	}
+}
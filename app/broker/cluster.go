@@ -0,0 +1,202 @@
+package broker
+
+import (
+	"fmt"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+)
+
+const (
+	leaderLease = "leader" //lease held by the instance which runs the singleton jobs
+
+	clusterLeaseDuration = 15 * time.Second //how long a lease lasts without being renewed
+	heartbeatInterval    = 5 * time.Second  //how often an instance renews its registration, leadership and subscriptions
+)
+
+//coordinates running several broker instances against the same storage. a nil manager means the
+//broker is running on its own, in which case it is always the leader and owns every subscription
+type clusterManager struct {
+	instanceID           string
+	address              string
+	store                storage.Store
+	leaderRequestChannel chan chan bool        //ask the heartbeat loop whether this instance is the leader
+	holdChannel          chan subscriptionHold //tell the heartbeat loop which subscription leases to renew
+	subscriptions        map[string]bool       //subscriptions this instance holds, only used by the heartbeat loop
+	stopChannel          chan bool
+	stoppedChannel       chan bool //closed once the instance has left the cluster
+}
+
+func newClusterManager(instanceID string, address string, store storage.Store) *clusterManager {
+	return &clusterManager{
+		instanceID:           instanceID,
+		address:              address,
+		store:                store,
+		leaderRequestChannel: make(chan chan bool),
+		holdChannel:          make(chan subscriptionHold),
+		subscriptions:        make(map[string]bool),
+		stopChannel:          make(chan bool),
+		stoppedChannel:       make(chan bool),
+	}
+}
+
+//a subscription this instance has claimed or let go of
+type subscriptionHold struct {
+	subscriptionID string
+	held           bool
+}
+
+func subscriptionLease(subscriptionID string) string {
+	return "subscription:" + subscriptionID
+}
+
+//acquire or renew a lease, returns false if it's held by another live instance
+func (cluster *clusterManager) acquireLease(name string) (bool, error) {
+	return cluster.store.AcquireLease(name, cluster.instanceID, time.Now().Add(clusterLeaseDuration))
+}
+
+func (cluster *clusterManager) releaseLease(name string) {
+	err := cluster.store.ReleaseLease(name, cluster.instanceID)
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+}
+
+//claim or renew ownership of a subscription so only one instance delivers its messages at a time
+func (cluster *clusterManager) claimSubscription(subscriptionID string) bool {
+	if cluster == nil {
+		return true
+	}
+	owned, err := cluster.acquireLease(subscriptionLease(subscriptionID))
+	if err != nil {
+		fmt.Println(err.Error())
+		return false
+	}
+	cluster.hold(subscriptionID, owned)
+	return owned
+}
+
+//give up ownership of a subscription so another instance can pick it up straight away
+func (cluster *clusterManager) releaseSubscription(subscriptionID string) {
+	if cluster == nil {
+		return
+	}
+	//stop the heartbeat renewing it first, otherwise it could take the lease straight back
+	cluster.hold(subscriptionID, false)
+	cluster.releaseLease(subscriptionLease(subscriptionID))
+}
+
+//have the heartbeat renew the lease of a subscription, or stop renewing it. the heartbeat keeps the lease while
+//whatever holds the subscription is blocked, on a slow session or webhook, for longer than the lease lasts
+func (cluster *clusterManager) hold(subscriptionID string, held bool) {
+	select {
+	case cluster.holdChannel <- subscriptionHold{subscriptionID: subscriptionID, held: held}:
+	case <-cluster.stopChannel:
+	}
+}
+
+//renew the leases of the subscriptions this instance holds, forgetting any another instance has taken
+func (cluster *clusterManager) renewSubscriptions() {
+	for subscriptionID := range cluster.subscriptions {
+		owned, err := cluster.acquireLease(subscriptionLease(subscriptionID))
+		if err != nil {
+			fmt.Println(err.Error())
+			continue
+		}
+		if !owned {
+			delete(cluster.subscriptions, subscriptionID)
+		}
+	}
+}
+
+//whether this instance should be running the singleton jobs
+func (cluster *clusterManager) isLeader() bool {
+	if cluster == nil {
+		return true
+	}
+	responseChannel := make(chan bool)
+	select {
+	case cluster.leaderRequestChannel <- responseChannel:
+		return <-responseChannel
+	case <-cluster.stopChannel:
+		return false
+	}
+}
+
+//register the instance or renew its registration
+func (cluster *clusterManager) register() error {
+	return cluster.store.RegisterInstance(cluster.instanceID, cluster.address, time.Now().Add(clusterLeaseDuration))
+}
+
+//remove instances which have stopped renewing their registration
+func (cluster *clusterManager) removeExpiredInstances() {
+	err := cluster.store.RemoveExpiredInstances(time.Now())
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+}
+
+//loop running in a goroutine keeping the instance registered and competing for leadership
+func (cluster *clusterManager) heartbeatLoop() {
+	defer close(cluster.stoppedChannel)
+	leader := false
+	closed := false
+	heartbeat := time.After(0)
+	for {
+		select {
+		case <-heartbeat:
+			err := cluster.register()
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+			wasLeader := leader
+			leader, err = cluster.acquireLease(leaderLease)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+			if leader != wasLeader {
+				fmt.Printf("instance %s leader: %t\n", cluster.instanceID, leader)
+			}
+			if leader {
+				cluster.removeExpiredInstances()
+			}
+			cluster.renewSubscriptions()
+			heartbeat = time.After(heartbeatInterval)
+		case responseChannel := <-cluster.leaderRequestChannel:
+			responseChannel <- leader
+		case hold := <-cluster.holdChannel:
+			if hold.held {
+				cluster.subscriptions[hold.subscriptionID] = true
+			} else {
+				delete(cluster.subscriptions, hold.subscriptionID)
+			}
+		case <-cluster.stopChannel:
+			closed = true
+		}
+		if closed {
+			break
+		}
+	}
+	//leave the cluster so the other instances can take over without waiting for the leases to expire
+	if leader {
+		cluster.releaseLease(leaderLease)
+	}
+	for subscriptionID := range cluster.subscriptions {
+		cluster.releaseLease(subscriptionLease(subscriptionID))
+	}
+	err := cluster.store.RemoveInstance(cluster.instanceID)
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+}
+
+func (cluster *clusterManager) stop() {
+	if cluster == nil {
+		return
+	}
+	close(cluster.stopChannel)
+	select {
+	case <-cluster.stoppedChannel:
+	case <-time.After(5 * time.Second):
+	}
+}
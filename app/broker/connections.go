@@ -0,0 +1,420 @@
+package broker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/auth"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	drainTimeout   = 30 * time.Second //how long to wait for in-flight messages to be confirmed when shutting down
+	reconnectAfter = 5 * time.Second  //minimum time clients are asked to wait before reconnecting after a shutdown
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	Subprotocols:    []string{stompSubprotocol}, //connections which don't ask for STOMP use the JSON protocol
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+//struct for reusable success/error channel responses
+type errorSuccess struct {
+	successChannel chan bool
+	errorChannel   chan error
+}
+
+//struct to define JSON messages sent to and from the client
+type jsonCommunication struct {
+	Action  string      `json:"action"`
+	Message string      `json:"message,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+//struct sent to the send channel with the message to be sent + the error/success response channels
+type sendRequest struct {
+	message      interface{}
+	errorSuccess errorSuccess
+}
+
+type confirmMessageData struct {
+	Id             string `json:"id"`              //id of the message being confirmed
+	SubscriptionID string `json:"subscription_id"` //id of the subscription the message was received on
+}
+type confirmRequestData struct {
+	Messages []confirmMessageData `json:"messages"` //slice of messages being confirmed from the client including the message ID and the subscription id
+}
+type confirmRequest struct {
+	Action  string             `json:"action"`
+	Message string             `json:"message"`
+	Data    confirmRequestData `json:"data"`
+}
+
+func handleConfirmMessage(message string, client *clientConnection) {
+	confirmRequest := confirmRequest{}
+	err := json.Unmarshal([]byte(message), &confirmRequest)
+	if err != nil {
+		client.send(jsonCommunication{
+			Action:  "failed_confirmation",
+			Message: "Invalid json format",
+		}, errorSuccess{})
+		return
+	}
+	confirmMessagesStruct := subscriptionManagerConfirmation{
+		messages:               client.scope.filterConfirmations(confirmRequest.Data.Messages),
+		numberConfirmedChannel: make(chan int),
+	}
+	client.subscriptionManager.confirmChannel <- &confirmMessagesStruct
+	confirmed := <-confirmMessagesStruct.numberConfirmedChannel
+	client.send(jsonCommunication{
+		Action: "messages_confirmed",
+		Data: map[string]int{
+			"confirmed": confirmed,
+		},
+	}, errorSuccess{})
+}
+func handleListSessions(client *clientConnection) {
+	request := listSessionsRequest{
+		current:         client,
+		responseChannel: make(chan []sessionDetails),
+	}
+	client.sessions.listSessionsChannel <- request
+	client.send(jsonCommunication{
+		Action: "sessions",
+		Data:   <-request.responseChannel,
+	}, errorSuccess{})
+}
+
+func handleClientMessage(message string, client *clientConnection, store storage.Store) {
+	jsonMsg := jsonCommunication{}
+	err := json.Unmarshal([]byte(message), &jsonMsg)
+	if err != nil {
+		client.send(jsonCommunication{
+			Action:  "invalid_message",
+			Message: "The message sent was incorrectly formatted",
+		}, errorSuccess{})
+		return
+	}
+	switch jsonMsg.Action {
+	case "confirm_messages": //request to confirm that the client received a set of messages from a subscription
+		handleConfirmMessage(message, client)
+	case "list_sessions": //request to list the sessions the client has open
+		handleListSessions(client)
+	}
+}
+
+//loop running in a goroutine to handle messages coming from the client via the websocket
+func clientMessagesLoop(client *clientConnection, store storage.Store) {
+	closed := false
+	for {
+		select {
+		case message := <-client.receiveChannel: //received a message from the client
+			handleClientMessage(message, client, store)
+		case <-client.receiveClosedChannel:
+			closed = true
+			fmt.Println("receive loop stop")
+		}
+		if closed {
+			break
+		}
+	}
+}
+
+//loop running in a goroutine to handle deleting expired messages
+func handleExpiredMessages(store storage.Store, cluster *clusterManager, stopChannel chan bool) {
+
+	for {
+		//only one instance in a cluster needs to be clearing out the messages
+		if !cluster.isLeader() {
+			select {
+			case <-time.After(time.Second * 30):
+				continue
+			case <-stopChannel:
+				fmt.Println("expired messages stop")
+				return
+			}
+		}
+		_, err := store.DeleteExpiredMessages(time.Now())
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+		select {
+		case <-time.After(time.Second * 30):
+		case <-stopChannel:
+			fmt.Println("expired messages stop")
+			return
+		}
+	}
+}
+
+//request to add a newly authenticated connection to the sessions of its client
+type newConnectionRequest struct {
+	session         session
+	subscriptions   []storage.Subscription //subscriptions the session consumes, started if they haven't been already
+	connectionLimit int                    //most sessions the client can have open to this instance at once, 0 for no limit
+	addedChannel    chan error             //receives errShuttingDown, errConnectionLost, a *limits.RateLimitError or a *deliveryConflictError if the connection was refused
+}
+
+//channels for the connection manager
+type connectionManagerChannels struct {
+	newConnection  chan *newConnectionRequest //receive new client connections
+	lostConnection chan session               //channel to remove closed client connections
+	confirm        chan *httpConfirmRequest   //confirm messages received over HTTP
+	notify         chan *clientNotice         //pass a notice on to the open sessions of a client
+	shutdown       chan *drainRequest         //stop accepting connections and drain the open ones
+	draining       chan bool                  //closed once the server starts shutting down, ending pulls waiting for messages
+}
+
+//pass a confirmation on to the subscription manager of the client's open sessions
+func forwardConfirmation(manager *subscriptionManager, confirmation *subscriptionManagerConfirmation) {
+	select {
+	case manager.confirmChannel <- confirmation:
+	case <-time.After(httpConfirmTimeout):
+	}
+}
+
+//drain all of the open sessions at once, responding when they have all finished or the deadline has passed
+func drainSessions(groups []*clientSessions, request *drainRequest) {
+	drainRequests := []*drainRequest{}
+	timeout := time.After(time.Until(request.deadline))
+	for _, group := range groups {
+		groupRequest := drainRequest{
+			deadline:       request.deadline,
+			reconnectAfter: request.reconnectAfter,
+			drainedChannel: make(chan bool, 1),
+		}
+		select {
+		case group.drainChannel <- &groupRequest:
+			drainRequests = append(drainRequests, &groupRequest)
+		case <-group.stoppedChannel: //its last session went after the drain started
+		case <-timeout:
+			request.drainedChannel <- false
+			return
+		}
+	}
+	allDrained := true
+	for _, groupRequest := range drainRequests {
+		select {
+		case drained := <-groupRequest.drainedChannel:
+			allDrained = allDrained && drained
+		case <-timeout:
+			request.drainedChannel <- false
+			return
+		}
+	}
+	request.drainedChannel <- allDrained
+}
+
+//manage client connections
+func connectionManager(channels connectionManagerChannels, store storage.Store, cluster *clusterManager, settings settings) {
+	//map to store the open sessions of each client
+	connections := make(map[string]*clientSessions)
+	//number of sessions each client has open, for the connection limit
+	open := make(map[string]int)
+	draining := false
+	for {
+		select {
+		case request := <-channels.newConnection: //received a new client connection, add it to the client's sessions
+			if draining {
+				request.addedChannel <- errShuttingDown
+				continue
+			}
+			info := request.session.info()
+			if info.lost {
+				//closed while waiting to be added, adding it now would leave it open for good
+				request.addedChannel <- errConnectionLost
+				continue
+			}
+			//a single session replaces the client's other sessions rather than adding to them
+			if request.connectionLimit > 0 && !info.policy.singleSession && open[info.id] >= request.connectionLimit {
+				request.addedChannel <- connectionLimitError()
+				continue
+			}
+			group, exists := connections[info.id]
+			if exists {
+				if err := group.checkDelivery(info.policy); err != nil {
+					request.addedChannel <- err
+					continue
+				}
+			} else {
+				group = newClientSessions(info.id, info.policy)
+				go group.loop()
+				group.startSubscriptions(store, cluster, settings)
+				connections[info.id] = group
+			}
+			info.sessions = group
+			group.addSessionChannel <- addSessionRequest{session: request.session, subscriptions: request.subscriptions}
+			open[info.id]++
+			request.addedChannel <- nil
+		case lostCon := <-channels.lostConnection: //lost a client connection, remove it from the client's sessions
+			info := lostCon.info()
+			group, exists := connections[info.id]
+			if !exists || info.sessions != group {
+				//connection was never authenticated or hasn't been added yet, make sure it isn't added after this
+				info.lost = true
+				continue
+			}
+			remaining := make(chan int)
+			group.removeSessionChannel <- removeSessionRequest{
+				session:          lostCon,
+				remainingChannel: remaining,
+			}
+			open[info.id]--
+			if <-remaining == 0 {
+				//last session for the client has gone so stop its subscriptions
+				delete(connections, info.id)
+				delete(open, info.id)
+				go group.stop()
+			}
+		case request := <-channels.confirm: //confirmation over HTTP
+			group, exists := connections[request.clientID]
+			if exists {
+				//the subscription may be waiting on the confirmation so pass it through the client's sessions
+				go forwardConfirmation(group.subscriptionManager, request.confirmation)
+				continue
+			}
+			messageIDs := []string{}
+			for _, message := range request.confirmation.messages {
+				messageIDs = append(messageIDs, message.Id)
+			}
+			go func(request *httpConfirmRequest) {
+				request.confirmation.numberConfirmedChannel <- confirmMessages(store, request.subscription.PublisherID, request.clientID, messageIDs)
+			}(request)
+		case request := <-channels.notify: //notice for a client, dropped if it has no sessions open
+			group, exists := connections[request.clientID]
+			if !exists {
+				continue
+			}
+			select {
+			case group.noticeChannel <- request.notice:
+			default:
+				fmt.Println("dropped notice for client", request.clientID)
+			}
+			if request.subscription != nil {
+				go group.subscribe(*request.subscription)
+			}
+		case request := <-channels.shutdown: //shutting down, refuse any new connections and drain the existing ones
+			draining = true
+			groups := []*clientSessions{}
+			for _, group := range connections {
+				groups = append(groups, group)
+			}
+			go drainSessions(groups, request)
+		}
+	}
+}
+
+//credentials given when opening a websocket, an access_token in the query or a bearer token in the Authorization
+//header along with the delivery and single_session options. nil if there isn't a token so the client is asked for
+//its credentials
+func connectAuth(r *http.Request) *jsonAuthResponse {
+	query := r.URL.Query()
+	token := query.Get("access_token")
+	if token == "" {
+		token = auth.BearerToken(r.Header.Get("Authorization"))
+	}
+	if token == "" {
+		return nil
+	}
+	return &jsonAuthResponse{
+		Token:         token,
+		Delivery:      query.Get("delivery"),
+		SingleSession: query.Get("single_session") == "true",
+	}
+}
+
+//handle setting up and authenticating a new client connection
+func handleConnection(con *websocket.Conn, connectAuth *jsonAuthResponse, managerChannels connectionManagerChannels, store storage.Store, access clientAccess, settings settings) {
+	client := clientConnection{
+		sessionInfo: sessionInfo{
+			id:            uuid.New().String(),
+			sessionID:     uuid.New().String(),
+			remoteAddress: con.RemoteAddr().String(),
+			connectedAt:   time.Now(),
+		},
+		connection:           con,
+		receiveChannel:       make(chan string),
+		sendChannel:          make(chan sendRequest, 100),
+		receiveClosedChannel: make(chan bool),
+		sendClosedChannel:    make(chan bool),
+		closedChannel:        make(chan bool),
+	}
+
+	//start the receive messages loop
+	go client.receiveLoop(managerChannels)
+
+	//start the send message loop
+	go client.sendLoop()
+
+	//authenticate the client connection
+	authenticatedClient, err := authenticate(&client, store, access, settings.authTimeout, connectAuth)
+
+	if err != nil {
+		client.close()
+		return
+	}
+
+	//add authed client to the manager, joining any other sessions the client already has open
+	request := newConnectionRequest{
+		session:         &client,
+		subscriptions:   authenticatedClient.Subscriptions,
+		connectionLimit: authenticatedClient.connectionLimit(access),
+		addedChannel:    make(chan error),
+	}
+	managerChannels.newConnection <- &request
+	err = <-request.addedChannel
+	if err == errConnectionLost {
+		return
+	}
+	if limited := rateLimitError(err); limited != nil {
+		client.send(jsonCommunication{
+			Action:  "rate_limited",
+			Message: limited.Error(),
+			Data: map[string]interface{}{
+				"limit":          limited.Limit,
+				"retry_after_ms": retryAfterMS(limited),
+			},
+		}, errorSuccess{})
+		client.close()
+		return
+	}
+	if conflict, ok := err.(*deliveryConflictError); ok {
+		client.send(jsonCommunication{
+			Action:  "session_refused",
+			Message: conflict.Error(),
+		}, errorSuccess{})
+		client.close()
+		return
+	}
+	if err != nil {
+		client.send(jsonCommunication{
+			Action:  "server_shutting_down",
+			Message: "The server is shutting down, please reconnect",
+			Data: map[string]int64{
+				"reconnect_after_ms": reconnectAfter.Milliseconds(),
+			},
+		}, errorSuccess{})
+		client.close()
+		return
+	}
+	client.subscriptionManager = client.sessions.subscriptionManager
+
+	client.send(jsonCommunication{
+		Action: "session_started",
+		Data: map[string]string{
+			"session_id": client.sessionID,
+			"delivery":   client.sessions.delivery,
+		},
+	}, errorSuccess{})
+
+	//start receiving messages from the client
+	go clientMessagesLoop(&client, store)
+}
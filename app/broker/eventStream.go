@@ -0,0 +1,131 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+)
+
+const eventStreamKeepAlive = 15 * time.Second //how often a comment is sent to stop proxies closing an idle stream
+
+//session delivering the messages of a single subscription as server-sent events
+type eventStream struct {
+	sessionInfo
+	subscriptionID string
+	eventsChannel  chan string //formatted events waiting to be written out
+	closedChannel  chan bool   //closed once the stream has been closed
+	closeOnce      sync.Once
+}
+
+func newEventStream(info sessionInfo, subscriptionID string) *eventStream {
+	return &eventStream{
+		sessionInfo:    info,
+		subscriptionID: subscriptionID,
+		eventsChannel:  make(chan string, 100),
+		closedChannel:  make(chan bool),
+	}
+}
+
+//format an event, each line of the data needs its own data field
+func formatEvent(id string, event string, retry time.Duration, data interface{}) string {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		fmt.Println(err.Error())
+		return ""
+	}
+	builder := strings.Builder{}
+	if id != "" {
+		builder.WriteString("id: " + id + "\n")
+	}
+	if retry > 0 {
+		builder.WriteString(fmt.Sprintf("retry: %d\n", retry.Milliseconds()))
+	}
+	builder.WriteString("event: " + event + "\n")
+	for _, line := range strings.Split(string(jsonData), "\n") {
+		builder.WriteString("data: " + line + "\n")
+	}
+	builder.WriteString("\n")
+	return builder.String()
+}
+
+func (stream *eventStream) queue(event string) {
+	if event == "" {
+		return
+	}
+	select {
+	case stream.eventsChannel <- event:
+	case <-stream.closedChannel:
+	case <-time.After(sessionQueueTimeout): //not keeping up
+		stream.close()
+	}
+}
+
+func (stream *eventStream) accepts(subscriptionID string) bool {
+	return subscriptionID == stream.subscriptionID
+}
+
+//each message is sent as its own event so its id can be used to resume the stream
+func (stream *eventStream) deliverMessages(messages []jsonMessageItem) {
+	for _, message := range messages {
+		stream.queue(formatEvent(message.Id, "message", 0, message))
+	}
+}
+
+func (stream *eventStream) notify(message jsonCommunication) {
+	retry := time.Duration(0)
+	if data, ok := message.Data.(map[string]int64); ok && message.Action == "server_shutting_down" {
+		//let the browser know how long to wait before reconnecting
+		retry = time.Duration(data["reconnect_after_ms"]) * time.Millisecond
+	}
+	stream.queue(formatEvent("", message.Action, retry, message))
+}
+
+func (stream *eventStream) close() {
+	stream.closeOnce.Do(func() {
+		close(stream.closedChannel)
+	})
+}
+
+//loop writing the queued events to the response until the stream is closed or the client goes away
+func (stream *eventStream) writeLoop(rw http.ResponseWriter, flusher http.Flusher, ctx context.Context) {
+	for {
+		select {
+		case event := <-stream.eventsChannel:
+			_, err := rw.Write([]byte(event))
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-time.After(eventStreamKeepAlive):
+			_, err := rw.Write([]byte(": keep-alive\n\n"))
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-ctx.Done(): //client disconnected
+			return
+		case <-stream.closedChannel:
+			//write out anything still waiting, e.g. the shutdown notice
+			for {
+				select {
+				case event := <-stream.eventsChannel:
+					rw.Write([]byte(event))
+				default:
+					flusher.Flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+//confirm every message in the subscription up to and including the last event the client received before reconnecting
+func resumeFromEvent(store storage.Store, clientID string, sub storage.Subscription, lastEventID string) error {
+	return store.ConfirmMessagesThrough(sub.PublisherID, clientID, lastEventID)
+}
@@ -0,0 +1,307 @@
+package broker
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"bezberr.com/messagebrokerapi/brokerpb"
+	storage "bezberr.com/messagebrokerstorage"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+//gRPC version of the websocket on /ws
+type brokerServer struct {
+	brokerpb.UnimplementedBrokerServer
+	channels connectionManagerChannels
+	store    storage.Store
+	access   clientAccess
+}
+
+func newGRPCServer(channels connectionManagerChannels, store storage.Store, access clientAccess, settings settings) *grpc.Server {
+	server := grpc.NewServer(grpc.MaxRecvMsgSize(settings.maxFrameSize))
+	brokerpb.RegisterBrokerServer(server, &brokerServer{
+		channels: channels,
+		store:    store,
+		access:   access,
+	})
+	return server
+}
+
+//session delivering messages over a gRPC stream
+type grpcStream struct {
+	sessionInfo
+	responsesChannel    chan *brokerpb.StreamResponse //responses waiting to be sent out on the stream
+	closedChannel       chan bool                     //closed once the stream has been closed
+	closeOnce           sync.Once
+	subscriptionManager *subscriptionManager
+}
+
+func (stream *grpcStream) queue(response *brokerpb.StreamResponse) {
+	select {
+	case stream.responsesChannel <- response:
+	case <-stream.closedChannel:
+	case <-time.After(sessionQueueTimeout): //not keeping up
+		stream.close()
+	}
+}
+
+//gRPC sessions receive messages from all of the client's subscriptions, or those its API key is scoped to
+func (stream *grpcStream) accepts(subscriptionID string) bool {
+	return stream.scope.allows(subscriptionID)
+}
+
+func (stream *grpcStream) deliverMessages(messages []jsonMessageItem) {
+	pbMessages := []*brokerpb.Message{}
+	for _, message := range messages {
+		pbMessages = append(pbMessages, &brokerpb.Message{
+			Id:             message.Id,
+			PublisherId:    message.PublisherID,
+			SubscriptionId: message.SubscriptionID,
+			Payload:        message.Payload,
+			SchemaVersion:  int32(message.SchemaVersion),
+		})
+	}
+	stream.queue(&brokerpb.StreamResponse{
+		Response: &brokerpb.StreamResponse_Messages{
+			Messages: &brokerpb.Messages{Messages: pbMessages},
+		},
+	})
+}
+
+func (stream *grpcStream) notify(message jsonCommunication) {
+	data := map[string]string{}
+	switch values := message.Data.(type) {
+	case map[string]string:
+		data = values
+	case map[string]int64:
+		for key, value := range values {
+			data[key] = fmt.Sprint(value)
+		}
+	}
+	stream.queue(&brokerpb.StreamResponse{
+		Response: &brokerpb.StreamResponse_Notice{
+			Notice: &brokerpb.Notice{
+				Action:  message.Action,
+				Message: message.Message,
+				Data:    data,
+			},
+		},
+	})
+}
+
+func (stream *grpcStream) close() {
+	stream.closeOnce.Do(func() {
+		close(stream.closedChannel)
+	})
+}
+
+func authenticationResult(success bool, message string, client *brokerClient) *brokerpb.StreamResponse {
+	result := &brokerpb.AuthenticationResult{
+		Success: success,
+		Message: message,
+	}
+	if client != nil {
+		result.Client = &brokerpb.Client{
+			Id:   client.Id,
+			Name: client.Name,
+		}
+	}
+	return &brokerpb.StreamResponse{
+		Response: &brokerpb.StreamResponse_Authentication{Authentication: result},
+	}
+}
+
+//loop running in a goroutine receiving requests from the stream until it errors
+func receiveStreamRequests(server brokerpb.Broker_StreamServer, requestsChannel chan *brokerpb.StreamRequest, errorChannel chan error) {
+	for {
+		request, err := server.Recv()
+		if err != nil {
+			errorChannel <- err
+			return
+		}
+		requestsChannel <- request
+	}
+}
+
+//authenticate the stream, the client has 30 seconds to send its authentication request
+func (broker *brokerServer) authenticateStream(server brokerpb.Broker_StreamServer, requestsChannel chan *brokerpb.StreamRequest, errorChannel chan error) (*brokerClient, *brokerpb.StreamAuthenticate, error) {
+	var request *brokerpb.StreamRequest
+	select {
+	case request = <-requestsChannel:
+	case err := <-errorChannel:
+		return nil, nil, err
+	case <-time.After(time.Second * 30):
+		server.Send(authenticationResult(false, "Authentication timed out", nil))
+		return nil, nil, status.Error(codes.DeadlineExceeded, "authentication timed out")
+	}
+	auth := request.GetAuthenticate()
+	if auth == nil {
+		server.Send(authenticationResult(false, "Failed authentication", nil))
+		return nil, nil, status.Error(codes.Unauthenticated, "first request must authenticate")
+	}
+	client, err := broker.access.authenticateCredentials(credentials{
+		id:     auth.Id,
+		secret: auth.Secret,
+		apiKey: auth.ApiKey,
+		token:  auth.Token,
+	}, broker.store)
+	if errors.Is(err, storage.ErrNotFound) {
+		server.Send(authenticationResult(false, "Incorrect credentials", nil))
+		return nil, nil, status.Error(codes.Unauthenticated, "incorrect credentials")
+	} else if err != nil {
+		server.Send(authenticationResult(false, "Error occurred", nil))
+		return nil, nil, status.Error(codes.Internal, err.Error())
+	}
+	err = server.Send(authenticationResult(true, "", client))
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, auth, nil
+}
+
+func (broker *brokerServer) confirmMessages(stream *grpcStream, request *brokerpb.ConfirmMessages) {
+	messages := []confirmMessageData{}
+	for _, message := range request.Messages {
+		messages = append(messages, confirmMessageData{
+			Id:             message.Id,
+			SubscriptionID: message.SubscriptionId,
+		})
+	}
+	confirmation := subscriptionManagerConfirmation{
+		messages:               stream.scope.filterConfirmations(messages),
+		numberConfirmedChannel: make(chan int),
+	}
+	stream.subscriptionManager.confirmChannel <- &confirmation
+	confirmed := <-confirmation.numberConfirmedChannel
+	stream.queue(&brokerpb.StreamResponse{
+		Response: &brokerpb.StreamResponse_MessagesConfirmed{
+			MessagesConfirmed: &brokerpb.MessagesConfirmed{Confirmed: int32(confirmed)},
+		},
+	})
+}
+
+func (broker *brokerServer) listSessions(stream *grpcStream) {
+	request := listSessionsRequest{
+		current:         stream,
+		responseChannel: make(chan []sessionDetails),
+	}
+	stream.sessions.listSessionsChannel <- request
+	pbSessions := []*brokerpb.Session{}
+	for _, details := range <-request.responseChannel {
+		pbSessions = append(pbSessions, &brokerpb.Session{
+			Id:            details.Id,
+			RemoteAddress: details.RemoteAddress,
+			ConnectedAt:   details.ConnectedAt.Unix(),
+			Current:       details.Current,
+		})
+	}
+	stream.queue(&brokerpb.StreamResponse{
+		Response: &brokerpb.StreamResponse_Sessions{
+			Sessions: &brokerpb.Sessions{Sessions: pbSessions},
+		},
+	})
+}
+
+//handle a stream in the same way as a websocket connection, authenticate it then deliver messages until either side closes it
+func (broker *brokerServer) Stream(server brokerpb.Broker_StreamServer) error {
+	requestsChannel := make(chan *brokerpb.StreamRequest)
+	errorChannel := make(chan error, 1)
+	go receiveStreamRequests(server, requestsChannel, errorChannel)
+
+	client, auth, err := broker.authenticateStream(server, requestsChannel, errorChannel)
+	if err != nil {
+		return err
+	}
+
+	remoteAddress := ""
+	if p, ok := peer.FromContext(server.Context()); ok {
+		remoteAddress = p.Addr.String()
+	}
+	stream := &grpcStream{
+		sessionInfo: sessionInfo{
+			id:            client.Id,
+			name:          client.Name,
+			sessionID:     uuid.New().String(),
+			remoteAddress: remoteAddress,
+			connectedAt:   time.Now(),
+			policy: sessionPolicy{
+				delivery:      auth.Delivery,
+				singleSession: auth.SingleSession && client.scope == nil,
+			},
+			scope: client.scope,
+		},
+		responsesChannel: make(chan *brokerpb.StreamResponse, 100),
+		closedChannel:    make(chan bool),
+	}
+
+	request := newConnectionRequest{
+		session:         stream,
+		subscriptions:   client.Subscriptions,
+		connectionLimit: client.connectionLimit(broker.access),
+		addedChannel:    make(chan error),
+	}
+	broker.channels.newConnection <- &request
+	err = <-request.addedChannel
+	if limited := rateLimitError(err); limited != nil {
+		server.SetHeader(metadata.Pairs("retry-after", strconv.Itoa(limited.RetryAfterSeconds())))
+		return status.Error(codes.ResourceExhausted, limited.Error())
+	}
+	if conflict, ok := err.(*deliveryConflictError); ok {
+		return status.Error(codes.FailedPrecondition, conflict.Error())
+	}
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	stream.subscriptionManager = stream.sessions.subscriptionManager
+	defer func() {
+		stream.close()
+		broker.channels.lostConnection <- stream
+	}()
+
+	stream.queue(&brokerpb.StreamResponse{
+		Response: &brokerpb.StreamResponse_SessionStarted{
+			SessionStarted: &brokerpb.SessionStarted{
+				SessionId: stream.sessionID,
+				Delivery:  stream.sessions.delivery,
+			},
+		},
+	})
+
+	for {
+		select {
+		case response := <-stream.responsesChannel:
+			err := server.Send(response)
+			if err != nil {
+				return err
+			}
+		case request := <-requestsChannel:
+			switch {
+			case request.GetConfirmMessages() != nil: //confirm that the client received a set of messages
+				go broker.confirmMessages(stream, request.GetConfirmMessages())
+			case request.GetListSessions() != nil: //list the sessions the client has open
+				go broker.listSessions(stream)
+			}
+		case <-errorChannel: //client closed the stream
+			fmt.Println("lost stream")
+			return nil
+		case <-stream.closedChannel:
+			//send anything still waiting, e.g. the shutdown notice
+			for {
+				select {
+				case response := <-stream.responsesChannel:
+					server.Send(response)
+				default:
+					return nil
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package broker
+
+import (
+	"fmt"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+)
+
+//how long a client has to confirm a message it has been sent before it can be delivered again
+const messageLeaseDuration = 30 * time.Second
+
+//messages handed out to a client are leased to it so the same message isn't handed to the client's websocket
+//sessions and its pull requests at the same time
+
+//lease up to max of the oldest available messages from a publisher to the client
+func leaseMessages(store storage.Store, publisherID string, clientID string, subscriptionID string, max int64, duration time.Duration) []jsonMessageItem {
+	leased, err := store.LeaseMessages(publisherID, clientID, int(max), time.Now().Add(duration))
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	messages := []jsonMessageItem{}
+	for _, message := range leased {
+		messages = append(messages, jsonMessageItem{
+			Id:             message.ID,
+			PublisherID:    message.PublisherID,
+			SubscriptionID: subscriptionID,
+			Payload:        message.Payload,
+			SchemaVersion:  message.SchemaVersion,
+		})
+	}
+	return messages
+}
+
+//extend the client's leases on messages it is still working on
+func renewLeases(store storage.Store, clientID string, messageIDs []string, duration time.Duration) {
+	err := store.RenewLeases(clientID, messageIDs, time.Now().Add(duration))
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+}
+
+//give up the client's leases so the messages can be delivered again, after the delay if there is one
+func releaseLeases(store storage.Store, publisherID string, clientID string, messageIDs []string, delay time.Duration) int {
+	availableAt := time.Time{}
+	if delay > 0 {
+		availableAt = time.Now().Add(delay)
+	}
+	released, err := store.ReleaseLeases(publisherID, clientID, messageIDs, availableAt)
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	return released
+}
@@ -0,0 +1,402 @@
+package broker
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"github.com/google/uuid"
+)
+
+const (
+	mqttWriteTimeout = 10 * time.Second
+
+	//username a device sends to authenticate with the API key in its password
+	mqttAPIKeyUsername = "api-key"
+)
+
+//session delivering messages to a device over MQTT, each subscription of the client is a topic named after its publisher
+type mqttSession struct {
+	sessionInfo
+	connection    net.Conn
+	writeChannel  chan []byte //encoded packets waiting to be written out
+	closedChannel chan bool   //closed once the connection has been closed
+	closeOnce     sync.Once
+	lock          sync.Mutex
+	filters       map[string]byte   //topic filters the device has subscribed to along with the QoS granted
+	subscriptions map[string]string //publisher id of each of the client's subscriptions
+	nextPacketID  uint16
+	inFlight      map[uint16]string //id of the message sent with each QoS 1 packet id
+	acks          *ackTracker
+	maxPacketSize int //largest packet accepted from the device
+}
+
+func (session *mqttSession) queue(packet []byte) {
+	select {
+	case session.writeChannel <- packet:
+	case <-session.closedChannel:
+	case <-time.After(sessionQueueTimeout): //not keeping up
+		session.close()
+	}
+}
+
+//loop writing queued packets to the connection until it is closed
+func (session *mqttSession) writeLoop() {
+	for {
+		select {
+		case packet := <-session.writeChannel:
+			session.connection.SetWriteDeadline(time.Now().Add(mqttWriteTimeout))
+			_, err := session.connection.Write(packet)
+			if err != nil {
+				session.close()
+				return
+			}
+		case <-session.closedChannel:
+			return
+		}
+	}
+}
+
+//highest QoS granted by the filters matching a publisher's topic, false if the device hasn't subscribed to it
+func (session *mqttSession) subscriptionQoS(publisherID string) (byte, bool) {
+	topic := mqttTopic(publisherID)
+	matched := false
+	qos := byte(0)
+	for filter, granted := range session.filters {
+		if mqttTopicMatches(filter, topic) {
+			matched = true
+			if granted > qos {
+				qos = granted
+			}
+		}
+	}
+	return qos, matched
+}
+
+//MQTT sessions receive messages from the subscriptions matching the device's topic filters which its API key is
+//scoped to
+func (session *mqttSession) accepts(subscriptionID string) bool {
+	session.lock.Lock()
+	defer session.lock.Unlock()
+	publisherID, exists := session.subscriptions[subscriptionID]
+	if !exists || !session.scope.allows(subscriptionID) {
+		return false
+	}
+	_, matched := session.subscriptionQoS(publisherID)
+	return matched
+}
+
+//next free packet id, ids of messages which are no longer waiting on a PUBACK can be reused
+func (session *mqttSession) allocatePacketID() uint16 {
+	for {
+		session.nextPacketID++
+		if session.nextPacketID == 0 {
+			continue
+		}
+		messageID, used := session.inFlight[session.nextPacketID]
+		if !used || !session.acks.waiting(messageID) {
+			return session.nextPacketID
+		}
+	}
+}
+
+//QoS 0 messages are confirmed as soon as they are sent, QoS 1 messages once the device has sent PUBACK for them
+func (session *mqttSession) deliverMessages(messages []jsonMessageItem) {
+	session.lock.Lock()
+	defer session.lock.Unlock()
+	pending := make(map[string][]string)
+	acked := make(map[string][]string)
+	order := []string{}
+	for _, message := range messages {
+		if _, exists := acked[message.SubscriptionID]; !exists {
+			acked[message.SubscriptionID] = []string{}
+			order = append(order, message.SubscriptionID)
+		}
+		qos, _ := session.subscriptionQoS(message.PublisherID)
+		packetID := uint16(0)
+		if qos > 0 {
+			packetID = session.allocatePacketID()
+			session.inFlight[packetID] = message.Id
+			pending[message.SubscriptionID] = append(pending[message.SubscriptionID], message.Id)
+		} else {
+			acked[message.SubscriptionID] = append(acked[message.SubscriptionID], message.Id)
+		}
+		session.queue(encodeMQTTPublish(mqttTopic(message.PublisherID), packetID, qos, []byte(message.Payload)))
+	}
+	for _, subscriptionID := range order {
+		session.acks.track(subscriptionID, pending[subscriptionID], acked[subscriptionID])
+	}
+}
+
+//PUBACK received from the device
+func (session *mqttSession) acknowledge(packetID uint16) {
+	session.lock.Lock()
+	defer session.lock.Unlock()
+	messageID, exists := session.inFlight[packetID]
+	if !exists {
+		return
+	}
+	delete(session.inFlight, packetID)
+	session.acks.settle(messageID, true)
+}
+
+//MQTT has no way of sending notices, the device finds out about a shutdown or being replaced when the connection closes
+func (session *mqttSession) notify(message jsonCommunication) {
+}
+
+func (session *mqttSession) close() {
+	session.closeOnce.Do(func() {
+		close(session.closedChannel)
+		session.connection.Close()
+	})
+}
+
+//handle a SUBSCRIBE topic filter, returning the QoS granted or the failure return code.
+//subscribing to a publisher's topic subscribes the client to the publisher if it isn't already
+func (session *mqttSession) subscribe(filter mqttTopicFilter, store storage.Store) byte {
+	if !validMQTTFilter(filter.filter) {
+		return mqttSubscribeFailure
+	}
+	qos := filter.qos
+	if qos > mqttMaxQoS {
+		qos = mqttMaxQoS
+	}
+	if !strings.ContainsAny(filter.filter, "+#") {
+		publisherID, valid := mqttTopicPublisher(filter.filter)
+		if !valid {
+			return mqttSubscribeFailure
+		}
+		session.lock.Lock()
+		subscribed, scoped := session.scope.subscribedTo(session.subscriptions, publisherID)
+		session.lock.Unlock()
+		if !scoped {
+			//an API key can only consume the subscriptions it's scoped to
+			return mqttSubscribeFailure
+		}
+		if !subscribed {
+			sub, err := subscribeClient(store, session.id, publisherID)
+			if err != nil {
+				fmt.Println(err.Error())
+				return mqttSubscribeFailure
+			}
+			session.lock.Lock()
+			session.subscriptions[sub.ID] = sub.PublisherID
+			session.lock.Unlock()
+			session.sessions.subscribe(*sub)
+		}
+	}
+	session.lock.Lock()
+	session.filters[filter.filter] = qos
+	session.lock.Unlock()
+	return qos
+}
+
+func (session *mqttSession) unsubscribe(filters []string) {
+	session.lock.Lock()
+	defer session.lock.Unlock()
+	for _, filter := range filters {
+		delete(session.filters, filter)
+	}
+}
+
+//publish a message sent by a device to the publisher named by the topic
+func publishMQTTMessage(store storage.Store, access clientAccess, clientID string, scope keyScope, publish *mqttPublishPacket) error {
+	publisherID, valid := mqttTopicPublisher(publish.topic)
+	if !valid {
+		return fmt.Errorf("invalid topic %s", publish.topic)
+	}
+	return access.publishMessage(store, clientID, scope, publisherID, string(publish.payload))
+}
+
+//wait up to the auth timeout for the CONNECT packet and authenticate the device, the username is used as the client id falling back to the client identifier
+//and the password is the client's secret. a device authenticates with an API key by sending it as the password with
+//mqttAPIKeyUsername as the username
+func authenticateMQTT(con net.Conn, reader *bufio.Reader, store storage.Store, access clientAccess, settings settings) (*brokerClient, *mqttConnectPacket, error) {
+	con.SetReadDeadline(time.Now().Add(settings.authTimeout))
+	packet, err := readMQTTPacket(reader, settings.maxFrameSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	if packet.packetType != mqttConnect {
+		return nil, nil, errors.New("expected CONNECT")
+	}
+	connect, err := parseMQTTConnect(packet)
+	if err != nil {
+		return nil, nil, err
+	}
+	if connect.protocolName != mqttProtocolName || connect.protocolLevel != mqttProtocolLevel {
+		con.Write(encodeMQTTConnack(mqttUnacceptableVersion))
+		return nil, nil, errors.New("unsupported protocol version")
+	}
+	supplied := credentials{id: connect.username, secret: connect.password}
+	if connect.username == mqttAPIKeyUsername {
+		supplied = credentials{apiKey: connect.password}
+	} else if supplied.id == "" {
+		supplied.id = connect.clientIdentifier
+	}
+	if supplied.id == "" && supplied.apiKey == "" {
+		con.Write(encodeMQTTConnack(mqttIdentifierRejected))
+		return nil, nil, errors.New("no client id supplied")
+	}
+	client, err := access.authenticateCredentials(supplied, store)
+	if errors.Is(err, storage.ErrNotFound) {
+		con.Write(encodeMQTTConnack(mqttNotAuthorized))
+		return nil, nil, errors.New("incorrect credentials")
+	} else if err != nil {
+		con.Write(encodeMQTTConnack(mqttServerUnavailable))
+		return nil, nil, err
+	}
+	return client, connect, nil
+}
+
+//handle a device connecting over MQTT, it joins the client's sessions in the same way as a websocket
+func handleMQTTConnection(con net.Conn, channels connectionManagerChannels, store storage.Store, access clientAccess, settings settings) {
+	reader := bufio.NewReader(con)
+	client, connect, err := authenticateMQTT(con, reader, store, access, settings)
+	if err != nil {
+		fmt.Println(err.Error())
+		con.Close()
+		return
+	}
+
+	session := &mqttSession{
+		sessionInfo: sessionInfo{
+			id:            client.Id,
+			name:          client.Name,
+			sessionID:     uuid.New().String(),
+			remoteAddress: con.RemoteAddr().String(),
+			connectedAt:   time.Now(),
+			scope:         client.scope,
+		},
+		connection:    con,
+		writeChannel:  make(chan []byte, 100),
+		closedChannel: make(chan bool),
+		filters:       make(map[string]byte),
+		subscriptions: make(map[string]string),
+		inFlight:      make(map[uint16]string),
+		maxPacketSize: settings.maxFrameSize,
+	}
+	session.acks = newAckTracker(func(subscriptionID string, messageIDs []string) {
+		confirmSessionMessages(session.sessions, subscriptionID, messageIDs)
+	})
+	for _, sub := range client.Subscriptions {
+		session.subscriptions[sub.ID] = sub.PublisherID
+	}
+
+	request := newConnectionRequest{
+		session:         session,
+		subscriptions:   client.Subscriptions,
+		connectionLimit: client.connectionLimit(access),
+		addedChannel:    make(chan error),
+	}
+	channels.newConnection <- &request
+	if <-request.addedChannel != nil {
+		con.Write(encodeMQTTConnack(mqttServerUnavailable))
+		con.Close()
+		return
+	}
+
+	go session.writeLoop()
+	session.queue(encodeMQTTConnack(mqttConnectionAccepted))
+
+	disconnected := session.receiveLoop(reader, connect.keepAlive, store, access)
+	if !disconnected && connect.hasWill {
+		//connection was lost without a DISCONNECT so publish the device's will
+		err := publishMQTTMessage(store, access, client.Id, client.scope, &mqttPublishPacket{
+			topic:   connect.willTopic,
+			payload: connect.willMessage,
+		})
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+	}
+
+	session.close()
+	channels.lostConnection <- session
+}
+
+//loop handling the packets sent by the device, returns true if the device disconnected cleanly
+func (session *mqttSession) receiveLoop(reader *bufio.Reader, keepAlive uint16, store storage.Store, access clientAccess) bool {
+	for {
+		//the device has one and a half keep alive periods to send something before it is treated as gone
+		deadline := time.Time{}
+		if keepAlive > 0 {
+			deadline = time.Now().Add(time.Duration(keepAlive) * 1500 * time.Millisecond)
+		}
+		session.connection.SetReadDeadline(deadline)
+		packet, err := readMQTTPacket(reader, session.maxPacketSize)
+		if err != nil {
+			fmt.Println("lost mqtt connection")
+			return false
+		}
+
+		switch packet.packetType {
+		case mqttPublish:
+			publish, err := parseMQTTPublish(packet)
+			if err != nil || publish.qos > mqttMaxQoS {
+				return false
+			}
+			err = throttlePublish(func() error {
+				return publishMQTTMessage(store, access, session.id, session.scope, publish)
+			})
+			if err != nil {
+				//MQTT 3.1.1 has no way to refuse a publish other than closing the connection
+				fmt.Println(err.Error())
+				return false
+			}
+			if publish.qos == 1 {
+				session.queue(encodeMQTTPuback(publish.packetID))
+			}
+		case mqttPuback:
+			r := mqttReader{body: packet.body}
+			packetID := r.readUint16()
+			if r.err != nil {
+				return false
+			}
+			session.acknowledge(packetID)
+		case mqttSubscribe:
+			packetID, filters, err := parseMQTTSubscribe(packet)
+			if err != nil {
+				return false
+			}
+			returnCodes := []byte{}
+			for _, filter := range filters {
+				returnCodes = append(returnCodes, session.subscribe(filter, store))
+			}
+			session.queue(encodeMQTTSuback(packetID, returnCodes))
+		case mqttUnsubscribe:
+			packetID, filters, err := parseMQTTUnsubscribe(packet)
+			if err != nil {
+				return false
+			}
+			session.unsubscribe(filters)
+			session.queue(encodeMQTTUnsuback(packetID))
+		case mqttPingreq:
+			session.queue(encodeMQTTPacket(mqttPingresp, 0, nil))
+		case mqttDisconnect:
+			return true
+		default:
+			//includes a second CONNECT, which is a protocol violation
+			return false
+		}
+	}
+}
+
+//accept MQTT connections until the listener is closed
+func serveMQTT(listener net.Listener, channels connectionManagerChannels, store storage.Store, access clientAccess, settings settings) {
+	for {
+		con, err := listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				fmt.Println(err.Error())
+			}
+			return
+		}
+		go handleMQTTConnection(con, channels, store, access, settings)
+	}
+}
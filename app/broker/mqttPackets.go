@@ -0,0 +1,346 @@
+package broker
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+)
+
+//MQTT 3.1.1 control packet types
+const (
+	mqttConnect     = 1
+	mqttConnack     = 2
+	mqttPublish     = 3
+	mqttPuback      = 4
+	mqttSubscribe   = 8
+	mqttSuback      = 9
+	mqttUnsubscribe = 10
+	mqttUnsuback    = 11
+	mqttPingreq     = 12
+	mqttPingresp    = 13
+	mqttDisconnect  = 14
+)
+
+//CONNACK return codes
+const (
+	mqttConnectionAccepted  = 0
+	mqttUnacceptableVersion = 1
+	mqttIdentifierRejected  = 2
+	mqttServerUnavailable   = 3
+	mqttNotAuthorized       = 5
+)
+
+const (
+	mqttSubscribeFailure  = 0x80 //SUBACK return code for a filter which was refused
+	mqttTopicPrefix       = "publishers/"
+	mqttProtocolName      = "MQTT"
+	mqttProtocolLevel     = 4
+	mqttMaxQoS            = 1 //QoS 2 isn't supported, subscriptions are granted QoS 1 at most
+	mqttMaxRemainingBytes = 4
+)
+
+var errMalformedPacket = errors.New("malformed mqtt packet")
+
+type mqttPacket struct {
+	packetType byte
+	flags      byte
+	body       []byte
+}
+
+//details sent by a device when connecting
+type mqttConnectPacket struct {
+	protocolName     string
+	protocolLevel    byte
+	cleanSession     bool
+	keepAlive        uint16 //seconds
+	clientIdentifier string
+	hasWill          bool
+	willTopic        string
+	willMessage      []byte
+	willQoS          byte
+	username         string
+	password         string
+}
+
+type mqttPublishPacket struct {
+	topic    string
+	packetID uint16
+	qos      byte
+	retain   bool
+	dup      bool
+	payload  []byte
+}
+
+type mqttTopicFilter struct {
+	filter string
+	qos    byte
+}
+
+//read the next control packet from the connection, refusing ones larger than maxSize
+func readMQTTPacket(reader *bufio.Reader, maxSize int) (*mqttPacket, error) {
+	header, err := reader.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	length := 0
+	multiplier := 1
+	for i := 0; ; i++ {
+		if i == mqttMaxRemainingBytes {
+			return nil, errMalformedPacket
+		}
+		encoded, err := reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		length += int(encoded&127) * multiplier
+		multiplier *= 128
+		if encoded&128 == 0 {
+			break
+		}
+	}
+	if length > maxSize {
+		return nil, errors.New("mqtt packet too large")
+	}
+	body := make([]byte, length)
+	_, err = io.ReadFull(reader, body)
+	if err != nil {
+		return nil, err
+	}
+	return &mqttPacket{
+		packetType: header >> 4,
+		flags:      header & 15,
+		body:       body,
+	}, nil
+}
+
+//reads the fields out of the variable header and payload of a packet
+type mqttReader struct {
+	body   []byte
+	offset int
+	err    error
+}
+
+func (r *mqttReader) readUint16() uint16 {
+	if r.err != nil || r.offset+2 > len(r.body) {
+		r.err = errMalformedPacket
+		return 0
+	}
+	value := binary.BigEndian.Uint16(r.body[r.offset:])
+	r.offset += 2
+	return value
+}
+
+func (r *mqttReader) readByte() byte {
+	if r.err != nil || r.offset+1 > len(r.body) {
+		r.err = errMalformedPacket
+		return 0
+	}
+	value := r.body[r.offset]
+	r.offset++
+	return value
+}
+
+func (r *mqttReader) readBytes() []byte {
+	length := int(r.readUint16())
+	if r.err != nil || r.offset+length > len(r.body) {
+		r.err = errMalformedPacket
+		return nil
+	}
+	value := r.body[r.offset : r.offset+length]
+	r.offset += length
+	return value
+}
+
+func (r *mqttReader) readString() string {
+	return string(r.readBytes())
+}
+
+func (r *mqttReader) remaining() int {
+	return len(r.body) - r.offset
+}
+
+func parseMQTTConnect(packet *mqttPacket) (*mqttConnectPacket, error) {
+	r := mqttReader{body: packet.body}
+	connect := mqttConnectPacket{}
+	connect.protocolName = r.readString()
+	connect.protocolLevel = r.readByte()
+	flags := r.readByte()
+	connect.keepAlive = r.readUint16()
+	if r.err != nil || flags&1 != 0 {
+		return nil, errMalformedPacket
+	}
+	connect.cleanSession = flags&2 != 0
+	connect.clientIdentifier = r.readString()
+	if flags&4 != 0 {
+		connect.hasWill = true
+		connect.willQoS = (flags >> 3) & 3
+		connect.willTopic = r.readString()
+		connect.willMessage = r.readBytes()
+	}
+	if flags&128 != 0 {
+		connect.username = r.readString()
+	}
+	if flags&64 != 0 {
+		connect.password = r.readString()
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &connect, nil
+}
+
+func parseMQTTPublish(packet *mqttPacket) (*mqttPublishPacket, error) {
+	r := mqttReader{body: packet.body}
+	publish := mqttPublishPacket{
+		dup:    packet.flags&8 != 0,
+		qos:    (packet.flags >> 1) & 3,
+		retain: packet.flags&1 != 0,
+	}
+	publish.topic = r.readString()
+	if publish.qos > 0 {
+		publish.packetID = r.readUint16()
+	}
+	if r.err != nil || publish.qos == 3 {
+		return nil, errMalformedPacket
+	}
+	publish.payload = r.body[r.offset:]
+	return &publish, nil
+}
+
+func parseMQTTSubscribe(packet *mqttPacket) (uint16, []mqttTopicFilter, error) {
+	if packet.flags != 2 {
+		return 0, nil, errMalformedPacket
+	}
+	r := mqttReader{body: packet.body}
+	packetID := r.readUint16()
+	filters := []mqttTopicFilter{}
+	for r.err == nil && r.remaining() > 0 {
+		filter := mqttTopicFilter{}
+		filter.filter = r.readString()
+		filter.qos = r.readByte()
+		filters = append(filters, filter)
+	}
+	if r.err != nil || len(filters) == 0 {
+		return 0, nil, errMalformedPacket
+	}
+	return packetID, filters, nil
+}
+
+func parseMQTTUnsubscribe(packet *mqttPacket) (uint16, []string, error) {
+	if packet.flags != 2 {
+		return 0, nil, errMalformedPacket
+	}
+	r := mqttReader{body: packet.body}
+	packetID := r.readUint16()
+	filters := []string{}
+	for r.err == nil && r.remaining() > 0 {
+		filters = append(filters, r.readString())
+	}
+	if r.err != nil || len(filters) == 0 {
+		return 0, nil, errMalformedPacket
+	}
+	return packetID, filters, nil
+}
+
+//encode a control packet, working out the remaining length from the body
+func encodeMQTTPacket(packetType byte, flags byte, body []byte) []byte {
+	packet := []byte{packetType<<4 | flags}
+	length := len(body)
+	for {
+		encoded := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			encoded |= 128
+		}
+		packet = append(packet, encoded)
+		if length == 0 {
+			break
+		}
+	}
+	return append(packet, body...)
+}
+
+func appendMQTTString(body []byte, value string) []byte {
+	body = binary.BigEndian.AppendUint16(body, uint16(len(value)))
+	return append(body, value...)
+}
+
+func encodeMQTTConnack(returnCode byte) []byte {
+	return encodeMQTTPacket(mqttConnack, 0, []byte{0, returnCode})
+}
+
+func encodeMQTTPublish(topic string, packetID uint16, qos byte, payload []byte) []byte {
+	body := appendMQTTString([]byte{}, topic)
+	if qos > 0 {
+		body = binary.BigEndian.AppendUint16(body, packetID)
+	}
+	body = append(body, payload...)
+	return encodeMQTTPacket(mqttPublish, qos<<1, body)
+}
+
+func encodeMQTTPuback(packetID uint16) []byte {
+	return encodeMQTTPacket(mqttPuback, 0, binary.BigEndian.AppendUint16([]byte{}, packetID))
+}
+
+func encodeMQTTSuback(packetID uint16, returnCodes []byte) []byte {
+	body := binary.BigEndian.AppendUint16([]byte{}, packetID)
+	return encodeMQTTPacket(mqttSuback, 0, append(body, returnCodes...))
+}
+
+func encodeMQTTUnsuback(packetID uint16) []byte {
+	return encodeMQTTPacket(mqttUnsuback, 0, binary.BigEndian.AppendUint16([]byte{}, packetID))
+}
+
+//whether a topic filter is valid, the multi-level wildcard has to be the last level and wildcards have to fill a level
+func validMQTTFilter(filter string) bool {
+	if filter == "" {
+		return false
+	}
+	levels := strings.Split(filter, "/")
+	for i, level := range levels {
+		if strings.Contains(level, "#") && (level != "#" || i != len(levels)-1) {
+			return false
+		}
+		if strings.Contains(level, "+") && level != "+" {
+			return false
+		}
+	}
+	return true
+}
+
+//whether a topic name matches a topic filter, which can contain the + and # wildcards
+func mqttTopicMatches(filter string, topic string) bool {
+	filterLevels := strings.Split(filter, "/")
+	topicLevels := strings.Split(topic, "/")
+	for i, level := range filterLevels {
+		if level == "#" {
+			return true
+		}
+		if i >= len(topicLevels) {
+			return false
+		}
+		if level != "+" && level != topicLevels[i] {
+			return false
+		}
+	}
+	return len(filterLevels) == len(topicLevels)
+}
+
+//topics are named after the publisher the messages are from
+func mqttTopic(publisherID string) string {
+	return mqttTopicPrefix + publisherID
+}
+
+//id of the publisher a topic refers to
+func mqttTopicPublisher(topic string) (string, bool) {
+	if !strings.HasPrefix(topic, mqttTopicPrefix) {
+		return "", false
+	}
+	publisherID := strings.TrimPrefix(topic, mqttTopicPrefix)
+	if publisherID == "" || strings.ContainsAny(publisherID, "/+#") {
+		return "", false
+	}
+	return publisherID, true
+}
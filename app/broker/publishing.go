@@ -0,0 +1,94 @@
+package broker
+
+import (
+	"errors"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/limits"
+	"bezberr.com/messagebrokerstorage/schema"
+	"github.com/google/uuid"
+)
+
+//subscribe a client to a publisher in the same way as the publisher service, publishers the client isn't allowed
+//to subscribe to or can't see are treated as not found
+func subscribeClient(store storage.Store, clientID string, publisherID string) (*storage.Subscription, error) {
+	publisher, err := store.FindPublisher(publisherID)
+	if err != nil {
+		return nil, err
+	}
+	client, err := store.FindClient(clientID)
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := publisher.Allows(client, store)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, storage.ErrNotFound
+	}
+	sub := storage.Subscription{
+		ID:          uuid.New().String(),
+		PublisherID: publisherID,
+	}
+	err = store.AddSubscription(clientID, sub)
+	if errors.Is(err, storage.ErrConflict) {
+		//subscribed since the device connected, use the existing subscription
+		client, err := findClient(clientID, store)
+		if err != nil {
+			return nil, err
+		}
+		for _, existing := range client.Subscriptions {
+			if existing.PublisherID == publisherID {
+				return &existing, nil
+			}
+		}
+		return nil, errors.New("failed to subscribe")
+	} else if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+//insert a message into a publisher, the client has to manage the publisher in the same way as publishing through
+//the publisher service and an API key has to be scoped to it
+func (access clientAccess) publishMessage(store storage.Store, clientID string, scope keyScope, publisherID string, payload string) error {
+	if !scope.allowsPublish(publisherID) {
+		return errors.New("API key isn't scoped to the publisher")
+	}
+	publisher, err := store.FindPublisher(publisherID)
+	if err != nil {
+		return err
+	}
+	manages, err := publisher.ManagedBy(clientID, store)
+	if err != nil {
+		return err
+	}
+	if !manages {
+		return errors.New("publisher not found")
+	}
+	err = limits.CheckPayload(publisher.Payload, payload, access.maxPayload)
+	if err != nil {
+		return err
+	}
+	schemaVersion, err := schema.CheckSchema(store, publisherID, payload)
+	if err != nil {
+		return err
+	}
+	err = access.allowPublish(store, clientID, publisherID)
+	if err != nil {
+		return err
+	}
+	err = access.quotas.CheckQuotas(store, publisher, len(payload))
+	if err != nil {
+		return err
+	}
+	return store.InsertMessage(storage.Message{
+		ID:            uuid.New().String(),
+		PublisherID:   publisherID,
+		Payload:       payload,
+		CreatedAt:     time.Now(),
+		SchemaVersion: schemaVersion,
+	})
+}
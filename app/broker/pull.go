@@ -0,0 +1,173 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+)
+
+const (
+	defaultPullMax  = 10               //number of messages returned by a pull when no max is given
+	maxPullMax      = 100              //most messages a single pull can return
+	maxPullWait     = 60 * time.Second //longest a pull can wait for messages to arrive
+	maxLease        = 10 * time.Minute //longest a pulled message can be leased for
+	pullPollingRate = time.Second      //how often a waiting pull checks for new messages
+)
+
+type pullResponse struct {
+	Success       bool              `json:"success"`
+	Messages      []jsonMessageItem `json:"messages"`
+	LeaseExpires  time.Time         `json:"lease_expires"` //time the messages must be acknowledged by before they can be delivered again
+	LeaseDuration int64             `json:"lease_seconds"`
+}
+
+type settleRequestBody struct {
+	Messages []string `json:"messages"`        //ids of the messages being acknowledged
+	Delay    string   `json:"delay,omitempty"` //nack only, how long to wait before the messages can be delivered again e.g. 10s
+}
+
+type settleResponse struct {
+	Success bool `json:"success"`
+	Settled int  `json:"settled"`
+}
+
+//read a duration param, e.g. 30s, falling back to the default when it isn't supplied
+func durationParam(query url.Values, name string, defaultValue time.Duration, max time.Duration) (time.Duration, error) {
+	value := query.Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if duration < 0 {
+		return 0, errors.New("duration must not be negative")
+	}
+	if duration > max {
+		duration = max
+	}
+	return duration, nil
+}
+
+//lease messages from a subscription, waiting up to the requested time for some to arrive if there aren't any.
+//returns the status to answer with along with the response
+func handlePull(ctx context.Context, query url.Values, client *brokerClient, sub storage.Subscription, store storage.Store, draining chan bool) (int, []byte) {
+	max := int64(defaultPullMax)
+	if query.Get("max") != "" {
+		parsed, err := strconv.ParseInt(query.Get("max"), 10, 64)
+		if err != nil || parsed < 1 {
+			return http.StatusBadRequest, createMessageResponse(false, "invalid max")
+		}
+		max = parsed
+		if max > maxPullMax {
+			max = maxPullMax
+		}
+	}
+	wait, err := durationParam(query, "wait", 0, maxPullWait)
+	if err != nil {
+		return http.StatusBadRequest, createMessageResponse(false, "invalid wait, "+err.Error())
+	}
+	lease, err := durationParam(query, "lease", messageLeaseDuration, maxLease)
+	if err != nil {
+		return http.StatusBadRequest, createMessageResponse(false, "invalid lease, "+err.Error())
+	}
+	if lease == 0 {
+		return http.StatusBadRequest, createMessageResponse(false, "invalid lease, must be more than 0")
+	}
+
+	timeout := time.After(wait)
+	messages := []jsonMessageItem{}
+	for {
+		messages = leaseMessages(store, sub.PublisherID, client.Id, sub.ID, max, lease)
+		if len(messages) > 0 || wait == 0 {
+			break
+		}
+		closed := false
+		select {
+		case <-time.After(pullPollingRate):
+		case <-timeout:
+			closed = true
+		case <-ctx.Done(): //client gave up waiting
+			closed = true
+		case <-draining: //shutting down, return what there is rather than holding up the shutdown
+			closed = true
+		}
+		if closed {
+			break
+		}
+	}
+	if ctx.Err() != nil && len(messages) > 0 {
+		//client went away while they were leased so there's no one to send them to
+		releaseLeases(store, sub.PublisherID, client.Id, unconfirmedMessages(messages, []string{}), 0)
+		return http.StatusServiceUnavailable, createMessageResponse(false, "request cancelled")
+	}
+
+	response, err := json.Marshal(pullResponse{
+		Success:       true,
+		Messages:      messages,
+		LeaseExpires:  time.Now().Add(lease),
+		LeaseDuration: int64(lease.Seconds()),
+	})
+	if err != nil {
+		//lost the messages so let them go again
+		ids := unconfirmedMessages(messages, []string{})
+		releaseLeases(store, sub.PublisherID, client.Id, ids, 0)
+		return http.StatusInternalServerError, createMessageResponse(false, "failed to pull messages")
+	}
+	return http.StatusOK, response
+}
+
+func readSettleRequest(body io.ReadCloser) (settleRequestBody, error) {
+	defer body.Close()
+	requestBody := settleRequestBody{}
+	bytes, err := io.ReadAll(body)
+	if err != nil {
+		return requestBody, err
+	}
+	err = json.Unmarshal(bytes, &requestBody)
+	return requestBody, err
+}
+
+func createSettleResponse(settled int) []byte {
+	response, _ := json.Marshal(settleResponse{
+		Success: true,
+		Settled: settled,
+	})
+	return response
+}
+
+//acknowledge pulled messages, marking them as received by the client
+func handleAck(body io.ReadCloser, client *brokerClient, sub storage.Subscription, store storage.Store) (int, []byte) {
+	request, err := readSettleRequest(body)
+	if err != nil {
+		return http.StatusBadRequest, createMessageResponse(false, "Invalid json format")
+	}
+	return http.StatusOK, createSettleResponse(confirmMessages(store, sub.PublisherID, client.Id, request.Messages))
+}
+
+//reject pulled messages, releasing the lease so they are delivered again
+func handleNack(body io.ReadCloser, client *brokerClient, sub storage.Subscription, store storage.Store) (int, []byte) {
+	request, err := readSettleRequest(body)
+	if err != nil {
+		return http.StatusBadRequest, createMessageResponse(false, "Invalid json format")
+	}
+	delay := time.Duration(0)
+	if request.Delay != "" {
+		delay, err = time.ParseDuration(request.Delay)
+		if err != nil || delay < 0 {
+			return http.StatusBadRequest, createMessageResponse(false, "invalid delay")
+		}
+		if delay > maxLease {
+			delay = maxLease
+		}
+	}
+	return http.StatusOK, createSettleResponse(releaseLeases(store, sub.PublisherID, client.Id, request.Messages, delay))
+}
@@ -0,0 +1,100 @@
+package broker
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/limits"
+)
+
+//longest an MQTT device's publishes are held back for before the connection is closed
+const mqttMaxThrottle = 30 * time.Second
+
+//sent on addedChannel when a connection is refused as the server is shutting down
+var errShuttingDown = errors.New("The server is shutting down, please reconnect")
+
+//sent on addedChannel when the connection closed before it could be added
+var errConnectionLost = errors.New("The connection was lost")
+
+//most sessions the client can have open at once, 0 for no limit. counted by each instance on its own as the
+//sessions are only known to the instance they're connected to
+func (client *brokerClient) connectionLimit(access clientAccess) int {
+	return access.limiter.Limits(client.rateLimits).Connections
+}
+
+//the rate limit an error is from, nil if it isn't from one
+func rateLimitError(err error) *limits.RateLimitError {
+	limited := &limits.RateLimitError{}
+	if errors.As(err, &limited) {
+		return limited
+	}
+	return nil
+}
+
+//refuse a connection once the client has as many sessions open as it's allowed, it's asked to wait as long as
+//after a shutdown as there's no telling when one of its sessions will close
+func connectionLimitError() *limits.RateLimitError {
+	return &limits.RateLimitError{Limit: limits.LimitConnections, RetryAfter: reconnectAfter}
+}
+
+func retryAfterMS(limited *limits.RateLimitError) int64 {
+	return int64(math.Ceil(float64(limited.RetryAfter.Microseconds()) / 1000))
+}
+
+//respond to an HTTP request refused by a rate limit with 429 Too Many Requests
+func writeRateLimited(rw http.ResponseWriter, limited *limits.RateLimitError) {
+	response, _ := json.Marshal(messageResponse{
+		Success:      false,
+		Message:      limited.Error(),
+		RetryAfterMS: retryAfterMS(limited),
+	})
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Header().Set("Retry-After", strconv.Itoa(limited.RetryAfterSeconds()))
+	rw.WriteHeader(http.StatusTooManyRequests)
+	rw.Write(response)
+}
+
+//take a token from the client's request bucket, the client's override is looked up so changes to it apply straight
+//away
+func (access clientAccess) allowRequest(store storage.Store, clientID string) error {
+	if access.limiter == nil {
+		return nil
+	}
+	client, err := store.FindClient(clientID)
+	if err != nil {
+		return err
+	}
+	return access.limiter.AllowRequest(clientID, client.RateLimits)
+}
+
+//take a token from the client's and publisher's publish buckets
+func (access clientAccess) allowPublish(store storage.Store, clientID string, publisherID string) error {
+	if access.limiter == nil {
+		return nil
+	}
+	client, err := store.FindClient(clientID)
+	if err != nil {
+		return err
+	}
+	return access.limiter.AllowPublish(clientID, client.RateLimits, publisherID)
+}
+
+//MQTT 3.1.1 can't refuse a publish without closing the connection, so a device over its publish limit is slowed
+//down by holding back its publishes until the limit allows them, up to mqttMaxThrottle
+func throttlePublish(publish func() error) error {
+	waited := time.Duration(0)
+	for {
+		err := publish()
+		limited := rateLimitError(err)
+		if limited == nil || waited+limited.RetryAfter > mqttMaxThrottle {
+			return err
+		}
+		time.Sleep(limited.RetryAfter)
+		waited += limited.RetryAfter
+	}
+}
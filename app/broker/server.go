@@ -0,0 +1,516 @@
+//Package broker is the message broker, delivering the messages of the clients' subscriptions over websockets
+//(JSON or STOMP), server-sent events, HTTP pulls, webhooks, gRPC streams and MQTT. It can be embedded in another
+//process, e.g. for integration tests, by creating a Server against a store shared with the publisher service.
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/auth"
+	"bezberr.com/messagebrokerstorage/limits"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+)
+
+const (
+	DefaultHTTPAddress = ":8001"
+	DefaultGRPCAddress = ":8002"
+	DefaultMQTTAddress = ":1883"
+
+	DefaultAuthTimeout  = 30 * time.Second
+	DefaultPollInterval = 2 * time.Second
+	DefaultBatchSize    = 10
+
+	DefaultAllowedOrigin = "http://localhost:8080"
+)
+
+//settings tuning how the broker delivers messages, set by the Server's options
+type settings struct {
+	authTimeout  time.Duration //how long a client has to authenticate after connecting
+	pollInterval time.Duration //wait between checking a subscription for new messages
+	batchSize    int           //most messages sent to a client or webhook at once
+	maxFrameSize int           //largest websocket message, MQTT packet, gRPC message or request body read from a client
+
+	privateWebhooks bool //deliver to webhooks on loopback, private and link-local addresses
+}
+
+//Authenticator checks the id and secret a client presents when connecting over any of the transports, returning
+//the client along with its subscriptions or storage.ErrNotFound to refuse it
+type Authenticator func(clientID string, secret string) (*storage.Client, error)
+
+//Option configures a Server
+type Option func(*Server)
+
+//WithStore sets the store the broker reads clients and messages from, required
+func WithStore(store storage.Store) Option {
+	return func(server *Server) {
+		server.store = store
+	}
+}
+
+//WithHTTPAddress sets the address to accept websocket and HTTP connections on, DefaultHTTPAddress if not set
+func WithHTTPAddress(address string) Option {
+	return func(server *Server) {
+		server.httpAddress = address
+	}
+}
+
+//WithHTTPListener accepts websocket and HTTP connections on a listener which is already open
+func WithHTTPListener(listener net.Listener) Option {
+	return func(server *Server) {
+		server.httpListener = listener
+	}
+}
+
+//WithGRPCAddress sets the address to accept gRPC streams on, DefaultGRPCAddress if not set. empty disables gRPC
+func WithGRPCAddress(address string) Option {
+	return func(server *Server) {
+		server.grpcAddress = address
+	}
+}
+
+//WithGRPCListener accepts gRPC streams on a listener which is already open
+func WithGRPCListener(listener net.Listener) Option {
+	return func(server *Server) {
+		server.grpcListener = listener
+	}
+}
+
+//WithMQTTAddress sets the address to accept MQTT connections on, DefaultMQTTAddress if not set. empty disables MQTT
+func WithMQTTAddress(address string) Option {
+	return func(server *Server) {
+		server.mqttAddress = address
+	}
+}
+
+//WithMQTTListener accepts MQTT connections on a listener which is already open
+func WithMQTTListener(listener net.Listener) Option {
+	return func(server *Server) {
+		server.mqttListener = listener
+	}
+}
+
+//WithCluster coordinates with other instances sharing the store, see the readme. the instance id defaults to the
+//hostname plus a random suffix
+func WithCluster(instanceID string, advertiseAddress string) Option {
+	return func(server *Server) {
+		if instanceID == "" {
+			hostname, _ := os.Hostname()
+			instanceID = hostname + "-" + uuid.New().String()[:8]
+		}
+		server.clusterInstanceID = instanceID
+		server.clusterAddress = advertiseAddress
+	}
+}
+
+//WithAuthenticator replaces how connecting clients are checked, by default any client registered in the store can
+//connect with its id and secret
+func WithAuthenticator(authenticator Authenticator) Option {
+	return func(server *Server) {
+		server.authenticator = authenticator
+	}
+}
+
+//WithTokenSecret accepts the access tokens the publisher service signs with the same secret, on every transport
+//alongside the client's id and secret. tokens aren't accepted if it isn't set
+func WithTokenSecret(secret []byte) Option {
+	return func(server *Server) {
+		server.tokenSecret = secret
+	}
+}
+
+//WithAuthTimeout sets how long a client has to authenticate after connecting, DefaultAuthTimeout if not set
+func WithAuthTimeout(timeout time.Duration) Option {
+	return func(server *Server) {
+		server.settings.authTimeout = timeout
+	}
+}
+
+//WithPollInterval sets how long a subscription waits between checking for new messages, DefaultPollInterval if
+//not set
+func WithPollInterval(interval time.Duration) Option {
+	return func(server *Server) {
+		server.settings.pollInterval = interval
+	}
+}
+
+//WithBatchSize sets the most messages sent to a client or webhook at once, DefaultBatchSize if not set
+func WithBatchSize(size int) Option {
+	return func(server *Server) {
+		server.settings.batchSize = size
+	}
+}
+
+//WithAllowedOrigin sets the origin of the web frontend which may consume subscriptions over HTTP from a browser,
+//DefaultAllowedOrigin if not set
+func WithAllowedOrigin(origin string) Option {
+	return func(server *Server) {
+		server.allowedOrigin = origin
+	}
+}
+
+//WithPrivateWebhooks allows webhooks to be delivered to loopback, private and link-local addresses, e.g. for local
+//development. deliveries are only made to public addresses if not set
+func WithPrivateWebhooks(allow bool) Option {
+	return func(server *Server) {
+		server.settings.privateWebhooks = allow
+	}
+}
+
+//WithRateLimits limits how fast clients can publish and call the HTTP routes, along with how many sessions each
+//client can have open. limits are off if not set
+func WithRateLimits(limits limits.RateLimitConfig) Option {
+	return func(server *Server) {
+		server.rateLimits = limits
+	}
+}
+
+//WithQuotas limits the messages which can be stored for each publisher and client when publishing over MQTT and
+//STOMP, nothing is limited if it isn't set
+func WithQuotas(quotas limits.Quotas) Option {
+	return func(server *Server) {
+		server.quotas = quotas
+	}
+}
+
+//WithPayloadLimits sets the largest payload which can be published over MQTT and STOMP and the largest frame,
+//packet or request body read from a client, limits.DefaultPayloadLimits if not set
+func WithPayloadLimits(payloadLimits limits.PayloadLimits) Option {
+	return func(server *Server) {
+		server.payloadLimits = payloadLimits
+	}
+}
+
+//Server is a message broker which can be started and shut down inside another process
+type Server struct {
+	store             storage.Store
+	authenticator     Authenticator
+	tokenSecret       []byte
+	rateLimits        limits.RateLimitConfig
+	quotas            limits.Quotas
+	payloadLimits     limits.PayloadLimits
+	httpAddress       string
+	httpListener      net.Listener
+	grpcAddress       string
+	grpcListener      net.Listener
+	mqttAddress       string
+	mqttListener      net.Listener
+	clusterInstanceID string
+	clusterAddress    string
+	allowedOrigin     string
+	settings          settings
+	tokens            *auth.TokenSigner //nil if tokens aren't accepted
+	limiter           *limits.RateLimiter
+
+	lock                sync.Mutex
+	started             bool
+	stopped             bool //a server can't be started again once it's been shut down
+	channels            connectionManagerChannels
+	cluster             *clusterManager
+	dispatcher          *webhookDispatcher
+	httpServer          *http.Server
+	grpcServer          *grpc.Server
+	stopExpiredMessages chan bool
+	stopRequestNotices  chan bool
+}
+
+//how the transports authenticate clients and limit what they do, built from the Server's options so every transport
+//checks clients the same way
+type clientAccess struct {
+	authenticate Authenticator
+	tokens       *auth.TokenSigner //nil if tokens aren't accepted
+	limiter      *limits.RateLimiter
+	quotas       limits.Quotas
+	maxPayload   int //largest payload which can be published, publishers can lower it for themselves
+}
+
+//authenticator checking clients against the secret hashes in the store
+func storeAuthenticator(store storage.Store) Authenticator {
+	return func(clientID string, secret string) (*storage.Client, error) {
+		client, err := store.FindClient(clientID)
+		if err != nil {
+			return nil, err
+		}
+		if !auth.CheckSecret(client.SecretHash, secret) {
+			return nil, storage.ErrNotFound
+		}
+		return client, nil
+	}
+}
+
+//New creates a broker, it doesn't accept connections until it's started
+func New(options ...Option) (*Server, error) {
+	server := &Server{
+		httpAddress:   DefaultHTTPAddress,
+		grpcAddress:   DefaultGRPCAddress,
+		mqttAddress:   DefaultMQTTAddress,
+		allowedOrigin: DefaultAllowedOrigin,
+		settings: settings{
+			authTimeout:  DefaultAuthTimeout,
+			pollInterval: DefaultPollInterval,
+			batchSize:    DefaultBatchSize,
+		},
+	}
+	for _, option := range options {
+		option(server)
+	}
+	if server.store == nil {
+		return nil, errors.New("a store is required")
+	}
+	if server.settings.authTimeout <= 0 || server.settings.pollInterval <= 0 || server.settings.batchSize <= 0 {
+		return nil, errors.New("the auth timeout, poll interval and batch size must be positive")
+	}
+	if server.allowedOrigin == "" {
+		return nil, errors.New("an allowed origin is required")
+	}
+	err := server.rateLimits.Validate()
+	if err == nil {
+		err = server.quotas.Validate()
+	}
+	if err == nil {
+		err = server.payloadLimits.Validate()
+	}
+	if err != nil {
+		return nil, err
+	}
+	server.payloadLimits = server.payloadLimits.WithDefaults()
+	server.settings.maxFrameSize = server.payloadLimits.MaxRequestBytes
+	if server.authenticator == nil {
+		server.authenticator = storeAuthenticator(server.store)
+	}
+	server.limiter = limits.NewRateLimiter(server.rateLimits)
+	if len(server.tokenSecret) > 0 {
+		server.tokens = auth.NewTokenSigner(server.tokenSecret, 0, 0)
+	}
+	return server, nil
+}
+
+//the authentication and limits the transports check clients against
+func (server *Server) access() clientAccess {
+	return clientAccess{
+		authenticate: server.authenticator,
+		tokens:       server.tokens,
+		limiter:      server.limiter,
+		quotas:       server.quotas,
+		maxPayload:   server.payloadLimits.MaxPayloadBytes,
+	}
+}
+
+//open a listener for the address unless one was supplied, returning nil if neither was
+func listen(ctx context.Context, listener net.Listener, address string) (net.Listener, error) {
+	if listener != nil || address == "" {
+		return listener, nil
+	}
+	config := net.ListenConfig{}
+	return config.Listen(ctx, "tcp", address)
+}
+
+//Start opens the listeners and starts accepting connections, returning once the broker is ready. ctx only covers
+//starting up, the broker runs until it's shut down
+func (server *Server) Start(ctx context.Context) error {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	if server.started {
+		return errors.New("already started")
+	}
+
+	var err error
+	server.httpListener, err = listen(ctx, server.httpListener, server.httpAddress)
+	if err == nil && server.httpListener == nil {
+		err = errors.New("an HTTP address is required")
+	}
+	if err == nil {
+		server.grpcListener, err = listen(ctx, server.grpcListener, server.grpcAddress)
+	}
+	if err == nil {
+		server.mqttListener, err = listen(ctx, server.mqttListener, server.mqttAddress)
+	}
+	if err != nil {
+		server.closeListeners()
+		return err
+	}
+	server.started = true
+
+	//channels for the client manager
+	server.channels = connectionManagerChannels{
+		newConnection:  make(chan *newConnectionRequest),
+		lostConnection: make(chan session),
+		confirm:        make(chan *httpConfirmRequest),
+		notify:         make(chan *clientNotice),
+		shutdown:       make(chan *drainRequest),
+		draining:       make(chan bool),
+	}
+	store := server.store
+	if server.clusterInstanceID != "" {
+		server.cluster = newClusterManager(server.clusterInstanceID, server.clusterAddress, store)
+		go server.cluster.heartbeatLoop()
+		fmt.Printf("Running in cluster mode as instance %s\n", server.clusterInstanceID)
+	}
+
+	//start the client manager
+	go connectionManager(server.channels, store, server.cluster, server.settings)
+
+	//push the messages of webhook subscriptions
+	server.dispatcher = newWebhookDispatcher(store, server.cluster, server.settings)
+	go server.dispatcher.loop()
+
+	server.stopExpiredMessages = make(chan bool)
+	go handleExpiredMessages(store, server.cluster, server.stopExpiredMessages)
+
+	server.stopRequestNotices = make(chan bool)
+	go notifySubscriptionRequests(store, server.channels.notify, server.settings.pollInterval, server.stopRequestNotices)
+
+	server.httpServer = &http.Server{Handler: server.routes()}
+	go func() {
+		if err := server.httpServer.Serve(server.httpListener); err != http.ErrServerClosed {
+			fmt.Println(err.Error())
+		}
+	}()
+
+	//gRPC streams run alongside the websocket server
+	if server.grpcListener != nil {
+		server.grpcServer = newGRPCServer(server.channels, store, server.access(), server.settings)
+		go func() {
+			if err := server.grpcServer.Serve(server.grpcListener); err != nil {
+				fmt.Println(err.Error())
+			}
+		}()
+	}
+
+	//MQTT gateway for devices which can't use the other transports
+	if server.mqttListener != nil {
+		go serveMQTT(server.mqttListener, server.channels, store, server.access(), server.settings)
+	}
+	return nil
+}
+
+func (server *Server) closeListeners() {
+	for _, listener := range []net.Listener{server.httpListener, server.grpcListener, server.mqttListener} {
+		if listener != nil {
+			listener.Close()
+		}
+	}
+}
+
+func (server *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	//route to open a websocket connection
+	mux.HandleFunc("/ws", func(rw http.ResponseWriter, r *http.Request) {
+		//hijack the request and turn it into a websocket connection
+		con, err := upgrader.Upgrade(rw, r, nil)
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+		//reading a larger message fails and closes the connection
+		con.SetReadLimit(int64(server.settings.maxFrameSize))
+		if con.Subprotocol() == stompSubprotocol {
+			go handleStompConnection(con, connectAuth(r), server.channels, server.store, server.access(), server.settings)
+		} else {
+			//start handling the connection
+			go handleConnection(con, connectAuth(r), server.channels, server.store, server.access(), server.settings)
+		}
+	})
+
+	//routes to consume subscriptions over HTTP
+	mux.HandleFunc("/subscriptions/", func(rw http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(rw, r.Body, int64(server.settings.maxFrameSize))
+		handleSubscriptionRequest(rw, r, server.channels, server.store, server.access(), server.allowedOrigin)
+	})
+	return mux
+}
+
+//HTTPAddr is the address websocket and HTTP connections are accepted on, nil until the broker has started
+func (server *Server) HTTPAddr() net.Addr {
+	return listenerAddr(server.httpListener)
+}
+
+//GRPCAddr is the address gRPC streams are accepted on, nil until the broker has started or if gRPC is disabled
+func (server *Server) GRPCAddr() net.Addr {
+	return listenerAddr(server.grpcListener)
+}
+
+//MQTTAddr is the address MQTT connections are accepted on, nil until the broker has started or if MQTT is disabled
+func (server *Server) MQTTAddr() net.Addr {
+	return listenerAddr(server.mqttListener)
+}
+
+func listenerAddr(listener net.Listener) net.Addr {
+	if listener == nil {
+		return nil
+	}
+	return listener.Addr()
+}
+
+//Shutdown stops accepting connections and drains the open ones, giving messages already sent out until ctx is done
+//or the drain timeout has passed to be confirmed. the store is left open for the caller to close
+func (server *Server) Shutdown(ctx context.Context) error {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	if !server.started || server.stopped {
+		return errors.New("not running")
+	}
+	server.stopped = true
+
+	deadline := time.Now().Add(drainTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	//stop accepting new connections, websockets have been hijacked so are left open to drain while
+	//the shutdown waits on any event streams which are closed once they have drained, and on pulls which return
+	//as soon as draining starts
+	if server.mqttListener != nil {
+		server.mqttListener.Close()
+	}
+	close(server.channels.draining)
+	serverClosed := make(chan error, 1)
+	go func() {
+		serverClosed <- server.httpServer.Shutdown(ctx)
+	}()
+
+	request := drainRequest{
+		deadline:       deadline,
+		reconnectAfter: reconnectAfter,
+		drainedChannel: make(chan bool, 1),
+	}
+	server.channels.shutdown <- &request
+	if !<-request.drainedChannel {
+		fmt.Println("Timed out waiting for messages to be confirmed")
+	}
+	err := <-serverClosed
+
+	//the streams have been closed by the drain so this only has to wait for them to finish sending
+	if server.grpcServer != nil {
+		grpcStopped := make(chan bool)
+		go func() {
+			server.grpcServer.GracefulStop()
+			close(grpcStopped)
+		}()
+		select {
+		case <-grpcStopped:
+		case <-time.After(5 * time.Second):
+			server.grpcServer.Stop()
+		case <-ctx.Done():
+			server.grpcServer.Stop()
+		}
+	}
+
+	close(server.stopExpiredMessages)
+
+	close(server.stopRequestNotices)
+
+	server.dispatcher.stop()
+
+	server.cluster.stop()
+	return err
+}
@@ -0,0 +1,477 @@
+package broker
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	stompWriteTimeout = 10 * time.Second
+)
+
+//STOMP ack modes
+const (
+	stompAckAuto             = "auto"              //messages are confirmed as soon as they are sent
+	stompAckClient           = "client"            //an ACK settles the message and every message sent before it on the subscription
+	stompAckClientIndividual = "client-individual" //an ACK settles just the message
+)
+
+//subscription opened by a SUBSCRIBE frame
+type stompSubscription struct {
+	id          string //id chosen by the client
+	publisherID string
+	ackMode     string
+	unacked     []string //ids of the messages sent which haven't been settled, in the order they were sent
+}
+
+//session speaking STOMP 1.2 over a websocket, each subscription of the client is a destination named after its publisher
+type stompSession struct {
+	sessionInfo
+	connection           *websocket.Conn
+	writeChannel         chan []byte //encoded frames waiting to be written out
+	closedChannel        chan bool   //closed once the session has been closed
+	closeOnce            sync.Once
+	lock                 sync.Mutex
+	subscriptions        []*stompSubscription
+	clientSubscriptions  map[string]string //publisher id of each of the client's subscriptions
+	messageSubscriptions map[string]string //id of the STOMP subscription each unsettled message was sent on
+	closeNotice          string            //sent as an ERROR frame when the session closes, e.g. the server shutting down
+	acks                 *ackTracker
+}
+
+func (session *stompSession) queue(frame *stompFrame) {
+	select {
+	case session.writeChannel <- encodeStompFrame(frame):
+	case <-session.closedChannel:
+	case <-time.After(sessionQueueTimeout): //not keeping up
+		session.close()
+	}
+}
+
+func (session *stompSession) write(data []byte) error {
+	session.connection.SetWriteDeadline(time.Now().Add(stompWriteTimeout))
+	return session.connection.WriteMessage(websocket.TextMessage, data)
+}
+
+//loop writing queued frames to the websocket, once the session is closed anything still waiting is written out
+//before the connection is closed
+func (session *stompSession) writeLoop() {
+	defer session.connection.Close()
+	for {
+		select {
+		case data := <-session.writeChannel:
+			if session.write(data) != nil {
+				session.close()
+				return
+			}
+		case <-session.closedChannel:
+			for {
+				select {
+				case data := <-session.writeChannel:
+					session.write(data)
+				default:
+					session.lock.Lock()
+					notice := session.closeNotice
+					session.lock.Unlock()
+					if notice != "" {
+						session.write(encodeStompFrame(stompError(notice, "")))
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+func (session *stompSession) findSubscription(id string) (int, *stompSubscription) {
+	for i, sub := range session.subscriptions {
+		if sub.id == id {
+			return i, sub
+		}
+	}
+	return -1, nil
+}
+
+//the first STOMP subscription to a publisher receives its messages
+func (session *stompSession) publisherSubscription(publisherID string) *stompSubscription {
+	for _, sub := range session.subscriptions {
+		if sub.publisherID == publisherID {
+			return sub
+		}
+	}
+	return nil
+}
+
+//STOMP sessions receive messages from the subscriptions whose destinations the client has subscribed to, if its API
+//key is scoped to them
+func (session *stompSession) accepts(subscriptionID string) bool {
+	session.lock.Lock()
+	defer session.lock.Unlock()
+	publisherID, exists := session.clientSubscriptions[subscriptionID]
+	return exists && session.scope.allows(subscriptionID) && session.publisherSubscription(publisherID) != nil
+}
+
+//messages on auto subscriptions are confirmed as soon as they are sent, the rest once the client has sent ACK or NACK for them
+func (session *stompSession) deliverMessages(messages []jsonMessageItem) {
+	session.lock.Lock()
+	defer session.lock.Unlock()
+	pending := make(map[string][]string)
+	acked := make(map[string][]string)
+	order := []string{}
+	for _, sub := range session.subscriptions {
+		//forget anything which has already expired
+		unacked := []string{}
+		for _, id := range sub.unacked {
+			if session.acks.waiting(id) {
+				unacked = append(unacked, id)
+			} else {
+				delete(session.messageSubscriptions, id)
+			}
+		}
+		sub.unacked = unacked
+	}
+	for _, message := range messages {
+		if _, exists := acked[message.SubscriptionID]; !exists {
+			acked[message.SubscriptionID] = []string{}
+			order = append(order, message.SubscriptionID)
+		}
+		sub := session.publisherSubscription(message.PublisherID)
+		if sub == nil {
+			continue
+		}
+		frame := stompFrame{
+			command: "MESSAGE",
+			headers: []stompHeader{
+				{key: "subscription", value: sub.id},
+				{key: "message-id", value: message.Id},
+				{key: "destination", value: stompDestination(message.PublisherID)},
+			},
+			body: []byte(message.Payload),
+		}
+		if message.SchemaVersion > 0 {
+			frame.headers = append(frame.headers, stompHeader{key: "schema-version", value: strconv.Itoa(message.SchemaVersion)})
+		}
+		if sub.ackMode == stompAckAuto {
+			acked[message.SubscriptionID] = append(acked[message.SubscriptionID], message.Id)
+		} else {
+			frame.headers = append(frame.headers, stompHeader{key: "ack", value: message.Id})
+			pending[message.SubscriptionID] = append(pending[message.SubscriptionID], message.Id)
+			sub.unacked = append(sub.unacked, message.Id)
+			session.messageSubscriptions[message.Id] = sub.id
+		}
+		session.queue(&frame)
+	}
+	for _, subscriptionID := range order {
+		session.acks.track(subscriptionID, pending[subscriptionID], acked[subscriptionID])
+	}
+}
+
+//handle an ACK or NACK frame, in client mode every message sent before the one given is settled along with it
+func (session *stompSession) settle(messageID string, acknowledged bool) {
+	session.lock.Lock()
+	defer session.lock.Unlock()
+	subID, exists := session.messageSubscriptions[messageID]
+	if !exists {
+		//already settled or expired
+		return
+	}
+	_, sub := session.findSubscription(subID)
+	if sub == nil {
+		return
+	}
+	settled := []string{messageID}
+	remaining := []string{}
+	for i, id := range sub.unacked {
+		if id == messageID {
+			if sub.ackMode == stompAckClient {
+				settled = sub.unacked[:i+1]
+			}
+			remaining = append(remaining, sub.unacked[i+1:]...)
+			break
+		}
+		remaining = append(remaining, id)
+	}
+	sub.unacked = remaining
+	for _, id := range settled {
+		delete(session.messageSubscriptions, id)
+		session.acks.settle(id, acknowledged)
+	}
+}
+
+//the client has to reconnect after being told the server is shutting down or its session has been replaced,
+//STOMP has no notices so the reason is sent as an ERROR frame when the session closes. other notices are dropped
+func (session *stompSession) notify(message jsonCommunication) {
+	if message.Action != "server_shutting_down" && message.Action != "session_replaced" {
+		return
+	}
+	session.lock.Lock()
+	defer session.lock.Unlock()
+	session.closeNotice = message.Message
+}
+
+func (session *stompSession) close() {
+	session.closeOnce.Do(func() {
+		close(session.closedChannel)
+	})
+}
+
+//handle a SUBSCRIBE frame, subscribing to a publisher's destination subscribes the client to the publisher if it isn't already
+func (session *stompSession) subscribe(frame *stompFrame, store storage.Store) error {
+	id := frame.header("id")
+	if id == "" {
+		return errors.New("SUBSCRIBE requires an id header")
+	}
+	publisherID, valid := stompDestinationPublisher(frame.header("destination"))
+	if !valid {
+		return errors.New("destination must be " + stompDestinationPrefix + "{publisher_id}")
+	}
+	ackMode := frame.header("ack")
+	if ackMode == "" {
+		ackMode = stompAckAuto
+	}
+	if ackMode != stompAckAuto && ackMode != stompAckClient && ackMode != stompAckClientIndividual {
+		return errors.New("unknown ack mode " + ackMode)
+	}
+
+	session.lock.Lock()
+	_, existing := session.findSubscription(id)
+	subscribed, scoped := session.scope.subscribedTo(session.clientSubscriptions, publisherID)
+	session.lock.Unlock()
+	if existing != nil {
+		return errors.New("subscription " + id + " already exists")
+	}
+	if !scoped {
+		return errors.New("API key isn't scoped to a subscription to the publisher")
+	}
+	if !subscribed {
+		sub, err := subscribeClient(store, session.id, publisherID)
+		if err != nil {
+			return err
+		}
+		session.lock.Lock()
+		session.clientSubscriptions[sub.ID] = sub.PublisherID
+		session.lock.Unlock()
+		session.sessions.subscribe(*sub)
+	}
+
+	session.lock.Lock()
+	defer session.lock.Unlock()
+	session.subscriptions = append(session.subscriptions, &stompSubscription{
+		id:          id,
+		publisherID: publisherID,
+		ackMode:     ackMode,
+		unacked:     []string{},
+	})
+	return nil
+}
+
+//handle an UNSUBSCRIBE frame, anything unsettled on the subscription is sent again
+func (session *stompSession) unsubscribe(frame *stompFrame) error {
+	session.lock.Lock()
+	defer session.lock.Unlock()
+	i, sub := session.findSubscription(frame.header("id"))
+	if sub == nil {
+		return errors.New("subscription not found")
+	}
+	session.subscriptions = append(session.subscriptions[:i], session.subscriptions[i+1:]...)
+	for _, id := range sub.unacked {
+		delete(session.messageSubscriptions, id)
+		session.acks.settle(id, false)
+	}
+	return nil
+}
+
+//handle a frame sent by the client, returns false once the session should close
+func (session *stompSession) handleFrame(frame *stompFrame, store storage.Store, access clientAccess) bool {
+	var err error
+	switch frame.command {
+	case "SEND":
+		publisherID, valid := stompDestinationPublisher(frame.header("destination"))
+		if !valid {
+			err = errors.New("destination must be " + stompDestinationPrefix + "{publisher_id}")
+		} else {
+			err = access.publishMessage(store, session.id, session.scope, publisherID, string(frame.body))
+		}
+	case "SUBSCRIBE":
+		err = session.subscribe(frame, store)
+	case "UNSUBSCRIBE":
+		err = session.unsubscribe(frame)
+	case "ACK", "NACK":
+		if frame.header("transaction") != "" {
+			err = errors.New("transactions aren't supported")
+		} else if frame.header("id") == "" {
+			err = errors.New(frame.command + " requires an id header")
+		} else {
+			session.settle(frame.header("id"), frame.command == "ACK")
+		}
+	case "BEGIN", "COMMIT", "ABORT":
+		err = errors.New("transactions aren't supported")
+	case "DISCONNECT":
+	default:
+		err = errors.New("unknown command " + frame.command)
+	}
+
+	receipt := frame.header("receipt")
+	if err != nil {
+		errorFrame := stompError(err.Error(), receipt)
+		if limited := rateLimitError(err); limited != nil {
+			errorFrame.headers = append(errorFrame.headers, stompHeader{key: "retry-after", value: strconv.Itoa(limited.RetryAfterSeconds())})
+		}
+		session.queue(errorFrame)
+		return false
+	}
+	if receipt != "" {
+		session.queue(&stompFrame{
+			command: "RECEIPT",
+			headers: []stompHeader{{key: "receipt-id", value: receipt}},
+		})
+	}
+	return frame.command != "DISCONNECT"
+}
+
+//send an ERROR frame during the handshake then close the connection
+func refuseStompConnection(con *websocket.Conn, message string) {
+	con.SetWriteDeadline(time.Now().Add(stompWriteTimeout))
+	con.WriteMessage(websocket.TextMessage, encodeStompFrame(stompError(message, "")))
+	con.Close()
+}
+
+//wait up to the auth timeout for the CONNECT frame and authenticate the client, the login header is the client id
+//and the passcode header its secret, or the api-key header an API key or the token header an access token. a
+//CONNECT frame without any of them is authenticated with the token given when opening the websocket
+func authenticateStomp(con *websocket.Conn, connectAuth *jsonAuthResponse, store storage.Store, access clientAccess, authTimeout time.Duration) (*brokerClient, *stompFrame, []*stompFrame, error) {
+	con.SetReadDeadline(time.Now().Add(authTimeout))
+	_, message, err := con.ReadMessage()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	con.SetReadDeadline(time.Time{})
+	frames, err := parseStompFrames(message)
+	if err != nil || len(frames) == 0 {
+		refuseStompConnection(con, "Expected a CONNECT frame")
+		return nil, nil, nil, errMalformedFrame
+	}
+	connect := frames[0]
+	if connect.command != "CONNECT" && connect.command != "STOMP" {
+		refuseStompConnection(con, "Expected a CONNECT frame")
+		return nil, nil, nil, errors.New("expected CONNECT")
+	}
+	versionSupported := false
+	for _, version := range strings.Split(connect.header("accept-version"), ",") {
+		versionSupported = versionSupported || version == stompVersion
+	}
+	if !versionSupported {
+		refuseStompConnection(con, "Supported protocol versions are "+stompVersion)
+		return nil, nil, nil, errors.New("unsupported protocol version")
+	}
+	supplied := credentials{
+		id:     connect.header("login"),
+		secret: connect.header("passcode"),
+		apiKey: connect.header("api-key"),
+		token:  connect.header("token"),
+	}
+	if supplied == (credentials{}) && connectAuth != nil {
+		supplied.token = connectAuth.Token
+	}
+	client, err := access.authenticateCredentials(supplied, store)
+	if errors.Is(err, storage.ErrNotFound) {
+		refuseStompConnection(con, "Incorrect credentials")
+		return nil, nil, nil, errors.New("incorrect credentials")
+	} else if err != nil {
+		refuseStompConnection(con, "Error occurred")
+		return nil, nil, nil, err
+	}
+	return client, connect, frames[1:], nil
+}
+
+//handle a websocket which negotiated STOMP, it joins the client's sessions in the same way as the JSON protocol
+func handleStompConnection(con *websocket.Conn, connectAuth *jsonAuthResponse, channels connectionManagerChannels, store storage.Store, access clientAccess, settings settings) {
+	client, connect, frames, err := authenticateStomp(con, connectAuth, store, access, settings.authTimeout)
+	if err != nil {
+		fmt.Println(err.Error())
+		con.Close()
+		return
+	}
+
+	session := &stompSession{
+		sessionInfo: sessionInfo{
+			id:            client.Id,
+			name:          client.Name,
+			sessionID:     uuid.New().String(),
+			remoteAddress: con.RemoteAddr().String(),
+			connectedAt:   time.Now(),
+			policy: sessionPolicy{
+				delivery:      connect.header("delivery"),
+				singleSession: connect.header("single-session") == "true" && client.scope == nil,
+			},
+			scope: client.scope,
+		},
+		connection:           con,
+		writeChannel:         make(chan []byte, 100),
+		closedChannel:        make(chan bool),
+		subscriptions:        []*stompSubscription{},
+		clientSubscriptions:  make(map[string]string),
+		messageSubscriptions: make(map[string]string),
+	}
+	session.acks = newAckTracker(func(subscriptionID string, messageIDs []string) {
+		confirmSessionMessages(session.sessions, subscriptionID, messageIDs)
+	})
+	for _, sub := range client.Subscriptions {
+		session.clientSubscriptions[sub.ID] = sub.PublisherID
+	}
+
+	request := newConnectionRequest{
+		session:         session,
+		subscriptions:   client.Subscriptions,
+		connectionLimit: client.connectionLimit(access),
+		addedChannel:    make(chan error),
+	}
+	channels.newConnection <- &request
+	err = <-request.addedChannel
+	if err != nil {
+		refuseStompConnection(con, err.Error())
+		return
+	}
+
+	go session.writeLoop()
+	session.queue(&stompFrame{
+		command: "CONNECTED",
+		headers: []stompHeader{
+			{key: "version", value: stompVersion},
+			{key: "session", value: session.sessionID},
+			{key: "server", value: "message-broker"},
+			{key: "heart-beat", value: "0,0"},
+		},
+	})
+
+	open := true
+	for _, frame := range frames {
+		open = open && session.handleFrame(frame, store, access)
+	}
+	for open {
+		_, message, err := con.ReadMessage()
+		if err != nil {
+			fmt.Println("lost stomp connection")
+			break
+		}
+		frames, err := parseStompFrames(message)
+		if err != nil {
+			session.queue(stompError(err.Error(), ""))
+			break
+		}
+		for _, frame := range frames {
+			open = open && session.handleFrame(frame, store, access)
+		}
+	}
+
+	session.close()
+	channels.lostConnection <- session
+}
@@ -0,0 +1,200 @@
+package broker
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	stompSubprotocol       = "v12.stomp" //websocket subprotocol clients request to speak STOMP 1.2
+	stompVersion           = "1.2"
+	stompDestinationPrefix = "/publishers/"
+)
+
+var errMalformedFrame = errors.New("malformed stomp frame")
+
+type stompHeader struct {
+	key   string
+	value string
+}
+
+type stompFrame struct {
+	command string
+	headers []stompHeader
+	body    []byte
+}
+
+//value of a header, if a header is repeated the first value is used
+func (frame *stompFrame) header(key string) string {
+	for _, header := range frame.headers {
+		if header.key == key {
+			return header.value
+		}
+	}
+	return ""
+}
+
+//headers of CONNECT and CONNECTED frames aren't escaped, for backwards compatibility with STOMP 1.0
+func stompEscapes(command string) bool {
+	return command != "CONNECT" && command != "CONNECTED"
+}
+
+var stompEscaper = strings.NewReplacer("\\", "\\\\", "\r", "\\r", "\n", "\\n", ":", "\\c")
+
+func unescapeStompHeader(value string) (string, error) {
+	if !strings.Contains(value, "\\") {
+		return value, nil
+	}
+	builder := strings.Builder{}
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' {
+			builder.WriteByte(value[i])
+			continue
+		}
+		i++
+		if i == len(value) {
+			return "", errMalformedFrame
+		}
+		switch value[i] {
+		case 'r':
+			builder.WriteByte('\r')
+		case 'n':
+			builder.WriteByte('\n')
+		case 'c':
+			builder.WriteByte(':')
+		case '\\':
+			builder.WriteByte('\\')
+		default:
+			return "", errMalformedFrame
+		}
+	}
+	return builder.String(), nil
+}
+
+//parse the frames in a websocket message, heart-beats sent as blank lines are skipped
+func parseStompFrames(data []byte) ([]*stompFrame, error) {
+	frames := []*stompFrame{}
+	for {
+		data = bytes.TrimLeft(data, "\r\n")
+		if len(data) == 0 {
+			return frames, nil
+		}
+		frame, rest, err := parseStompFrame(data)
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, frame)
+		data = rest
+	}
+}
+
+//parse a single frame, returning whatever follows it
+func parseStompFrame(data []byte) (*stompFrame, []byte, error) {
+	readLine := func() (string, bool) {
+		end := bytes.IndexByte(data, '\n')
+		if end < 0 {
+			return "", false
+		}
+		line := strings.TrimSuffix(string(data[:end]), "\r")
+		data = data[end+1:]
+		return line, true
+	}
+
+	command, ok := readLine()
+	if !ok || command == "" {
+		return nil, nil, errMalformedFrame
+	}
+	frame := stompFrame{command: command, headers: []stompHeader{}}
+	for {
+		line, ok := readLine()
+		if !ok {
+			return nil, nil, errMalformedFrame
+		}
+		if line == "" {
+			break
+		}
+		separator := strings.IndexByte(line, ':')
+		if separator < 0 {
+			return nil, nil, errMalformedFrame
+		}
+		key, value := line[:separator], line[separator+1:]
+		if stompEscapes(command) {
+			var err error
+			key, err = unescapeStompHeader(key)
+			if err != nil {
+				return nil, nil, err
+			}
+			value, err = unescapeStompHeader(value)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		frame.headers = append(frame.headers, stompHeader{key: key, value: value})
+	}
+
+	//the body runs for content-length bytes if it is given, otherwise up to the NULL
+	if contentLength := frame.header("content-length"); contentLength != "" {
+		length, err := strconv.Atoi(contentLength)
+		if err != nil || length < 0 || length >= len(data) || data[length] != 0 {
+			return nil, nil, errMalformedFrame
+		}
+		frame.body = data[:length]
+		return &frame, data[length+1:], nil
+	}
+	end := bytes.IndexByte(data, 0)
+	if end < 0 {
+		return nil, nil, errMalformedFrame
+	}
+	frame.body = data[:end]
+	return &frame, data[end+1:], nil
+}
+
+func encodeStompFrame(frame *stompFrame) []byte {
+	buffer := bytes.Buffer{}
+	buffer.WriteString(frame.command + "\n")
+	for _, header := range frame.headers {
+		if stompEscapes(frame.command) {
+			buffer.WriteString(stompEscaper.Replace(header.key) + ":" + stompEscaper.Replace(header.value) + "\n")
+		} else {
+			buffer.WriteString(header.key + ":" + header.value + "\n")
+		}
+	}
+	if len(frame.body) > 0 {
+		buffer.WriteString("content-length:" + strconv.Itoa(len(frame.body)) + "\n")
+	}
+	buffer.WriteString("\n")
+	buffer.Write(frame.body)
+	buffer.WriteByte(0)
+	return buffer.Bytes()
+}
+
+//ERROR frame sent before closing the connection, echoing the receipt of the frame which caused it
+func stompError(message string, receipt string) *stompFrame {
+	frame := stompFrame{
+		command: "ERROR",
+		headers: []stompHeader{{key: "message", value: message}},
+	}
+	if receipt != "" {
+		frame.headers = append(frame.headers, stompHeader{key: "receipt-id", value: receipt})
+	}
+	return &frame
+}
+
+//destinations are named after the publisher the messages are from
+func stompDestination(publisherID string) string {
+	return stompDestinationPrefix + publisherID
+}
+
+//id of the publisher a destination refers to
+func stompDestinationPublisher(destination string) (string, bool) {
+	if !strings.HasPrefix(destination, stompDestinationPrefix) {
+		return "", false
+	}
+	publisherID := strings.TrimPrefix(destination, stompDestinationPrefix)
+	if publisherID == "" || strings.Contains(publisherID, "/") {
+		return "", false
+	}
+	return publisherID, true
+}
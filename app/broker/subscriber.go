@@ -0,0 +1,198 @@
+package broker
+
+import (
+	"fmt"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+)
+
+type subscription struct {
+	id                      string
+	publisherID             string
+	clientID                string
+	cluster                 *clusterManager //coordinates which instance owns the subscription when running as a cluster
+	pollInterval            time.Duration   //wait between checking for new messages
+	batchSize               int
+	cancelChannel           chan bool
+	drainingChannel         chan bool //closed when the server is shutting down, the loop stops once nothing is waiting on confirmation
+	stoppedChannel          chan bool //closed when the running loop exits
+	messagesChannel         chan []jsonMessageItem
+	receiveConfirmedChannel chan *subscriptionMessagesConfirmation
+}
+
+type jsonMessageItem struct {
+	Id             string `json:"id"`
+	PublisherID    string `json:"publisher_id"`
+	SubscriptionID string `json:"subscription_id"`
+	Payload        string `json:"payload"`
+	SchemaVersion  int    `json:"schema_version,omitempty"` //version of the publisher's schema the payload matched
+}
+
+type subscriptionMessagesConfirmation struct {
+	messages         []string
+	release          bool //the session holding the batch has gone, only applies if messages are the whole batch
+	confirmedChannel chan int
+}
+
+//mark messages from a publisher as received by the client, returns the number which were confirmed
+func confirmMessages(store storage.Store, publisherID string, clientID string, messageIDs []string) int {
+	confirmed, err := store.ConfirmMessages(publisherID, clientID, messageIDs)
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	return confirmed
+}
+
+//lease the next batch of messages the client hasn't yet received from the publisher
+func (sub *subscription) fetchMessages(store storage.Store) []jsonMessageItem {
+	return leaseMessages(store, sub.publisherID, sub.clientID, sub.id, int64(sub.batchSize), messageLeaseDuration)
+}
+
+//ids of the messages in a batch which weren't confirmed
+func unconfirmedMessages(messages []jsonMessageItem, confirmed []string) []string {
+	confirmedIDs := make(map[string]bool)
+	for _, id := range confirmed {
+		confirmedIDs[id] = true
+	}
+	unconfirmed := []string{}
+	for _, message := range messages {
+		if !confirmedIDs[message.Id] {
+			unconfirmed = append(unconfirmed, message.Id)
+		}
+	}
+	return unconfirmed
+}
+
+//whether a set of message ids is the same as the batch's, ignoring the order
+func sameMessages(batch []string, messageIDs []string) bool {
+	if len(batch) != len(messageIDs) {
+		return false
+	}
+	ids := make(map[string]bool)
+	for _, id := range batch {
+		ids[id] = true
+	}
+	for _, id := range messageIDs {
+		if !ids[id] {
+			return false
+		}
+	}
+	return true
+}
+
+//whether a confirmation or release is for an earlier batch than the one waiting on it. a confirmation naming no
+//messages is the client acking none of the batch, so it still settles it
+func staleConfirmation(batch []string, confirmation *subscriptionMessagesConfirmation) bool {
+	if confirmation.release {
+		return !sameMessages(batch, confirmation.messages)
+	}
+	batchIDs := make(map[string]bool)
+	for _, id := range batch {
+		batchIDs[id] = true
+	}
+	stale := false
+	for _, id := range confirmation.messages {
+		if batchIDs[id] {
+			return false
+		}
+		stale = stale || id != ""
+	}
+	return stale
+}
+
+//answer a confirmation or release of messages which aren't in the batch waiting on confirmation, so a duplicate
+//or late one isn't left blocking the subscription manager. they were settled along with their own batch
+func settledConfirmation(confirmation *subscriptionMessagesConfirmation) {
+	confirmation.confirmedChannel <- 0
+}
+
+func (sub *subscription) loop(store storage.Store, stoppedChannel chan bool) {
+	defer close(stoppedChannel)
+	defer sub.cluster.releaseSubscription(sub.id)
+	closed := false
+	for {
+		messages := []jsonMessageItem{}
+		if sub.cluster.claimSubscription(sub.id) {
+			messages = sub.fetchMessages(store)
+		}
+		for sent := false; !sent && !closed; {
+			select {
+			case sub.messagesChannel <- messages:
+				sent = true
+			case confirmation := <-sub.receiveConfirmedChannel:
+				settledConfirmation(confirmation)
+			case <-sub.cancelChannel:
+				closed = true
+			case <-sub.drainingChannel: //shutting down so don't deliver anything new
+				closed = true
+			}
+		}
+		if closed {
+			//let go of a batch which was fetched but never handed on
+			if len(messages) > 0 {
+				releaseLeases(store, sub.publisherID, sub.clientID, unconfirmedMessages(messages, []string{}), 0)
+			}
+			break
+		}
+		if len(messages) > 0 {
+			messageIDs := unconfirmedMessages(messages, []string{})
+			var confirmation *subscriptionMessagesConfirmation
+			for confirmation == nil && !closed {
+				select {
+				case confirmation = <-sub.receiveConfirmedChannel:
+					if staleConfirmation(messageIDs, confirmation) {
+						//releasing or confirming an earlier batch which has already been settled
+						settledConfirmation(confirmation)
+						confirmation = nil
+					}
+				case <-sub.cancelChannel:
+					closed = true
+				case <-time.After(clusterLeaseDuration / 3):
+					//keep hold of the messages while the client has unconfirmed messages, the cluster's heartbeat
+					//keeps hold of the subscription
+					renewLeases(store, sub.clientID, messageIDs, messageLeaseDuration)
+				}
+			}
+			if closed {
+				//let the messages go so they can be delivered again straight away
+				releaseLeases(store, sub.publisherID, sub.clientID, messageIDs, 0)
+				break
+			}
+
+			confirmed := confirmation.messages
+			if confirmation.release {
+				//nothing is left to confirm the batch so let it go to be delivered to another session
+				confirmed = []string{}
+				confirmation.confirmedChannel <- 0
+			} else {
+				confirmation.confirmedChannel <- confirmMessages(store, sub.publisherID, sub.clientID, confirmed)
+			}
+
+			//anything which wasn't confirmed can be sent again in the next batch
+			unconfirmed := unconfirmedMessages(messages, confirmed)
+			if len(unconfirmed) > 0 {
+				releaseLeases(store, sub.publisherID, sub.clientID, unconfirmed, 0)
+			}
+
+		}
+
+		poll := time.After(sub.pollInterval)
+		for polled := false; !polled && !closed; {
+			select {
+			case <-poll:
+				polled = true
+			case confirmation := <-sub.receiveConfirmedChannel:
+				settledConfirmation(confirmation)
+			case <-sub.cancelChannel:
+				closed = true
+			case <-sub.drainingChannel:
+				closed = true
+			}
+		}
+		if closed {
+			break
+		}
+	}
+
+}
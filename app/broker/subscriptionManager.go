@@ -0,0 +1,216 @@
+package broker
+
+import (
+	"fmt"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+)
+
+const releaseTimeout = 5 * time.Second //how long a released batch waits for the subscription to take it back
+
+type subscriptionManager struct {
+	subscriptions             map[string]*subscription
+	newSubscriptionChannel    chan *subscription
+	confirmChannel            chan *subscriptionManagerConfirmation
+	sendToClientChannel       chan []jsonMessageItem
+	removeSubscriptionChannel chan string
+	cancelReceiveChannel      chan bool
+	cancelManagerChannel      chan bool
+	drainChannel              chan *drainRequest
+	drainingChannel           chan bool //closed to tell the subscriptions to stop once their in-flight messages are confirmed
+}
+
+//request to stop delivering new messages and wait for any in-flight messages to be confirmed
+type drainRequest struct {
+	deadline       time.Time     //time to give up waiting on confirmations
+	reconnectAfter time.Duration //hint given to clients on how long to wait before reconnecting
+	drainedChannel chan bool     //buffered, receives true if everything was confirmed before the deadline
+}
+
+type subscriptionManagerConfirmation struct {
+	messages               []confirmMessageData
+	release                bool //hand the messages back to be delivered again rather than confirming them
+	numberConfirmedChannel chan int
+}
+
+//closing cancelChannel stops the wait, including when the messages have been taken but not handed on, otherwise
+//the goroutine would be left behind to take a later batch from the subscription. the subscription is stopped
+//along with the receive loop, which releases the leases on anything dropped here
+func waitForSubMessages(sub *subscription, receiveChannel chan []jsonMessageItem, cancelChannel chan bool) {
+	select {
+	case messages := <-sub.messagesChannel:
+		select {
+		case receiveChannel <- messages:
+		case <-cancelChannel:
+		}
+	case <-cancelChannel:
+	}
+}
+
+func (subManager *subscriptionManager) receiveLoop() {
+	closed := false
+	for {
+		receiveSubMessagesChannels := []chan []jsonMessageItem{}
+		cancelSubMessagesChannel := make(chan bool)
+		for _, sub := range subManager.subscriptions {
+
+			receiveSubMessageChannel := make(chan []jsonMessageItem)
+			receiveSubMessagesChannels = append(receiveSubMessagesChannels, receiveSubMessageChannel)
+			go waitForSubMessages(sub, receiveSubMessageChannel, cancelSubMessagesChannel)
+
+		}
+		allMessages := []jsonMessageItem{}
+		for _, receiveChannel := range receiveSubMessagesChannels {
+			select {
+			case messages := <-receiveChannel:
+				allMessages = append(allMessages, messages...)
+			case <-subManager.cancelReceiveChannel:
+				closed = true
+			}
+			if closed {
+				break
+			}
+		}
+		if closed {
+			//stop every wait, including any which has already taken messages
+			close(cancelSubMessagesChannel)
+			break
+		} else {
+			if len(allMessages) > 0 {
+				subManager.sendToClientChannel <- allMessages
+			}
+		}
+	}
+}
+
+func (subManager *subscriptionManager) stop() {
+	timeout := time.After(2 * time.Second)
+	select {
+	case subManager.cancelReceiveChannel <- true:
+	case <-timeout:
+	}
+
+	for _, sub := range subManager.subscriptions {
+		timeout := time.After(30 * time.Second)
+		select {
+		case sub.cancelChannel <- true:
+		case <-sub.stoppedChannel: //already stopped after draining
+		case <-timeout:
+		}
+	}
+}
+
+func (subManager *subscriptionManager) start(store storage.Store) {
+	go subManager.receiveLoop()
+	for _, sub := range subManager.subscriptions {
+		sub.stoppedChannel = make(chan bool)
+		go sub.loop(store, sub.stoppedChannel)
+	}
+}
+
+func waitForSubToConfirm(messages []string, sub *subscription, confirmedChannel chan int) {
+	if sub == nil {
+		//not one of the client's subscriptions
+		confirmedChannel <- 0
+		return
+	}
+	select {
+	case sub.receiveConfirmedChannel <- &subscriptionMessagesConfirmation{
+		messages:         messages,
+		confirmedChannel: confirmedChannel,
+	}:
+	case <-sub.stoppedChannel:
+		confirmedChannel <- 0
+	}
+}
+
+//hand a batch back to a subscription to be delivered again. a subscription still waiting on the batch takes it
+//straight away, otherwise the batch has already been settled and the release is dropped
+func releaseBatch(messages []string, sub *subscription) {
+	if sub == nil {
+		return
+	}
+	select {
+	case sub.receiveConfirmedChannel <- &subscriptionMessagesConfirmation{
+		messages:         messages,
+		release:          true,
+		confirmedChannel: make(chan int, 1),
+	}:
+	case <-sub.stoppedChannel:
+	case <-time.After(releaseTimeout):
+	}
+}
+
+//wait for each of the subscription loops to stop after being told to drain
+func waitForSubsToStop(stoppedChannels []chan bool, request *drainRequest) {
+	timeout := time.After(time.Until(request.deadline))
+	for _, stopped := range stoppedChannels {
+		select {
+		case <-stopped:
+		case <-timeout:
+			request.drainedChannel <- false
+			return
+		}
+	}
+	request.drainedChannel <- true
+}
+
+func (subManager *subscriptionManager) managerLoop(store storage.Store) {
+	closed := false
+	draining := false
+	for {
+		select {
+		case sub := <-subManager.newSubscriptionChannel:
+			sub.drainingChannel = subManager.drainingChannel
+			subManager.stop()
+			subManager.subscriptions[sub.id] = sub
+			subManager.start(store)
+		case subId := <-subManager.removeSubscriptionChannel:
+			subManager.stop()
+			delete(subManager.subscriptions, subId)
+			subManager.start(store)
+		case confirmation := <-subManager.confirmChannel:
+			subMessages := make(map[string][]string)
+			for _, msg := range confirmation.messages {
+				subMessages[msg.SubscriptionID] = append(subMessages[msg.SubscriptionID], msg.Id)
+			}
+			if confirmation.release {
+				for key, v := range subMessages {
+					go releaseBatch(v, subManager.subscriptions[key])
+				}
+				confirmation.numberConfirmedChannel <- 0
+				continue
+			}
+			subConfirmedChannels := []chan int{}
+			for key, v := range subMessages {
+				confirmedChannel := make(chan int)
+				subConfirmedChannels = append(subConfirmedChannels, confirmedChannel)
+				go waitForSubToConfirm(v, subManager.subscriptions[key], confirmedChannel)
+			}
+			totalConfirmed := 0
+			for _, confirmChannel := range subConfirmedChannels {
+				totalConfirmed += <-confirmChannel
+			}
+			confirmation.numberConfirmedChannel <- totalConfirmed
+		case request := <-subManager.drainChannel:
+			if !draining {
+				draining = true
+				close(subManager.drainingChannel)
+			}
+			stoppedChannels := []chan bool{}
+			for _, sub := range subManager.subscriptions {
+				stoppedChannels = append(stoppedChannels, sub.stoppedChannel)
+			}
+			//keep handling confirmations while waiting for the subscriptions to finish
+			go waitForSubsToStop(stoppedChannels, request)
+		case <-subManager.cancelManagerChannel:
+			fmt.Println("sub manager stop")
+			subManager.stop()
+			closed = true
+		}
+		if closed {
+			break
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package broker
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+)
+
+const noticeBuffer = 64 //notices waiting to be sent to a client's sessions, any more are dropped
+
+//notice for the open sessions of a client, e.g. a request to subscribe to one of its publishers
+type clientNotice struct {
+	clientID     string
+	notice       jsonCommunication
+	subscription *storage.Subscription //started in the client's open sessions, e.g. once its request is approved
+}
+
+//notices are only sent to sessions the client opened with its own credentials, sessions opened with an API key
+//are limited to consuming their subscriptions
+func (group *clientSessions) notifySessions(notice jsonCommunication) {
+	for _, session := range group.sessions {
+		if session.info().scope == nil {
+			session.notify(notice)
+		}
+	}
+}
+
+//position in the subscription requests already notified, by the time they were made or decided. requests at the
+//time of the last one seen are returned again by the next poll so their ids are kept to skip them
+type requestCursor struct {
+	since time.Time
+	seen  map[string]bool
+	at    func(request *storage.SubscriptionRequest) time.Time
+}
+
+func newRequestCursor(since time.Time, at func(request *storage.SubscriptionRequest) time.Time) *requestCursor {
+	return &requestCursor{since: since, seen: map[string]bool{}, at: at}
+}
+
+//the requests which haven't been seen yet in the order they happened, moving the cursor past them
+func (cursor *requestCursor) advance(requests []storage.SubscriptionRequest) []storage.SubscriptionRequest {
+	sort.SliceStable(requests, func(i, j int) bool {
+		return cursor.at(&requests[i]).Before(cursor.at(&requests[j]))
+	})
+	unseen := []storage.SubscriptionRequest{}
+	for _, request := range requests {
+		if cursor.seen[request.ID] {
+			continue
+		}
+		if at := cursor.at(&request); at.After(cursor.since) {
+			cursor.since = at
+			cursor.seen = map[string]bool{}
+		}
+		cursor.seen[request.ID] = true
+		unseen = append(unseen, request)
+	}
+	return unseen
+}
+
+//notice to the owner of the publisher about a new request
+func requestedNotice(request storage.SubscriptionRequest, store storage.Store) (*clientNotice, error) {
+	publisher, err := store.FindPublisher(request.PublisherID)
+	if err != nil {
+		return nil, err
+	}
+	client, err := store.FindClient(request.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	return &clientNotice{
+		clientID: request.OwnerID,
+		notice: jsonCommunication{
+			Action:  "subscription_requested",
+			Message: client.Name + " has asked to subscribe to " + publisher.Name,
+			Data: map[string]string{
+				"request_id":     request.ID,
+				"publisher_id":   publisher.ID,
+				"publisher_name": publisher.Name,
+				"client_id":      client.ID,
+				"client_name":    client.Name,
+				"message":        request.Message,
+			},
+		},
+	}, nil
+}
+
+//notice to the client which made a request that it's been approved or denied, an approved request's
+//subscription is started in the client's open sessions
+func decidedNotice(request storage.SubscriptionRequest, store storage.Store) (*clientNotice, error) {
+	publisher, err := store.FindPublisher(request.PublisherID)
+	if err != nil {
+		return nil, err
+	}
+	notice := &clientNotice{
+		clientID: request.ClientID,
+		notice: jsonCommunication{
+			Action:  "subscription_request_" + request.Status,
+			Message: "Your request to subscribe to " + publisher.Name + " has been " + request.Status,
+			Data: map[string]string{
+				"request_id":     request.ID,
+				"publisher_id":   publisher.ID,
+				"publisher_name": publisher.Name,
+				"reason":         request.Reason,
+			},
+		},
+	}
+	if request.Status == storage.RequestApproved {
+		subscription := request.Subscription
+		notice.subscription = &subscription
+		notice.notice.Data.(map[string]string)["subscription_id"] = subscription.ID
+	}
+	return notice, nil
+}
+
+//loop running in a goroutine telling the owners of publishers about requests to subscribe to them, and the clients
+//which made the requests once they've been decided. the requests are made and decided through the publisher
+//service so the store is polled for them, every instance in a cluster polls as the sessions could be open on any
+func notifySubscriptionRequests(store storage.Store, noticeChannel chan *clientNotice, pollInterval time.Duration, stopChannel chan bool) {
+	now := time.Now()
+	created := newRequestCursor(now, func(request *storage.SubscriptionRequest) time.Time { return request.CreatedAt })
+	decided := newRequestCursor(now, func(request *storage.SubscriptionRequest) time.Time { return request.DecidedAt })
+	for {
+		select {
+		case <-time.After(pollInterval):
+		case <-stopChannel:
+			fmt.Println("subscription request notices stop")
+			return
+		}
+		notices := []*clientNotice{}
+		pending, err := store.ListSubscriptionRequests(storage.SubscriptionRequestQuery{
+			Status:       storage.RequestPending,
+			CreatedAfter: created.since,
+		})
+		if err != nil {
+			fmt.Println(err.Error())
+			continue
+		}
+		for _, request := range created.advance(pending) {
+			notice, err := requestedNotice(request, store)
+			if err != nil {
+				fmt.Println(err.Error())
+				continue
+			}
+			notices = append(notices, notice)
+		}
+		decisions, err := store.ListSubscriptionRequests(storage.SubscriptionRequestQuery{DecidedAfter: decided.since})
+		if err != nil {
+			fmt.Println(err.Error())
+			continue
+		}
+		for _, request := range decided.advance(decisions) {
+			notice, err := decidedNotice(request, store)
+			if err != nil {
+				fmt.Println(err.Error())
+				continue
+			}
+			notices = append(notices, notice)
+		}
+		for _, notice := range notices {
+			select {
+			case noticeChannel <- notice:
+			case <-stopChannel:
+				fmt.Println("subscription request notices stop")
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package broker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/auth"
+	"github.com/google/uuid"
+)
+
+const httpConfirmTimeout = 30 * time.Second //how long a confirmation over HTTP waits on the subscription
+
+type messageResponse struct {
+	Success      bool   `json:"success"`
+	Message      string `json:"message"`
+	RetryAfterMS int64  `json:"retry_after_ms,omitempty"` //set when refused by a rate limit
+}
+
+func createMessageResponse(success bool, message string) []byte {
+	res, _ := json.Marshal(messageResponse{
+		Success: success,
+		Message: message,
+	})
+	return res
+}
+
+func separateRoute(url string) []string {
+	path := strings.Split(url, "/")
+	if path[0] == "" {
+		path = path[1:]
+	}
+	if len(path) > 0 && path[len(path)-1] == "" {
+		path = path[:len(path)-1]
+	}
+	return path
+}
+
+//authenticate the client making an HTTP request, the id and secret are taken from the X-Client-Id and
+//X-Client-Secret headers or the client_id and client_secret query params as browsers can't set headers on an
+//event stream. an access token can be sent instead in the Authorization header or access_token query param, or
+//an API key in the X-API-Key header or api_key query param
+func authenticateRequest(r *http.Request, store storage.Store, access clientAccess) (*brokerClient, error) {
+	query := r.URL.Query()
+	supplied := credentials{
+		id:     r.Header.Get("X-Client-Id"),
+		secret: r.Header.Get("X-Client-Secret"),
+		apiKey: r.Header.Get("X-API-Key"),
+		token:  auth.BearerToken(r.Header.Get("Authorization")),
+	}
+	if supplied.id == "" {
+		supplied.id = query.Get("client_id")
+		supplied.secret = query.Get("client_secret")
+	}
+	if supplied.apiKey == "" {
+		supplied.apiKey = query.Get("api_key")
+	}
+	if supplied.token == "" {
+		supplied.token = query.Get("access_token")
+	}
+	if supplied.id == "" && supplied.apiKey == "" && supplied.token == "" {
+		return nil, fmt.Errorf("no credentials supplied")
+	}
+	return access.authenticateCredentials(supplied, store)
+}
+
+//request to confirm messages received over HTTP, passed on to the client's sessions if it has any open
+type httpConfirmRequest struct {
+	clientID     string
+	subscription storage.Subscription
+	confirmation *subscriptionManagerConfirmation
+}
+
+type httpConfirmRequestBody struct {
+	Messages []string `json:"messages"` //ids of the messages being confirmed
+}
+
+type httpConfirmResponse struct {
+	Success   bool `json:"success"`
+	Confirmed int  `json:"confirmed"`
+}
+
+//handle requests for consuming a subscription over HTTP rather than a websocket, either streamed as
+//server-sent events or pulled in batches
+func handleSubscriptionRequest(rw http.ResponseWriter, r *http.Request, channels connectionManagerChannels, store storage.Store, access clientAccess, allowedOrigin string) {
+	rw.Header().Set("Access-Control-Allow-Origin", allowedOrigin)
+	rw.Header().Set("Access-Control-Allow-Headers", "Content-Type, Last-Event-ID, X-Client-Id, X-Client-Secret, X-API-Key, Authorization")
+	if r.Method == "OPTIONS" {
+		rw.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		rw.Write(createMessageResponse(true, ""))
+		return
+	}
+
+	//subscriptions/{subscription_id}/{action}
+	path := separateRoute(r.URL.Path)
+	if len(path) != 3 {
+		http.NotFound(rw, r)
+		return
+	}
+	subscriptionID, action := path[1], path[2]
+
+	client, err := authenticateRequest(r, store, access)
+	if err != nil {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusForbidden)
+		rw.Write(createMessageResponse(false, "Forbidden >:("))
+		return
+	}
+	if limited := rateLimitError(access.allowRequest(store, client.Id)); limited != nil {
+		writeRateLimited(rw, limited)
+		return
+	}
+	sub, found := client.findSubscription(subscriptionID)
+	if found && !client.scope.allows(sub.ID) {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusForbidden)
+		rw.Write(createMessageResponse(false, "API key isn't scoped to the subscription"))
+		return
+	}
+	if !found {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusNotFound)
+		rw.Write(createMessageResponse(false, "subscription not found"))
+		return
+	}
+	if sub.Type == storage.SubscriptionTypeWebhook {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(http.StatusConflict)
+		rw.Write(createMessageResponse(false, "subscription is delivered to a webhook"))
+		return
+	}
+
+	switch {
+	case action == "events" && r.Method == "GET":
+		handleSubscriptionEvents(rw, r, client, sub, channels, store, access)
+	case action == "confirm" && r.Method == "POST":
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Write(handleHTTPConfirm(r.Body, client, sub, channels))
+	case action == "pull" && r.Method == "POST":
+		status, response := handlePull(r.Context(), r.URL.Query(), client, sub, store, channels.draining)
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(status)
+		rw.Write(response)
+	case action == "ack" && r.Method == "POST":
+		status, response := handleAck(r.Body, client, sub, store)
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(status)
+		rw.Write(response)
+	case action == "nack" && r.Method == "POST":
+		status, response := handleNack(r.Body, client, sub, store)
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(status)
+		rw.Write(response)
+	default:
+		http.NotFound(rw, r)
+	}
+}
+
+//stream a subscription's messages to the client as server-sent events
+func handleSubscriptionEvents(rw http.ResponseWriter, r *http.Request, client *brokerClient, sub storage.Subscription, channels connectionManagerChannels, store storage.Store, access clientAccess) {
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		http.Error(rw, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	//the browser sends the id of the last event it received when reconnecting
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+	if lastEventID != "" {
+		err := resumeFromEvent(store, client.Id, sub, lastEventID)
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+	}
+
+	stream := newEventStream(sessionInfo{
+		id:            client.Id,
+		name:          client.Name,
+		sessionID:     uuid.New().String(),
+		remoteAddress: r.RemoteAddr,
+		connectedAt:   time.Now(),
+		policy: sessionPolicy{
+			delivery: r.URL.Query().Get("delivery"),
+		},
+	}, sub.ID)
+
+	request := newConnectionRequest{
+		session:         stream,
+		subscriptions:   []storage.Subscription{sub},
+		connectionLimit: client.connectionLimit(access),
+		addedChannel:    make(chan error),
+	}
+	channels.newConnection <- &request
+	err := <-request.addedChannel
+	if limited := rateLimitError(err); limited != nil {
+		writeRateLimited(rw, limited)
+		return
+	}
+	if err != nil {
+		status := http.StatusServiceUnavailable
+		if _, conflict := err.(*deliveryConflictError); conflict {
+			status = http.StatusConflict
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(status)
+		rw.Write(createMessageResponse(false, err.Error()))
+		return
+	}
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.Header().Set("Connection", "keep-alive")
+	rw.WriteHeader(http.StatusOK)
+	stream.queue(formatEvent("", "session_started", 0, jsonCommunication{
+		Action: "session_started",
+		Data: map[string]string{
+			"session_id": stream.sessionID,
+			"delivery":   stream.sessions.delivery,
+		},
+	}))
+
+	stream.writeLoop(rw, flusher, r.Context())
+
+	stream.close()
+	channels.lostConnection <- stream
+}
+
+//confirm messages received on an event stream
+func handleHTTPConfirm(body io.ReadCloser, client *brokerClient, sub storage.Subscription, channels connectionManagerChannels) []byte {
+	failedMessage := "failed to confirm messages"
+	defer body.Close()
+	bytes, err := io.ReadAll(body)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	requestBody := httpConfirmRequestBody{}
+	err = json.Unmarshal(bytes, &requestBody)
+	if err != nil {
+		return createMessageResponse(false, "Invalid json format")
+	}
+
+	messages := []confirmMessageData{}
+	for _, id := range requestBody.Messages {
+		messages = append(messages, confirmMessageData{
+			Id:             id,
+			SubscriptionID: sub.ID,
+		})
+	}
+	request := httpConfirmRequest{
+		clientID:     client.Id,
+		subscription: sub,
+		confirmation: &subscriptionManagerConfirmation{
+			messages:               messages,
+			numberConfirmedChannel: make(chan int, 1),
+		},
+	}
+	channels.confirm <- &request
+
+	select {
+	case confirmed := <-request.confirmation.numberConfirmedChannel:
+		response, _ := json.Marshal(httpConfirmResponse{
+			Success:   true,
+			Confirmed: confirmed,
+		})
+		return response
+	case <-time.After(httpConfirmTimeout):
+		return createMessageResponse(false, "timed out waiting for the subscription")
+	}
+}
@@ -0,0 +1,306 @@
+package broker
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"github.com/google/uuid"
+)
+
+const (
+	webhookTimeout         = 10 * time.Second //how long the target has to respond to a delivery
+	webhookRefreshInterval = 10 * time.Second //how often the dispatcher looks for webhooks which have been added, changed or removed
+	webhookInitialBackoff  = time.Second      //wait after the first failed delivery, doubled with each failure after it
+	webhookMaxBackoff      = 5 * time.Minute
+	webhookUnhealthyAfter  = 5 //consecutive failures before the webhook is marked unhealthy
+
+	webhookSignatureHeader = "X-Webhook-Signature"
+	webhookTimestampHeader = "X-Webhook-Timestamp"
+	webhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+//body POSTed to a webhook
+type webhookPayload struct {
+	SubscriptionID string            `json:"subscription_id"`
+	PublisherID    string            `json:"publisher_id"`
+	Messages       []jsonMessageItem `json:"messages"`
+}
+
+//hex HMAC-SHA256 of the timestamp and body, signed with the webhook's secret
+func signWebhook(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+//whether an address is on the broker's own host or network, e.g. loopback, a private range or link-local such as
+//a cloud provider's metadata service
+func privateAddress(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified()
+}
+
+//refuse to connect to a private address, checked once the host has been resolved so hostnames and redirects
+//pointing at one are refused too
+func refusePrivateAddress(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || privateAddress(ip) {
+		return fmt.Errorf("webhook address %s isn't public", host)
+	}
+	return nil
+}
+
+//client POSTing deliveries to webhooks, which only connects to public addresses unless private ones are allowed
+func webhookClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: webhookTimeout}
+	if !allowPrivate {
+		dialer.Control = refusePrivateAddress
+	}
+	return &http.Client{
+		Timeout: webhookTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: webhookTimeout,
+			MaxIdleConnsPerHost: 2,
+		},
+	}
+}
+
+//wait before retrying after a number of consecutive failures
+func webhookBackoff(failures int) time.Duration {
+	backoff := webhookInitialBackoff
+	for i := 1; i < failures && backoff < webhookMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > webhookMaxBackoff {
+		backoff = webhookMaxBackoff
+	}
+	return backoff
+}
+
+//delivers the messages of a single webhook subscription
+type webhookWorker struct {
+	clientID       string
+	subscription   storage.Subscription
+	settings       settings //poll interval is the wait between checking for messages when there was nothing to send
+	stopChannel    chan bool
+	stoppedChannel chan bool //closed once the loop has exited
+}
+
+//POST a batch to the webhook, any 2xx response confirms it
+func (worker *webhookWorker) deliver(client *http.Client, messages []jsonMessageItem) error {
+	body, err := json.Marshal(webhookPayload{
+		SubscriptionID: worker.subscription.ID,
+		PublisherID:    worker.subscription.PublisherID,
+		Messages:       messages,
+	})
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, "POST", worker.subscription.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(webhookTimestampHeader, timestamp)
+	request.Header.Set(webhookSignatureHeader, signWebhook(worker.subscription.Webhook.Secret, timestamp, body))
+	request.Header.Set(webhookDeliveryHeader, uuid.New().String())
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64*1024))
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", response.StatusCode)
+	}
+	return nil
+}
+
+//record the outcome of a delivery against the subscription so it shows up when listing subscriptions
+func (worker *webhookWorker) recordStatus(store storage.Store, failures int, deliveryErr error, nextAttempt time.Time) {
+	delivery := storage.WebhookDelivery{
+		Status:              storage.WebhookStatusHealthy,
+		ConsecutiveFailures: failures,
+		NextAttemptAt:       nextAttempt,
+	}
+	if failures >= webhookUnhealthyAfter {
+		delivery.Status = storage.WebhookStatusUnhealthy
+	}
+	if deliveryErr != nil {
+		delivery.Error = deliveryErr.Error()
+	}
+	err := store.RecordWebhookDelivery(worker.clientID, worker.subscription.ID, delivery)
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+}
+
+//loop delivering batches to the webhook until stopped, backing off while deliveries are failing
+func (worker *webhookWorker) loop(store storage.Store, cluster *clusterManager, client *http.Client) {
+	defer close(worker.stoppedChannel)
+	defer cluster.releaseSubscription(worker.subscription.ID)
+	failures := 0
+	for {
+		wait := worker.settings.pollInterval
+		if cluster.claimSubscription(worker.subscription.ID) {
+			messages := leaseMessages(store, worker.subscription.PublisherID, worker.clientID, worker.subscription.ID, int64(worker.settings.batchSize), messageLeaseDuration)
+			if len(messages) > 0 {
+				messageIDs := unconfirmedMessages(messages, []string{})
+				err := worker.deliver(client, messages)
+				if err == nil {
+					confirmMessages(store, worker.subscription.PublisherID, worker.clientID, messageIDs)
+					if failures > 0 || len(messages) < worker.settings.batchSize {
+						//only record successes when recovering or once caught up, rather than writing for every batch
+						worker.recordStatus(store, 0, nil, time.Time{})
+					}
+					failures = 0
+					//there may be more waiting
+					wait = 0
+				} else {
+					releaseLeases(store, worker.subscription.PublisherID, worker.clientID, messageIDs, 0)
+					failures++
+					wait = webhookBackoff(failures)
+					worker.recordStatus(store, failures, err, time.Now().Add(wait))
+				}
+			}
+		}
+		select {
+		case <-time.After(wait):
+		case <-worker.stopChannel:
+			return
+		}
+	}
+}
+
+func (worker *webhookWorker) stop() {
+	close(worker.stopChannel)
+	select {
+	case <-worker.stoppedChannel:
+	case <-time.After(webhookTimeout + 5*time.Second):
+	}
+}
+
+//runs a worker for each webhook subscription, pushing their messages to the target URLs
+type webhookDispatcher struct {
+	store          storage.Store
+	cluster        *clusterManager
+	settings       settings
+	client         *http.Client
+	workers        map[string]*webhookWorker //workers by subscription id
+	stopChannel    chan bool
+	stoppedChannel chan bool //closed once every worker has stopped
+}
+
+func newWebhookDispatcher(store storage.Store, cluster *clusterManager, settings settings) *webhookDispatcher {
+	return &webhookDispatcher{
+		store:          store,
+		cluster:        cluster,
+		settings:       settings,
+		client:         webhookClient(settings.privateWebhooks),
+		workers:        make(map[string]*webhookWorker),
+		stopChannel:    make(chan bool),
+		stoppedChannel: make(chan bool),
+	}
+}
+
+//find every webhook subscription
+func (dispatcher *webhookDispatcher) findWebhooks() (map[string]*webhookWorker, error) {
+	found, err := dispatcher.store.ListWebhooks()
+	if err != nil {
+		return nil, err
+	}
+	webhooks := make(map[string]*webhookWorker)
+	for _, webhook := range found {
+		if webhook.Subscription.Webhook.URL == "" {
+			continue
+		}
+		webhooks[webhook.Subscription.ID] = &webhookWorker{
+			clientID:       webhook.ClientID,
+			subscription:   webhook.Subscription,
+			settings:       dispatcher.settings,
+			stopChannel:    make(chan bool),
+			stoppedChannel: make(chan bool),
+		}
+	}
+	return webhooks, nil
+}
+
+//start workers for new webhooks, restart the ones which have changed and stop the ones which have been removed
+func (dispatcher *webhookDispatcher) refresh() {
+	webhooks, err := dispatcher.findWebhooks()
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+	for id, worker := range dispatcher.workers {
+		found, exists := webhooks[id]
+		if exists && found.subscription.PublisherID == worker.subscription.PublisherID &&
+			found.subscription.Webhook.URL == worker.subscription.Webhook.URL && found.subscription.Webhook.Secret == worker.subscription.Webhook.Secret {
+			continue
+		}
+		go worker.stop()
+		delete(dispatcher.workers, id)
+	}
+	for id, worker := range webhooks {
+		if _, running := dispatcher.workers[id]; running {
+			continue
+		}
+		dispatcher.workers[id] = worker
+		go worker.loop(dispatcher.store, dispatcher.cluster, dispatcher.client)
+	}
+}
+
+//loop running in a goroutine keeping the workers in step with the webhook subscriptions
+func (dispatcher *webhookDispatcher) loop() {
+	defer close(dispatcher.stoppedChannel)
+	for {
+		dispatcher.refresh()
+		select {
+		case <-time.After(webhookRefreshInterval):
+		case <-dispatcher.stopChannel:
+			//stop the workers together rather than waiting on each delivery in turn
+			for _, worker := range dispatcher.workers {
+				close(worker.stopChannel)
+			}
+			timeout := time.After(webhookTimeout + 5*time.Second)
+			for _, worker := range dispatcher.workers {
+				select {
+				case <-worker.stoppedChannel:
+				case <-timeout:
+				}
+			}
+			fmt.Println("webhook dispatcher stop")
+			return
+		}
+	}
+}
+
+//stop the workers, waiting for any deliveries in progress to finish
+func (dispatcher *webhookDispatcher) stop() {
+	close(dispatcher.stopChannel)
+	select {
+	case <-dispatcher.stoppedChannel:
+	case <-time.After(webhookTimeout + 5*time.Second):
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	"bezberr.com/messagebroker/broker"
+	config "bezberr.com/messagebrokerconfig"
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/limits"
+)
+
+const envPrefix = "MESSAGE_BROKER"
+
+//settings of the message broker, see config.Load for where they're read from
+type brokerConfig struct {
+	HTTPAddress     string                 `config:"http_address" flag:"http-address" usage:"address to accept websocket and HTTP connections on"`
+	GRPCAddress     string                 `config:"grpc_address" flag:"grpc-address" usage:"address to accept gRPC streams on, empty to disable gRPC"`
+	MQTTAddress     string                 `config:"mqtt_address" flag:"mqtt-address" usage:"address to accept MQTT connections on, empty to disable MQTT"`
+	AuthTimeout     time.Duration          `config:"auth_timeout" flag:"auth-timeout" usage:"how long a client has to authenticate after connecting"`
+	PollInterval    time.Duration          `config:"poll_interval" flag:"poll-interval" usage:"wait between checking a subscription for new messages"`
+	BatchSize       int                    `config:"batch_size" flag:"batch-size" usage:"most messages sent to a client or webhook at once"`
+	AllowedOrigin   string                 `config:"allowed_origin" flag:"allowed-origin" usage:"origin of the web frontend allowed to consume subscriptions over HTTP"`
+	PrivateWebhooks bool                   `config:"private_webhooks" flag:"private-webhooks" usage:"deliver to webhooks on loopback, private and link-local addresses, for local development"`
+	TokenSecret     string                 `config:"token_secret" flag:"token-secret" secret:"true" usage:"key the publisher service signs access tokens with, tokens aren't accepted if empty"`
+	RateLimits      limits.RateLimitConfig `config:"rate_limits"`
+	Quotas          limits.Quotas         `config:"quotas"`
+	Payloads        limits.PayloadLimits   `config:"payloads"`
+	Cluster         clusterConfig          `config:"cluster"`
+	Storage         storage.Config         `config:"storage"`
+}
+
+type clusterConfig struct {
+	Enabled          bool   `config:"enabled" flag:"cluster" usage:"coordinate with other instances sharing the storage"`
+	InstanceID       string `config:"instance_id" flag:"instance-id" usage:"unique id of this instance in the cluster, defaults to the hostname plus a random suffix"`
+	AdvertiseAddress string `config:"advertise_address" flag:"advertise-address" usage:"address other instances and load balancers can reach this instance on"`
+}
+
+func defaultConfig() *brokerConfig {
+	return &brokerConfig{
+		HTTPAddress:   broker.DefaultHTTPAddress,
+		GRPCAddress:   broker.DefaultGRPCAddress,
+		MQTTAddress:   broker.DefaultMQTTAddress,
+		AuthTimeout:   broker.DefaultAuthTimeout,
+		PollInterval:  broker.DefaultPollInterval,
+		BatchSize:     broker.DefaultBatchSize,
+		AllowedOrigin: broker.DefaultAllowedOrigin,
+		Payloads:      limits.DefaultPayloadLimits(),
+		Storage:       storage.DefaultConfig(),
+	}
+}
+
+func (brokerConfig *brokerConfig) Validate() error {
+	err := config.ValidateAddress("http_address", brokerConfig.HTTPAddress)
+	if err == nil && brokerConfig.GRPCAddress != "" {
+		err = config.ValidateAddress("grpc_address", brokerConfig.GRPCAddress)
+	}
+	if err == nil && brokerConfig.MQTTAddress != "" {
+		err = config.ValidateAddress("mqtt_address", brokerConfig.MQTTAddress)
+	}
+	if err != nil {
+		return err
+	}
+	if brokerConfig.AuthTimeout <= 0 {
+		return errors.New("auth_timeout must be positive")
+	}
+	if brokerConfig.PollInterval <= 0 {
+		return errors.New("poll_interval must be positive")
+	}
+	if brokerConfig.BatchSize < 1 || brokerConfig.BatchSize > 1000 {
+		return errors.New("batch_size must be between 1 and 1000")
+	}
+	if brokerConfig.AllowedOrigin == "" {
+		return errors.New("allowed_origin is required")
+	}
+	err = brokerConfig.RateLimits.Validate()
+	if err == nil {
+		err = brokerConfig.Quotas.Validate()
+	}
+	if err == nil {
+		err = brokerConfig.Payloads.Validate()
+	}
+	if err != nil {
+		return err
+	}
+	return brokerConfig.Storage.Validate()
+}
+
+//options for the broker set by the config
+func (brokerConfig *brokerConfig) serverOptions() []broker.Option {
+	options := []broker.Option{
+		broker.WithHTTPAddress(brokerConfig.HTTPAddress),
+		broker.WithGRPCAddress(brokerConfig.GRPCAddress),
+		broker.WithMQTTAddress(brokerConfig.MQTTAddress),
+		broker.WithAuthTimeout(brokerConfig.AuthTimeout),
+		broker.WithPollInterval(brokerConfig.PollInterval),
+		broker.WithBatchSize(brokerConfig.BatchSize),
+		broker.WithAllowedOrigin(brokerConfig.AllowedOrigin),
+		broker.WithPrivateWebhooks(brokerConfig.PrivateWebhooks),
+		broker.WithTokenSecret([]byte(brokerConfig.TokenSecret)),
+		broker.WithRateLimits(brokerConfig.RateLimits),
+		broker.WithQuotas(brokerConfig.Quotas),
+		broker.WithPayloadLimits(brokerConfig.Payloads),
+	}
+	if brokerConfig.Cluster.Enabled {
+		options = append(options, broker.WithCluster(brokerConfig.Cluster.InstanceID, brokerConfig.Cluster.AdvertiseAddress))
+	}
+	return options
+}
@@ -1,236 +1,62 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"net/http"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
-	"github.com/google/uuid"
-	"github.com/gorilla/websocket"
-	"go.mongodb.org/mongo-driver/bson"
+	"bezberr.com/messagebroker/broker"
+	config "bezberr.com/messagebrokerconfig"
+	storage "bezberr.com/messagebrokerstorage"
 )
 
-var upgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-}
-
-//struct for reusable success/error channel responses
-type errorSuccess struct {
-	successChannel chan bool
-	errorChannel   chan error
-}
-
-//struct to define JSON messages sent to and from the client
-type jsonCommunication struct {
-	Action  string      `json:"action"`
-	Message string      `json:"message,omitempty"`
-	Data    interface{} `json:"data,omitempty"`
-}
-
-//struct sent to the send channel with the message to be sent + the error/success response channels
-type sendRequest struct {
-	message      interface{}
-	errorSuccess errorSuccess
-}
-
-type confirmMessageData struct {
-	Id             string `json:"id"`              //id of the message being confirmed
-	SubscriptionID string `json:"subscription_id"` //id of the subscription the message was received on
-}
-type confirmRequestData struct {
-	Messages []confirmMessageData `json:"messages"` //slice of messages being confirmed from the client including the message ID and the subscription id
-}
-type confirmRequest struct {
-	Action  string             `json:"action"`
-	Message string             `json:"message"`
-	Data    confirmRequestData `json:"data"`
-}
-
-func handleConfirmMessage(message string, client *clientConnection) {
-	confirmRequest := confirmRequest{}
-	err := json.Unmarshal([]byte(message), &confirmRequest)
-	if err != nil {
-		client.send(jsonCommunication{
-			Action:  "failed_confirmation",
-			Message: "Invalid json format",
-		}, errorSuccess{})
-		return
-	}
-	confirmMessagesStruct := subscriptionManagerConfirmation{
-		messages:               confirmRequest.Data.Messages,
-		numberConfirmedChannel: make(chan int),
-	}
-	client.subscriptionManager.confirmChannel <- &confirmMessagesStruct
-	confirmed := <-confirmMessagesStruct.numberConfirmedChannel
-	client.send(jsonCommunication{
-		Action: "messages_confirmed",
-		Data: map[string]int{
-			"confirmed": confirmed,
-		},
-	}, errorSuccess{})
-}
-func handleClientMessage(message string, client *clientConnection, mongoManager *mongoManager) {
-	jsonMsg := jsonCommunication{}
-	err := json.Unmarshal([]byte(message), &jsonMsg)
-	if err != nil {
-		client.send(jsonCommunication{
-			Action:  "invalid_message",
-			Message: "The message sent was incorrectly formatted",
-		}, errorSuccess{})
+func main() {
+	brokerConfig := defaultConfig()
+	err := config.Load(brokerConfig, config.Options{Name: "message-broker", EnvPrefix: envPrefix})
+	if errors.Is(err, flag.ErrHelp) {
 		return
+	} else if err != nil {
+		log.Fatalf("Failed loading config, %s", err.Error())
 	}
-	switch jsonMsg.Action {
-	case "confirm_messages": //request to confirm that the client received a set of messages from a subscription
-		handleConfirmMessage(message, client)
-	}
-}
-
-//loop running in a goroutine to handle messages coming from the client via the websocket
-func clientMessagesLoop(client *clientConnection, mongoManager *mongoManager) {
-	closed := false
-	for {
-		select {
-		case message := <-client.receiveChannel: //received a message from the client
-			handleClientMessage(message, client, mongoManager)
-		case <-client.receiveClosedChannel:
-			closed = true
-			fmt.Println("receive loop stop")
-		}
-		if closed {
-			break
-		}
-	}
-}
-
-//loop running in a goroutine to handle deleting expired messages
-func handleExpiredMessages(mongoManager *mongoManager) {
-
-	for {
-		filter := bson.D{
-			{Key: "ttl", Value: bson.D{
-				{Key: "$lt", Value: time.Now().Unix()},
-				{Key: "$ne", Value: 0},
-			},
-			},
-		}
+	fmt.Println("Config:")
+	config.Print(os.Stdout, brokerConfig)
 
-		col := mongoManager.connection.Database("message-broker").Collection("publisher_messages")
-		_, err := mongoDeleteMany(col, filter)
-		if err != nil {
-			fmt.Println(err.Error())
-		}
-		<-time.After(time.Second * 30)
-	}
-}
-
-//channels for the connection manager
-type connectionManagerChannels struct {
-	newConnection  chan *clientConnection //receive new client connections
-	lostConnection chan *clientConnection //channel to remove closed client connections
-}
-
-//manage client connections
-func connectionManager(channels connectionManagerChannels) {
-	//map to store open connections
-	connections := make(map[string]*clientConnection)
-	for {
-		select {
-		case newCon := <-channels.newConnection: //received a new client connection, add it to the map
-			connections[newCon.id] = newCon
-		case lostCon := <-channels.lostConnection: //lost a client connection, remove it from the map
-			delete(connections, lostCon.id)
-		}
-	}
-}
-
-//handle setting up and authenticating a new client connection
-func handleConnection(con *websocket.Conn, managerChannels connectionManagerChannels, mongoManager *mongoManager) {
-	client := clientConnection{
-		id:                   uuid.New().String(),
-		connection:           con,
-		receiveChannel:       make(chan string),
-		sendChannel:          make(chan sendRequest),
-		receiveClosedChannel: make(chan bool),
-		sendClosedChannel:    make(chan bool),
+	store, err := storage.Open(brokerConfig.Storage)
+	if err != nil {
+		log.Fatalf("Failed opening storage, %s", err.Error())
 	}
-
-	//start the receive messages loop
-	go client.receiveLoop(managerChannels)
-
-	//start the send message loop
-	go client.sendLoop()
-
-	//authenticate the client connection
-	bsonClient, err := authenticate(&client, mongoManager)
-
+	server, err := broker.New(append(brokerConfig.serverOptions(), broker.WithStore(store))...)
 	if err != nil {
-		client.close()
-		return
+		log.Fatalf("Failed creating the broker, %s", err.Error())
 	}
-
-	//add authed client to the manager
-	managerChannels.newConnection <- &client
-	subManager := subscriptionManager{
-		subscriptions:             map[string]*subscription{},
-		newSubscriptionChannel:    make(chan *subscription),
-		confirmChannel:            make(chan *subscriptionManagerConfirmation),
-		removeSubscriptionChannel: make(chan string),
-		cancelReceiveChannel:      make(chan bool),
-		cancelManagerChannel:      make(chan bool),
-		sendToClientChannel:       client.sendChannel,
+	err = server.Start(context.Background())
+	if err != nil {
+		log.Fatalf("Failed starting the broker, %s", err.Error())
 	}
-	client.subscriptionManager = &subManager
 
-	go client.subscriptionManager.managerLoop(mongoManager)
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
-	for _, sub := range bsonClient.Subscriptions {
-		client.subscriptionManager.newSubscriptionChannel <- &subscription{
-			id:                      sub.Id,
-			publisherID:             sub.PublisherId,
-			clientID:                client.id,
-			cancelChannel:           make(chan bool),
-			messagesChannel:         make(chan []jsonMessageItem),
-			receiveConfirmedChannel: make(chan *subscriptionMessagesConfirmation),
-		}
-	}
-
-	//start receiving messages from the client
-	go clientMessagesLoop(&client, mongoManager)
-}
+	//wait for SIGINT or SIGTERM
+	<-stop
 
-func main() {
-	//channels for the client manager
-	channels := connectionManagerChannels{
-		newConnection:  make(chan *clientConnection),
-		lostConnection: make(chan *clientConnection),
+	fmt.Println("Draining")
+	ctx, cancel := context.WithTimeout(context.Background(), 35*time.Second)
+	defer cancel()
+	err = server.Shutdown(ctx)
+	if err != nil {
+		fmt.Println(err)
 	}
 
-	mongoManager, err := startMongo()
+	err = store.Close()
 	if err != nil {
-		fmt.Printf("Failed opening mongo connection, %s", err.Error())
+		fmt.Println(err.Error())
 	}
-	//start the client manager
-	go connectionManager(channels)
-
-	go handleExpiredMessages(mongoManager)
-
-	//route to open a websocket connection
-	http.HandleFunc("/ws", func(rw http.ResponseWriter, r *http.Request) {
-		//hijack the request and turn it into a websocket connection
-		upgrader.CheckOrigin = func(r *http.Request) bool {
-
-			return true
-		}
-		con, err := upgrader.Upgrade(rw, r, nil)
-		if err != nil {
-			fmt.Println(err.Error())
-		} else {
-			//start handling the connection
-			go handleConnection(con, channels, mongoManager)
-		}
-	})
-
-	http.ListenAndServe(":8001", nil)
+	fmt.Println("Close")
 }
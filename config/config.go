@@ -0,0 +1,348 @@
+//Package config loads the settings of the message broker and the publisher service. Settings are the fields of a
+//struct, tagged with the key they're set by, and are read from (in increasing priority) the defaults the struct
+//already holds, a YAML or TOML file, environment variables and command line flags:
+//
+//	type serviceConfig struct {
+//		HTTPAddress string        `config:"http_address" flag:"http-address" usage:"address to serve HTTP on"`
+//		Storage     storageConfig `config:"storage"`
+//	}
+//
+//http_address is read from the http_address key of the file, the <PREFIX>_HTTP_ADDRESS environment variable and
+//the -http-address flag. fields of nested structs are keyed under the struct's key, e.g. storage.backend in the
+//file and <PREFIX>_STORAGE_BACKEND in the environment, and need a flag tag to be set from the command line.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+//Options for loading a config
+type Options struct {
+	Name      string   //name of the program, shown in the usage message
+	EnvPrefix string   //prefix of the environment variables, e.g. MESSAGE_BROKER
+	Args      []string //command line arguments without the program name, os.Args[1:] if nil
+}
+
+//Validator is implemented by configs which check their settings once they're loaded
+type Validator interface {
+	Validate() error
+}
+
+//setting is a field of the config along with how it's set
+type setting struct {
+	key    string //dotted path of the setting in the file
+	env    string
+	flag   string
+	usage  string
+	secret bool
+	value  reflect.Value
+}
+
+//settings of a config struct, depth first in the order the fields are declared
+func settings(value reflect.Value, prefix string, envPrefix string) []*setting {
+	found := []*setting{}
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		name := field.Tag.Get("config")
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+		key := prefix + name
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Duration(0)) {
+			found = append(found, settings(value.Field(i), key+".", envPrefix)...)
+			continue
+		}
+		env := strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+		if envPrefix != "" {
+			env = envPrefix + "_" + env
+		}
+		found = append(found, &setting{
+			key:    key,
+			env:    env,
+			flag:   field.Tag.Get("flag"),
+			usage:  field.Tag.Get("usage"),
+			secret: field.Tag.Get("secret") == "true",
+			value:  value.Field(i),
+		})
+	}
+	return found
+}
+
+//set a setting from text, as found in the environment or on the command line
+func (setting *setting) set(text string) error {
+	value := setting.value
+	switch {
+	case value.Type() == reflect.TypeOf(time.Duration(0)):
+		duration, err := time.ParseDuration(text)
+		if err != nil {
+			return fmt.Errorf("%s must be a duration such as 30s", setting.key)
+		}
+		value.SetInt(int64(duration))
+	case value.Kind() == reflect.String:
+		value.SetString(text)
+	case value.Kind() == reflect.Bool:
+		parsed, err := strconv.ParseBool(text)
+		if err != nil {
+			return fmt.Errorf("%s must be true or false", setting.key)
+		}
+		value.SetBool(parsed)
+	case value.Kind() == reflect.Int:
+		parsed, err := strconv.Atoi(text)
+		if err != nil {
+			return fmt.Errorf("%s must be a whole number", setting.key)
+		}
+		value.SetInt(int64(parsed))
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.String:
+		items := []string{}
+		for _, item := range strings.Split(text, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("%s has an unsupported type %s", setting.key, value.Type())
+	}
+	return nil
+}
+
+//set a setting from a value decoded from a file
+func (setting *setting) setDecoded(decoded interface{}) error {
+	switch typed := decoded.(type) {
+	case string:
+		return setting.set(typed)
+	case bool:
+		return setting.set(strconv.FormatBool(typed))
+	case int:
+		return setting.set(strconv.Itoa(typed))
+	case int64:
+		return setting.set(strconv.FormatInt(typed, 10))
+	case []interface{}:
+		items := []string{}
+		for _, item := range typed {
+			items = append(items, fmt.Sprint(item))
+		}
+		return setting.set(strings.Join(items, ","))
+	}
+	return fmt.Errorf("%s has an invalid value %v", setting.key, decoded)
+}
+
+//text form of a setting's value, as given in the usage message and printed config
+func (setting *setting) String() string {
+	value := setting.value
+	switch {
+	case value.Type() == reflect.TypeOf(time.Duration(0)):
+		return time.Duration(value.Int()).String()
+	case value.Kind() == reflect.Slice:
+		items := []string{}
+		for i := 0; i < value.Len(); i++ {
+			items = append(items, value.Index(i).String())
+		}
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+//value of a command line flag, kept until the file and environment have been read so the flag takes priority
+type flagValue struct {
+	setting *setting
+	isBool  bool
+	values  *[]flagAssignment
+}
+
+type flagAssignment struct {
+	setting *setting
+	text    string
+}
+
+func (value flagValue) String() string {
+	if value.setting == nil || value.setting.secret {
+		return ""
+	}
+	return value.setting.String()
+}
+
+func (value flagValue) Set(text string) error {
+	*value.values = append(*value.values, flagAssignment{setting: value.setting, text: text})
+	return nil
+}
+
+func (value flagValue) IsBoolFlag() bool {
+	return value.isBool
+}
+
+//read a YAML or TOML file, picked by its extension, into nested maps
+func readFile(path string) (map[string]interface{}, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	decoded := map[string]interface{}{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(contents, &decoded)
+	case ".toml":
+		err = toml.Unmarshal(contents, &decoded)
+	default:
+		return nil, fmt.Errorf("config file %s should be .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading config file %s: %w", path, err)
+	}
+	return decoded, nil
+}
+
+//flatten nested maps into dotted keys
+func flatten(decoded map[string]interface{}, prefix string, flattened map[string]interface{}) {
+	for key, value := range decoded {
+		if nested, ok := value.(map[string]interface{}); ok {
+			flatten(nested, prefix+key+".", flattened)
+		} else {
+			flattened[prefix+key] = value
+		}
+	}
+}
+
+//Load the config, a pointer to a struct holding the defaults, from the file named by the -config flag or the
+//<PREFIX>_CONFIG environment variable, then the environment and then the command line. the config is validated
+//if it implements Validator. flag.ErrHelp is returned if the usage message was asked for
+func Load(config interface{}, options Options) error {
+	value := reflect.ValueOf(config)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return errors.New("config must be a pointer to a struct")
+	}
+	found := settings(value.Elem(), "", options.EnvPrefix)
+	configEnv := "CONFIG"
+	if options.EnvPrefix != "" {
+		configEnv = options.EnvPrefix + "_CONFIG"
+	}
+
+	//the flags are parsed first to find the config file, but only applied once everything else has been
+	flags := flag.NewFlagSet(options.Name, flag.ContinueOnError)
+	configPath := flags.String("config", os.Getenv(configEnv), "YAML or TOML file to read the settings from, or set "+configEnv)
+	assignments := []flagAssignment{}
+	for _, setting := range found {
+		if setting.flag == "" {
+			continue
+		}
+		usage := setting.usage
+		if usage != "" {
+			usage += ", "
+		}
+		usage += "or set " + setting.env
+		flags.Var(flagValue{setting: setting, isBool: setting.value.Kind() == reflect.Bool, values: &assignments}, setting.flag, usage)
+	}
+	args := options.Args
+	if args == nil {
+		args = os.Args[1:]
+	}
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %s", flags.Arg(0))
+	}
+
+	if *configPath != "" {
+		decoded, err := readFile(*configPath)
+		if err != nil {
+			return err
+		}
+		flattened := map[string]interface{}{}
+		flatten(decoded, "", flattened)
+		for _, setting := range found {
+			if decodedValue, exists := flattened[setting.key]; exists {
+				err = setting.setDecoded(decodedValue)
+				if err != nil {
+					return err
+				}
+				delete(flattened, setting.key)
+			}
+		}
+		unknown := []string{}
+		for key := range flattened {
+			unknown = append(unknown, key)
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return fmt.Errorf("unknown setting %s in %s", strings.Join(unknown, ", "), *configPath)
+		}
+	}
+
+	for _, setting := range found {
+		if text, exists := os.LookupEnv(setting.env); exists {
+			err = setting.set(text)
+			if err != nil {
+				return fmt.Errorf("%s: %w", setting.env, err)
+			}
+		}
+	}
+
+	for _, assignment := range assignments {
+		err = assignment.setting.set(assignment.text)
+		if err != nil {
+			return fmt.Errorf("-%s: %w", assignment.setting.flag, err)
+		}
+	}
+
+	if validator, ok := config.(Validator); ok {
+		err = validator.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid config, %w", err)
+		}
+	}
+	return nil
+}
+
+//redact the password of a URL, e.g. a connection string
+func redactURL(text string) string {
+	parsed, err := url.Parse(text)
+	if err != nil || parsed.User == nil {
+		return text
+	}
+	if _, hasPassword := parsed.User.Password(); !hasPassword {
+		return text
+	}
+	return parsed.Redacted()
+}
+
+//Print the settings of a config, one key = value per line. secret settings are masked and passwords in URLs redacted
+func Print(writer io.Writer, config interface{}) {
+	value := reflect.ValueOf(config)
+	if value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+	for _, setting := range settings(value, "", "") {
+		text := redactURL(setting.String())
+		if setting.secret && text != "" {
+			text = "********"
+		}
+		fmt.Fprintf(writer, "%s = %s\n", setting.key, text)
+	}
+}
+
+//ValidateAddress checks an address to listen on is a host and port, e.g. :8001
+func ValidateAddress(name string, address string) error {
+	_, port, err := net.SplitHostPort(address)
+	if err != nil || port == "" {
+		return fmt.Errorf("%s %q should be a host and port such as :8001", name, address)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package messagebrokerclient
+
+import (
+	"context"
+	"net/url"
+)
+
+//visibilities of a publisher, deciding who can subscribe to it
+const (
+	VisibilityPublic    = "public"    //any client in the publisher's organization or one it's shared with, the default
+	VisibilityPrivate   = "private"   //only the owner and clients whose subscription requests were approved
+	VisibilityAllowlist = "allowlist" //as private, along with the clients and groups on the allowlist
+)
+
+//ACL decides which clients can subscribe to a publisher
+type ACL struct {
+	Visibility string   `json:"visibility"`
+	Clients    []string `json:"clients"`  //ids of the clients on the allowlist
+	Groups     []string `json:"groups"`   //ids of the owner's groups on the allowlist
+	Approved   []string `json:"approved"` //ids of the clients whose subscription requests were approved, SetACL keeps them
+
+	Organizations []string `json:"organizations"` //ids of the organizations the publisher is shared with
+}
+
+//Group of clients which can be put on the allowlists of the client's publishers
+type Group struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Members []string `json:"members"` //ids of the clients in the group
+}
+
+//ACLChange is a publisher's ACL after a change, along with how many subscriptions the change took access away from.
+//those subscriptions are removed and stop receiving messages straight away
+type ACLChange struct {
+	ACL     ACL `json:"acl"`
+	Revoked int `json:"revoked"`
+}
+
+//GroupChange is a group after a change, along with how many subscriptions the change took access away from
+type GroupChange struct {
+	Group   Group `json:"group"`
+	Revoked int   `json:"revoked"`
+}
+
+func aclPath(publisherID string) string {
+	return "/publishers/" + url.PathEscape(publisherID) + "/acl"
+}
+
+//GetACL of one of the client's publishers
+func (client *Client) GetACL(ctx context.Context, publisherID string) (*ACL, error) {
+	result := ACLChange{}
+	err := client.call(ctx, "GET", aclPath(publisherID), nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result.ACL, nil
+}
+
+func (client *Client) changeACL(ctx context.Context, method string, path string, body interface{}) (*ACLChange, error) {
+	result := ACLChange{}
+	err := client.call(ctx, method, path, body, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+//SetACL replaces the ACL of one of the client's publishers
+func (client *Client) SetACL(ctx context.Context, publisherID string, acl ACL) (*ACLChange, error) {
+	return client.changeACL(ctx, "PUT", aclPath(publisherID), acl)
+}
+
+//AllowClient adds a client to a publisher's allowlist
+func (client *Client) AllowClient(ctx context.Context, publisherID string, clientID string) (*ACLChange, error) {
+	return client.changeACL(ctx, "POST", aclPath(publisherID)+"/clients", map[string]string{"client_id": clientID})
+}
+
+//DisallowClient takes a client off a publisher's allowlist
+func (client *Client) DisallowClient(ctx context.Context, publisherID string, clientID string) (*ACLChange, error) {
+	return client.changeACL(ctx, "DELETE", aclPath(publisherID)+"/clients/"+url.PathEscape(clientID), nil)
+}
+
+//AllowGroup adds one of the client's groups to a publisher's allowlist
+func (client *Client) AllowGroup(ctx context.Context, publisherID string, groupID string) (*ACLChange, error) {
+	return client.changeACL(ctx, "POST", aclPath(publisherID)+"/groups", map[string]string{"group_id": groupID})
+}
+
+//DisallowGroup takes a group off a publisher's allowlist
+func (client *Client) DisallowGroup(ctx context.Context, publisherID string, groupID string) (*ACLChange, error) {
+	return client.changeACL(ctx, "DELETE", aclPath(publisherID)+"/groups/"+url.PathEscape(groupID), nil)
+}
+
+//ListGroups of the client
+func (client *Client) ListGroups(ctx context.Context) ([]Group, error) {
+	result := struct {
+		Groups []Group `json:"groups"`
+	}{}
+	err := client.call(ctx, "GET", "/groups", nil, &result)
+	return result.Groups, err
+}
+
+//CreateGroup with a name unique amongst the client's groups
+func (client *Client) CreateGroup(ctx context.Context, name string, members []string) (*Group, error) {
+	result := GroupChange{}
+	err := client.call(ctx, "POST", "/groups", map[string]interface{}{"name": name, "members": members}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Group, nil
+}
+
+//SetGroupMembers replaces the members of one of the client's groups
+func (client *Client) SetGroupMembers(ctx context.Context, groupID string, members []string) (*GroupChange, error) {
+	result := GroupChange{}
+	err := client.call(ctx, "PUT", "/groups/"+url.PathEscape(groupID)+"/members", map[string]interface{}{"members": members}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+//DeleteGroup takes the group off the allowlists it's on and deletes it
+func (client *Client) DeleteGroup(ctx context.Context, groupID string) error {
+	return client.call(ctx, "DELETE", "/groups/"+url.PathEscape(groupID), nil, nil)
+}
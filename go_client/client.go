@@ -0,0 +1,426 @@
+//Package messagebrokerclient is a client for the message broker, covering the REST API of the publisher service
+//for registering, authenticating and managing publishers and subscriptions, and a Consumer which receives the
+//messages of the client's subscriptions over the message broker's websocket.
+package messagebrokerclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"time"
+)
+
+//Client calls the publisher service's REST API. It keeps the session cookie set when registering or
+//authenticating, so it should be reused for every call made as the same client.
+type Client struct {
+	serviceURL  string
+	httpClient  *http.Client
+	apiKey      string
+	token       func(ctx context.Context) (string, error)
+	adminSecret string
+}
+
+//Option configures a Client
+type Option func(*Client)
+
+//WithHTTPClient sets the HTTP client used for requests, a cookie jar is added if it doesn't have one
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(client *Client) {
+		client.httpClient = httpClient
+	}
+}
+
+//WithAPIKey authenticates every request with an API key rather than the session cookie, limiting the client to
+//the key's scopes
+func WithAPIKey(apiKey string) Option {
+	return func(client *Client) {
+		client.apiKey = apiKey
+	}
+}
+
+//WithToken authenticates every request with an access token, e.g. TokenSource.Token, rather than the session
+//cookie
+func WithToken(token func(ctx context.Context) (string, error)) Option {
+	return func(client *Client) {
+		client.token = token
+	}
+}
+
+//WithAdminSecret sends the publisher service's admin secret with every request, needed to call the admin methods
+//e.g. SetClientRateLimits
+func WithAdminSecret(secret string) Option {
+	return func(client *Client) {
+		client.adminSecret = secret
+	}
+}
+
+//New creates a client for the publisher service at serviceURL, e.g. http://localhost:8081
+func New(serviceURL string, options ...Option) (*Client, error) {
+	client := &Client{
+		serviceURL: strings.TrimSuffix(serviceURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, option := range options {
+		option(client)
+	}
+	if client.httpClient.Jar == nil {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, err
+		}
+		client.httpClient.Jar = jar
+	}
+	return client, nil
+}
+
+//APIError is returned when the service responds with success set to false
+type APIError struct {
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration //how long to wait before trying again when the call was refused by a rate limit
+	Quota      string        //the quota a message would have gone over, e.g. publisher_bytes
+	//size limit a payload was over, when the call was refused with 413 Request Entity Too Large because of it
+	PayloadLimit int
+	//version of the publisher's schema a payload didn't match, when the call was refused with 422 Unprocessable
+	//Entity because of it
+	SchemaVersion int
+}
+
+func (err *APIError) Error() string {
+	if err.Message == "" {
+		return fmt.Sprintf("message broker request failed with status %d", err.StatusCode)
+	}
+	return err.Message
+}
+
+//ClientDetails identifies a registered client
+type ClientDetails struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	OrganizationID string `json:"organization_id,omitempty"` //empty unless the client is a member of an organization
+}
+
+//Credentials a client authenticates with, the secret is only returned when registering or rotating it
+type Credentials struct {
+	ID     string `json:"id"`
+	Secret string `json:"secret"`
+}
+
+//APIKey lets a machine client act for the client without its secret, limited to the key's scopes
+type APIKey struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Key       string     `json:"key,omitempty"` //only returned when the key is created
+}
+
+//scopes an API key can be granted
+const (
+	ScopeManage = "manage" //manage the client's publishers and subscriptions
+)
+
+//ScopePublish lets an API key publish to a publisher
+func ScopePublish(publisherID string) string {
+	return "publish:" + publisherID
+}
+
+//ScopeSubscribe lets an API key consume a subscription
+func ScopeSubscribe(subscriptionID string) string {
+	return "subscribe:" + subscriptionID
+}
+
+//Publisher owned by the client, or by a member of its organization
+type Publisher struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	OwnerID string `json:"owner_id,omitempty"` //only set by ListOrganizationPublishers
+
+	ContentType     string `json:"content_type,omitempty"`      //payloads have to be, empty if they can be anything
+	MaxPayloadBytes int    `json:"max_payload_bytes,omitempty"` //only set by CreatePublisherWithPayload
+}
+
+//SubscriptionPublisher is the publisher a subscription is to
+type SubscriptionPublisher struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	OwnerID string `json:"owner_id"`
+}
+
+//WebhookStatus is the target and delivery status of a webhook subscription
+type WebhookStatus struct {
+	URL                 string     `json:"url"`
+	Status              string     `json:"status"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt       *time.Time `json:"last_failure_at,omitempty"`
+	NextAttemptAt       *time.Time `json:"next_attempt_at,omitempty"`
+}
+
+//Subscription of the client to a publisher
+type Subscription struct {
+	ID        string                `json:"id"`
+	Type      string                `json:"type"`
+	Publisher SubscriptionPublisher `json:"publisher"`
+	Webhook   *WebhookStatus        `json:"webhook,omitempty"`
+}
+
+//response fields shared by every route
+type apiResponse struct {
+	Success       bool   `json:"success"`
+	Message       string `json:"message"`
+	RetryAfterMS  int64  `json:"retry_after_ms"`
+	Quota         string `json:"quota"`
+	PayloadLimit  int    `json:"payload_limit"`
+	SchemaVersion int    `json:"schema_version"`
+}
+
+//send a JSON request and decode the response into result, an APIError is returned if the response doesn't have success set
+func doJSON(ctx context.Context, httpClient *http.Client, method string, requestURL string, header http.Header, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, requestURL, reader)
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	status := apiResponse{}
+	if err := json.Unmarshal(responseBody, &status); err != nil {
+		return &APIError{StatusCode: response.StatusCode, Message: strings.TrimSpace(string(responseBody))}
+	}
+	if !status.Success {
+		return &APIError{
+			StatusCode:    response.StatusCode,
+			Message:       status.Message,
+			RetryAfter:    time.Duration(status.RetryAfterMS) * time.Millisecond,
+			Quota:         status.Quota,
+			PayloadLimit:  status.PayloadLimit,
+			SchemaVersion: status.SchemaVersion,
+		}
+	}
+	if result != nil {
+		return json.Unmarshal(responseBody, result)
+	}
+	return nil
+}
+
+//send a request to the publisher service
+func (client *Client) call(ctx context.Context, method string, path string, body interface{}, result interface{}) error {
+	var header http.Header
+	if client.apiKey != "" {
+		header = http.Header{}
+		header.Set("X-API-Key", client.apiKey)
+	} else if client.token != nil {
+		token, err := client.token(ctx)
+		if err != nil {
+			return err
+		}
+		header = http.Header{}
+		header.Set("Authorization", "Bearer "+token)
+	}
+	if client.adminSecret != "" {
+		if header == nil {
+			header = http.Header{}
+		}
+		header.Set("X-Admin-Secret", client.adminSecret)
+	}
+	return doJSON(ctx, client.httpClient, method, client.serviceURL+path, header, body, result)
+}
+
+//Register a new client with a name unique amongst the clients outside of any organization, the client is logged in as it. Returns the id and secret of the new
+//client, which are needed to authenticate later on and can't be retrieved again
+func (client *Client) Register(ctx context.Context, name string) (*Credentials, error) {
+	result := struct {
+		Row Credentials `json:"row"`
+	}{}
+	err := client.call(ctx, "POST", "/register", map[string]string{"name": name}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Row, nil
+}
+
+//Authenticate as an existing client
+func (client *Client) Authenticate(ctx context.Context, id string, secret string) (*ClientDetails, error) {
+	result := struct {
+		Data ClientDetails `json:"data"`
+	}{}
+	err := client.call(ctx, "POST", "/auth", map[string]string{"id": id, "secret": secret}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+//AuthenticatedClient returns the client currently logged in
+func (client *Client) AuthenticatedClient(ctx context.Context) (*ClientDetails, error) {
+	result := struct {
+		Data ClientDetails `json:"data"`
+	}{}
+	err := client.call(ctx, "GET", "/auth", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+//RotateSecret issues the client logged in a new secret, the old one stops working straight away
+func (client *Client) RotateSecret(ctx context.Context) (*Credentials, error) {
+	result := struct {
+		Data Credentials `json:"data"`
+	}{}
+	err := client.call(ctx, "POST", "/auth/secret", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+//ResetClientSecret issues any client a new secret, e.g. one registered before secrets were issued, requires
+//WithAdminSecret. the old secret stops working straight away along with the client's sessions and tokens
+func (client *Client) ResetClientSecret(ctx context.Context, clientID string) (*Credentials, error) {
+	result := struct {
+		Data Credentials `json:"data"`
+	}{}
+	err := client.call(ctx, "POST", "/admin/clients/"+url.PathEscape(clientID)+"/secret", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+//ListPublishers owned by the client
+func (client *Client) ListPublishers(ctx context.Context) ([]Publisher, error) {
+	result := struct {
+		Publishers []Publisher `json:"publishers"`
+	}{}
+	err := client.call(ctx, "GET", "/publishers", nil, &result)
+	return result.Publishers, err
+}
+
+//CreatePublisher with a name unique amongst the publishers of the client's organization, or amongst the client's
+//publishers if it isn't in one
+func (client *Client) CreatePublisher(ctx context.Context, name string) (*Publisher, error) {
+	result := struct {
+		Row Publisher `json:"row"`
+	}{}
+	err := client.call(ctx, "POST", "/publishers", map[string]string{"name": name}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Row, nil
+}
+
+//DeletePublisher along with its messages and subscriptions
+func (client *Client) DeletePublisher(ctx context.Context, publisherID string) error {
+	return client.call(ctx, "DELETE", "/publishers/"+url.PathEscape(publisherID), nil, nil)
+}
+
+//ListSubscribers of one of the client's publishers
+func (client *Client) ListSubscribers(ctx context.Context, publisherID string) ([]ClientDetails, error) {
+	result := struct {
+		Subscribers []ClientDetails `json:"subscribers"`
+	}{}
+	err := client.call(ctx, "GET", "/publishers/"+url.PathEscape(publisherID)+"/subscribers", nil, &result)
+	return result.Subscribers, err
+}
+
+//Publish a message to one of the client's publishers, a ttl of 0 keeps the message until it is deleted
+func (client *Client) Publish(ctx context.Context, publisherID string, payload string, ttl time.Duration) error {
+	body := struct {
+		Ttl     int64  `json:"ttl"`
+		Payload string `json:"payload"`
+	}{
+		Ttl:     int64(ttl / time.Second),
+		Payload: payload,
+	}
+	return client.call(ctx, "POST", "/publishers/"+url.PathEscape(publisherID)+"/messages", body, nil)
+}
+
+//ListSubscriptions of the client
+func (client *Client) ListSubscriptions(ctx context.Context) ([]Subscription, error) {
+	result := struct {
+		Subscriptions []Subscription `json:"subscriptions"`
+	}{}
+	err := client.call(ctx, "GET", "/subscriptions", nil, &result)
+	return result.Subscriptions, err
+}
+
+//Subscribe to a publisher, its messages are received by a Consumer
+func (client *Client) Subscribe(ctx context.Context, publisherID string) error {
+	return client.call(ctx, "POST", "/subscriptions", map[string]string{"publisher_id": publisherID}, nil)
+}
+
+//SubscribeWebhook subscribes to a publisher with its messages pushed to a URL, signed with the secret
+func (client *Client) SubscribeWebhook(ctx context.Context, publisherID string, webhookURL string, secret string) error {
+	return client.call(ctx, "POST", "/subscriptions", map[string]string{
+		"publisher_id": publisherID,
+		"type":         "webhook",
+		"url":          webhookURL,
+		"secret":       secret,
+	}, nil)
+}
+
+//Unsubscribe removes one of the client's subscriptions
+func (client *Client) Unsubscribe(ctx context.Context, subscriptionID string) error {
+	return client.call(ctx, "DELETE", "/subscriptions/"+url.PathEscape(subscriptionID), nil, nil)
+}
+
+//ListAPIKeys of the client logged in, the keys themselves aren't returned
+func (client *Client) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	result := struct {
+		Keys []APIKey `json:"keys"`
+	}{}
+	err := client.call(ctx, "GET", "/api-keys", nil, &result)
+	return result.Keys, err
+}
+
+//CreateAPIKey with the scopes given, expiresAt is optional. The returned key's Key field is the token to
+//authenticate with and can't be retrieved again
+func (client *Client) CreateAPIKey(ctx context.Context, name string, scopes []string, expiresAt *time.Time) (*APIKey, error) {
+	result := struct {
+		Key APIKey `json:"key"`
+	}{}
+	err := client.call(ctx, "POST", "/api-keys", map[string]interface{}{
+		"name":       name,
+		"scopes":     scopes,
+		"expires_at": expiresAt,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Key, nil
+}
+
+//RevokeAPIKey so it can no longer be used
+func (client *Client) RevokeAPIKey(ctx context.Context, keyID string) error {
+	return client.call(ctx, "DELETE", "/api-keys/"+url.PathEscape(keyID), nil, nil)
+}
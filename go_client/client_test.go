@@ -0,0 +1,547 @@
+package messagebrokerclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"bezberr.com/messagebroker/broker"
+	mb "bezberr.com/messagebrokerclient"
+	"bezberr.com/messagebrokerpublisherservice/management"
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/limits"
+	"github.com/gorilla/websocket"
+)
+
+//longest a test waits for a message to arrive
+const receiveTimeout = 5 * time.Second
+
+//publisher service and message broker running in process on the same store
+type servers struct {
+	serviceURL string
+	brokerURL  string
+	socketURL  string
+	broker     *broker.Server
+}
+
+type serverConfig struct {
+	rateLimits limits.RateLimitConfig
+	quotas     limits.Quotas
+}
+
+func startServers(t *testing.T, config serverConfig) servers {
+	t.Helper()
+	ctx := context.Background()
+	store := storage.NewMemory()
+	service, err := management.New(
+		management.WithStore(store),
+		management.WithHTTPAddress("127.0.0.1:0"),
+		management.WithGRPCAddress(""),
+		management.WithRateLimits(config.rateLimits),
+		management.WithQuotas(config.quotas),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { service.Shutdown(ctx) })
+	messageBroker, err := broker.New(
+		broker.WithStore(store),
+		broker.WithHTTPAddress("127.0.0.1:0"),
+		broker.WithGRPCAddress(""),
+		broker.WithMQTTAddress(""),
+		broker.WithPollInterval(50*time.Millisecond),
+		broker.WithRateLimits(config.rateLimits),
+		broker.WithQuotas(config.quotas),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := messageBroker.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { messageBroker.Shutdown(ctx) })
+	return servers{
+		serviceURL: "http://" + service.HTTPAddr().String(),
+		brokerURL:  "http://" + messageBroker.HTTPAddr().String(),
+		socketURL:  "ws://" + messageBroker.HTTPAddr().String() + "/ws",
+		broker:     messageBroker,
+	}
+}
+
+//register a client with the publisher service
+func register(t *testing.T, running servers, name string) (*mb.Client, *mb.Credentials) {
+	t.Helper()
+	client, err := mb.New(running.serviceURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentials, err := client.Register(context.Background(), name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, credentials
+}
+
+//register a publisher and a subscriber to one of its publishers
+func subscribed(t *testing.T, running servers) (*mb.Client, *mb.Publisher, *mb.Client, *mb.Credentials) {
+	t.Helper()
+	ctx := context.Background()
+	owner, _ := register(t, running, "owner")
+	publisher, err := owner.CreatePublisher(ctx, "orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	subscriber, credentials := register(t, running, "subscriber")
+	if err := subscriber.Subscribe(ctx, publisher.ID); err != nil {
+		t.Fatal(err)
+	}
+	return owner, publisher, subscriber, credentials
+}
+
+//the APIError a call failed with, failing the test if it didn't
+func apiError(t *testing.T, err error) *mb.APIError {
+	t.Helper()
+	apiErr := &mb.APIError{}
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an APIError, got %v", err)
+	}
+	return apiErr
+}
+
+//run a consumer until the test ends, sending the messages it receives on the returned channel. the handler acks
+//or nacks each message as settle says
+func consume(t *testing.T, running servers, credentials *mb.Credentials, settle func(*mb.Message)) <-chan *mb.Message {
+	t.Helper()
+	received := make(chan *mb.Message, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	consumer := mb.NewConsumer(mb.ConsumerConfig{
+		URL:          running.socketURL,
+		ClientID:     credentials.ID,
+		ClientSecret: credentials.Secret,
+		MinBackoff:   50 * time.Millisecond,
+		Handler: func(ctx context.Context, message *mb.Message) error {
+			settle(message)
+			received <- message
+			return nil
+		},
+	})
+	go func() {
+		defer close(done)
+		consumer.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return received
+}
+
+func receive(t *testing.T, received <-chan *mb.Message) *mb.Message {
+	t.Helper()
+	select {
+	case message := <-received:
+		return message
+	case <-time.After(receiveTimeout):
+		t.Fatal("no message received")
+	}
+	return nil
+}
+
+func expectNothing(t *testing.T, received <-chan *mb.Message, wait time.Duration) {
+	t.Helper()
+	select {
+	case message := <-received:
+		t.Fatalf("unexpected message %q", message.Payload)
+	case <-time.After(wait):
+	}
+}
+
+func TestPublishConsumeConfirm(t *testing.T) {
+	running := startServers(t, serverConfig{})
+	ctx := context.Background()
+	owner, publisher, subscriber, credentials := subscribed(t, running)
+
+	received := consume(t, running, credentials, (*mb.Message).Ack)
+	if err := owner.Publish(ctx, publisher.ID, "first", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	message := receive(t, received)
+	if message.Payload != "first" || message.PublisherID != publisher.ID {
+		t.Fatalf("received %q from %s", message.Payload, message.PublisherID)
+	}
+	subscriptions, err := subscriber.ListSubscriptions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subscriptions) != 1 || message.SubscriptionID != subscriptions[0].ID {
+		t.Fatalf("message from subscription %s, subscribed to %v", message.SubscriptionID, subscriptions)
+	}
+
+	//a confirmed message isn't delivered to the client's next session
+	second := consume(t, running, credentials, (*mb.Message).Ack)
+	if err := owner.Publish(ctx, publisher.ID, "second", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	for _, messages := range []<-chan *mb.Message{received, second} {
+		if message := receive(t, messages); message.Payload != "second" {
+			t.Fatalf("received %q, the confirmed message was delivered again", message.Payload)
+		}
+	}
+}
+
+func TestNackedMessageIsRedelivered(t *testing.T) {
+	running := startServers(t, serverConfig{})
+	ctx := context.Background()
+	owner, publisher, _, credentials := subscribed(t, running)
+
+	deliveries := 0
+	received := consume(t, running, credentials, func(message *mb.Message) {
+		deliveries++
+		if deliveries == 1 {
+			message.Nack()
+		} else {
+			message.Ack()
+		}
+	})
+	if err := owner.Publish(ctx, publisher.ID, "retried", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	first := receive(t, received)
+	again := receive(t, received)
+	if again.ID != first.ID {
+		t.Fatalf("redelivered %s, nacked %s", again.ID, first.ID)
+	}
+	expectNothing(t, received, 500*time.Millisecond)
+}
+
+//session opened over the websocket directly, for sending what the Consumer wouldn't
+type rawSession struct {
+	t          *testing.T
+	connection *websocket.Conn
+}
+
+type rawMessage struct {
+	Action string          `json:"action"`
+	Data   json.RawMessage `json:"data"`
+}
+
+func openRawSession(t *testing.T, running servers, credentials *mb.Credentials) *rawSession {
+	t.Helper()
+	connection, _, err := websocket.DefaultDialer.Dial(running.socketURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { connection.Close() })
+	session := &rawSession{t: t, connection: connection}
+	session.expect("authenticate")
+	session.send(map[string]string{"id": credentials.ID, "secret": credentials.Secret})
+	session.expect("session_started")
+	return session
+}
+
+func (session *rawSession) send(message interface{}) {
+	session.t.Helper()
+	if err := session.connection.WriteJSON(message); err != nil {
+		session.t.Fatal(err)
+	}
+}
+
+//read until a message with the action arrives, returning its data
+func (session *rawSession) expect(action string) json.RawMessage {
+	session.t.Helper()
+	session.connection.SetReadDeadline(time.Now().Add(receiveTimeout))
+	for {
+		message := rawMessage{}
+		if err := session.connection.ReadJSON(&message); err != nil {
+			session.t.Fatalf("waiting for %s: %v", action, err)
+		}
+		if message.Action == action {
+			return message.Data
+		}
+	}
+}
+
+//confirm messages, returning how many the broker says were confirmed
+func (session *rawSession) confirm(messages []*mb.Message) int {
+	session.t.Helper()
+	confirmations := []map[string]string{}
+	for _, message := range messages {
+		confirmations = append(confirmations, map[string]string{"id": message.ID, "subscription_id": message.SubscriptionID})
+	}
+	session.send(map[string]interface{}{
+		"action": "confirm_messages",
+		"data":   map[string]interface{}{"messages": confirmations},
+	})
+	confirmed := struct {
+		Confirmed int `json:"confirmed"`
+	}{}
+	if err := json.Unmarshal(session.expect("messages_confirmed"), &confirmed); err != nil {
+		session.t.Fatal(err)
+	}
+	return confirmed.Confirmed
+}
+
+func (session *rawSession) receive() []*mb.Message {
+	session.t.Helper()
+	messages := []*mb.Message{}
+	if err := json.Unmarshal(session.expect("messages"), &messages); err != nil {
+		session.t.Fatal(err)
+	}
+	return messages
+}
+
+func TestDuplicateConfirmationIsAnswered(t *testing.T) {
+	running := startServers(t, serverConfig{})
+	ctx := context.Background()
+	owner, publisher, _, credentials := subscribed(t, running)
+	session := openRawSession(t, running, credentials)
+
+	if err := owner.Publish(ctx, publisher.ID, "first", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	first := session.receive()
+	if confirmed := session.confirm(first); confirmed != 1 {
+		t.Fatalf("confirmed %d", confirmed)
+	}
+	//the batch is already settled so there's nothing waiting on the confirmation
+	if confirmed := session.confirm(first); confirmed != 0 {
+		t.Fatalf("confirmed %d again", confirmed)
+	}
+
+	if err := owner.Publish(ctx, publisher.ID, "second", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	second := session.receive()
+	//a late confirmation of the first batch doesn't settle the second one
+	if confirmed := session.confirm(first); confirmed != 0 {
+		t.Fatalf("confirmed %d of the first batch while the second was waiting", confirmed)
+	}
+	if confirmed := session.confirm(second); confirmed != 1 || second[0].Payload != "second" {
+		t.Fatalf("confirmed %d of %v", confirmed, second)
+	}
+}
+
+func TestConflictingDeliveryIsRefused(t *testing.T) {
+	running := startServers(t, serverConfig{})
+	_, _, _, credentials := subscribed(t, running)
+	//the first session sets the delivery mode, fanout as it doesn't ask for one
+	openRawSession(t, running, credentials)
+
+	ctx, cancel := context.WithTimeout(context.Background(), receiveTimeout)
+	defer cancel()
+	consumer := mb.NewConsumer(mb.ConsumerConfig{
+		URL:          running.socketURL,
+		ClientID:     credentials.ID,
+		ClientSecret: credentials.Secret,
+		Delivery:     mb.DeliveryBalanced,
+		Handler:      func(ctx context.Context, message *mb.Message) error { return nil },
+	})
+	if err := consumer.Run(ctx); !errors.Is(err, mb.ErrSessionRefused) {
+		t.Fatalf("balanced session alongside a fanout one ended with %v", err)
+	}
+}
+
+func TestPulledMessagesAreLeased(t *testing.T) {
+	running := startServers(t, serverConfig{})
+	ctx := context.Background()
+	owner, publisher, subscriber, credentials := subscribed(t, running)
+	subscriptions, err := subscriber.ListSubscriptions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	subscriptionID := subscriptions[0].ID
+	if err := owner.Publish(ctx, publisher.ID, "leased", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	puller := mb.NewPuller(running.brokerURL, credentials.ID, credentials.Secret)
+	pulled, err := puller.Pull(ctx, subscriptionID, mb.PullOptions{Max: 10, Lease: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pulled.Messages) != 1 || pulled.Messages[0].Payload != "leased" {
+		t.Fatalf("pulled %v", pulled.Messages)
+	}
+	//held by the lease until it expires
+	held, err := puller.Pull(ctx, subscriptionID, mb.PullOptions{Max: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(held.Messages) != 0 {
+		t.Fatalf("pulled %d leased messages", len(held.Messages))
+	}
+	time.Sleep(time.Until(pulled.LeaseExpires) + 100*time.Millisecond)
+	expired, err := puller.Pull(ctx, subscriptionID, mb.PullOptions{Max: 10, Wait: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expired.Messages) != 1 || expired.Messages[0].ID != pulled.Messages[0].ID {
+		t.Fatalf("pulled %v after the lease expired", expired.Messages)
+	}
+	acked, err := puller.Ack(ctx, subscriptionID, []string{expired.Messages[0].ID})
+	if err != nil || acked != 1 {
+		t.Fatalf("acked %d, %v", acked, err)
+	}
+	empty, err := puller.Pull(ctx, subscriptionID, mb.PullOptions{Max: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(empty.Messages) != 0 {
+		t.Fatalf("pulled %d acked messages", len(empty.Messages))
+	}
+}
+
+func TestShutdownEndsWaitingPull(t *testing.T) {
+	running := startServers(t, serverConfig{})
+	ctx := context.Background()
+	_, _, subscriber, credentials := subscribed(t, running)
+	subscriptions, err := subscriber.ListSubscriptions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pulled := make(chan error, 1)
+	go func() {
+		puller := mb.NewPuller(running.brokerURL, credentials.ID, credentials.Secret)
+		result, err := puller.Pull(ctx, subscriptions[0].ID, mb.PullOptions{Max: 10, Wait: time.Minute})
+		if err == nil && len(result.Messages) != 0 {
+			err = errors.New("pulled messages from an empty subscription")
+		}
+		pulled <- err
+	}()
+	time.Sleep(200 * time.Millisecond)
+
+	started := time.Now()
+	if err := running.broker.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(started) > receiveTimeout {
+		t.Fatalf("shutdown took %s with a pull waiting", time.Since(started))
+	}
+	select {
+	case err := <-pulled:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(receiveTimeout):
+		t.Fatal("pull still waiting after the shutdown")
+	}
+}
+
+func TestInvalidSettleRequestIsRefused(t *testing.T) {
+	running := startServers(t, serverConfig{})
+	ctx := context.Background()
+	_, _, subscriber, credentials := subscribed(t, running)
+	subscriptions, err := subscriber.ListSubscriptions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for action, body := range map[string]string{"ack": `{"messages":`, "nack": `{"messages":[],"delay":"soon"}`} {
+		request, err := http.NewRequest("POST", running.brokerURL+"/subscriptions/"+subscriptions[0].ID+"/"+action, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		request.Header.Set("X-Client-Id", credentials.ID)
+		request.Header.Set("X-Client-Secret", credentials.Secret)
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		response.Body.Close()
+		if response.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s with %s answered %d", action, body, response.StatusCode)
+		}
+	}
+}
+
+func TestRevokedAccessStopsDelivery(t *testing.T) {
+	running := startServers(t, serverConfig{})
+	ctx := context.Background()
+	owner, publisher, subscriber, credentials := subscribed(t, running)
+
+	received := consume(t, running, credentials, (*mb.Message).Ack)
+	if err := owner.Publish(ctx, publisher.ID, "allowed", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	receive(t, received)
+
+	if _, err := owner.SetACL(ctx, publisher.ID, mb.ACL{Visibility: mb.VisibilityPrivate}); err != nil {
+		t.Fatal(err)
+	}
+	subscriptions, err := subscriber.ListSubscriptions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subscriptions) != 0 {
+		t.Fatalf("still subscribed to %d publishers", len(subscriptions))
+	}
+	if err := owner.Publish(ctx, publisher.ID, "revoked", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	expectNothing(t, received, time.Second)
+	//refused by the service rather than the broker, which responds with success set to false
+	if refused := apiError(t, subscriber.Subscribe(ctx, publisher.ID)); refused.Message == "" {
+		t.Fatalf("resubscribing refused without a reason, status %d", refused.StatusCode)
+	}
+}
+
+func TestQuotaRefusesPublish(t *testing.T) {
+	running := startServers(t, serverConfig{quotas: limits.Quotas{PublisherMessages: 1}})
+	ctx := context.Background()
+	owner, publisher, _, _ := subscribed(t, running)
+
+	if err := owner.Publish(ctx, publisher.ID, "kept", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	refused := apiError(t, owner.Publish(ctx, publisher.ID, "over", time.Hour))
+	if refused.StatusCode != http.StatusInsufficientStorage || refused.Quota != "publisher_messages" {
+		t.Fatalf("refused with %d %q", refused.StatusCode, refused.Quota)
+	}
+}
+
+func TestRateLimitRefusesPublish(t *testing.T) {
+	running := startServers(t, serverConfig{rateLimits: limits.RateLimitConfig{
+		Client: storage.RateLimits{PublishPerMinute: 1, PublishBurst: 1},
+	}})
+	ctx := context.Background()
+	owner, publisher, _, _ := subscribed(t, running)
+
+	if err := owner.Publish(ctx, publisher.ID, "allowed", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	refused := apiError(t, owner.Publish(ctx, publisher.ID, "limited", time.Hour))
+	if refused.StatusCode != http.StatusTooManyRequests || refused.RetryAfter <= 0 {
+		t.Fatalf("refused with %d, retry after %s", refused.StatusCode, refused.RetryAfter)
+	}
+}
+
+func TestSchemaValidatesPublish(t *testing.T) {
+	running := startServers(t, serverConfig{})
+	ctx := context.Background()
+	owner, publisher, _, credentials := subscribed(t, running)
+
+	registered, err := owner.RegisterSchema(ctx, publisher.ID, `{"type":"object","properties":{"id":{"type":"string"}},"required":["id"]}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	refused := apiError(t, owner.Publish(ctx, publisher.ID, `{"id":5}`, time.Hour))
+	if refused.StatusCode != http.StatusUnprocessableEntity || refused.SchemaVersion != registered.Version {
+		t.Fatalf("refused with %d, schema version %d", refused.StatusCode, refused.SchemaVersion)
+	}
+
+	received := consume(t, running, credentials, (*mb.Message).Ack)
+	if err := owner.Publish(ctx, publisher.ID, `{"id":"a"}`, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	message := receive(t, received)
+	if message.Payload != `{"id":"a"}` || message.SchemaVersion != registered.Version {
+		t.Fatalf("received %q matching schema version %d", message.Payload, message.SchemaVersion)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+//credentials saved by register and login so later commands run as the same client
+type credentials struct {
+	ClientID   string `json:"client_id"`
+	Secret     string `json:"secret"`
+	Name       string `json:"name"`
+	ServiceURL string `json:"service_url,omitempty"`
+	BrokerURL  string `json:"broker_url,omitempty"`
+}
+
+var errNotLoggedIn = errors.New("not logged in, run msgbroker register or msgbroker login first")
+
+//file the credentials are kept in, MSGBROKER_CREDENTIALS overrides the default under the user's config directory
+func credentialsPath() (string, error) {
+	if path := os.Getenv("MSGBROKER_CREDENTIALS"); path != "" {
+		return path, nil
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "msgbroker", "credentials.json"), nil
+}
+
+func loadCredentials() (*credentials, error) {
+	path, err := credentialsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errNotLoggedIn
+	}
+	if err != nil {
+		return nil, err
+	}
+	saved := credentials{}
+	err = json.Unmarshal(data, &saved)
+	if err != nil {
+		return nil, err
+	}
+	if saved.ClientID == "" {
+		return nil, errNotLoggedIn
+	}
+	return &saved, nil
+}
+
+//the file is only readable by the user as the client's secret is all it takes to act as the client
+func saveCredentials(saved *credentials) error {
+	path, err := credentialsPath()
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
+
+func removeCredentials() error {
+	path, err := credentialsPath()
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
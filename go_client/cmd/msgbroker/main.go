@@ -0,0 +1,1157 @@
+//Command msgbroker is a command-line client for the message broker, for registering clients, managing
+//publishers and subscriptions, publishing messages and tailing subscriptions.
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	messagebrokerclient "bezberr.com/messagebrokerclient"
+)
+
+const (
+	defaultServiceURL = "http://localhost:8081"
+	defaultBrokerURL  = "http://localhost:8001"
+)
+
+const usage = `usage: msgbroker [-service url] [-broker url] <command> [arguments]
+
+commands:
+  register <name>                         register a new client and log in as it
+  login <client id>                       log in as an existing client, reading its secret from
+                                          MSGBROKER_CLIENT_SECRET or stdin
+  rotate-secret                           issue the client logged in a new secret
+  reset-secret <client id>                issue any client a new secret, e.g. one registered before secrets
+                                          were issued. needs MSGBROKER_ADMIN_SECRET
+  logout                                  forget the stored credentials
+  whoami                                  show the client logged in
+  organization create <name> <admin name> create an organization along with its first admin and log in as it
+  organization                            show your organization and its members
+  organization add-member <name>          register a new member of your organization
+  organization admin <client id>          make a member an admin of your organization
+  organization unadmin <client id>        stop a member being an admin
+  organization publishers                 list every publisher in your organization
+  publishers                              list your publishers
+  publishers create [-content-type t -max-payload n] <name>
+                                          create a publisher, optionally limiting its payloads
+  publishers delete <publisher id>        delete a publisher with its messages and subscriptions
+  publishers subscribers <publisher id>   list the subscribers of a publisher
+  publishers payload <publisher id>       show the size limit and content type of a publisher's payloads
+  publishers payload [-content-type t -max-payload n] <publisher id>
+                                          replace them, 0 and an empty content type go back to the defaults
+  publishers schemas <publisher id>       list the versions of a publisher's schema
+  publishers schema <publisher id> [version]
+                                          print a version of a publisher's schema, the latest by default
+  publishers register-schema [-compatibility c] [-file path] <publisher id> [schema]
+                                          register the next version of a publisher's schema from the argument,
+                                          the file or stdin. c is backward, forward, full or none
+  publishers acl <publisher id>           show who can subscribe to a publisher
+  publishers visibility <publisher id> <public|private|allowlist>
+                                          set who can subscribe, subscriptions it disallows are removed
+  publishers allow <publisher id> <client|group> <id>
+                                          add a client or group to a publisher's allowlist
+  publishers disallow <publisher id> <client|group> <id>
+                                          take a client or group off the allowlist, removing its subscriptions
+  publishers remove-subscriber <publisher id> <client id>
+                                          remove a client's subscriptions, taking it off the allowlist
+  publishers share <publisher id> <organization id>
+                                          let another organization's clients see a publisher
+  publishers unshare <publisher id> <organization id>
+                                          stop sharing a publisher, removing that organization's subscriptions
+  groups                                  list your groups of clients
+  groups create <name> [client id...]     create a group to put on your publishers' allowlists
+  groups members <group id> [client id...]
+                                          replace the members of a group
+  groups delete <group id>                delete a group, taking it off the allowlists
+  keys                                    list your API keys
+  keys create [-expires d] <name> <scope>...
+                                          create an API key, scopes are manage, publish:<publisher id>
+                                          and subscribe:<subscription id>
+  keys revoke <key id>                    revoke an API key
+  subscriptions                           list your subscriptions
+  subscribe [-webhook url -secret s] <publisher id>
+                                          subscribe to a publisher, optionally pushing to a webhook
+  unsubscribe <subscription id>           remove a subscription
+  request [-webhook url -secret s] <publisher id> [message]
+                                          ask the owner of a publisher to let you subscribe to it
+  requests [status]                       list your subscription requests
+  requests incoming [status]              list requests to subscribe to your publishers
+  requests approve <request id> [reason]  approve a request, subscribing the client which made it
+  requests deny <request id> [reason]     deny a request
+  publish [-ttl d] [-file path] <publisher id> [message]
+                                          publish the message, the file or stdin when neither is given or the message is -
+  tail [-confirm] [-max n] [-lease d] <subscription id>
+                                          print messages from a subscription as JSON lines until interrupted
+  usage [publisher id]                    show the messages stored for you or a publisher against the quotas
+  limits                                  show your rate limits
+  limits show <client id>                 show a client's rate limits, needs MSGBROKER_ADMIN_SECRET
+  limits set [-publish n -publish-burst n -requests n -requests-burst n -connections n] <client id>
+                                          override the defaults for a client, 0 keeps the default and
+                                          -1 turns the limit off. needs MSGBROKER_ADMIN_SECRET
+  limits reset <client id>                go back to the default limits for a client
+
+The service and broker URLs default to MSGBROKER_SERVICE_URL and MSGBROKER_BROKER_URL, then the URLs
+stored when logging in, then http://localhost:8081 and http://localhost:8001.
+`
+
+//state shared by the commands
+type cli struct {
+	serviceURL string //set by flag, empty when the stored or default URL should be used
+	brokerURL  string
+	stdin      io.Reader
+	stdout     io.Writer
+}
+
+func main() {
+	app := cli{stdin: os.Stdin, stdout: os.Stdout}
+	flag.StringVar(&app.serviceURL, "service", os.Getenv("MSGBROKER_SERVICE_URL"), "publisher service URL")
+	flag.StringVar(&app.brokerURL, "broker", os.Getenv("MSGBROKER_BROKER_URL"), "message broker URL")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	err := app.run(ctx, flag.Arg(0), flag.Args()[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "msgbroker:", err)
+		os.Exit(1)
+	}
+}
+
+func (app *cli) run(ctx context.Context, command string, args []string) error {
+	switch command {
+	case "register":
+		return app.register(ctx, args)
+	case "login":
+		return app.login(ctx, args)
+	case "rotate-secret":
+		return app.rotateSecret(ctx)
+	case "reset-secret":
+		return app.resetSecret(ctx, args)
+	case "logout":
+		return removeCredentials()
+	case "whoami":
+		return app.whoami(ctx)
+	case "organization":
+		return app.organization(ctx, args)
+	case "publishers":
+		return app.publishers(ctx, args)
+	case "keys":
+		return app.keys(ctx, args)
+	case "groups":
+		return app.groups(ctx, args)
+	case "subscriptions":
+		return app.subscriptions(ctx)
+	case "subscribe":
+		return app.subscribe(ctx, args)
+	case "unsubscribe":
+		return app.unsubscribe(ctx, args)
+	case "request":
+		return app.request(ctx, args)
+	case "requests":
+		return app.requests(ctx, args)
+	case "publish":
+		return app.publish(ctx, args)
+	case "tail":
+		return app.tail(ctx, args)
+	case "usage":
+		return app.usage(ctx, args)
+	case "limits":
+		return app.limits(ctx, args)
+	case "help":
+		flag.Usage()
+		return nil
+	}
+	return fmt.Errorf("unknown command %q, run msgbroker help for the list of commands", command)
+}
+
+//first URL given, out of the flag or environment, the stored credentials and the default
+func pickURL(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+//check a command was given exactly the arguments it takes
+func expectArgs(args []string, names ...string) error {
+	if len(args) != len(names) {
+		return fmt.Errorf("expected arguments: %s", strings.Join(names, " "))
+	}
+	return nil
+}
+
+//parse a command's flags, leaving its positional arguments
+func parseFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	flags.SetOutput(os.Stderr)
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+	return flags.Args(), nil
+}
+
+//create a client for the publisher service authenticated with the stored credentials
+func (app *cli) client(ctx context.Context) (*messagebrokerclient.Client, *credentials, error) {
+	saved, err := loadCredentials()
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := messagebrokerclient.New(pickURL(app.serviceURL, saved.ServiceURL, defaultServiceURL))
+	if err != nil {
+		return nil, nil, err
+	}
+	_, err = client.Authenticate(ctx, saved.ClientID, saved.Secret)
+	if err != nil {
+		return nil, nil, fmt.Errorf("logging in as %s: %w", saved.ClientID, err)
+	}
+	return client, saved, nil
+}
+
+//store the credentials along with any URLs given so later commands talk to the same servers
+func (app *cli) saveLogin(details *messagebrokerclient.ClientDetails, secret string) error {
+	err := saveCredentials(&credentials{
+		ClientID:   details.ID,
+		Secret:     secret,
+		Name:       details.Name,
+		ServiceURL: app.serviceURL,
+		BrokerURL:  app.brokerURL,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(app.stdout, "logged in as %s (%s)\n", details.Name, details.ID)
+	return nil
+}
+
+func (app *cli) register(ctx context.Context, args []string) error {
+	if err := expectArgs(args, "<name>"); err != nil {
+		return err
+	}
+	client, err := messagebrokerclient.New(pickURL(app.serviceURL, defaultServiceURL))
+	if err != nil {
+		return err
+	}
+	registered, err := client.Register(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	err = app.saveLogin(&messagebrokerclient.ClientDetails{ID: registered.ID, Name: args[0]}, registered.Secret)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(app.stdout, "secret %s\nkeep it somewhere safe, it's needed to log in again and can't be shown later\n", registered.Secret)
+	return nil
+}
+
+func (app *cli) login(ctx context.Context, args []string) error {
+	if err := expectArgs(args, "<client id>"); err != nil {
+		return err
+	}
+	client, err := messagebrokerclient.New(pickURL(app.serviceURL, defaultServiceURL))
+	if err != nil {
+		return err
+	}
+	secret := os.Getenv("MSGBROKER_CLIENT_SECRET")
+	if secret == "" {
+		secret, err = app.readSecret()
+		if err != nil {
+			return err
+		}
+	}
+	details, err := client.Authenticate(ctx, args[0], secret)
+	if err != nil {
+		return err
+	}
+	return app.saveLogin(details, secret)
+}
+
+//read the secret from the first line of stdin, rather than an argument which would show up in the process list
+func (app *cli) readSecret() (string, error) {
+	line, err := bufio.NewReader(app.stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	secret := strings.TrimSpace(line)
+	if secret == "" {
+		return "", errors.New("no secret given, set MSGBROKER_CLIENT_SECRET or pass it on stdin")
+	}
+	return secret, nil
+}
+
+func (app *cli) rotateSecret(ctx context.Context) error {
+	client, saved, err := app.client(ctx)
+	if err != nil {
+		return err
+	}
+	rotated, err := client.RotateSecret(ctx)
+	if err != nil {
+		return err
+	}
+	saved.Secret = rotated.Secret
+	err = saveCredentials(saved)
+	if err != nil {
+		return fmt.Errorf("the secret was rotated but couldn't be saved, it's %s: %w", rotated.Secret, err)
+	}
+	fmt.Fprintf(app.stdout, "secret %s\n", rotated.Secret)
+	return nil
+}
+
+func (app *cli) resetSecret(ctx context.Context, args []string) error {
+	if err := expectArgs(args, "<client id>"); err != nil {
+		return err
+	}
+	client, err := app.adminClient()
+	if err != nil {
+		return err
+	}
+	reset, err := client.ResetClientSecret(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(app.stdout, "secret %s\n", reset.Secret)
+	return nil
+}
+
+func (app *cli) whoami(ctx context.Context) error {
+	client, _, err := app.client(ctx)
+	if err != nil {
+		return err
+	}
+	details, err := client.AuthenticatedClient(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(app.stdout, "%s (%s)\n", details.Name, details.ID)
+	if details.OrganizationID != "" {
+		fmt.Fprintf(app.stdout, "organization %s\n", details.OrganizationID)
+	}
+	return nil
+}
+
+//create an organization, logging in as its first admin
+func (app *cli) createOrganization(ctx context.Context, args []string) error {
+	if err := expectArgs(args, "<name>", "<admin name>"); err != nil {
+		return err
+	}
+	client, err := messagebrokerclient.New(pickURL(app.serviceURL, defaultServiceURL))
+	if err != nil {
+		return err
+	}
+	organization, admin, err := client.CreateOrganization(ctx, args[0], args[1])
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(app.stdout, "organization %s (%s)\n", organization.Name, organization.ID)
+	err = app.saveLogin(&messagebrokerclient.ClientDetails{ID: admin.ID, Name: args[1]}, admin.Secret)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(app.stdout, "secret %s\nkeep it somewhere safe, it's needed to log in again and can't be shown later\n", admin.Secret)
+	return nil
+}
+
+func (app *cli) organization(ctx context.Context, args []string) error {
+	subcommand := "show"
+	if len(args) > 0 {
+		subcommand, args = args[0], args[1:]
+	}
+	if subcommand == "create" {
+		return app.createOrganization(ctx, args)
+	}
+	client, _, err := app.client(ctx)
+	if err != nil {
+		return err
+	}
+	switch subcommand {
+	case "show":
+		if err := expectArgs(args); err != nil {
+			return err
+		}
+		organization, members, err := client.GetOrganization(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(app.stdout, "%s (%s)\n", organization.Name, organization.ID)
+		table := tabwriter.NewWriter(app.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(table, "ID\tNAME\tADMIN")
+		for _, member := range members {
+			fmt.Fprintf(table, "%s\t%s\t%t\n", member.ID, member.Name, member.Admin)
+		}
+		return table.Flush()
+	case "add-member":
+		if err := expectArgs(args, "<name>"); err != nil {
+			return err
+		}
+		member, err := client.AddOrganizationMember(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(app.stdout, "id %s\nsecret %s\n", member.ID, member.Secret)
+		return nil
+	case "admin", "unadmin":
+		if err := expectArgs(args, "<client id>"); err != nil {
+			return err
+		}
+		var organization *messagebrokerclient.Organization
+		if subcommand == "admin" {
+			organization, err = client.AddOrganizationAdmin(ctx, args[0])
+		} else {
+			organization, err = client.RemoveOrganizationAdmin(ctx, args[0])
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(app.stdout, "admins: %s\n", strings.Join(organization.Admins, ","))
+		return nil
+	case "publishers":
+		if err := expectArgs(args); err != nil {
+			return err
+		}
+		publishers, err := client.ListOrganizationPublishers(ctx)
+		if err != nil {
+			return err
+		}
+		table := tabwriter.NewWriter(app.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(table, "ID\tNAME\tOWNER")
+		for _, publisher := range publishers {
+			fmt.Fprintf(table, "%s\t%s\t%s\n", publisher.ID, publisher.Name, publisher.OwnerID)
+		}
+		return table.Flush()
+	}
+	return fmt.Errorf("unknown organization command %q", subcommand)
+}
+
+func (app *cli) publishers(ctx context.Context, args []string) error {
+	subcommand := "list"
+	if len(args) > 0 {
+		subcommand, args = args[0], args[1:]
+	}
+	client, _, err := app.client(ctx)
+	if err != nil {
+		return err
+	}
+	switch subcommand {
+	case "list":
+		if err := expectArgs(args); err != nil {
+			return err
+		}
+		publishers, err := client.ListPublishers(ctx)
+		if err != nil {
+			return err
+		}
+		table := tabwriter.NewWriter(app.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(table, "ID\tNAME\tCONTENT TYPE")
+		for _, publisher := range publishers {
+			fmt.Fprintf(table, "%s\t%s\t%s\n", publisher.ID, publisher.Name, publisher.ContentType)
+		}
+		return table.Flush()
+	case "create":
+		flags, policy := payloadFlags("publishers create")
+		args, err := parseFlags(flags, args)
+		if err != nil {
+			return err
+		}
+		if err := expectArgs(args, "<name>"); err != nil {
+			return err
+		}
+		publisher, err := client.CreatePublisherWithPayload(ctx, args[0], *policy)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(app.stdout, publisher.ID)
+		return nil
+	case "payload":
+		flags, policy := payloadFlags("publishers payload")
+		args, err := parseFlags(flags, args)
+		if err != nil {
+			return err
+		}
+		if err := expectArgs(args, "<publisher id>"); err != nil {
+			return err
+		}
+		current, limit, err := client.GetPublisherPayload(ctx, args[0])
+		if err == nil && flags.NFlag() > 0 {
+			current, limit, err = client.SetPublisherPayload(ctx, args[0], *policy)
+		}
+		if err != nil {
+			return err
+		}
+		contentType := current.ContentType
+		if contentType == "" {
+			contentType = "any"
+		}
+		fmt.Fprintf(app.stdout, "content type: %s\nmax bytes:    %d\n", contentType, limit)
+		return nil
+	case "schemas":
+		if err := expectArgs(args, "<publisher id>"); err != nil {
+			return err
+		}
+		schemas, err := client.ListSchemas(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		table := tabwriter.NewWriter(app.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(table, "VERSION	COMPATIBILITY	CREATED")
+		for _, schema := range schemas {
+			fmt.Fprintf(table, "%d\t%s\t%s\n", schema.Version, schema.Compatibility, schema.CreatedAt.Format(time.RFC3339))
+		}
+		return table.Flush()
+	case "schema":
+		if len(args) < 1 || len(args) > 2 {
+			return errors.New("expected arguments: <publisher id> [version]")
+		}
+		version := 0
+		if len(args) == 2 {
+			version, err = strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid version %q", args[1])
+			}
+		}
+		schema, err := client.GetSchema(ctx, args[0], version)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(app.stdout, schema.Schema)
+		return nil
+	case "register-schema":
+		flags := flag.NewFlagSet("publishers register-schema", flag.ContinueOnError)
+		compatibility := flags.String("compatibility", "", "rule the schema is checked against the latest version with, defaults to the latest version's")
+		file := flags.String("file", "", "file to read the schema from")
+		args, err := parseFlags(flags, args)
+		if err != nil {
+			return err
+		}
+		if len(args) < 1 || len(args) > 2 {
+			return errors.New("expected arguments: <publisher id> [schema]")
+		}
+		var definition []byte
+		switch {
+		case *file != "" && len(args) == 2:
+			return errors.New("give either a schema or -file, not both")
+		case *file != "":
+			definition, err = os.ReadFile(*file)
+		case len(args) == 2 && args[1] != "-":
+			definition = []byte(args[1])
+		default:
+			definition, err = io.ReadAll(app.stdin)
+		}
+		if err != nil {
+			return err
+		}
+		schema, err := client.RegisterSchema(ctx, args[0], string(definition), *compatibility)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(app.stdout, "registered version %d, %s compatible\n", schema.Version, schema.Compatibility)
+		return nil
+	case "delete":
+		if err := expectArgs(args, "<publisher id>"); err != nil {
+			return err
+		}
+		return client.DeletePublisher(ctx, args[0])
+	case "subscribers":
+		if err := expectArgs(args, "<publisher id>"); err != nil {
+			return err
+		}
+		subscribers, err := client.ListSubscribers(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		table := tabwriter.NewWriter(app.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(table, "ID\tNAME")
+		for _, subscriber := range subscribers {
+			fmt.Fprintf(table, "%s\t%s\n", subscriber.ID, subscriber.Name)
+		}
+		return table.Flush()
+	case "acl":
+		if err := expectArgs(args, "<publisher id>"); err != nil {
+			return err
+		}
+		acl, err := client.GetACL(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		app.printACL(*acl)
+		return nil
+	case "visibility":
+		if err := expectArgs(args, "<publisher id>", "<public|private|allowlist>"); err != nil {
+			return err
+		}
+		acl, err := client.GetACL(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		acl.Visibility = args[1]
+		change, err := client.SetACL(ctx, args[0], *acl)
+		if err != nil {
+			return err
+		}
+		app.printACLChange(change)
+		return nil
+	case "allow", "disallow":
+		if err := expectArgs(args, "<publisher id>", "<client|group>", "<id>"); err != nil {
+			return err
+		}
+		var change *messagebrokerclient.ACLChange
+		switch {
+		case args[1] == "client" && subcommand == "allow":
+			change, err = client.AllowClient(ctx, args[0], args[2])
+		case args[1] == "client":
+			change, err = client.DisallowClient(ctx, args[0], args[2])
+		case args[1] == "group" && subcommand == "allow":
+			change, err = client.AllowGroup(ctx, args[0], args[2])
+		case args[1] == "group":
+			change, err = client.DisallowGroup(ctx, args[0], args[2])
+		default:
+			return fmt.Errorf("expected client or group, not %q", args[1])
+		}
+		if err != nil {
+			return err
+		}
+		app.printACLChange(change)
+		return nil
+	case "remove-subscriber":
+		if err := expectArgs(args, "<publisher id>", "<client id>"); err != nil {
+			return err
+		}
+		return client.RemoveSubscriber(ctx, args[0], args[1])
+	case "share", "unshare":
+		if err := expectArgs(args, "<publisher id>", "<organization id>"); err != nil {
+			return err
+		}
+		acl, err := client.GetACL(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		organizations := []string{}
+		for _, id := range acl.Organizations {
+			if id != args[1] {
+				organizations = append(organizations, id)
+			}
+		}
+		if subcommand == "share" {
+			organizations = append(organizations, args[1])
+		}
+		acl.Organizations = organizations
+		change, err := client.SetACL(ctx, args[0], *acl)
+		if err != nil {
+			return err
+		}
+		app.printACLChange(change)
+		return nil
+	}
+	return fmt.Errorf("unknown publishers command %q", subcommand)
+}
+
+//flags setting a publisher's payload policy
+func payloadFlags(name string) (*flag.FlagSet, *messagebrokerclient.PayloadPolicy) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	policy := &messagebrokerclient.PayloadPolicy{}
+	flags.StringVar(&policy.ContentType, "content-type", "", "content type payloads have to be, e.g. application/json")
+	flags.IntVar(&policy.MaxBytes, "max-payload", 0, "largest payload in bytes, 0 for the service's limit")
+	return flags, policy
+}
+
+func (app *cli) printACL(acl messagebrokerclient.ACL) {
+	fmt.Fprintf(app.stdout, "visibility: %s\nclients:    %s\ngroups:     %s\napproved:   %s\nshared:     %s\n",
+		acl.Visibility, strings.Join(acl.Clients, ","), strings.Join(acl.Groups, ","), strings.Join(acl.Approved, ","),
+		strings.Join(acl.Organizations, ","))
+}
+
+func (app *cli) printACLChange(change *messagebrokerclient.ACLChange) {
+	app.printACL(change.ACL)
+	if change.Revoked > 0 {
+		fmt.Fprintf(app.stdout, "revoked:    %d subscriptions\n", change.Revoked)
+	}
+}
+
+func (app *cli) groups(ctx context.Context, args []string) error {
+	subcommand := "list"
+	if len(args) > 0 {
+		subcommand, args = args[0], args[1:]
+	}
+	client, _, err := app.client(ctx)
+	if err != nil {
+		return err
+	}
+	switch subcommand {
+	case "list":
+		if err := expectArgs(args); err != nil {
+			return err
+		}
+		groups, err := client.ListGroups(ctx)
+		if err != nil {
+			return err
+		}
+		table := tabwriter.NewWriter(app.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(table, "ID\tNAME\tMEMBERS")
+		for _, group := range groups {
+			fmt.Fprintf(table, "%s\t%s\t%s\n", group.ID, group.Name, strings.Join(group.Members, ","))
+		}
+		return table.Flush()
+	case "create":
+		if len(args) < 1 {
+			return errors.New("expected arguments: <name> [client id...]")
+		}
+		group, err := client.CreateGroup(ctx, args[0], args[1:])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(app.stdout, group.ID)
+		return nil
+	case "members":
+		if len(args) < 1 {
+			return errors.New("expected arguments: <group id> [client id...]")
+		}
+		change, err := client.SetGroupMembers(ctx, args[0], args[1:])
+		if err != nil {
+			return err
+		}
+		if change.Revoked > 0 {
+			fmt.Fprintf(app.stdout, "revoked %d subscriptions\n", change.Revoked)
+		}
+		return nil
+	case "delete":
+		if err := expectArgs(args, "<group id>"); err != nil {
+			return err
+		}
+		return client.DeleteGroup(ctx, args[0])
+	}
+	return fmt.Errorf("unknown groups command %q", subcommand)
+}
+
+func (app *cli) keys(ctx context.Context, args []string) error {
+	subcommand := "list"
+	if len(args) > 0 {
+		subcommand, args = args[0], args[1:]
+	}
+	client, _, err := app.client(ctx)
+	if err != nil {
+		return err
+	}
+	switch subcommand {
+	case "list":
+		if err := expectArgs(args); err != nil {
+			return err
+		}
+		keys, err := client.ListAPIKeys(ctx)
+		if err != nil {
+			return err
+		}
+		table := tabwriter.NewWriter(app.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(table, "ID\tNAME\tSCOPES\tEXPIRES")
+		for _, key := range keys {
+			expires := "never"
+			if key.ExpiresAt != nil {
+				expires = key.ExpiresAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", key.ID, key.Name, strings.Join(key.Scopes, ","), expires)
+		}
+		return table.Flush()
+	case "create":
+		flags := flag.NewFlagSet("keys create", flag.ContinueOnError)
+		expires := flags.Duration("expires", 0, "how long until the key expires, it never expires if not set")
+		args, err := parseFlags(flags, args)
+		if err != nil {
+			return err
+		}
+		if len(args) < 2 {
+			return errors.New("expected arguments: <name> <scope>...")
+		}
+		var expiresAt *time.Time
+		if *expires > 0 {
+			at := time.Now().Add(*expires)
+			expiresAt = &at
+		}
+		key, err := client.CreateAPIKey(ctx, args[0], args[1:], expiresAt)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(app.stdout, "id:  %s\nkey: %s\n", key.ID, key.Key)
+		return nil
+	case "revoke":
+		if err := expectArgs(args, "<key id>"); err != nil {
+			return err
+		}
+		return client.RevokeAPIKey(ctx, args[0])
+	}
+	return fmt.Errorf("unknown keys command %q", subcommand)
+}
+
+func (app *cli) subscriptions(ctx context.Context) error {
+	client, _, err := app.client(ctx)
+	if err != nil {
+		return err
+	}
+	subscriptions, err := client.ListSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	table := tabwriter.NewWriter(app.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "ID\tTYPE\tPUBLISHER ID\tPUBLISHER\tWEBHOOK")
+	for _, subscription := range subscriptions {
+		webhook := ""
+		if subscription.Webhook != nil {
+			webhook = subscription.Webhook.URL + " (" + subscription.Webhook.Status + ")"
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", subscription.ID, subscription.Type, subscription.Publisher.ID, subscription.Publisher.Name, webhook)
+	}
+	return table.Flush()
+}
+
+func (app *cli) subscribe(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("subscribe", flag.ContinueOnError)
+	webhookURL := flags.String("webhook", "", "URL to push the messages to instead of consuming them")
+	secret := flags.String("secret", "", "secret the webhook deliveries are signed with")
+	args, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if err := expectArgs(args, "<publisher id>"); err != nil {
+		return err
+	}
+	client, _, err := app.client(ctx)
+	if err != nil {
+		return err
+	}
+	if *webhookURL != "" {
+		return client.SubscribeWebhook(ctx, args[0], *webhookURL, *secret)
+	}
+	return client.Subscribe(ctx, args[0])
+}
+
+func (app *cli) request(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("request", flag.ContinueOnError)
+	webhookURL := flags.String("webhook", "", "URL to push the messages to once the request is approved")
+	secret := flags.String("secret", "", "secret the webhook deliveries are signed with")
+	args, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(args) < 1 || len(args) > 2 {
+		return errors.New("expected arguments: <publisher id> [message]")
+	}
+	message := ""
+	if len(args) == 2 {
+		message = args[1]
+	}
+	client, _, err := app.client(ctx)
+	if err != nil {
+		return err
+	}
+	var request *messagebrokerclient.SubscriptionRequest
+	if *webhookURL != "" {
+		request, err = client.RequestWebhookSubscription(ctx, args[0], message, *webhookURL, *secret)
+	} else {
+		request, err = client.RequestSubscription(ctx, args[0], message)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(app.stdout, request.ID)
+	return nil
+}
+
+func (app *cli) requests(ctx context.Context, args []string) error {
+	subcommand := "list"
+	if len(args) > 0 {
+		switch args[0] {
+		case "incoming", "approve", "deny":
+			subcommand, args = args[0], args[1:]
+		}
+	}
+	client, _, err := app.client(ctx)
+	if err != nil {
+		return err
+	}
+	switch subcommand {
+	case "list", "incoming":
+		if len(args) > 1 {
+			return errors.New("expected arguments: [status]")
+		}
+		status := ""
+		if len(args) == 1 {
+			status = args[0]
+		}
+		var requests []messagebrokerclient.SubscriptionRequest
+		if subcommand == "incoming" {
+			requests, err = client.ListIncomingSubscriptionRequests(ctx, status)
+		} else {
+			requests, err = client.ListSubscriptionRequests(ctx, status)
+		}
+		if err != nil {
+			return err
+		}
+		table := tabwriter.NewWriter(app.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(table, "ID\tSTATUS\tCLIENT ID\tPUBLISHER ID\tPUBLISHER\tMESSAGE")
+		for _, request := range requests {
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n", request.ID, request.Status, request.ClientID, request.Publisher.ID, request.Publisher.Name, request.Message)
+		}
+		return table.Flush()
+	case "approve", "deny":
+		if len(args) < 1 || len(args) > 2 {
+			return errors.New("expected arguments: <request id> [reason]")
+		}
+		reason := ""
+		if len(args) == 2 {
+			reason = args[1]
+		}
+		if subcommand == "approve" {
+			_, err = client.ApproveSubscriptionRequest(ctx, args[0], reason)
+		} else {
+			_, err = client.DenySubscriptionRequest(ctx, args[0], reason)
+		}
+		return err
+	}
+	return fmt.Errorf("unknown requests command %q", subcommand)
+}
+
+func (app *cli) unsubscribe(ctx context.Context, args []string) error {
+	if err := expectArgs(args, "<subscription id>"); err != nil {
+		return err
+	}
+	client, _, err := app.client(ctx)
+	if err != nil {
+		return err
+	}
+	return client.Unsubscribe(ctx, args[0])
+}
+
+func (app *cli) publish(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("publish", flag.ContinueOnError)
+	ttl := flags.Duration("ttl", 0, "how long the message is kept, 0 keeps it until it is deleted")
+	file := flags.String("file", "", "file to publish the contents of")
+	args, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(args) < 1 || len(args) > 2 {
+		return errors.New("expected arguments: <publisher id> [message]")
+	}
+	var payload []byte
+	switch {
+	case *file != "" && len(args) == 2:
+		return errors.New("give either a message or -file, not both")
+	case *file != "":
+		payload, err = os.ReadFile(*file)
+	case len(args) == 2 && args[1] != "-":
+		payload = []byte(args[1])
+	default:
+		payload, err = io.ReadAll(app.stdin)
+	}
+	if err != nil {
+		return err
+	}
+	client, _, err := app.client(ctx)
+	if err != nil {
+		return err
+	}
+	return client.Publish(ctx, args[0], string(payload), *ttl)
+}
+
+func (app *cli) tail(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("tail", flag.ContinueOnError)
+	confirm := flags.Bool("confirm", false, "acknowledge messages once printed, otherwise they are delivered again when their lease runs out")
+	max := flags.Int("max", 10, "most messages to pull at a time")
+	lease := flags.Duration("lease", 0, "how long pulled messages are leased for, defaults to the broker's lease")
+	args, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if err := expectArgs(args, "<subscription id>"); err != nil {
+		return err
+	}
+	saved, err := loadCredentials()
+	if err != nil {
+		return err
+	}
+	puller := messagebrokerclient.NewPuller(pickURL(app.brokerURL, saved.BrokerURL, defaultBrokerURL), saved.ClientID, saved.Secret)
+	encoder := json.NewEncoder(app.stdout)
+	options := messagebrokerclient.PullOptions{Max: *max, Wait: 30 * time.Second, Lease: *lease}
+	for {
+		result, err := puller.Pull(ctx, args[0], options)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		ids := make([]string, 0, len(result.Messages))
+		for _, message := range result.Messages {
+			err = encoder.Encode(message)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, message.ID)
+		}
+		if *confirm && len(ids) > 0 {
+			//confirm even when interrupted as the messages have already been printed
+			_, err = puller.Ack(context.WithoutCancel(ctx), args[0], ids)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (app *cli) printLimits(limits *messagebrokerclient.RateLimits, override *messagebrokerclient.RateLimits) {
+	show := func(value int) string {
+		if value == 0 {
+			return "no limit"
+		}
+		return fmt.Sprint(value)
+	}
+	//the burst defaults to a second's worth
+	burst := func(value int) string {
+		if value == 0 {
+			return ""
+		}
+		return fmt.Sprintf("burst %d", value)
+	}
+	table := tabwriter.NewWriter(app.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(table, "publish/minute:\t%s\t%s\n", show(limits.PublishPerMinute), burst(limits.PublishBurst))
+	fmt.Fprintf(table, "requests/minute:\t%s\t%s\n", show(limits.RequestsPerMinute), burst(limits.RequestsBurst))
+	fmt.Fprintf(table, "connections:\t%s\n", show(limits.Connections))
+	if override != nil {
+		fmt.Fprintln(table, "overridden:\tyes")
+	}
+	table.Flush()
+}
+
+//client calling the admin routes with MSGBROKER_ADMIN_SECRET rather than as the client logged in
+func (app *cli) adminClient() (*messagebrokerclient.Client, error) {
+	secret := os.Getenv("MSGBROKER_ADMIN_SECRET")
+	if secret == "" {
+		return nil, errors.New("MSGBROKER_ADMIN_SECRET must be set")
+	}
+	saved, err := loadCredentials()
+	if err != nil {
+		saved = &credentials{}
+	}
+	return messagebrokerclient.New(pickURL(app.serviceURL, saved.ServiceURL, defaultServiceURL), messagebrokerclient.WithAdminSecret(secret))
+}
+
+func (app *cli) limits(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		client, _, err := app.client(ctx)
+		if err != nil {
+			return err
+		}
+		limits, override, err := client.GetRateLimits(ctx)
+		if err != nil {
+			return err
+		}
+		app.printLimits(limits, override)
+		return nil
+	}
+	subcommand, args := args[0], args[1:]
+	client, err := app.adminClient()
+	if err != nil {
+		return err
+	}
+	switch subcommand {
+	case "show":
+		if err := expectArgs(args, "<client id>"); err != nil {
+			return err
+		}
+		limits, override, err := client.GetClientRateLimits(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		app.printLimits(limits, override)
+		return nil
+	case "set":
+		flags := flag.NewFlagSet("limits set", flag.ContinueOnError)
+		override := messagebrokerclient.RateLimits{}
+		flags.IntVar(&override.PublishPerMinute, "publish", 0, "messages the client can publish a minute")
+		flags.IntVar(&override.PublishBurst, "publish-burst", 0, "messages the client can publish at once")
+		flags.IntVar(&override.RequestsPerMinute, "requests", 0, "API calls the client can make a minute")
+		flags.IntVar(&override.RequestsBurst, "requests-burst", 0, "API calls the client can make at once")
+		flags.IntVar(&override.Connections, "connections", 0, "sessions the client can have open at once")
+		args, err := parseFlags(flags, args)
+		if err != nil {
+			return err
+		}
+		if err := expectArgs(args, "<client id>"); err != nil {
+			return err
+		}
+		limits, err := client.SetClientRateLimits(ctx, args[0], override)
+		if err != nil {
+			return err
+		}
+		app.printLimits(limits, &override)
+		return nil
+	case "reset":
+		if err := expectArgs(args, "<client id>"); err != nil {
+			return err
+		}
+		limits, err := client.ResetClientRateLimits(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		app.printLimits(limits, nil)
+		return nil
+	}
+	return fmt.Errorf("unknown limits command %q", subcommand)
+}
+
+//usage against a quota, e.g. 120/1000
+func formatUsage(used int64, max int64) string {
+	if max == 0 {
+		return fmt.Sprint(used)
+	}
+	return fmt.Sprintf("%d/%d", used, max)
+}
+
+func (app *cli) usage(ctx context.Context, args []string) error {
+	if len(args) > 1 {
+		return errors.New("expected arguments: [publisher id]")
+	}
+	client, _, err := app.client(ctx)
+	if err != nil {
+		return err
+	}
+	table := tabwriter.NewWriter(app.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "ID\tNAME\tMESSAGES\tBYTES")
+	if len(args) == 1 {
+		usage, err := client.GetPublisherUsage(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(table, "%s\t\t%s\t%s\n", args[0], formatUsage(usage.Messages, usage.MaxMessages), formatUsage(usage.Bytes, usage.MaxBytes))
+		return table.Flush()
+	}
+	total, publishers, err := client.GetUsage(ctx)
+	if err != nil {
+		return err
+	}
+	for _, publisher := range publishers {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", publisher.ID, publisher.Name, formatUsage(publisher.Usage.Messages, publisher.Usage.MaxMessages), formatUsage(publisher.Usage.Bytes, publisher.Usage.MaxBytes))
+	}
+	fmt.Fprintf(table, "total\t\t%s\t%s\n", formatUsage(total.Messages, total.MaxMessages), formatUsage(total.Bytes, total.MaxBytes))
+	return table.Flush()
+}
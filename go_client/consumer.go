@@ -0,0 +1,303 @@
+package messagebrokerclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	DeliveryFanOut   = "fanout"   //every session of the client receives every batch of messages
+	DeliveryBalanced = "balanced" //batches of messages are handed to the client's sessions in turn
+)
+
+var (
+	//ErrAuthenticationFailed is returned by Run when the message broker doesn't accept the client id
+	ErrAuthenticationFailed = errors.New("message broker authentication failed")
+	//ErrSessionReplaced is returned by Run when another session of the client was opened with SingleSession
+	ErrSessionReplaced = errors.New("session replaced by a new session of the client")
+	//ErrSessionRefused is returned by Run when the client's open sessions use a different Delivery
+	ErrSessionRefused = errors.New("session refused by the message broker")
+)
+
+//Message received from one of the client's subscriptions
+type Message struct {
+	ID             string `json:"id"`
+	PublisherID    string `json:"publisher_id"`
+	SubscriptionID string `json:"subscription_id"`
+	Payload        string `json:"payload"`
+	SchemaVersion  int    `json:"schema_version"` //version of the publisher's schema the payload matched, 0 if it doesn't have one
+
+	lock    sync.Mutex
+	settled bool
+	acked   bool
+}
+
+//Ack marks the message as handled, it is confirmed once the handler returns
+func (message *Message) Ack() {
+	message.settle(true)
+}
+
+//Nack marks the message as not handled, it will be delivered again
+func (message *Message) Nack() {
+	message.settle(false)
+}
+
+func (message *Message) settle(acked bool) {
+	message.lock.Lock()
+	defer message.lock.Unlock()
+	if !message.settled {
+		message.settled = true
+		message.acked = acked
+	}
+}
+
+//Handler is called for each message received. If it doesn't Ack or Nack the message, returning nil acks it
+//and returning an error nacks it.
+type Handler func(ctx context.Context, message *Message) error
+
+//Notice sent by the message broker, e.g. session_started or server_shutting_down
+type Notice struct {
+	Action  string          `json:"action"`
+	Message string          `json:"message,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+//ConsumerConfig configures a Consumer
+type ConsumerConfig struct {
+	URL           string //websocket URL of the message broker, e.g. ws://localhost:8001/ws
+	ClientID      string
+	ClientSecret  string
+	APIKey        string                                    //authenticate with an API key instead of the client id and secret
+	Token         func(ctx context.Context) (string, error) //authenticate with an access token each time it connects, e.g. TokenSource.Token
+	Delivery      string                                    //DeliveryFanOut (the default) or DeliveryBalanced
+	SingleSession bool                                      //close any other sessions the client has open
+	Handler       Handler
+	OnNotice      func(Notice) //optional, called for notices from the message broker
+	OnError       func(error)  //optional, called when the connection is lost before reconnecting
+	MinBackoff    time.Duration
+	MaxBackoff    time.Duration
+	Dialer        *websocket.Dialer
+}
+
+//Consumer receives the messages of a client's subscriptions over a websocket, handling the authentication
+//handshake, confirming the messages the handler acks and reconnecting with backoff when the connection is lost
+type Consumer struct {
+	config ConsumerConfig
+}
+
+//NewConsumer creates a consumer, call Run to start receiving messages
+func NewConsumer(config ConsumerConfig) *Consumer {
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = time.Second
+	}
+	if config.MaxBackoff < config.MinBackoff {
+		config.MaxBackoff = 30 * time.Second
+	}
+	if config.Dialer == nil {
+		config.Dialer = websocket.DefaultDialer
+	}
+	return &Consumer{config: config}
+}
+
+//websocket message sent to and from the message broker
+type communication struct {
+	Action  string          `json:"action"`
+	Message string          `json:"message,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+type authenticationResponse struct {
+	ID            string `json:"id"`
+	Secret        string `json:"secret,omitempty"`
+	APIKey        string `json:"api_key,omitempty"`
+	Token         string `json:"token,omitempty"`
+	Delivery      string `json:"delivery,omitempty"`
+	SingleSession bool   `json:"single_session,omitempty"`
+}
+
+type confirmation struct {
+	ID             string `json:"id"`
+	SubscriptionID string `json:"subscription_id"`
+}
+
+//Run receives messages until ctx is cancelled, reconnecting whenever the connection is lost. It returns
+//ctx's error once cancelled, or ErrAuthenticationFailed, ErrSessionReplaced or ErrSessionRefused as reconnecting
+//wouldn't help.
+func (consumer *Consumer) Run(ctx context.Context) error {
+	if consumer.config.Handler == nil {
+		return errors.New("consumer handler required")
+	}
+	backoff := consumer.config.MinBackoff
+	for {
+		started, reconnectAfter, err := consumer.runSession(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, ErrAuthenticationFailed) || errors.Is(err, ErrSessionReplaced) || errors.Is(err, ErrSessionRefused) {
+			return err
+		}
+		if err != nil && consumer.config.OnError != nil {
+			consumer.config.OnError(err)
+		}
+		if started {
+			backoff = consumer.config.MinBackoff
+		}
+		wait := backoff
+		if reconnectAfter > 0 {
+			//the server said when to come back as it was shutting down or the client had too many sessions open
+			wait = reconnectAfter
+		} else {
+			backoff *= 2
+			if backoff > consumer.config.MaxBackoff {
+				backoff = consumer.config.MaxBackoff
+			}
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+//run a single connection, returns whether the session started and how long the server asked to wait before reconnecting
+func (consumer *Consumer) runSession(ctx context.Context) (bool, time.Duration, error) {
+	connection, _, err := consumer.config.Dialer.DialContext(ctx, consumer.config.URL, nil)
+	if err != nil {
+		return false, 0, err
+	}
+	defer connection.Close()
+
+	//read in the background so a cancelled context isn't stuck behind a blocking read
+	received := make(chan communication)
+	readErrors := make(chan error, 1)
+	done := make(chan bool)
+	defer close(done)
+	go func() {
+		for {
+			message := communication{}
+			err := connection.ReadJSON(&message)
+			if err != nil {
+				readErrors <- err
+				return
+			}
+			select {
+			case received <- message:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	started := false
+	reconnectAfter := time.Duration(0)
+	for {
+		select {
+		case <-ctx.Done():
+			connection.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			return started, 0, ctx.Err()
+		case err := <-readErrors:
+			return started, reconnectAfter, err
+		case message := <-received:
+			switch message.Action {
+			case "authenticate":
+				response := authenticationResponse{
+					ID:            consumer.config.ClientID,
+					Secret:        consumer.config.ClientSecret,
+					APIKey:        consumer.config.APIKey,
+					Delivery:      consumer.config.Delivery,
+					SingleSession: consumer.config.SingleSession,
+				}
+				if consumer.config.Token != nil {
+					response.Token, err = consumer.config.Token(ctx)
+					if err != nil {
+						return false, 0, err
+					}
+				}
+				err = connection.WriteJSON(response)
+			case "authentication_failed":
+				return false, 0, fmt.Errorf("%w: %s", ErrAuthenticationFailed, message.Message)
+			case "session_refused":
+				return false, 0, fmt.Errorf("%w: %s", ErrSessionRefused, message.Message)
+			case "session_started":
+				started = true
+				consumer.notice(message)
+			case "messages":
+				err = consumer.handleMessages(ctx, connection, message.Data)
+			case "session_replaced":
+				consumer.notice(message)
+				return started, 0, ErrSessionReplaced
+			case "server_shutting_down":
+				data := struct {
+					ReconnectAfterMS int64 `json:"reconnect_after_ms"`
+				}{}
+				json.Unmarshal(message.Data, &data)
+				reconnectAfter = time.Duration(data.ReconnectAfterMS) * time.Millisecond
+				consumer.notice(message)
+			case "rate_limited":
+				data := struct {
+					RetryAfterMS int64 `json:"retry_after_ms"`
+				}{}
+				json.Unmarshal(message.Data, &data)
+				reconnectAfter = time.Duration(data.RetryAfterMS) * time.Millisecond
+				consumer.notice(message)
+			default:
+				consumer.notice(message)
+			}
+			if err != nil {
+				return started, reconnectAfter, err
+			}
+		}
+	}
+}
+
+func (consumer *Consumer) notice(message communication) {
+	if consumer.config.OnNotice != nil {
+		consumer.config.OnNotice(Notice(message))
+	}
+}
+
+//pass a batch to the handler then confirm the messages it acked
+func (consumer *Consumer) handleMessages(ctx context.Context, connection *websocket.Conn, data json.RawMessage) error {
+	messages := []*Message{}
+	err := json.Unmarshal(data, &messages)
+	if err != nil {
+		return err
+	}
+	confirmations := []confirmation{}
+	subscriptionsConfirmed := make(map[string]bool)
+	subscriptionOrder := []string{}
+	for _, message := range messages {
+		if _, exists := subscriptionsConfirmed[message.SubscriptionID]; !exists {
+			subscriptionsConfirmed[message.SubscriptionID] = false
+			subscriptionOrder = append(subscriptionOrder, message.SubscriptionID)
+		}
+		handlerErr := consumer.config.Handler(ctx, message)
+		message.settle(handlerErr == nil)
+		if message.acked {
+			confirmations = append(confirmations, confirmation{
+				ID:             message.ID,
+				SubscriptionID: message.SubscriptionID,
+			})
+			subscriptionsConfirmed[message.SubscriptionID] = true
+		}
+	}
+	for _, subscriptionID := range subscriptionOrder {
+		if !subscriptionsConfirmed[subscriptionID] {
+			//nothing from the subscription was acked, the broker still needs to hear back to release the messages
+			confirmations = append(confirmations, confirmation{SubscriptionID: subscriptionID})
+		}
+	}
+	return connection.WriteJSON(map[string]interface{}{
+		"action": "confirm_messages",
+		"data": map[string]interface{}{
+			"messages": confirmations,
+		},
+	})
+}
@@ -0,0 +1,44 @@
+module bezberr.com/messagebrokerclient
+
+go 1.24.0
+
+require (
+	bezberr.com/messagebroker v0.0.0-00010101000000-000000000000
+	bezberr.com/messagebrokerpublisherservice v0.0.0-00010101000000-000000000000
+	bezberr.com/messagebrokerstorage v0.0.0-00010101000000-000000000000
+	github.com/gorilla/websocket v1.4.2
+)
+
+require (
+	bezberr.com/messagebrokerapi v0.0.0-00010101000000-000000000000 // indirect
+	github.com/go-stack/stack v1.8.0 // indirect
+	github.com/golang/snappy v0.0.1 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/gorilla/securecookie v1.1.1 // indirect
+	github.com/gorilla/sessions v1.2.1 // indirect
+	github.com/klauspost/compress v1.13.6 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/xdg-go/pbkdf2 v1.0.0 // indirect
+	github.com/xdg-go/scram v1.0.2 // indirect
+	github.com/xdg-go/stringprep v1.0.2 // indirect
+	github.com/youmark/pkcs8 v0.0.0-20181117223130-1be2e3e5546d // indirect
+	go.mongodb.org/mongo-driver v1.8.4 // indirect
+	golang.org/x/crypto v0.39.0 // indirect
+	golang.org/x/net v0.41.0 // indirect
+	golang.org/x/sync v0.15.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/text v0.26.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7 // indirect
+	google.golang.org/grpc v1.75.0 // indirect
+	google.golang.org/protobuf v1.36.9 // indirect
+)
+
+replace bezberr.com/messagebrokerapi => ../api
+
+replace bezberr.com/messagebrokerstorage => ../storage
+
+replace bezberr.com/messagebrokerconfig => ../config
+
+replace bezberr.com/messagebroker => ../app
+
+replace bezberr.com/messagebrokerpublisherservice => ../publisher_service/publisher
@@ -0,0 +1,92 @@
+package messagebrokerclient
+
+import (
+	"context"
+	"net/url"
+)
+
+//Organization keeps its members and their publishers apart from other tenants, whose clients can't see them
+//unless a publisher is shared with their organization through its ACL
+type Organization struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Admins []string `json:"admins"` //ids of the members who manage the organization and its publishers
+}
+
+//OrganizationMember is a client registered in an organization
+type OrganizationMember struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Admin bool   `json:"admin"`
+}
+
+//CreateOrganization with a unique name along with its first admin, the client is logged in as the admin. Returns
+//the admin's id and secret, which can't be retrieved again
+func (client *Client) CreateOrganization(ctx context.Context, name string, adminName string) (*Organization, *Credentials, error) {
+	result := struct {
+		Organization Organization `json:"organization"`
+		Row          Credentials  `json:"row"`
+	}{}
+	err := client.call(ctx, "POST", "/organizations", map[string]string{"name": name, "admin_name": adminName}, &result)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &result.Organization, &result.Row, nil
+}
+
+//GetOrganization the client is a member of, along with its members
+func (client *Client) GetOrganization(ctx context.Context) (*Organization, []OrganizationMember, error) {
+	result := struct {
+		Organization Organization         `json:"organization"`
+		Members      []OrganizationMember `json:"members"`
+	}{}
+	err := client.call(ctx, "GET", "/organization", nil, &result)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &result.Organization, result.Members, nil
+}
+
+//AddOrganizationMember registers a new client in the organization the client is an admin of, with a name unique
+//in the organization. Returns the id and secret of the new member
+func (client *Client) AddOrganizationMember(ctx context.Context, name string) (*Credentials, error) {
+	result := struct {
+		Row Credentials `json:"row"`
+	}{}
+	err := client.call(ctx, "POST", "/organization/members", map[string]string{"name": name}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Row, nil
+}
+
+func (client *Client) changeAdmins(ctx context.Context, method string, path string, body interface{}) (*Organization, error) {
+	result := struct {
+		Organization Organization `json:"organization"`
+	}{}
+	err := client.call(ctx, method, path, body, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Organization, nil
+}
+
+//AddOrganizationAdmin makes a member an admin of the organization, letting it manage the organization and every
+//publisher in it
+func (client *Client) AddOrganizationAdmin(ctx context.Context, clientID string) (*Organization, error) {
+	return client.changeAdmins(ctx, "POST", "/organization/admins", map[string]string{"client_id": clientID})
+}
+
+//RemoveOrganizationAdmin stops a member being an admin, the last admin can't be removed
+func (client *Client) RemoveOrganizationAdmin(ctx context.Context, clientID string) (*Organization, error) {
+	return client.changeAdmins(ctx, "DELETE", "/organization/admins/"+url.PathEscape(clientID), nil)
+}
+
+//ListOrganizationPublishers lists every publisher in the client's organization along with their owners
+func (client *Client) ListOrganizationPublishers(ctx context.Context) ([]Publisher, error) {
+	result := struct {
+		Publishers []Publisher `json:"publishers"`
+	}{}
+	err := client.call(ctx, "GET", "/publishers?organization=true", nil, &result)
+	return result.Publishers, err
+}
@@ -0,0 +1,52 @@
+package messagebrokerclient
+
+import (
+	"context"
+	"net/url"
+)
+
+//PayloadPolicy of a publisher, the limit on the size of its payloads and the content type they have to be
+type PayloadPolicy struct {
+	MaxBytes    int    `json:"max_bytes"`    //0 uses the service's limit
+	ContentType string `json:"content_type"` //e.g. application/json, empty accepts anything
+}
+
+//CreatePublisherWithPayload creates a publisher in the same way as CreatePublisher, with a policy for its payloads
+func (client *Client) CreatePublisherWithPayload(ctx context.Context, name string, policy PayloadPolicy) (*Publisher, error) {
+	result := struct {
+		Row Publisher `json:"row"`
+	}{}
+	request := map[string]interface{}{
+		"name":              name,
+		"content_type":      policy.ContentType,
+		"max_payload_bytes": policy.MaxBytes,
+	}
+	err := client.call(ctx, "POST", "/publishers", request, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Row, nil
+}
+
+//GetPublisherPayload returns the policy of one of the client's publishers, along with the limit its payloads are
+//held to once the service's limit is taken into account
+func (client *Client) GetPublisherPayload(ctx context.Context, publisherID string) (*PayloadPolicy, int, error) {
+	return client.payloadCall(ctx, "GET", publisherID, nil)
+}
+
+//SetPublisherPayload replaces the policy of one of the client's publishers, messages already published aren't checked
+func (client *Client) SetPublisherPayload(ctx context.Context, publisherID string, policy PayloadPolicy) (*PayloadPolicy, int, error) {
+	return client.payloadCall(ctx, "PUT", publisherID, policy)
+}
+
+func (client *Client) payloadCall(ctx context.Context, method string, publisherID string, body interface{}) (*PayloadPolicy, int, error) {
+	result := struct {
+		Payload         PayloadPolicy `json:"payload"`
+		MaxPayloadBytes int           `json:"max_payload_bytes"`
+	}{}
+	err := client.call(ctx, method, "/publishers/"+url.PathEscape(publisherID)+"/payload", body, &result)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &result.Payload, result.MaxPayloadBytes, nil
+}
@@ -0,0 +1,125 @@
+package messagebrokerclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Puller pulls messages from the client's subscriptions over HTTP rather than holding a websocket open
+type Puller struct {
+	brokerURL  string
+	clientID   string
+	secret     string
+	apiKey     string
+	token      func(ctx context.Context) (string, error)
+	httpClient *http.Client
+}
+
+//NewPuller creates a puller for the message broker at brokerURL, e.g. http://localhost:8001
+func NewPuller(brokerURL string, clientID string, secret string) *Puller {
+	return &Puller{
+		brokerURL:  strings.TrimSuffix(brokerURL, "/"),
+		clientID:   clientID,
+		secret:     secret,
+		httpClient: &http.Client{},
+	}
+}
+
+//NewAPIKeyPuller creates a puller authenticating with an API key, which can only pull the subscriptions it's
+//scoped to
+func NewAPIKeyPuller(brokerURL string, apiKey string) *Puller {
+	return &Puller{
+		brokerURL:  strings.TrimSuffix(brokerURL, "/"),
+		apiKey:     apiKey,
+		httpClient: &http.Client{},
+	}
+}
+
+//NewTokenPuller creates a puller authenticating with an access token, e.g. TokenSource.Token
+func NewTokenPuller(brokerURL string, token func(ctx context.Context) (string, error)) *Puller {
+	return &Puller{
+		brokerURL:  strings.TrimSuffix(brokerURL, "/"),
+		token:      token,
+		httpClient: &http.Client{},
+	}
+}
+
+//PullOptions controls a pull, zero values use the message broker's defaults
+type PullOptions struct {
+	Max   int           //most messages to return
+	Wait  time.Duration //how long to wait for messages to arrive if there aren't any
+	Lease time.Duration //how long the messages are leased to the client before they can be delivered again
+}
+
+//PullResult is the messages leased by a pull
+type PullResult struct {
+	Messages     []*Message `json:"messages"`
+	LeaseExpires time.Time  `json:"lease_expires"` //time the messages must be acked by
+}
+
+func (puller *Puller) call(ctx context.Context, subscriptionID string, action string, query url.Values, body interface{}, result interface{}) error {
+	requestURL := puller.brokerURL + "/subscriptions/" + url.PathEscape(subscriptionID) + "/" + action
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+	header := http.Header{}
+	if puller.apiKey != "" {
+		header.Set("X-API-Key", puller.apiKey)
+	} else if puller.token != nil {
+		token, err := puller.token(ctx)
+		if err != nil {
+			return err
+		}
+		header.Set("Authorization", "Bearer "+token)
+	} else {
+		header.Set("X-Client-Id", puller.clientID)
+		header.Set("X-Client-Secret", puller.secret)
+	}
+	return doJSON(ctx, puller.httpClient, "POST", requestURL, header, body, result)
+}
+
+//Pull leases the next messages from a subscription
+func (puller *Puller) Pull(ctx context.Context, subscriptionID string, options PullOptions) (*PullResult, error) {
+	query := url.Values{}
+	if options.Max > 0 {
+		query.Set("max", strconv.Itoa(options.Max))
+	}
+	if options.Wait > 0 {
+		query.Set("wait", options.Wait.String())
+	}
+	if options.Lease > 0 {
+		query.Set("lease", options.Lease.String())
+	}
+	result := PullResult{}
+	err := puller.call(ctx, subscriptionID, "pull", query, nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+type settleResult struct {
+	Settled int `json:"settled"`
+}
+
+//Ack confirms pulled messages, returning the number which were acknowledged
+func (puller *Puller) Ack(ctx context.Context, subscriptionID string, messageIDs []string) (int, error) {
+	result := settleResult{}
+	err := puller.call(ctx, subscriptionID, "ack", nil, map[string]interface{}{"messages": messageIDs}, &result)
+	return result.Settled, err
+}
+
+//Nack releases pulled messages to be delivered again after the delay
+func (puller *Puller) Nack(ctx context.Context, subscriptionID string, messageIDs []string, delay time.Duration) (int, error) {
+	body := map[string]interface{}{"messages": messageIDs}
+	if delay > 0 {
+		body["delay"] = delay.String()
+	}
+	result := settleResult{}
+	err := puller.call(ctx, subscriptionID, "nack", nil, body, &result)
+	return result.Settled, err
+}
@@ -0,0 +1,57 @@
+package messagebrokerclient
+
+import (
+	"context"
+	"net/url"
+)
+
+//RateLimits of a client, 0 when a limit is off. In an override 0 falls back to the default and a negative value
+//turns the limit off for the client
+type RateLimits struct {
+	PublishPerMinute  int `json:"publish_per_minute"`
+	PublishBurst      int `json:"publish_burst"`
+	RequestsPerMinute int `json:"requests_per_minute"`
+	RequestsBurst     int `json:"requests_burst"`
+	Connections       int `json:"connections"` //most sessions open on the message broker at once
+}
+
+type rateLimitsResult struct {
+	Limits   RateLimits  `json:"limits"`
+	Override *RateLimits `json:"override"`
+}
+
+func (client *Client) rateLimits(ctx context.Context, method string, path string, body interface{}) (*RateLimits, *RateLimits, error) {
+	result := rateLimitsResult{}
+	err := client.call(ctx, method, path, body, &result)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &result.Limits, result.Override, nil
+}
+
+//GetRateLimits applied to the client, along with its override of the defaults which is nil if it doesn't have one
+func (client *Client) GetRateLimits(ctx context.Context) (*RateLimits, *RateLimits, error) {
+	return client.rateLimits(ctx, "GET", "/rate-limits", nil)
+}
+
+func clientRateLimitsPath(clientID string) string {
+	return "/admin/clients/" + url.PathEscape(clientID) + "/rate-limits"
+}
+
+//GetClientRateLimits applied to any client along with its override, requires WithAdminSecret
+func (client *Client) GetClientRateLimits(ctx context.Context, clientID string) (*RateLimits, *RateLimits, error) {
+	return client.rateLimits(ctx, "GET", clientRateLimitsPath(clientID), nil)
+}
+
+//SetClientRateLimits overrides the default limits for a client, requires WithAdminSecret. Returns the limits now
+//applied to it
+func (client *Client) SetClientRateLimits(ctx context.Context, clientID string, override RateLimits) (*RateLimits, error) {
+	limits, _, err := client.rateLimits(ctx, "PUT", clientRateLimitsPath(clientID), override)
+	return limits, err
+}
+
+//ResetClientRateLimits removes a client's override so the defaults apply, requires WithAdminSecret
+func (client *Client) ResetClientRateLimits(ctx context.Context, clientID string) (*RateLimits, error) {
+	limits, _, err := client.rateLimits(ctx, "DELETE", clientRateLimitsPath(clientID), nil)
+	return limits, err
+}
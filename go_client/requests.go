@@ -0,0 +1,110 @@
+package messagebrokerclient
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+//statuses of a subscription request
+const (
+	RequestPending  = "pending"
+	RequestApproved = "approved"
+	RequestDenied   = "denied"
+)
+
+//SubscriptionRequest to subscribe to a publisher the client can't subscribe to directly, which the owner of the
+//publisher approves or denies. the subscription is added once the request is approved
+type SubscriptionRequest struct {
+	ID         string                `json:"id"`
+	ClientID   string                `json:"client_id"` //client asking to subscribe
+	Publisher  SubscriptionPublisher `json:"publisher"`
+	Message    string                `json:"message"`
+	Type       string                `json:"type"` //stream or webhook
+	WebhookURL string                `json:"webhook_url,omitempty"`
+	Status     string                `json:"status"`
+	Reason     string                `json:"reason,omitempty"` //from the owner when deciding
+	CreatedAt  time.Time             `json:"created_at"`
+	DecidedAt  *time.Time            `json:"decided_at,omitempty"`
+}
+
+func (client *Client) requestSubscription(ctx context.Context, body map[string]string) (*SubscriptionRequest, error) {
+	result := struct {
+		Request SubscriptionRequest `json:"request"`
+	}{}
+	err := client.call(ctx, "POST", "/subscription-requests", body, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Request, nil
+}
+
+//RequestSubscription asks the owner of a publisher to let the client subscribe to it, the message is passed on
+//to the owner
+func (client *Client) RequestSubscription(ctx context.Context, publisherID string, message string) (*SubscriptionRequest, error) {
+	return client.requestSubscription(ctx, map[string]string{"publisher_id": publisherID, "message": message})
+}
+
+//RequestWebhookSubscription asks to subscribe to a publisher with its messages pushed to a URL once approved
+func (client *Client) RequestWebhookSubscription(ctx context.Context, publisherID string, message string, webhookURL string, secret string) (*SubscriptionRequest, error) {
+	return client.requestSubscription(ctx, map[string]string{
+		"publisher_id": publisherID,
+		"message":      message,
+		"type":         "webhook",
+		"url":          webhookURL,
+		"secret":       secret,
+	})
+}
+
+func (client *Client) listSubscriptionRequests(ctx context.Context, path string, status string) ([]SubscriptionRequest, error) {
+	if status != "" {
+		path += "?status=" + url.QueryEscape(status)
+	}
+	result := struct {
+		Requests []SubscriptionRequest `json:"requests"`
+	}{}
+	err := client.call(ctx, "GET", path, nil, &result)
+	return result.Requests, err
+}
+
+//ListSubscriptionRequests the client has made, oldest first. an empty status lists every request
+func (client *Client) ListSubscriptionRequests(ctx context.Context, status string) ([]SubscriptionRequest, error) {
+	return client.listSubscriptionRequests(ctx, "/subscription-requests", status)
+}
+
+//ListIncomingSubscriptionRequests to subscribe to the client's publishers, oldest first
+func (client *Client) ListIncomingSubscriptionRequests(ctx context.Context, status string) ([]SubscriptionRequest, error) {
+	return client.listSubscriptionRequests(ctx, "/subscription-requests/incoming", status)
+}
+
+//ListPublisherSubscriptionRequests to subscribe to one of the client's publishers, oldest first
+func (client *Client) ListPublisherSubscriptionRequests(ctx context.Context, publisherID string, status string) ([]SubscriptionRequest, error) {
+	return client.listSubscriptionRequests(ctx, "/publishers/"+url.PathEscape(publisherID)+"/subscription-requests", status)
+}
+
+func (client *Client) decideSubscriptionRequest(ctx context.Context, requestID string, decision string, reason string) (*SubscriptionRequest, error) {
+	result := struct {
+		Request SubscriptionRequest `json:"request"`
+	}{}
+	err := client.call(ctx, "POST", "/subscription-requests/"+url.PathEscape(requestID)+"/"+decision, map[string]string{"reason": reason}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Request, nil
+}
+
+//ApproveSubscriptionRequest to one of the client's publishers, subscribing the client which made it
+func (client *Client) ApproveSubscriptionRequest(ctx context.Context, requestID string, reason string) (*SubscriptionRequest, error) {
+	return client.decideSubscriptionRequest(ctx, requestID, "approve", reason)
+}
+
+//DenySubscriptionRequest to one of the client's publishers, the reason is optional
+func (client *Client) DenySubscriptionRequest(ctx context.Context, requestID string, reason string) (*SubscriptionRequest, error) {
+	return client.decideSubscriptionRequest(ctx, requestID, "deny", reason)
+}
+
+//RemoveSubscriber removes a client's subscriptions to one of the client's publishers, taking it off the allowlist
+//and the approved clients
+func (client *Client) RemoveSubscriber(ctx context.Context, publisherID string, clientID string) error {
+	return client.call(ctx, "DELETE", "/publishers/"+url.PathEscape(publisherID)+"/subscribers/"+url.PathEscape(clientID), nil, nil)
+}
@@ -0,0 +1,70 @@
+package messagebrokerclient
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	CompatibilityBackward = "backward" //consumers on the new version can read messages published with the previous one
+	CompatibilityForward  = "forward"  //consumers still on the previous version can read messages published with the new one
+	CompatibilityFull     = "full"     //both backward and forward
+	CompatibilityNone     = "none"     //new versions aren't checked against the previous one
+)
+
+//Schema is a version of the JSON Schema payloads published to a publisher are validated against
+type Schema struct {
+	Version       int       `json:"version"`
+	Schema        string    `json:"schema"` //the JSON Schema document
+	Compatibility string    `json:"compatibility"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+//ListSchemas returns every version of a publisher's schema, oldest first. the publisher's owner and the clients
+//which can subscribe to it can read its schemas
+func (client *Client) ListSchemas(ctx context.Context, publisherID string) ([]Schema, error) {
+	result := struct {
+		Schemas []Schema `json:"schemas"`
+	}{}
+	err := client.call(ctx, "GET", "/publishers/"+url.PathEscape(publisherID)+"/schemas", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Schemas, nil
+}
+
+//GetSchema returns a version of a publisher's schema, or the latest if version is 0
+func (client *Client) GetSchema(ctx context.Context, publisherID string, version int) (*Schema, error) {
+	path := "latest"
+	if version != 0 {
+		path = strconv.Itoa(version)
+	}
+	result := struct {
+		Schema Schema `json:"schema"`
+	}{}
+	err := client.call(ctx, "GET", "/publishers/"+url.PathEscape(publisherID)+"/schemas/"+path, nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Schema, nil
+}
+
+//RegisterSchema adds the next version of one of the client's publishers' schemas, messages published after it have
+//to match it. compatibility is the rule it's checked against the latest version with, empty keeps the latest
+//version's or is backward for the first version. the APIError lists what's wrong if the schema is refused
+func (client *Client) RegisterSchema(ctx context.Context, publisherID string, schema string, compatibility string) (*Schema, error) {
+	result := struct {
+		Schema Schema `json:"schema"`
+	}{}
+	request := map[string]string{
+		"schema":        schema,
+		"compatibility": compatibility,
+	}
+	err := client.call(ctx, "POST", "/publishers/"+url.PathEscape(publisherID)+"/schemas", request, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Schema, nil
+}
@@ -0,0 +1,81 @@
+package messagebrokerclient
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+//Tokens issued by the publisher service, the access token authenticates with either service until it expires and
+//the refresh token exchanges for a new pair
+type Tokens struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`         //seconds until the access token expires
+	RefreshExpiresIn int64  `json:"refresh_expires_in"` //seconds until the refresh token expires
+}
+
+//IssueToken logs in with the client's id and secret, returning tokens rather than keeping a session cookie
+func (client *Client) IssueToken(ctx context.Context, id string, secret string) (*Tokens, error) {
+	result := struct {
+		Data Tokens `json:"data"`
+	}{}
+	err := client.call(ctx, "POST", "/auth/token", map[string]string{"id": id, "secret": secret}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+//RefreshToken exchanges a refresh token for a new pair, it stops working once the client's secret is rotated
+func (client *Client) RefreshToken(ctx context.Context, refreshToken string) (*Tokens, error) {
+	result := struct {
+		Data Tokens `json:"data"`
+	}{}
+	err := client.call(ctx, "POST", "/auth/token/refresh", map[string]string{"refresh_token": refreshToken}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+//TokenSource hands out an access token, refreshing it shortly before it expires and logging in again if the
+//refresh token has stopped working. it's safe to share between goroutines
+type TokenSource struct {
+	client    *Client
+	id        string
+	secret    string
+	lock      sync.Mutex
+	tokens    *Tokens
+	refreshAt time.Time
+}
+
+//NewTokenSource for the client with the id and secret, logging in through the publisher service client
+func NewTokenSource(client *Client, id string, secret string) *TokenSource {
+	return &TokenSource{client: client, id: id, secret: secret}
+}
+
+//Token returns an access token which is valid for a while yet
+func (source *TokenSource) Token(ctx context.Context) (string, error) {
+	source.lock.Lock()
+	defer source.lock.Unlock()
+	if source.tokens != nil && time.Now().Before(source.refreshAt) {
+		return source.tokens.AccessToken, nil
+	}
+	var tokens *Tokens
+	var err error
+	if source.tokens != nil {
+		tokens, err = source.client.RefreshToken(ctx, source.tokens.RefreshToken)
+	}
+	if tokens == nil {
+		tokens, err = source.client.IssueToken(ctx, source.id, source.secret)
+	}
+	if err != nil {
+		return "", err
+	}
+	source.tokens = tokens
+	//refresh with a fifth of the lifetime to spare so a token isn't sent just as it expires
+	source.refreshAt = time.Now().Add(time.Duration(tokens.ExpiresIn) * time.Second * 4 / 5)
+	return tokens.AccessToken, nil
+}
@@ -0,0 +1,46 @@
+package messagebrokerclient
+
+import (
+	"context"
+	"net/url"
+)
+
+//Usage of storage by messages against a quota, MaxMessages and MaxBytes are 0 when there isn't one
+type Usage struct {
+	Messages    int64 `json:"messages"`
+	Bytes       int64 `json:"bytes"` //of the messages' payloads
+	MaxMessages int64 `json:"max_messages"`
+	MaxBytes    int64 `json:"max_bytes"`
+}
+
+//PublisherUsage is the usage of one of the client's publishers
+type PublisherUsage struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Usage Usage  `json:"usage"`
+}
+
+//GetUsage of all of the client's publishers against the client's quota, along with each publisher's usage
+func (client *Client) GetUsage(ctx context.Context) (*Usage, []PublisherUsage, error) {
+	result := struct {
+		Usage      Usage            `json:"usage"`
+		Publishers []PublisherUsage `json:"publishers"`
+	}{}
+	err := client.call(ctx, "GET", "/usage", nil, &result)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &result.Usage, result.Publishers, nil
+}
+
+//GetPublisherUsage of one of the client's publishers against the publisher's quota
+func (client *Client) GetPublisherUsage(ctx context.Context, publisherID string) (*Usage, error) {
+	result := struct {
+		Usage Usage `json:"usage"`
+	}{}
+	err := client.call(ctx, "GET", "/publishers/"+url.PathEscape(publisherID)+"/usage", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Usage, nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	config "bezberr.com/messagebrokerconfig"
+	"bezberr.com/messagebrokerpublisherservice/management"
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/auth"
+	"bezberr.com/messagebrokerstorage/limits"
+)
+
+const envPrefix = "PUBLISHER_SERVICE"
+
+//settings of the publisher service, see config.Load for where they're read from
+type serviceConfig struct {
+	HTTPAddress     string                 `config:"http_address" flag:"http-address" usage:"address to serve the REST API on"`
+	GRPCAddress     string                 `config:"grpc_address" flag:"grpc-address" usage:"address to serve the gRPC API on, empty to disable gRPC"`
+	SessionSecret   string                 `config:"session_secret" flag:"session-secret" secret:"true" usage:"key the session cookies are signed with, a random one is generated each time the service starts if empty"`
+	AllowedOrigin   string                 `config:"allowed_origin" flag:"allowed-origin" usage:"origin of the web frontend allowed to call the REST API"`
+	TokenSecret     string                 `config:"token_secret" flag:"token-secret" secret:"true" usage:"key access and refresh tokens are signed with, shared with the message broker. tokens aren't issued if empty"`
+	AccessTokenTTL  time.Duration          `config:"access_token_ttl" flag:"access-token-ttl" usage:"how long an access token lasts"`
+	RefreshTokenTTL time.Duration          `config:"refresh_token_ttl" flag:"refresh-token-ttl" usage:"how long a refresh token lasts"`
+	AdminSecret     string                 `config:"admin_secret" flag:"admin-secret" secret:"true" usage:"secret sent in the X-Admin-Secret header to call the admin routes, they're disabled if empty"`
+	RateLimits      limits.RateLimitConfig `config:"rate_limits"`
+	Quotas          limits.Quotas          `config:"quotas"`
+	Payloads        limits.PayloadLimits   `config:"payloads"`
+	Storage         storage.Config         `config:"storage"`
+}
+
+func defaultConfig() *serviceConfig {
+	return &serviceConfig{
+		HTTPAddress:     management.DefaultHTTPAddress,
+		GRPCAddress:     management.DefaultGRPCAddress,
+		AllowedOrigin:   management.DefaultAllowedOrigin,
+		AccessTokenTTL:  auth.DefaultAccessTokenTTL,
+		RefreshTokenTTL: auth.DefaultRefreshTokenTTL,
+		Payloads:        limits.DefaultPayloadLimits(),
+		Storage:         storage.DefaultConfig(),
+	}
+}
+
+func (serviceConfig *serviceConfig) Validate() error {
+	err := config.ValidateAddress("http_address", serviceConfig.HTTPAddress)
+	if err == nil && serviceConfig.GRPCAddress != "" {
+		err = config.ValidateAddress("grpc_address", serviceConfig.GRPCAddress)
+	}
+	if err != nil {
+		return err
+	}
+	if serviceConfig.AllowedOrigin == "" {
+		return errors.New("allowed_origin is required")
+	}
+	if serviceConfig.AccessTokenTTL <= 0 || serviceConfig.RefreshTokenTTL <= 0 {
+		return errors.New("access_token_ttl and refresh_token_ttl must be positive")
+	}
+	err = serviceConfig.RateLimits.Validate()
+	if err == nil {
+		err = serviceConfig.Quotas.Validate()
+	}
+	if err == nil {
+		err = serviceConfig.Payloads.Validate()
+	}
+	if err != nil {
+		return err
+	}
+	return serviceConfig.Storage.Validate()
+}
+
+//options for the API set by the config
+func (serviceConfig *serviceConfig) serverOptions() []management.Option {
+	return []management.Option{
+		management.WithHTTPAddress(serviceConfig.HTTPAddress),
+		management.WithGRPCAddress(serviceConfig.GRPCAddress),
+		management.WithSessionSecret([]byte(serviceConfig.SessionSecret)),
+		management.WithAllowedOrigin(serviceConfig.AllowedOrigin),
+		management.WithTokenSecret([]byte(serviceConfig.TokenSecret)),
+		management.WithTokenTTLs(serviceConfig.AccessTokenTTL, serviceConfig.RefreshTokenTTL),
+		management.WithAdminSecret([]byte(serviceConfig.AdminSecret)),
+		management.WithRateLimits(serviceConfig.RateLimits),
+		management.WithQuotas(serviceConfig.Quotas),
+		management.WithPayloadLimits(serviceConfig.Payloads),
+	}
+}
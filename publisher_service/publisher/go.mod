@@ -1,17 +1,25 @@
 module bezberr.com/messagebrokerpublisherservice
 
-go 1.17
+go 1.24.0
 
-replace github.com/sberridge/bezmongo => ../mongo
+replace bezberr.com/messagebrokerapi => ../../api
+
+replace bezberr.com/messagebrokerstorage => ../../storage
+
+replace bezberr.com/messagebrokerconfig => ../../config
 
 require (
-	github.com/google/uuid v1.3.0
+	bezberr.com/messagebrokerapi v0.0.0-00010101000000-000000000000
+	bezberr.com/messagebrokerconfig v0.0.0-00010101000000-000000000000
+	bezberr.com/messagebrokerstorage v0.0.0-00010101000000-000000000000
+	github.com/google/uuid v1.6.0
 	github.com/gorilla/sessions v1.2.1
-	github.com/sberridge/bezmongo v0.0.0-00010101000000-000000000000
-	go.mongodb.org/mongo-driver v1.8.4
+	google.golang.org/grpc v1.75.0
+	google.golang.org/protobuf v1.36.9
 )
 
 require (
+	github.com/BurntSushi/toml v1.5.0 // indirect
 	github.com/go-stack/stack v1.8.0 // indirect
 	github.com/golang/snappy v0.0.1 // indirect
 	github.com/gorilla/securecookie v1.1.1 // indirect
@@ -21,7 +29,12 @@ require (
 	github.com/xdg-go/scram v1.0.2 // indirect
 	github.com/xdg-go/stringprep v1.0.2 // indirect
 	github.com/youmark/pkcs8 v0.0.0-20181117223130-1be2e3e5546d // indirect
-	golang.org/x/crypto v0.0.0-20201216223049-8b5274cf687f // indirect
-	golang.org/x/sync v0.0.0-20190911185100-cd5d95a43a6e // indirect
-	golang.org/x/text v0.3.5 // indirect
+	go.mongodb.org/mongo-driver v1.8.4 // indirect
+	golang.org/x/crypto v0.39.0 // indirect
+	golang.org/x/net v0.41.0 // indirect
+	golang.org/x/sync v0.15.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/text v0.26.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
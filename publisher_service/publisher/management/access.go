@@ -0,0 +1,297 @@
+package management
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	storage "bezberr.com/messagebrokerstorage"
+)
+
+type jsonACL struct {
+	Visibility string   `json:"visibility"` //public, private or allowlist
+	Clients    []string `json:"clients"`    //ids of the clients on the allowlist
+	Groups     []string `json:"groups"`     //ids of the owner's groups on the allowlist
+	Approved   []string `json:"approved"`   //ids of the clients whose subscription requests were approved, ignored when setting the ACL
+
+	Organizations []string `json:"organizations"` //ids of the organizations the publisher is shared with
+}
+
+type aclResult struct {
+	Success bool    `json:"success"`
+	ACL     jsonACL `json:"acl"`
+	Revoked int     `json:"revoked"` //subscriptions removed because the change took their access away
+}
+
+type aclClientRequest struct {
+	ClientID string `json:"client_id"`
+}
+
+type aclGroupRequest struct {
+	GroupID string `json:"group_id"`
+}
+
+func newJSONACL(access storage.Access) jsonACL {
+	acl := jsonACL{
+		Visibility: access.Visibility,
+		Clients:    append([]string{}, access.Clients...),
+		Groups:     append([]string{}, access.Groups...),
+		Approved:   append([]string{}, access.Approved...),
+
+		Organizations: append([]string{}, access.Organizations...),
+	}
+	if acl.Visibility == "" {
+		acl.Visibility = storage.VisibilityPublic
+	}
+	return acl
+}
+
+func createACLResponse(access storage.Access, revoked int, failedMessage string) []byte {
+	response, err := json.Marshal(aclResult{Success: true, ACL: newJSONACL(access), Revoked: revoked})
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	return response
+}
+
+//find a publisher if the client owns it or is an admin of its organization, nil if it isn't
+func findOwnedPublisher(pubId string, ownerId string, store storage.Store) (*storage.Publisher, error) {
+	publisher, err := store.FindPublisher(pubId)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	manages, err := publisher.ManagedBy(ownerId, store)
+	if err != nil || !manages {
+		return nil, err
+	}
+	return publisher, nil
+}
+
+//check the organizations a publisher is shared with exist, the clients on its allowlist exist and can see it once
+//the access is applied, and the groups belong to its owner
+func checkAccess(access storage.Access, publisher storage.Publisher, store storage.Store) error {
+	err := storage.ValidateVisibility(access.Visibility)
+	if err != nil {
+		return err
+	}
+	for _, id := range access.Organizations {
+		_, err := store.FindOrganization(id)
+		if errors.Is(err, storage.ErrNotFound) || (err == nil && id == publisher.OrganizationID) {
+			return fmt.Errorf("organization %s not found", id)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	publisher.Access = access
+	for _, id := range access.Clients {
+		client, err := store.FindClient(id)
+		if errors.Is(err, storage.ErrNotFound) || (err == nil && !publisher.VisibleTo(client)) {
+			return fmt.Errorf("client %s not found", id)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, id := range access.Groups {
+		group, err := store.FindGroup(id)
+		if errors.Is(err, storage.ErrNotFound) || (err == nil && group.OwnerID != publisher.OwnerID) {
+			return fmt.Errorf("group %s not found", id)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//remove the subscriptions of clients the publisher no longer allows, returning how many were removed. the
+//broker stops leasing the publisher's messages to a client as soon as its subscription is gone
+func revokeSubscriptions(publisherID string, store storage.Store) (int, error) {
+	publisher, err := store.FindPublisher(publisherID)
+	if err != nil {
+		return 0, err
+	}
+	subscribers, err := store.ListSubscribers(publisherID)
+	if err != nil {
+		return 0, err
+	}
+	revoked := 0
+	for _, subscriber := range subscribers {
+		client, err := store.FindClient(subscriber.ID)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return revoked, err
+		}
+		allowed, err := publisher.Allows(client, store)
+		if err != nil {
+			return revoked, err
+		}
+		if allowed {
+			continue
+		}
+		for _, subscription := range client.Subscriptions {
+			if subscription.PublisherID != publisherID {
+				continue
+			}
+			err = store.RemoveSubscription(client.ID, subscription.ID)
+			if err != nil && !errors.Is(err, storage.ErrNotFound) {
+				return revoked, err
+			}
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+func handleGetPublisherACL(pubId string, ownerId string, store storage.Store) []byte {
+	failedMessage := "get publisher access failed"
+	publisher, err := findOwnedPublisher(pubId, ownerId, store)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	if publisher == nil {
+		return createRefusalResponse(refusalNotFound, "publisher not found")
+	}
+	return createACLResponse(publisher.Access, 0, failedMessage)
+}
+
+//apply a change to the access of a publisher owned by the client, then revoke the subscriptions it disallows
+func updatePublisherAccess(pubId string, ownerId string, store storage.Store, update func(access *storage.Access) error) []byte {
+	failedMessage := "update publisher access failed"
+	publisher, err := findOwnedPublisher(pubId, ownerId, store)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	if publisher == nil {
+		return createRefusalResponse(refusalNotFound, "publisher not found")
+	}
+	access := publisher.Access
+	err = update(&access)
+	if err != nil {
+		return createMessageResponse(false, err.Error())
+	}
+	err = checkAccess(access, *publisher, store)
+	if err != nil {
+		return createMessageResponse(false, err.Error())
+	}
+	err = store.SetPublisherAccess(pubId, access)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	revoked, err := revokeSubscriptions(pubId, store)
+	if err != nil {
+		fmt.Println(err)
+		return createMessageResponse(false, "access updated but revoking subscriptions failed")
+	}
+	return createACLResponse(access, revoked, failedMessage)
+}
+
+func handleSetPublisherACL(pubId string, body io.ReadCloser, ownerId string, store storage.Store) []byte {
+	bytes, err := readBody(body)
+	if err != nil {
+		return createMessageResponse(false, "update publisher access failed")
+	}
+	request := jsonACL{}
+	err = json.Unmarshal(bytes, &request)
+	if err != nil {
+		return createMessageResponse(false, "update publisher access failed")
+	}
+	return updatePublisherAccess(pubId, ownerId, store, func(access *storage.Access) error {
+		*access = storage.Access{
+			Visibility: request.Visibility,
+			Clients:    uniqueIDs(request.Clients),
+			Groups:     uniqueIDs(request.Groups),
+			Approved:   access.Approved,
+
+			Organizations: uniqueIDs(request.Organizations),
+		}
+		return nil
+	})
+}
+
+func handleAllowClient(pubId string, body io.ReadCloser, ownerId string, store storage.Store) []byte {
+	bytes, err := readBody(body)
+	if err != nil {
+		return createMessageResponse(false, "update publisher access failed")
+	}
+	request := aclClientRequest{}
+	err = json.Unmarshal(bytes, &request)
+	if err != nil || request.ClientID == "" {
+		return createMessageResponse(false, "client_id required")
+	}
+	return updatePublisherAccess(pubId, ownerId, store, func(access *storage.Access) error {
+		access.Clients = uniqueIDs(append(access.Clients, request.ClientID))
+		return nil
+	})
+}
+
+func handleDisallowClient(pubId string, clientId string, ownerId string, store storage.Store) []byte {
+	return updatePublisherAccess(pubId, ownerId, store, func(access *storage.Access) error {
+		remaining, found := withoutID(access.Clients, clientId)
+		if !found {
+			return errors.New("client isn't on the allowlist")
+		}
+		access.Clients = remaining
+		return nil
+	})
+}
+
+func handleAllowGroup(pubId string, body io.ReadCloser, ownerId string, store storage.Store) []byte {
+	bytes, err := readBody(body)
+	if err != nil {
+		return createMessageResponse(false, "update publisher access failed")
+	}
+	request := aclGroupRequest{}
+	err = json.Unmarshal(bytes, &request)
+	if err != nil || request.GroupID == "" {
+		return createMessageResponse(false, "group_id required")
+	}
+	return updatePublisherAccess(pubId, ownerId, store, func(access *storage.Access) error {
+		access.Groups = uniqueIDs(append(access.Groups, request.GroupID))
+		return nil
+	})
+}
+
+func handleDisallowGroup(pubId string, groupId string, ownerId string, store storage.Store) []byte {
+	return updatePublisherAccess(pubId, ownerId, store, func(access *storage.Access) error {
+		remaining, found := withoutID(access.Groups, groupId)
+		if !found {
+			return errors.New("group isn't on the allowlist")
+		}
+		access.Groups = remaining
+		return nil
+	})
+}
+
+//ids in the order they first appear, without duplicates or empty ones
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool)
+	unique := []string{}
+	for _, id := range ids {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+func withoutID(ids []string, id string) ([]string, bool) {
+	remaining := []string{}
+	found := false
+	for _, existing := range ids {
+		if existing == id {
+			found = true
+			continue
+		}
+		remaining = append(remaining, existing)
+	}
+	return remaining, found
+}
@@ -0,0 +1,31 @@
+package management
+
+//API keys are managed with the client's own credentials, a key can't be used to create or revoke keys
+func apiKeyRoutes() []route {
+	return []route{
+		{
+			RoutePattern: "/api-keys",
+			Method:       "GET",
+			Authenticate: true,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleGetAPIKeys(rd.AuthID, rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/api-keys",
+			Method:       "POST",
+			Authenticate: true,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleCreateAPIKey(rd.Request.Body, rd.AuthID, rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/api-keys/{key_id}",
+			Method:       "DELETE",
+			Authenticate: true,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleRevokeAPIKey(rd.DynamicParams["key_id"], rd.AuthID, rd.Store)}
+			},
+		},
+	}
+}
@@ -0,0 +1,175 @@
+package management
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/auth"
+	"github.com/google/uuid"
+)
+
+type createAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"` //optional, the key never expires if it isn't set
+}
+
+type jsonAPIKey struct {
+	Id        string     `json:"id"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Key       string     `json:"key,omitempty"` //only returned when the key is created, just the hash of its secret is stored
+}
+
+type apiKeyResult struct {
+	Success bool       `json:"success"`
+	Key     jsonAPIKey `json:"key"`
+}
+
+type apiKeysResult struct {
+	Success bool         `json:"success"`
+	Keys    []jsonAPIKey `json:"keys"`
+}
+
+func newJSONAPIKey(key storage.APIKey) jsonAPIKey {
+	return jsonAPIKey{
+		Id:        key.ID,
+		Name:      key.Name,
+		Scopes:    key.Scopes,
+		CreatedAt: key.CreatedAt,
+		ExpiresAt: optionalTime(key.ExpiresAt),
+	}
+}
+
+//check a scope is well formed and names a publisher or subscription of the client
+func checkScope(scope string, client *storage.Client, store storage.Store) error {
+	err := auth.ValidateScope(scope)
+	if err != nil {
+		return err
+	}
+	switch {
+	case strings.HasPrefix(scope, auth.ScopePublishPrefix):
+		owned, err := checkOwnsPublisher(strings.TrimPrefix(scope, auth.ScopePublishPrefix), client.ID, store)
+		if err != nil {
+			return err
+		}
+		if !owned {
+			return fmt.Errorf("scope %s is for a publisher you don't own", scope)
+		}
+	case strings.HasPrefix(scope, auth.ScopeSubscribePrefix):
+		subscriptionID := strings.TrimPrefix(scope, auth.ScopeSubscribePrefix)
+		for _, subscription := range client.Subscriptions {
+			if subscription.ID == subscriptionID {
+				return nil
+			}
+		}
+		return fmt.Errorf("scope %s is for a subscription you don't have", scope)
+	}
+	return nil
+}
+
+func handleCreateAPIKey(body io.ReadCloser, clientId string, store storage.Store) []byte {
+	failedMessage := "create API key failed"
+	bytes, err := readBody(body)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	request := createAPIKeyRequest{}
+	err = json.Unmarshal(bytes, &request)
+	if err != nil {
+		return createMessageResponse(false, "Invalid json format")
+	}
+	if len(request.Scopes) == 0 {
+		return createMessageResponse(false, "an API key needs at least one scope")
+	}
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		return createMessageResponse(false, "expires_at must be in the future")
+	}
+
+	client, err := getClient(clientId, store)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	scopes := []string{}
+	for _, scope := range request.Scopes {
+		err = checkScope(scope, client, store)
+		if err != nil {
+			return createMessageResponse(false, err.Error())
+		}
+		scopes = append(scopes, scope)
+	}
+
+	secret, err := auth.NewSecret()
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	secretHash, err := auth.HashSecret(secret)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	key := storage.APIKey{
+		ID:         uuid.New().String(),
+		ClientID:   clientId,
+		Name:       request.Name,
+		SecretHash: secretHash,
+		Scopes:     scopes,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if request.ExpiresAt != nil {
+		key.ExpiresAt = request.ExpiresAt.UTC()
+	}
+	err = store.CreateAPIKey(key)
+	if err != nil {
+		fmt.Println(err)
+		return createMessageResponse(false, failedMessage)
+	}
+
+	result := newJSONAPIKey(key)
+	result.Key = auth.APIKeyToken(key.ID, secret)
+	response, err := json.Marshal(apiKeyResult{
+		Success: true,
+		Key:     result,
+	})
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	return response
+}
+
+func handleGetAPIKeys(clientId string, store storage.Store) []byte {
+	keys, err := store.ListAPIKeys(clientId)
+	if err != nil {
+		return createMessageResponse(false, "failed fetching API keys")
+	}
+	results := []jsonAPIKey{}
+	for _, key := range keys {
+		results = append(results, newJSONAPIKey(key))
+	}
+	response, err := json.Marshal(apiKeysResult{
+		Success: true,
+		Keys:    results,
+	})
+	if err != nil {
+		return createMessageResponse(false, "failed fetching API keys")
+	}
+	return response
+}
+
+//revoke a key, requests made with it are refused from then on
+func handleRevokeAPIKey(keyId string, clientId string, store storage.Store) []byte {
+	err := store.DeleteAPIKey(clientId, keyId)
+	if errors.Is(err, storage.ErrNotFound) {
+		return createRefusalResponse(refusalNotFound, "API key not found")
+	}
+	if err != nil {
+		return createMessageResponse(false, "revoke API key failed")
+	}
+	return createMessageResponse(true, "API key revoked")
+}
@@ -0,0 +1,54 @@
+package management
+
+func authRoutes() []route {
+	return []route{
+		{
+			Authenticate: false,
+			RoutePattern: "/auth",
+			Method:       "POST",
+			Func: func(data routeData, responseChan chan routeResponse) {
+				responseChan <- routeResponse{Body: handleAuth(data.Request.Body, data.Store, data.Session)}
+			},
+		},
+		{
+			Authenticate: false,
+			RoutePattern: "/auth",
+			Method:       "GET",
+			Func: func(data routeData, responseChan chan routeResponse) {
+				responseChan <- routeResponse{Body: handleCheckAuth(data.Session, data.Store)}
+			},
+		},
+		{
+			Authenticate: false,
+			RoutePattern: "/auth/token",
+			Method:       "POST",
+			Func: func(data routeData, responseChan chan routeResponse) {
+				responseChan <- routeResponse{Body: handleIssueToken(data.Request.Body, data.Store, data.Tokens)}
+			},
+		},
+		{
+			Authenticate: false,
+			RoutePattern: "/auth/token/refresh",
+			Method:       "POST",
+			Func: func(data routeData, responseChan chan routeResponse) {
+				responseChan <- routeResponse{Body: handleRefreshToken(data.Request.Body, data.Store, data.Tokens)}
+			},
+		},
+		{
+			Authenticate: true,
+			RoutePattern: "/auth/secret",
+			Method:       "POST",
+			Func: func(data routeData, responseChan chan routeResponse) {
+				responseChan <- routeResponse{Body: handleRotateSecret(data.AuthID, data.Store, data.Session)}
+			},
+		},
+		{
+			RoutePattern: "/admin/clients/{client_id}/secret",
+			Method:       "POST",
+			Admin:        true,
+			Func: func(data routeData, responseChan chan routeResponse) {
+				responseChan <- routeResponse{Body: handleResetClientSecret(data.DynamicParams["client_id"], data.Store)}
+			},
+		},
+	}
+}
@@ -0,0 +1,163 @@
+package management
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/auth"
+	"github.com/gorilla/sessions"
+)
+
+type authRequest struct {
+	UniqueId string `json:"id"`
+	Secret   string `json:"secret"`
+}
+
+type authResponseData struct {
+	Id             string `json:"id"`
+	Name           string `json:"name"`
+	OrganizationID string `json:"organization_id,omitempty"`
+}
+type authResponse struct {
+	Success bool             `json:"success"`
+	Data    authResponseData `json:"data"`
+}
+
+func handleAuth(body io.ReadCloser, store storage.Store, session *sessions.Session) []byte {
+
+	bytes, err := readBody(body)
+	failedAuthMessage := "Authentication failed"
+	if err != nil {
+		fmt.Println(err)
+		return createMessageResponse(false, failedAuthMessage)
+
+	}
+
+	requestBody := authRequest{}
+
+	err = json.Unmarshal(bytes, &requestBody)
+	if err != nil {
+		fmt.Println(err)
+		return createMessageResponse(false, failedAuthMessage)
+	}
+
+	clientStruct, err := authenticateClient(requestBody.UniqueId, requestBody.Secret, store)
+
+	if err != nil {
+		return createRefusalResponse(refusalUnauthenticated, failedAuthMessage)
+	}
+	response, err := json.Marshal(authResponse{
+		Success: true,
+		Data: authResponseData{
+			Id:             clientStruct.ID,
+			Name:           clientStruct.Name,
+			OrganizationID: clientStruct.OrganizationID,
+		},
+	})
+	if err != nil {
+		return createMessageResponse(false, failedAuthMessage)
+	}
+	startSession(session, clientStruct)
+	return response
+}
+
+type checkAuthResponseData struct {
+	Id             string `json:"id"`
+	Name           string `json:"name"`
+	OrganizationID string `json:"organization_id,omitempty"`
+}
+type checkAuthResponse struct {
+	Success bool                  `json:"success"`
+	Data    checkAuthResponseData `json:"data"`
+}
+
+func getClient(clientId string, store storage.Store) (*storage.Client, error) {
+	return store.FindClient(clientId)
+}
+
+//find a client by its id, returning storage.ErrNotFound unless the secret is the client's
+func authenticateClient(clientId string, secret string, store storage.Store) (*storage.Client, error) {
+	client, err := getClient(clientId, store)
+	if err != nil {
+		return nil, err
+	}
+	if !auth.CheckSecret(client.SecretHash, secret) {
+		return nil, storage.ErrNotFound
+	}
+	return client, nil
+}
+
+type rotateSecretResponse struct {
+	Success bool             `json:"success"`
+	Data    registerResponse `json:"data"`
+}
+
+//issue the client a new secret, the old one stops working straight away along with any sessions and tokens
+//except the session it was rotated from
+func handleRotateSecret(clientId string, store storage.Store, session *sessions.Session) []byte {
+	return issueSecret(clientId, store, session, "failed rotating secret")
+}
+
+//issue any client a new secret, e.g. one registered before secrets were issued which can't log in to rotate it
+func handleResetClientSecret(clientId string, store storage.Store) []byte {
+	return issueSecret(clientId, store, nil, "failed resetting secret")
+}
+
+func issueSecret(clientId string, store storage.Store, session *sessions.Session, failedMessage string) []byte {
+	secret, err := auth.NewSecret()
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	secretHash, err := auth.HashSecret(secret)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	err = store.SetClientSecret(clientId, secretHash)
+	if errors.Is(err, storage.ErrNotFound) {
+		return createRefusalResponse(refusalNotFound, "client not found")
+	}
+	if err != nil {
+		fmt.Println(err)
+		return createMessageResponse(false, failedMessage)
+	}
+	if session != nil && session.Values["auth_id"] == clientId {
+		session.Values["secret_version"] = auth.SecretVersion(secretHash)
+	}
+	response, err := json.Marshal(rotateSecretResponse{
+		Success: true,
+		Data: registerResponse{
+			Id:     clientId,
+			Secret: secret,
+		},
+	})
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	return response
+}
+
+func handleCheckAuth(ses *sessions.Session, store storage.Store) []byte {
+	id, authed := checkAuth(ses, store)
+	if authed {
+		client, err := getClient(id, store)
+		if err != nil {
+			return createMessageResponse(false, "failed checking auth")
+		}
+		response, err := json.Marshal(checkAuthResponse{
+			Success: true,
+			Data: checkAuthResponseData{
+				Id:             id,
+				Name:           client.Name,
+				OrganizationID: client.OrganizationID,
+			},
+		})
+		if err != nil {
+			return createMessageResponse(false, "failed checking auth")
+		}
+		return response
+	}
+	return createRefusalResponse(refusalUnauthenticated, "not authed")
+}
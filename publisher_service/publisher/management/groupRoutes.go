@@ -0,0 +1,45 @@
+package management
+
+import "bezberr.com/messagebrokerstorage/auth"
+
+//groups of clients which can be put on the allowlists of the owner's publishers
+func groupRoutes() []route {
+	return []route{
+		{
+			RoutePattern: "/groups",
+			Method:       "GET",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleGetGroups(rd.AuthID, rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/groups",
+			Method:       "POST",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleCreateGroup(rd.Request.Body, rd.AuthID, rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/groups/{group_id}/members",
+			Method:       "PUT",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleSetGroupMembers(rd.DynamicParams["group_id"], rd.Request.Body, rd.AuthID, rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/groups/{group_id}",
+			Method:       "DELETE",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleDeleteGroup(rd.DynamicParams["group_id"], rd.AuthID, rd.Store)}
+			},
+		},
+	}
+}
@@ -0,0 +1,214 @@
+package management
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	storage "bezberr.com/messagebrokerstorage"
+)
+
+type jsonGroup struct {
+	Id      string   `json:"id"`
+	Name    string   `json:"name"`
+	Members []string `json:"members"` //ids of the clients in the group
+}
+
+type groupResult struct {
+	Success bool      `json:"success"`
+	Group   jsonGroup `json:"group"`
+	Revoked int       `json:"revoked"` //subscriptions removed because the change took their access away
+}
+
+type groupsResult struct {
+	Success bool        `json:"success"`
+	Groups  []jsonGroup `json:"groups"`
+}
+
+type createGroupRequest struct {
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
+}
+
+type setGroupMembersRequest struct {
+	Members []string `json:"members"`
+}
+
+func newJSONGroup(group storage.Group) jsonGroup {
+	return jsonGroup{Id: group.ID, Name: group.Name, Members: append([]string{}, group.Members...)}
+}
+
+func createGroupResponse(group storage.Group, revoked int, failedMessage string) []byte {
+	response, err := json.Marshal(groupResult{Success: true, Group: newJSONGroup(group), Revoked: revoked})
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	return response
+}
+
+//check the members of a group are registered clients in the owner's organization
+func checkMembers(members []string, ownerId string, store storage.Store) error {
+	owner, err := store.FindClient(ownerId)
+	if err != nil {
+		return err
+	}
+	for _, id := range members {
+		member, err := store.FindClient(id)
+		if errors.Is(err, storage.ErrNotFound) || (err == nil && member.OrganizationID != owner.OrganizationID) {
+			return fmt.Errorf("client %s not found", id)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//find a group if it's owned by the client, nil if it isn't
+func findOwnedGroup(groupId string, ownerId string, store storage.Store) (*storage.Group, error) {
+	group, err := store.FindGroup(groupId)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if group.OwnerID != ownerId {
+		return nil, nil
+	}
+	return group, nil
+}
+
+//revoke the subscriptions to the owner's publishers which allowed the group, after its members have changed or
+//it's been deleted. a deleted group is taken off the allowlists too
+func revokeGroupSubscriptions(groupId string, ownerId string, deleted bool, store storage.Store) (int, error) {
+	publishers, err := store.ListPublishers(ownerId)
+	if err != nil {
+		return 0, err
+	}
+	revoked := 0
+	for _, publisher := range publishers {
+		remaining, allowsGroup := withoutID(publisher.Access.Groups, groupId)
+		if !allowsGroup {
+			continue
+		}
+		if deleted {
+			access := publisher.Access
+			access.Groups = remaining
+			err = store.SetPublisherAccess(publisher.ID, access)
+			if err != nil {
+				return revoked, err
+			}
+		}
+		count, err := revokeSubscriptions(publisher.ID, store)
+		revoked += count
+		if err != nil {
+			return revoked, err
+		}
+	}
+	return revoked, nil
+}
+
+func handleGetGroups(ownerId string, store storage.Store) []byte {
+	groups, err := store.ListGroups(ownerId)
+	if err != nil {
+		return createMessageResponse(false, "failed fetching groups")
+	}
+	results := []jsonGroup{}
+	for _, group := range groups {
+		results = append(results, newJSONGroup(group))
+	}
+	response, err := json.Marshal(groupsResult{Success: true, Groups: results})
+	if err != nil {
+		return createMessageResponse(false, "failed fetching groups")
+	}
+	return response
+}
+
+func handleCreateGroup(body io.ReadCloser, ownerId string, store storage.Store) []byte {
+	failedMessage := "create group failed"
+	bytes, err := readBody(body)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	request := createGroupRequest{}
+	err = json.Unmarshal(bytes, &request)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	if request.Name == "" {
+		return createMessageResponse(false, "name required")
+	}
+	members := uniqueIDs(request.Members)
+	err = checkMembers(members, ownerId, store)
+	if err != nil {
+		return createMessageResponse(false, err.Error())
+	}
+	group, err := store.CreateGroup(ownerId, request.Name, members)
+	if errors.Is(err, storage.ErrConflict) {
+		return createMessageResponse(false, "group already exists")
+	}
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	return createGroupResponse(*group, 0, failedMessage)
+}
+
+func handleSetGroupMembers(groupId string, body io.ReadCloser, ownerId string, store storage.Store) []byte {
+	failedMessage := "update group failed"
+	bytes, err := readBody(body)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	request := setGroupMembersRequest{}
+	err = json.Unmarshal(bytes, &request)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	group, err := findOwnedGroup(groupId, ownerId, store)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	if group == nil {
+		return createRefusalResponse(refusalNotFound, "group not found")
+	}
+	members := uniqueIDs(request.Members)
+	err = checkMembers(members, ownerId, store)
+	if err != nil {
+		return createMessageResponse(false, err.Error())
+	}
+	err = store.SetGroupMembers(groupId, members)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	group.Members = members
+	revoked, err := revokeGroupSubscriptions(groupId, ownerId, false, store)
+	if err != nil {
+		fmt.Println(err)
+		return createMessageResponse(false, "group updated but revoking subscriptions failed")
+	}
+	return createGroupResponse(*group, revoked, failedMessage)
+}
+
+//deleting a group takes it off the owner's allowlists, revoking the access it gave its members
+func handleDeleteGroup(groupId string, ownerId string, store storage.Store) []byte {
+	failedMessage := "delete group failed"
+	group, err := findOwnedGroup(groupId, ownerId, store)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	if group == nil {
+		return createRefusalResponse(refusalNotFound, "group not found")
+	}
+	err = store.DeleteGroup(groupId)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	_, err = revokeGroupSubscriptions(groupId, ownerId, true, store)
+	if err != nil {
+		fmt.Println(err)
+		return createMessageResponse(false, "group deleted but revoking subscriptions failed")
+	}
+	return createMessageResponse(true, "group deleted")
+}
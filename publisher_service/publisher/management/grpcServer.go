@@ -0,0 +1,429 @@
+package management
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"bezberr.com/messagebrokerapi/brokerpb"
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/auth"
+	"bezberr.com/messagebrokerstorage/limits"
+	"github.com/gorilla/sessions"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+//gRPC version of the REST routes, each call is passed through to the matching route so the two always behave the same
+type managementServer struct {
+	brokerpb.UnimplementedManagementServer
+	server *Server
+}
+
+//client from the client-id and client-secret metadata sent with the call, nil unless they're a registered
+//client's credentials
+func metadataAuth(ctx context.Context, store storage.Store) *storage.Client {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md.Get("client-id")) == 0 || len(md.Get("client-secret")) == 0 {
+		return nil
+	}
+	client, err := authenticateClient(md.Get("client-id")[0], md.Get("client-secret")[0], store)
+	if err != nil {
+		return nil
+	}
+	return client
+}
+
+//status of a call refused by a rate limit, the seconds to wait are sent in the retry-after header
+func rateLimitStatus(ctx context.Context, retryAfter int, message string) error {
+	grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(retryAfter)))
+	return status.Error(codes.ResourceExhausted, message)
+}
+
+//call the route matching the method and path with the request as the JSON body, the JSON response is decoded into
+//response. the request is encoded with encoding/json as the routes take int64 fields as numbers, which protojson
+//writes as strings, the response with protojson so it's read the way protobuf defines
+func (server *managementServer) callRoute(ctx context.Context, method string, path string, request interface{}, response proto.Message) error {
+	routePath, _, _ := strings.Cut(path, "?")
+	route, found := server.server.matchRoute(routePath, method)
+	if !found {
+		return status.Error(codes.Unimplemented, "route not found")
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, method, path, bytes.NewReader(body))
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	//there are no cookies so a new session is used for each call, with the client from the metadata
+	session := sessions.NewSession(server.server.sessionStore, "session")
+	rd := routeData{
+		Request:       httpRequest,
+		Store:         server.server.store,
+		Tokens:        server.server.tokens,
+		Limiter:       server.server.limiter,
+		Quotas:        server.server.quotas,
+		Payloads:      server.server.payloadLimits,
+		Session:       session,
+		DynamicParams: route.GetDynamicParams(routePath),
+	}
+	id, authed := "", false
+	client := metadataAuth(ctx, server.server.store)
+	if client != nil {
+		id, authed = client.ID, true
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if route.Admin && (len(md.Get("admin-secret")) == 0 || !server.server.checkAdminSecret(md.Get("admin-secret")[0])) {
+		return status.Error(codes.PermissionDenied, "Forbidden >:(")
+	}
+	var keyErr error
+	if len(md.Get("api-key")) > 0 {
+		id, keyErr = server.server.authenticateKey(route, md.Get("api-key")[0], rd.DynamicParams)
+		authed = keyErr == nil
+	} else if len(md.Get("authorization")) > 0 {
+		id, authed = server.server.authenticateToken(auth.BearerToken(md.Get("authorization")[0]))
+	} else if authed {
+		startSession(session, client)
+	}
+	if route.Authenticate {
+		if errors.Is(keyErr, errKeyScope) {
+			return status.Error(codes.PermissionDenied, keyErr.Error())
+		}
+		if !authed {
+			return status.Error(codes.Unauthenticated, "Forbidden >:(")
+		}
+		rd.AuthID = id
+	}
+	if limited := server.server.checkRequestRate(route, rd.AuthID); limited != nil {
+		return rateLimitStatus(ctx, limited.RetryAfterSeconds(), limited.Error())
+	}
+
+	channel := createResponseChannel()
+	go route.Func(rd, channel)
+	reply := <-channel
+	responseBody := reply.Body
+
+	result := messageResponse{}
+	err = json.Unmarshal(responseBody, &result)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	limited := &limits.RateLimitError{}
+	if errors.As(reply.Err, &limited) {
+		return rateLimitStatus(ctx, limited.RetryAfterSeconds(), result.Message)
+	}
+	switch refusalStatus(reply.Err) {
+	case http.StatusInsufficientStorage:
+		return status.Error(codes.ResourceExhausted, result.Message)
+	case http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity:
+		return status.Error(codes.InvalidArgument, result.Message)
+	}
+	switch result.Reason {
+	case refusalNotFound:
+		return status.Error(codes.NotFound, result.Message)
+	case refusalUnauthenticated:
+		return status.Error(codes.Unauthenticated, result.Message)
+	case refusalPermissionDenied:
+		return status.Error(codes.PermissionDenied, result.Message)
+	}
+	if !result.Success {
+		return status.Error(codes.FailedPrecondition, result.Message)
+	}
+	err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(responseBody, response)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+func (server *managementServer) Register(ctx context.Context, request *brokerpb.RegisterRequest) (*brokerpb.RegisterResponse, error) {
+	response := &brokerpb.RegisterResponse{}
+	return response, server.callRoute(ctx, "POST", "/register", request, response)
+}
+
+func (server *managementServer) Authenticate(ctx context.Context, request *brokerpb.AuthenticateRequest) (*brokerpb.AuthenticateResponse, error) {
+	response := &brokerpb.AuthenticateResponse{}
+	return response, server.callRoute(ctx, "POST", "/auth", request, response)
+}
+
+func (server *managementServer) GetAuthenticatedClient(ctx context.Context, request *brokerpb.GetAuthenticatedClientRequest) (*brokerpb.AuthenticateResponse, error) {
+	response := &brokerpb.AuthenticateResponse{}
+	return response, server.callRoute(ctx, "GET", "/auth", request, response)
+}
+
+func (server *managementServer) RotateSecret(ctx context.Context, request *brokerpb.RotateSecretRequest) (*brokerpb.RotateSecretResponse, error) {
+	response := &brokerpb.RotateSecretResponse{}
+	return response, server.callRoute(ctx, "POST", "/auth/secret", request, response)
+}
+
+func (server *managementServer) ResetClientSecret(ctx context.Context, request *brokerpb.ResetClientSecretRequest) (*brokerpb.RotateSecretResponse, error) {
+	response := &brokerpb.RotateSecretResponse{}
+	return response, server.callRoute(ctx, "POST", "/admin/clients/"+request.ClientId+"/secret", request, response)
+}
+
+func (server *managementServer) IssueToken(ctx context.Context, request *brokerpb.AuthenticateRequest) (*brokerpb.TokenResponse, error) {
+	response := &brokerpb.TokenResponse{}
+	return response, server.callRoute(ctx, "POST", "/auth/token", request, response)
+}
+
+func (server *managementServer) RefreshToken(ctx context.Context, request *brokerpb.RefreshTokenRequest) (*brokerpb.TokenResponse, error) {
+	response := &brokerpb.TokenResponse{}
+	return response, server.callRoute(ctx, "POST", "/auth/token/refresh", request, response)
+}
+
+func (server *managementServer) CreateOrganization(ctx context.Context, request *brokerpb.CreateOrganizationRequest) (*brokerpb.CreateOrganizationResponse, error) {
+	response := &brokerpb.CreateOrganizationResponse{}
+	return response, server.callRoute(ctx, "POST", "/organizations", request, response)
+}
+
+func (server *managementServer) GetOrganization(ctx context.Context, request *brokerpb.GetOrganizationRequest) (*brokerpb.OrganizationResponse, error) {
+	response := &brokerpb.OrganizationResponse{}
+	return response, server.callRoute(ctx, "GET", "/organization", request, response)
+}
+
+func (server *managementServer) AddOrganizationMember(ctx context.Context, request *brokerpb.AddOrganizationMemberRequest) (*brokerpb.RegisterResponse, error) {
+	response := &brokerpb.RegisterResponse{}
+	return response, server.callRoute(ctx, "POST", "/organization/members", request, response)
+}
+
+func (server *managementServer) AddOrganizationAdmin(ctx context.Context, request *brokerpb.OrganizationAdminRequest) (*brokerpb.OrganizationResponse, error) {
+	response := &brokerpb.OrganizationResponse{}
+	return response, server.callRoute(ctx, "POST", "/organization/admins", request, response)
+}
+
+func (server *managementServer) RemoveOrganizationAdmin(ctx context.Context, request *brokerpb.OrganizationAdminRequest) (*brokerpb.OrganizationResponse, error) {
+	response := &brokerpb.OrganizationResponse{}
+	return response, server.callRoute(ctx, "DELETE", "/organization/admins/"+request.ClientId, request, response)
+}
+
+func (server *managementServer) ListAPIKeys(ctx context.Context, request *brokerpb.ListAPIKeysRequest) (*brokerpb.ListAPIKeysResponse, error) {
+	response := &brokerpb.ListAPIKeysResponse{}
+	return response, server.callRoute(ctx, "GET", "/api-keys", request, response)
+}
+
+func (server *managementServer) CreateAPIKey(ctx context.Context, request *brokerpb.CreateAPIKeyRequest) (*brokerpb.CreateAPIKeyResponse, error) {
+	response := &brokerpb.CreateAPIKeyResponse{}
+	return response, server.callRoute(ctx, "POST", "/api-keys", request, response)
+}
+
+func (server *managementServer) RevokeAPIKey(ctx context.Context, request *brokerpb.RevokeAPIKeyRequest) (*brokerpb.MessageResponse, error) {
+	response := &brokerpb.MessageResponse{}
+	return response, server.callRoute(ctx, "DELETE", "/api-keys/"+request.KeyId, request, response)
+}
+
+func (server *managementServer) ListPublishers(ctx context.Context, request *brokerpb.ListPublishersRequest) (*brokerpb.ListPublishersResponse, error) {
+	response := &brokerpb.ListPublishersResponse{}
+	path := "/publishers"
+	if request.Organization {
+		path += "?organization=true"
+	}
+	return response, server.callRoute(ctx, "GET", path, request, response)
+}
+
+func (server *managementServer) CreatePublisher(ctx context.Context, request *brokerpb.CreatePublisherRequest) (*brokerpb.CreatePublisherResponse, error) {
+	response := &brokerpb.CreatePublisherResponse{}
+	return response, server.callRoute(ctx, "POST", "/publishers", request, response)
+}
+
+func (server *managementServer) DeletePublisher(ctx context.Context, request *brokerpb.DeletePublisherRequest) (*brokerpb.MessageResponse, error) {
+	response := &brokerpb.MessageResponse{}
+	return response, server.callRoute(ctx, "DELETE", "/publishers/"+request.PublisherId, request, response)
+}
+
+func (server *managementServer) ListPublisherSubscribers(ctx context.Context, request *brokerpb.ListPublisherSubscribersRequest) (*brokerpb.ListPublisherSubscribersResponse, error) {
+	response := &brokerpb.ListPublisherSubscribersResponse{}
+	return response, server.callRoute(ctx, "GET", "/publishers/"+request.PublisherId+"/subscribers", request, response)
+}
+
+func (server *managementServer) RemoveSubscriber(ctx context.Context, request *brokerpb.RemoveSubscriberRequest) (*brokerpb.MessageResponse, error) {
+	response := &brokerpb.MessageResponse{}
+	return response, server.callRoute(ctx, "DELETE", "/publishers/"+request.PublisherId+"/subscribers/"+request.ClientId, request, response)
+}
+
+//filter requests by their status, passed on in the query
+func statusQuery(status string) string {
+	if status == "" {
+		return ""
+	}
+	return "?status=" + url.QueryEscape(status)
+}
+
+func (server *managementServer) ListPublisherSubscriptionRequests(ctx context.Context, request *brokerpb.ListPublisherSubscriptionRequestsRequest) (*brokerpb.ListSubscriptionRequestsResponse, error) {
+	response := &brokerpb.ListSubscriptionRequestsResponse{}
+	return response, server.callRoute(ctx, "GET", "/publishers/"+request.PublisherId+"/subscription-requests"+statusQuery(request.Status), request, response)
+}
+
+func (server *managementServer) GetPublisherPayload(ctx context.Context, request *brokerpb.GetPublisherPayloadRequest) (*brokerpb.PublisherPayloadResponse, error) {
+	response := &brokerpb.PublisherPayloadResponse{}
+	return response, server.callRoute(ctx, "GET", "/publishers/"+request.PublisherId+"/payload", request, response)
+}
+
+func (server *managementServer) SetPublisherPayload(ctx context.Context, request *brokerpb.SetPublisherPayloadRequest) (*brokerpb.PublisherPayloadResponse, error) {
+	response := &brokerpb.PublisherPayloadResponse{}
+	return response, server.callRoute(ctx, "PUT", "/publishers/"+request.PublisherId+"/payload", request, response)
+}
+
+func (server *managementServer) ListSchemas(ctx context.Context, request *brokerpb.ListSchemasRequest) (*brokerpb.ListSchemasResponse, error) {
+	response := &brokerpb.ListSchemasResponse{}
+	return response, server.callRoute(ctx, "GET", "/publishers/"+request.PublisherId+"/schemas", request, response)
+}
+
+func (server *managementServer) RegisterSchema(ctx context.Context, request *brokerpb.RegisterSchemaRequest) (*brokerpb.SchemaResponse, error) {
+	response := &brokerpb.SchemaResponse{}
+	return response, server.callRoute(ctx, "POST", "/publishers/"+request.PublisherId+"/schemas", request, response)
+}
+
+func (server *managementServer) GetSchema(ctx context.Context, request *brokerpb.GetSchemaRequest) (*brokerpb.SchemaResponse, error) {
+	version := "latest"
+	if request.Version != 0 {
+		version = strconv.Itoa(int(request.Version))
+	}
+	response := &brokerpb.SchemaResponse{}
+	return response, server.callRoute(ctx, "GET", "/publishers/"+request.PublisherId+"/schemas/"+version, request, response)
+}
+
+func (server *managementServer) GetPublisherACL(ctx context.Context, request *brokerpb.GetPublisherACLRequest) (*brokerpb.PublisherACLResponse, error) {
+	response := &brokerpb.PublisherACLResponse{}
+	return response, server.callRoute(ctx, "GET", "/publishers/"+request.PublisherId+"/acl", request, response)
+}
+
+func (server *managementServer) SetPublisherACL(ctx context.Context, request *brokerpb.SetPublisherACLRequest) (*brokerpb.PublisherACLResponse, error) {
+	response := &brokerpb.PublisherACLResponse{}
+	return response, server.callRoute(ctx, "PUT", "/publishers/"+request.PublisherId+"/acl", request, response)
+}
+
+func (server *managementServer) AllowClient(ctx context.Context, request *brokerpb.AllowClientRequest) (*brokerpb.PublisherACLResponse, error) {
+	response := &brokerpb.PublisherACLResponse{}
+	return response, server.callRoute(ctx, "POST", "/publishers/"+request.PublisherId+"/acl/clients", request, response)
+}
+
+func (server *managementServer) DisallowClient(ctx context.Context, request *brokerpb.AllowClientRequest) (*brokerpb.PublisherACLResponse, error) {
+	response := &brokerpb.PublisherACLResponse{}
+	return response, server.callRoute(ctx, "DELETE", "/publishers/"+request.PublisherId+"/acl/clients/"+request.ClientId, request, response)
+}
+
+func (server *managementServer) AllowGroup(ctx context.Context, request *brokerpb.AllowGroupRequest) (*brokerpb.PublisherACLResponse, error) {
+	response := &brokerpb.PublisherACLResponse{}
+	return response, server.callRoute(ctx, "POST", "/publishers/"+request.PublisherId+"/acl/groups", request, response)
+}
+
+func (server *managementServer) DisallowGroup(ctx context.Context, request *brokerpb.AllowGroupRequest) (*brokerpb.PublisherACLResponse, error) {
+	response := &brokerpb.PublisherACLResponse{}
+	return response, server.callRoute(ctx, "DELETE", "/publishers/"+request.PublisherId+"/acl/groups/"+request.GroupId, request, response)
+}
+
+func (server *managementServer) ListGroups(ctx context.Context, request *brokerpb.ListGroupsRequest) (*brokerpb.ListGroupsResponse, error) {
+	response := &brokerpb.ListGroupsResponse{}
+	return response, server.callRoute(ctx, "GET", "/groups", request, response)
+}
+
+func (server *managementServer) CreateGroup(ctx context.Context, request *brokerpb.CreateGroupRequest) (*brokerpb.GroupResponse, error) {
+	response := &brokerpb.GroupResponse{}
+	return response, server.callRoute(ctx, "POST", "/groups", request, response)
+}
+
+func (server *managementServer) SetGroupMembers(ctx context.Context, request *brokerpb.SetGroupMembersRequest) (*brokerpb.GroupResponse, error) {
+	response := &brokerpb.GroupResponse{}
+	return response, server.callRoute(ctx, "PUT", "/groups/"+request.GroupId+"/members", request, response)
+}
+
+func (server *managementServer) DeleteGroup(ctx context.Context, request *brokerpb.DeleteGroupRequest) (*brokerpb.MessageResponse, error) {
+	response := &brokerpb.MessageResponse{}
+	return response, server.callRoute(ctx, "DELETE", "/groups/"+request.GroupId, request, response)
+}
+
+func (server *managementServer) PublishMessage(ctx context.Context, request *brokerpb.PublishMessageRequest) (*brokerpb.MessageResponse, error) {
+	response := &brokerpb.MessageResponse{}
+	return response, server.callRoute(ctx, "POST", "/publishers/"+request.PublisherId+"/messages", request, response)
+}
+
+func (server *managementServer) ListSubscriptions(ctx context.Context, request *brokerpb.ListSubscriptionsRequest) (*brokerpb.ListSubscriptionsResponse, error) {
+	response := &brokerpb.ListSubscriptionsResponse{}
+	return response, server.callRoute(ctx, "GET", "/subscriptions", request, response)
+}
+
+func (server *managementServer) Subscribe(ctx context.Context, request *brokerpb.SubscribeRequest) (*brokerpb.MessageResponse, error) {
+	response := &brokerpb.MessageResponse{}
+	return response, server.callRoute(ctx, "POST", "/subscriptions", request, response)
+}
+
+func (server *managementServer) Unsubscribe(ctx context.Context, request *brokerpb.UnsubscribeRequest) (*brokerpb.MessageResponse, error) {
+	response := &brokerpb.MessageResponse{}
+	return response, server.callRoute(ctx, "DELETE", "/subscriptions/"+request.SubscriptionId, request, response)
+}
+
+func newGRPCServer(server *Server) *grpc.Server {
+	//messages over the limit are refused before they're decoded, as request bodies are over REST
+	grpcServer := grpc.NewServer(grpc.MaxRecvMsgSize(server.payloadLimits.MaxRequestBytes))
+	brokerpb.RegisterManagementServer(grpcServer, &managementServer{
+		server: server,
+	})
+	return grpcServer
+}
+
+func (server *managementServer) ListSubscriptionRequests(ctx context.Context, request *brokerpb.ListSubscriptionRequestsRequest) (*brokerpb.ListSubscriptionRequestsResponse, error) {
+	response := &brokerpb.ListSubscriptionRequestsResponse{}
+	return response, server.callRoute(ctx, "GET", "/subscription-requests"+statusQuery(request.Status), request, response)
+}
+
+func (server *managementServer) CreateSubscriptionRequest(ctx context.Context, request *brokerpb.CreateSubscriptionRequestRequest) (*brokerpb.SubscriptionRequestResponse, error) {
+	response := &brokerpb.SubscriptionRequestResponse{}
+	return response, server.callRoute(ctx, "POST", "/subscription-requests", request, response)
+}
+
+func (server *managementServer) ListIncomingSubscriptionRequests(ctx context.Context, request *brokerpb.ListSubscriptionRequestsRequest) (*brokerpb.ListSubscriptionRequestsResponse, error) {
+	response := &brokerpb.ListSubscriptionRequestsResponse{}
+	return response, server.callRoute(ctx, "GET", "/subscription-requests/incoming"+statusQuery(request.Status), request, response)
+}
+
+func (server *managementServer) ApproveSubscriptionRequest(ctx context.Context, request *brokerpb.DecideSubscriptionRequestRequest) (*brokerpb.SubscriptionRequestResponse, error) {
+	response := &brokerpb.SubscriptionRequestResponse{}
+	return response, server.callRoute(ctx, "POST", "/subscription-requests/"+request.RequestId+"/approve", request, response)
+}
+
+func (server *managementServer) DenySubscriptionRequest(ctx context.Context, request *brokerpb.DecideSubscriptionRequestRequest) (*brokerpb.SubscriptionRequestResponse, error) {
+	response := &brokerpb.SubscriptionRequestResponse{}
+	return response, server.callRoute(ctx, "POST", "/subscription-requests/"+request.RequestId+"/deny", request, response)
+}
+
+func (server *managementServer) GetRateLimits(ctx context.Context, request *brokerpb.GetRateLimitsRequest) (*brokerpb.RateLimitsResponse, error) {
+	response := &brokerpb.RateLimitsResponse{}
+	return response, server.callRoute(ctx, "GET", "/rate-limits", request, response)
+}
+
+func (server *managementServer) GetClientRateLimits(ctx context.Context, request *brokerpb.ClientRateLimitsRequest) (*brokerpb.RateLimitsResponse, error) {
+	response := &brokerpb.RateLimitsResponse{}
+	return response, server.callRoute(ctx, "GET", "/admin/clients/"+request.ClientId+"/rate-limits", request, response)
+}
+
+func (server *managementServer) SetClientRateLimits(ctx context.Context, request *brokerpb.SetClientRateLimitsRequest) (*brokerpb.RateLimitsResponse, error) {
+	response := &brokerpb.RateLimitsResponse{}
+	return response, server.callRoute(ctx, "PUT", "/admin/clients/"+request.ClientId+"/rate-limits", request, response)
+}
+
+func (server *managementServer) ResetClientRateLimits(ctx context.Context, request *brokerpb.ClientRateLimitsRequest) (*brokerpb.RateLimitsResponse, error) {
+	response := &brokerpb.RateLimitsResponse{}
+	return response, server.callRoute(ctx, "DELETE", "/admin/clients/"+request.ClientId+"/rate-limits", request, response)
+}
+
+func (server *managementServer) GetUsage(ctx context.Context, request *brokerpb.GetUsageRequest) (*brokerpb.UsageResponse, error) {
+	response := &brokerpb.UsageResponse{}
+	return response, server.callRoute(ctx, "GET", "/usage", request, response)
+}
+
+func (server *managementServer) GetPublisherUsage(ctx context.Context, request *brokerpb.GetPublisherUsageRequest) (*brokerpb.PublisherUsageResponse, error) {
+	response := &brokerpb.PublisherUsageResponse{}
+	return response, server.callRoute(ctx, "GET", "/publishers/"+request.PublisherId+"/usage", request, response)
+}
@@ -0,0 +1,18 @@
+package management
+
+import "bezberr.com/messagebrokerstorage/auth"
+
+func messageRoutes() []route {
+	return []route{
+		{
+			RoutePattern: "/publishers/{publication_id}/messages",
+			Authenticate: true,
+			KeyScope:     auth.ScopePublishPrefix + "{publication_id}",
+			Method:       "POST",
+			Func: func(rd routeData, c chan routeResponse) {
+				response, err := handlePublishMessage(rd.Request.Body, rd.Store, rd.Limiter, rd.Quotas, rd.Payloads, rd.AuthID, rd.DynamicParams["publication_id"])
+				c <- routeResponse{Body: response, Err: err}
+			},
+		},
+	}
+}
@@ -0,0 +1,94 @@
+package management
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/limits"
+	"bezberr.com/messagebrokerstorage/schema"
+	"github.com/google/uuid"
+)
+
+type publishMessageRequest struct {
+	Ttl     int64  `json:"ttl"`     //time to live in seconds
+	Payload string `json:"payload"` //payload of the message
+}
+
+//publish a message to one of the client's publishers, returning the error a payload, rate limit or quota refused it
+//with along with the response
+func handlePublishMessage(body io.ReadCloser, store storage.Store, limiter *limits.RateLimiter, quotas limits.Quotas, payloadLimits limits.PayloadLimits, authId string, pubId string) ([]byte, error) {
+
+	failedMessage := "failed to publish message"
+
+	bytes, err := readBody(body)
+	if err != nil {
+		return createMessageResponse(false, failedMessage), nil
+	}
+
+	requestData := publishMessageRequest{}
+
+	err = json.Unmarshal(bytes, &requestData)
+
+	if err != nil {
+		return createMessageResponse(false, failedMessage), nil
+	}
+
+	publisher, err := findOwnedPublisher(pubId, authId, store)
+
+	if err != nil {
+		return createMessageResponse(false, failedMessage), nil
+	}
+
+	if publisher == nil {
+		return createRefusalResponse(refusalNotFound, "publisher not found"), nil
+	}
+
+	err = limits.CheckPayload(publisher.Payload, requestData.Payload, payloadLimits.MaxPayloadBytes)
+	if rejected, ok := err.(*limits.PayloadError); ok {
+		return createPayloadResponse(rejected), rejected
+	}
+	schemaVersion, err := schema.CheckSchema(store, pubId, requestData.Payload)
+	if rejected, ok := err.(*limits.PayloadError); ok {
+		return createPayloadResponse(rejected), rejected
+	}
+	if err != nil {
+		return createMessageResponse(false, failedMessage), nil
+	}
+
+	client, err := store.FindClient(authId)
+	if err != nil {
+		return createMessageResponse(false, failedMessage), nil
+	}
+	err = limiter.AllowPublish(authId, client.RateLimits, pubId)
+	if limited, ok := err.(*limits.RateLimitError); ok {
+		return createRateLimitResponse(limited), limited
+	}
+	err = quotas.CheckQuotas(store, publisher, len(requestData.Payload))
+	if exceeded, ok := err.(*limits.QuotaError); ok {
+		return createQuotaResponse(exceeded), exceeded
+	}
+	if err != nil {
+		return createMessageResponse(false, failedMessage), nil
+	}
+
+	message := storage.Message{
+		ID:            uuid.New().String(),
+		PublisherID:   pubId,
+		Payload:       requestData.Payload,
+		CreatedAt:     time.Now(),
+		SchemaVersion: schemaVersion,
+	}
+	if requestData.Ttl > 0 {
+		message.ExpiresAt = message.CreatedAt.Add(time.Duration(requestData.Ttl) * time.Second)
+	}
+
+	err = store.InsertMessage(message)
+
+	if err != nil {
+		return createMessageResponse(false, failedMessage), nil
+	}
+
+	return createMessageResponse(true, "message published"), nil
+}
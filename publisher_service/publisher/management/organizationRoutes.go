@@ -0,0 +1,53 @@
+package management
+
+import "bezberr.com/messagebrokerstorage/auth"
+
+//organizations keep their members and publishers apart from other tenants'
+func organizationRoutes() []route {
+	return []route{
+		{
+			RoutePattern: "/organizations",
+			Method:       "POST",
+			Authenticate: false,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleCreateOrganization(rd.Request.Body, rd.Store, rd.Session)}
+			},
+		},
+		{
+			RoutePattern: "/organization",
+			Method:       "GET",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleGetOrganization(rd.AuthID, rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/organization/members",
+			Method:       "POST",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleAddMember(rd.Request.Body, rd.AuthID, rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/organization/admins",
+			Method:       "POST",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleAddAdmin(rd.Request.Body, rd.AuthID, rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/organization/admins/{client_id}",
+			Method:       "DELETE",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleRemoveAdmin(rd.DynamicParams["client_id"], rd.AuthID, rd.Store)}
+			},
+		},
+	}
+}
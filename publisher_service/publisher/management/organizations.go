@@ -0,0 +1,265 @@
+package management
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"github.com/gorilla/sessions"
+)
+
+type createOrganizationRequest struct {
+	Name      string `json:"name"`
+	AdminName string `json:"admin_name"` //name of the first admin, registered as a member of the organization
+}
+
+type addMemberRequest struct {
+	Name string `json:"name"`
+}
+
+type adminRequest struct {
+	ClientID string `json:"client_id"`
+}
+
+type jsonOrganization struct {
+	Id     string   `json:"id"`
+	Name   string   `json:"name"`
+	Admins []string `json:"admins"` //ids of the members who manage the organization and its publishers
+}
+
+type jsonMember struct {
+	Id    string `json:"id"`
+	Name  string `json:"name"`
+	Admin bool   `json:"admin"`
+}
+
+type organizationResult struct {
+	Success      bool             `json:"success"`
+	Organization jsonOrganization `json:"organization"`
+	Members      []jsonMember     `json:"members,omitempty"`
+}
+
+type createOrganizationResult struct {
+	Success      bool             `json:"success"`
+	Organization jsonOrganization `json:"organization"`
+	Row          registerResponse `json:"row"` //credentials of the first admin
+}
+
+func newJSONOrganization(organization storage.Organization) jsonOrganization {
+	return jsonOrganization{Id: organization.ID, Name: organization.Name, Admins: append([]string{}, organization.Admins...)}
+}
+
+func createOrganizationResponse(organization storage.Organization, members []jsonMember, failedMessage string) []byte {
+	response, err := json.Marshal(organizationResult{
+		Success:      true,
+		Organization: newJSONOrganization(organization),
+		Members:      members,
+	})
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	return response
+}
+
+//organization the client is a member of, nil if it isn't in one
+func findClientOrganization(clientId string, store storage.Store) (*storage.Organization, error) {
+	client, err := store.FindClient(clientId)
+	if err != nil {
+		return nil, err
+	}
+	if client.OrganizationID == "" {
+		return nil, nil
+	}
+	return store.FindOrganization(client.OrganizationID)
+}
+
+//organization the client is an admin of, nil if it isn't one
+func findAdministeredOrganization(clientId string, store storage.Store) (*storage.Organization, error) {
+	organization, err := findClientOrganization(clientId, store)
+	if err != nil || organization == nil || !organization.IsAdmin(clientId) {
+		return nil, err
+	}
+	return organization, nil
+}
+
+//find a publisher if the client can see it, nil if it can't so other tenants' publishers look like they don't exist
+func findVisiblePublisher(pubId string, clientId string, store storage.Store) (*storage.Publisher, *storage.Client, error) {
+	client, err := store.FindClient(clientId)
+	if err != nil {
+		return nil, nil, err
+	}
+	publisher, err := store.FindPublisher(pubId)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, client, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if !publisher.VisibleTo(client) {
+		return nil, client, nil
+	}
+	return publisher, client, nil
+}
+
+//create an organization along with its first admin, who is logged in
+func handleCreateOrganization(body io.ReadCloser, store storage.Store, session *sessions.Session) []byte {
+	failedMessage := "create organization failed"
+	bytes, err := readBody(body)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	request := createOrganizationRequest{}
+	err = json.Unmarshal(bytes, &request)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	if request.Name == "" || request.AdminName == "" {
+		return createMessageResponse(false, "name and admin_name required")
+	}
+	organization, err := store.CreateOrganization(request.Name)
+	if errors.Is(err, storage.ErrConflict) {
+		return createMessageResponse(false, "organization already exists")
+	}
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	admin, secret, err := registerClient(organization.ID, request.AdminName, store)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	organization.Admins = []string{admin.ID}
+	err = store.SetOrganizationAdmins(organization.ID, organization.Admins)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	response, err := json.Marshal(createOrganizationResult{
+		Success:      true,
+		Organization: newJSONOrganization(*organization),
+		Row:          registerResponse{Id: admin.ID, Secret: secret},
+	})
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	startSession(session, admin)
+	return response
+}
+
+//the client's organization along with its members
+func handleGetOrganization(clientId string, store storage.Store) []byte {
+	failedMessage := "failed fetching organization"
+	organization, err := findClientOrganization(clientId, store)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	if organization == nil {
+		return createMessageResponse(false, "not in an organization")
+	}
+	clients, err := store.ListMembers(organization.ID)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	members := []jsonMember{}
+	for _, client := range clients {
+		members = append(members, jsonMember{Id: client.ID, Name: client.Name, Admin: organization.IsAdmin(client.ID)})
+	}
+	return createOrganizationResponse(*organization, members, failedMessage)
+}
+
+//register a new member of the admin's organization, returning its credentials
+func handleAddMember(body io.ReadCloser, clientId string, store storage.Store) []byte {
+	failedMessage := "add member failed"
+	bytes, err := readBody(body)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	request := addMemberRequest{}
+	err = json.Unmarshal(bytes, &request)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	if request.Name == "" {
+		return createMessageResponse(false, "name required")
+	}
+	organization, err := findAdministeredOrganization(clientId, store)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	if organization == nil {
+		return createRefusalResponse(refusalNotFound, "organization not found")
+	}
+	member, secret, err := registerClient(organization.ID, request.Name, store)
+	if errors.Is(err, storage.ErrConflict) {
+		return createMessageResponse(false, "name already taken in the organization")
+	}
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	response, err := json.Marshal(registerSuccessResponse{
+		Success: true,
+		Row:     registerResponse{Id: member.ID, Secret: secret},
+	})
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	return response
+}
+
+//apply a change to the admins of the client's organization, if the client is one of them
+func updateAdmins(clientId string, store storage.Store, update func(organization *storage.Organization) error) []byte {
+	failedMessage := "update admins failed"
+	organization, err := findAdministeredOrganization(clientId, store)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	if organization == nil {
+		return createRefusalResponse(refusalNotFound, "organization not found")
+	}
+	err = update(organization)
+	if err != nil {
+		return createMessageResponse(false, err.Error())
+	}
+	err = store.SetOrganizationAdmins(organization.ID, organization.Admins)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	return createOrganizationResponse(*organization, nil, failedMessage)
+}
+
+func handleAddAdmin(body io.ReadCloser, clientId string, store storage.Store) []byte {
+	bytes, err := readBody(body)
+	if err != nil {
+		return createMessageResponse(false, "update admins failed")
+	}
+	request := adminRequest{}
+	err = json.Unmarshal(bytes, &request)
+	if err != nil {
+		return createMessageResponse(false, "update admins failed")
+	}
+	return updateAdmins(clientId, store, func(organization *storage.Organization) error {
+		member, err := store.FindClient(request.ClientID)
+		if errors.Is(err, storage.ErrNotFound) || (err == nil && member.OrganizationID != organization.ID) {
+			return errors.New("member not found")
+		}
+		if err != nil {
+			return err
+		}
+		organization.Admins = uniqueIDs(append(organization.Admins, member.ID))
+		return nil
+	})
+}
+
+//an organization always keeps at least one admin
+func handleRemoveAdmin(adminId string, clientId string, store storage.Store) []byte {
+	return updateAdmins(clientId, store, func(organization *storage.Organization) error {
+		remaining, removed := withoutID(organization.Admins, adminId)
+		if !removed {
+			return errors.New("admin not found")
+		}
+		if len(remaining) == 0 {
+			return errors.New("an organization needs at least one admin")
+		}
+		organization.Admins = remaining
+		return nil
+	})
+}
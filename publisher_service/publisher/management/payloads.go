@@ -0,0 +1,92 @@
+package management
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/limits"
+)
+
+type jsonPayloadPolicy struct {
+	MaxBytes    int    `json:"max_bytes"`    //0 uses the service's limit
+	ContentType string `json:"content_type"` //empty accepts anything
+}
+
+type payloadPolicyResult struct {
+	Success         bool              `json:"success"`
+	Payload         jsonPayloadPolicy `json:"payload"`
+	MaxPayloadBytes int               `json:"max_payload_bytes"` //limit payloads to the publisher are held to
+}
+
+func createPayloadPolicyResponse(policy storage.PayloadPolicy, payloadLimits limits.PayloadLimits, failedMessage string) []byte {
+	response, err := json.Marshal(payloadPolicyResult{
+		Success:         true,
+		Payload:         jsonPayloadPolicy(policy),
+		MaxPayloadBytes: limits.PolicyLimit(policy, payloadLimits.MaxPayloadBytes),
+	})
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	return response
+}
+
+//response to a payload the publisher or service refused, sent with 413 Request Entity Too Large if it was too big
+//or 422 Unprocessable Entity if it didn't match the publisher's schema
+func createPayloadResponse(rejected *limits.PayloadError) []byte {
+	res, _ := json.Marshal(messageResponse{
+		Success:       false,
+		Message:       rejected.Error(),
+		PayloadLimit:  rejected.Limit,
+		SchemaVersion: rejected.SchemaVersion,
+	})
+	return res
+}
+
+//response to a request body over the service's limit
+func createBodyTooLargeResponse(limit int) []byte {
+	return createMessageResponse(false, fmt.Sprintf("request body is over the limit of %d bytes", limit))
+}
+
+func handleGetPublisherPayload(pubId string, ownerId string, store storage.Store, payloadLimits limits.PayloadLimits) []byte {
+	failedMessage := "get publisher payload policy failed"
+	publisher, err := findOwnedPublisher(pubId, ownerId, store)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	if publisher == nil {
+		return createRefusalResponse(refusalNotFound, "publisher not found")
+	}
+	return createPayloadPolicyResponse(publisher.Payload, payloadLimits, failedMessage)
+}
+
+func handleSetPublisherPayload(pubId string, body io.ReadCloser, ownerId string, store storage.Store, payloadLimits limits.PayloadLimits) []byte {
+	failedMessage := "update publisher payload policy failed"
+	bytes, err := readBody(body)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	request := jsonPayloadPolicy{}
+	err = json.Unmarshal(bytes, &request)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	publisher, err := findOwnedPublisher(pubId, ownerId, store)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	if publisher == nil {
+		return createRefusalResponse(refusalNotFound, "publisher not found")
+	}
+	policy := storage.PayloadPolicy(request)
+	err = limits.ValidatePolicy(policy, payloadLimits.MaxPayloadBytes)
+	if err != nil {
+		return createMessageResponse(false, err.Error())
+	}
+	err = store.SetPublisherPayload(pubId, policy)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	return createPayloadPolicyResponse(policy, payloadLimits, failedMessage)
+}
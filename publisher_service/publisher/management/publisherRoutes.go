@@ -0,0 +1,161 @@
+package management
+
+import "bezberr.com/messagebrokerstorage/auth"
+
+func publicationRoutes() []route {
+	return []route{
+		{
+			RoutePattern: "/publishers",
+			Method:       "GET",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleGetPublications(rd.Store, rd.AuthID, rd.Request.URL.Query())}
+			},
+		},
+		{
+			RoutePattern: "/publishers",
+			Method:       "POST",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleCreatePublisher(rd.Request.Body, rd.AuthID, rd.Store, rd.Payloads)}
+			},
+		},
+		{
+			RoutePattern: "/publishers/{publisher_id}",
+			Method:       "DELETE",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleDeletePublisher(rd.DynamicParams["publisher_id"], rd.AuthID, rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/publishers/{publisher_id}/subscribers",
+			Method:       "GET",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleGetPublisherSubscribers(rd.DynamicParams["publisher_id"], rd.AuthID, rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/publishers/{publisher_id}/subscribers/{client_id}",
+			Method:       "DELETE",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleRemoveSubscriber(rd.DynamicParams["publisher_id"], rd.DynamicParams["client_id"], rd.AuthID, rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/publishers/{publisher_id}/subscription-requests",
+			Method:       "GET",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleGetPublisherSubscriptionRequests(rd.DynamicParams["publisher_id"], rd.AuthID, rd.Request.URL.Query(), rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/publishers/{publisher_id}/payload",
+			Method:       "GET",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleGetPublisherPayload(rd.DynamicParams["publisher_id"], rd.AuthID, rd.Store, rd.Payloads)}
+			},
+		},
+		{
+			RoutePattern: "/publishers/{publisher_id}/payload",
+			Method:       "PUT",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleSetPublisherPayload(rd.DynamicParams["publisher_id"], rd.Request.Body, rd.AuthID, rd.Store, rd.Payloads)}
+			},
+		},
+		{
+			RoutePattern: "/publishers/{publisher_id}/schemas",
+			Method:       "GET",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleGetPublisherSchemas(rd.DynamicParams["publisher_id"], rd.AuthID, rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/publishers/{publisher_id}/schemas",
+			Method:       "POST",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleRegisterPublisherSchema(rd.DynamicParams["publisher_id"], rd.Request.Body, rd.AuthID, rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/publishers/{publisher_id}/schemas/{version}",
+			Method:       "GET",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleGetPublisherSchema(rd.DynamicParams["publisher_id"], rd.DynamicParams["version"], rd.AuthID, rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/publishers/{publisher_id}/acl",
+			Method:       "GET",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleGetPublisherACL(rd.DynamicParams["publisher_id"], rd.AuthID, rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/publishers/{publisher_id}/acl",
+			Method:       "PUT",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleSetPublisherACL(rd.DynamicParams["publisher_id"], rd.Request.Body, rd.AuthID, rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/publishers/{publisher_id}/acl/clients",
+			Method:       "POST",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleAllowClient(rd.DynamicParams["publisher_id"], rd.Request.Body, rd.AuthID, rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/publishers/{publisher_id}/acl/clients/{client_id}",
+			Method:       "DELETE",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleDisallowClient(rd.DynamicParams["publisher_id"], rd.DynamicParams["client_id"], rd.AuthID, rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/publishers/{publisher_id}/acl/groups",
+			Method:       "POST",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleAllowGroup(rd.DynamicParams["publisher_id"], rd.Request.Body, rd.AuthID, rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/publishers/{publisher_id}/acl/groups/{group_id}",
+			Method:       "DELETE",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleDisallowGroup(rd.DynamicParams["publisher_id"], rd.DynamicParams["group_id"], rd.AuthID, rd.Store)}
+			},
+		},
+	}
+}
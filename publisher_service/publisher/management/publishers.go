@@ -0,0 +1,206 @@
+package management
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/limits"
+)
+
+type jsonPublisher struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	OwnerID     string `json:"owner_id,omitempty"`     //only when listing the publishers of the client's organization
+	ContentType string `json:"content_type,omitempty"` //payloads have to be, only when listing publishers
+}
+type publishersResult struct {
+	Success    bool            `json:"success"`
+	Publishers []jsonPublisher `json:"publishers"`
+}
+
+//the client's publishers, or with ?organization=true every publisher in the client's organization
+func handleGetPublications(store storage.Store, id string, query url.Values) []byte {
+	organization := query.Get("organization") == "true"
+	var publishers []storage.Publisher
+	client, err := store.FindClient(id)
+	if err == nil && organization {
+		if client.OrganizationID == "" {
+			return createMessageResponse(false, "not in an organization")
+		}
+		publishers, err = store.ListOrganizationPublishers(client.OrganizationID)
+	} else if err == nil {
+		publishers, err = store.ListPublishers(id)
+	}
+	if err != nil {
+		return createMessageResponse(false, "failed fetching publications")
+	}
+	jResults := []jsonPublisher{}
+	for _, p := range publishers {
+		jPublisher := jsonPublisher{
+			Id:          p.ID,
+			Name:        p.Name,
+			ContentType: p.Payload.ContentType,
+		}
+		if organization {
+			jPublisher.OwnerID = p.OwnerID
+		}
+		jResults = append(jResults, jPublisher)
+	}
+	result, err := json.Marshal(publishersResult{
+		Success:    true,
+		Publishers: jResults,
+	})
+	if err != nil {
+		return createMessageResponse(false, "failed fetching publications")
+	}
+	return result
+}
+
+type createPublisherRequest struct {
+	Name            string `json:"name"`
+	ContentType     string `json:"content_type"`      //payloads have to be, empty accepts anything
+	MaxPayloadBytes int    `json:"max_payload_bytes"` //0 uses the service's limit
+}
+
+type createPublisherSuccessResponse struct {
+	Success bool                    `json:"success"`
+	Row     createPublisherResponse `json:"row"`
+}
+type createPublisherResponse struct {
+	Id              string `json:"id"`
+	Name            string `json:"name"`
+	ContentType     string `json:"content_type,omitempty"`
+	MaxPayloadBytes int    `json:"max_payload_bytes,omitempty"`
+}
+
+func handleCreatePublisher(body io.ReadCloser, id string, store storage.Store, payloadLimits limits.PayloadLimits) []byte {
+	publisherFailedMessage := "create publisher failed"
+	bytes, err := readBody(body)
+	if err != nil {
+		return createMessageResponse(false, publisherFailedMessage)
+	}
+
+	publisherRequest := createPublisherRequest{}
+	err = json.Unmarshal(bytes, &publisherRequest)
+	if err != nil {
+		return createMessageResponse(false, publisherFailedMessage)
+	}
+
+	policy := storage.PayloadPolicy{MaxBytes: publisherRequest.MaxPayloadBytes, ContentType: publisherRequest.ContentType}
+	err = limits.ValidatePolicy(policy, payloadLimits.MaxPayloadBytes)
+	if err != nil {
+		return createMessageResponse(false, err.Error())
+	}
+
+	client, err := store.FindClient(id)
+	if err != nil {
+		return createMessageResponse(false, publisherFailedMessage)
+	}
+	publisher, err := store.CreatePublisher(id, client.OrganizationID, publisherRequest.Name)
+
+	if errors.Is(err, storage.ErrConflict) {
+		return createMessageResponse(false, "publisher already exists")
+	}
+	if err != nil {
+		return createMessageResponse(false, publisherFailedMessage)
+	}
+
+	if policy != (storage.PayloadPolicy{}) {
+		err = store.SetPublisherPayload(publisher.ID, policy)
+		if err != nil {
+			return createMessageResponse(false, publisherFailedMessage)
+		}
+		publisher.Payload = policy
+	}
+
+	response, err := json.Marshal(createPublisherSuccessResponse{
+		Success: true,
+		Row: createPublisherResponse{
+			Id:              publisher.ID,
+			Name:            publisher.Name,
+			ContentType:     publisher.Payload.ContentType,
+			MaxPayloadBytes: publisher.Payload.MaxBytes,
+		},
+	})
+
+	if err != nil {
+		return createMessageResponse(false, publisherFailedMessage)
+	}
+
+	return response
+
+}
+
+//whether the client owns the publisher or is an admin of the organization it belongs to
+func checkOwnsPublisher(pubId string, ownerId string, store storage.Store) (bool, error) {
+	publisher, err := store.FindPublisher(pubId)
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return publisher.ManagedBy(ownerId, store)
+}
+
+func handleDeletePublisher(pubId string, ownerId string, store storage.Store) []byte {
+	deletePublisherFailedMessage := "delete publisher failed"
+	owned, err := checkOwnsPublisher(pubId, ownerId, store)
+	if err != nil {
+		return createMessageResponse(false, deletePublisherFailedMessage)
+	}
+
+	if !owned {
+		return createRefusalResponse(refusalNotFound, "publisher not found")
+	}
+
+	err = store.DeletePublisher(pubId)
+
+	if err != nil {
+		return createMessageResponse(false, deletePublisherFailedMessage)
+	}
+
+	return createMessageResponse(true, "publisher deleted")
+}
+
+type subscribersResult struct {
+	Success     bool            `json:"success"`
+	Subscribers []jsonPublisher `json:"subscribers"`
+}
+
+func getPublisherSubscribers(pubId string, store storage.Store) []byte {
+	subscribers, err := store.ListSubscribers(pubId)
+	if err != nil {
+		return createMessageResponse(false, "failed finding subscribers")
+	}
+	jResults := []jsonPublisher{}
+	for _, p := range subscribers {
+		jResults = append(jResults, jsonPublisher{
+			Id:   p.ID,
+			Name: p.Name,
+		})
+	}
+	result, err := json.Marshal(subscribersResult{
+		Success:     true,
+		Subscribers: jResults,
+	})
+	if err != nil {
+		return createMessageResponse(false, "failed fetching publications")
+	}
+	return result
+}
+
+func handleGetPublisherSubscribers(pubId string, ownerId string, store storage.Store) []byte {
+	deletePublisherFailedMessage := "get subscribers failed"
+	owned, err := checkOwnsPublisher(pubId, ownerId, store)
+	if err != nil {
+		return createMessageResponse(false, deletePublisherFailedMessage)
+	}
+	if !owned {
+		return createRefusalResponse(refusalNotFound, "publisher not found")
+	}
+	return getPublisherSubscribers(pubId, store)
+}
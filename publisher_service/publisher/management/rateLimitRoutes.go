@@ -0,0 +1,46 @@
+package management
+
+import (
+	"net/http"
+
+	"bezberr.com/messagebrokerstorage/auth"
+)
+
+//rate limits of the client calling, and the admin routes overriding them for a client
+func rateLimitRoutes() []route {
+	return []route{
+		{
+			RoutePattern: "/rate-limits",
+			Method:       "GET",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleGetRateLimits(rd.AuthID, rd.Store, rd.Limiter)}
+			},
+		},
+		{
+			RoutePattern: "/admin/clients/{client_id}/rate-limits",
+			Method:       "GET",
+			Admin:        true,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleGetRateLimits(rd.DynamicParams["client_id"], rd.Store, rd.Limiter)}
+			},
+		},
+		{
+			RoutePattern: "/admin/clients/{client_id}/rate-limits",
+			Method:       "PUT",
+			Admin:        true,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleSetRateLimits(rd.DynamicParams["client_id"], rd.Request.Body, rd.Store, rd.Limiter)}
+			},
+		},
+		{
+			RoutePattern: "/admin/clients/{client_id}/rate-limits",
+			Method:       "DELETE",
+			Admin:        true,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleSetRateLimits(rd.DynamicParams["client_id"], http.NoBody, rd.Store, rd.Limiter)}
+			},
+		},
+	}
+}
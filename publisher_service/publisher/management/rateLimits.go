@@ -0,0 +1,130 @@
+package management
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/limits"
+)
+
+type jsonRateLimits struct {
+	PublishPerMinute  int `json:"publish_per_minute"`
+	PublishBurst      int `json:"publish_burst"`
+	RequestsPerMinute int `json:"requests_per_minute"`
+	RequestsBurst     int `json:"requests_burst"`
+	Connections       int `json:"connections"`
+}
+
+type rateLimitsResult struct {
+	Success  bool            `json:"success"`
+	Limits   jsonRateLimits  `json:"limits"`             //limits applied to the client, 0 for no limit
+	Override *jsonRateLimits `json:"override,omitempty"` //the client's override of the defaults, if it has one
+}
+
+func newJSONRateLimits(limits storage.RateLimits) jsonRateLimits {
+	return jsonRateLimits{
+		PublishPerMinute:  limits.PublishPerMinute,
+		PublishBurst:      limits.PublishBurst,
+		RequestsPerMinute: limits.RequestsPerMinute,
+		RequestsBurst:     limits.RequestsBurst,
+		Connections:       limits.Connections,
+	}
+}
+
+//response to a call refused by a rate limit, sent with 429 Too Many Requests and a Retry-After header
+func createRateLimitResponse(limited *limits.RateLimitError) []byte {
+	res, _ := json.Marshal(messageResponse{
+		Success:      false,
+		Message:      limited.Error(),
+		RetryAfterMS: int64(math.Ceil(float64(limited.RetryAfter.Microseconds()) / 1000)),
+	})
+	return res
+}
+
+//whether the secret sent with a call to an admin route is the admin secret, always false if there isn't one
+func (server *Server) checkAdminSecret(secret string) bool {
+	return len(server.adminSecret) > 0 && subtle.ConstantTimeCompare([]byte(secret), server.adminSecret) == 1
+}
+
+//take a token from the request bucket of the client calling an authenticated route
+func (server *Server) checkRequestRate(route route, clientId string) *limits.RateLimitError {
+	if !route.Authenticate {
+		return nil
+	}
+	client, err := server.store.FindClient(clientId)
+	if err != nil {
+		//the route reports the client not being found
+		return nil
+	}
+	limited := &limits.RateLimitError{}
+	if errors.As(server.limiter.AllowRequest(clientId, client.RateLimits), &limited) {
+		return limited
+	}
+	return nil
+}
+
+func createRateLimitsResponse(client *storage.Client, limiter *limits.RateLimiter, failedMessage string) []byte {
+	result := rateLimitsResult{
+		Success: true,
+		Limits:  newJSONRateLimits(limiter.Limits(client.RateLimits)),
+	}
+	if client.RateLimits != nil {
+		override := newJSONRateLimits(*client.RateLimits)
+		result.Override = &override
+	}
+	response, err := json.Marshal(result)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	return response
+}
+
+//rate limits applied to a client
+func handleGetRateLimits(clientId string, store storage.Store, limiter *limits.RateLimiter) []byte {
+	failedMessage := "failed fetching rate limits"
+	client, err := store.FindClient(clientId)
+	if errors.Is(err, storage.ErrNotFound) {
+		return createRefusalResponse(refusalNotFound, "client not found")
+	}
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	return createRateLimitsResponse(client, limiter, failedMessage)
+}
+
+//override the default rate limits for a client, fields left at 0 keep the default and negative ones turn the
+//limit off. an empty body goes back to the defaults
+func handleSetRateLimits(clientId string, body io.ReadCloser, store storage.Store, limiter *limits.RateLimiter) []byte {
+	failedMessage := "failed setting rate limits"
+	bytes, err := readBody(body)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	var override *storage.RateLimits
+	if len(bytes) > 0 {
+		request := jsonRateLimits{}
+		err = json.Unmarshal(bytes, &request)
+		if err != nil {
+			return createMessageResponse(false, failedMessage)
+		}
+		override = &storage.RateLimits{
+			PublishPerMinute:  request.PublishPerMinute,
+			PublishBurst:      request.PublishBurst,
+			RequestsPerMinute: request.RequestsPerMinute,
+			RequestsBurst:     request.RequestsBurst,
+			Connections:       request.Connections,
+		}
+	}
+	err = store.SetClientRateLimits(clientId, override)
+	if errors.Is(err, storage.ErrNotFound) {
+		return createRefusalResponse(refusalNotFound, "client not found")
+	}
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	return handleGetRateLimits(clientId, store, limiter)
+}
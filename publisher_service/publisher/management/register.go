@@ -0,0 +1,69 @@
+package management
+
+import (
+	"encoding/json"
+	"io"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/auth"
+	"github.com/gorilla/sessions"
+)
+
+type registerRequest struct {
+	Name string `json:"name"`
+}
+type registerSuccessResponse struct {
+	Success bool             `json:"success"`
+	Row     registerResponse `json:"row"`
+}
+type registerResponse struct {
+	Id     string `json:"id"`
+	Secret string `json:"secret"` //only returned at registration and rotation, just the hash is stored
+}
+
+//register a client with a new secret in an organization, or outside of any if organizationId is empty
+func registerClient(organizationId string, name string, store storage.Store) (*storage.Client, string, error) {
+	secret, err := auth.NewSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	secretHash, err := auth.HashSecret(secret)
+	if err != nil {
+		return nil, "", err
+	}
+	client, err := store.CreateClient(organizationId, name, secretHash)
+	if err != nil {
+		return nil, "", err
+	}
+	return client, secret, nil
+}
+
+func handleRegistration(body io.ReadCloser, store storage.Store, session *sessions.Session) []byte {
+	registrationFailedMessage := "registration failed"
+	bytes, err := readBody(body)
+	if err != nil {
+		return createMessageResponse(false, registrationFailedMessage)
+	}
+	requestBody := registerRequest{}
+	err = json.Unmarshal(bytes, &requestBody)
+
+	if err != nil {
+		return createMessageResponse(false, registrationFailedMessage)
+	}
+
+	client, secret, err := registerClient("", requestBody.Name, store)
+
+	if err != nil {
+		return createMessageResponse(false, registrationFailedMessage)
+	}
+
+	res, _ := json.Marshal(registerSuccessResponse{
+		Success: true,
+		Row: registerResponse{
+			Id:     client.ID,
+			Secret: secret,
+		},
+	})
+	startSession(session, client)
+	return res
+}
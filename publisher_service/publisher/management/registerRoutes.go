@@ -0,0 +1,14 @@
+package management
+
+func registerRoutes() []route {
+	return []route{
+		{
+			RoutePattern: "/register",
+			Method:       "POST",
+			Authenticate: false,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleRegistration(rd.Request.Body, rd.Store, rd.Session)}
+			},
+		},
+	}
+}
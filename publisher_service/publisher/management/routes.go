@@ -0,0 +1,346 @@
+package management
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/auth"
+	"bezberr.com/messagebrokerstorage/limits"
+	"github.com/gorilla/sessions"
+)
+
+type messageResponse struct {
+	Success      bool   `json:"success"`
+	Message      string `json:"message"`
+	RetryAfterMS int64  `json:"retry_after_ms,omitempty"` //set when the client is over a rate limit
+	Quota        string `json:"quota,omitempty"`          //set when storing a message would go over the named quota
+	PayloadLimit int    `json:"payload_limit,omitempty"`  //set when a payload is over its size limit
+	//set when a payload doesn't match the version of the publisher's schema
+	SchemaVersion int    `json:"schema_version,omitempty"`
+	Reason        string `json:"reason,omitempty"` //set on refusals gRPC has a status for, one of the refusal constants
+}
+
+//why a call was refused, so gRPC calls get the matching status
+const (
+	refusalNotFound         = "not_found"         //the thing the call is about doesn't exist or belongs to someone else
+	refusalUnauthenticated  = "unauthenticated"   //the credentials or token sent weren't accepted
+	refusalPermissionDenied = "permission_denied" //the client isn't allowed to do it
+)
+
+//errKeyScope is returned by authenticateKey when the key is valid but isn't scoped to call the route
+var errKeyScope = errors.New("API key isn't scoped to the route")
+
+func readBody(body io.ReadCloser) ([]byte, error) {
+	defer body.Close()
+	bytes, err := io.ReadAll(body)
+	if err != nil {
+		fmt.Println(err)
+		return nil, err
+	}
+	return bytes, nil
+
+}
+
+func createMessageResponse(success bool, message string) []byte {
+	res, _ := json.Marshal(messageResponse{
+		Success: success,
+		Message: message,
+	})
+	return res
+}
+
+func createRefusalResponse(reason string, message string) []byte {
+	res, _ := json.Marshal(messageResponse{
+		Success: false,
+		Message: message,
+		Reason:  reason,
+	})
+	return res
+}
+
+//id of the client the session is logged in as, as long as the client's secret hasn't been rotated since
+func checkAuth(session *sessions.Session, store storage.Store) (string, bool) {
+	id, ok := session.Values["auth_id"].(string)
+	if !ok {
+		return "", false
+	}
+	//sessions from before secrets were versioned only carry on for clients which don't have a secret
+	version, _ := session.Values["secret_version"].(string)
+	client, err := store.FindClient(id)
+	if err != nil || version != auth.SecretVersion(client.SecretHash) {
+		return "", false
+	}
+	return id, true
+}
+
+//log the session in as the client, it stops working once the client's secret is rotated
+func startSession(session *sessions.Session, client *storage.Client) {
+	session.Values["auth_id"] = client.ID
+	session.Values["secret_version"] = auth.SecretVersion(client.SecretHash)
+}
+
+func (server *Server) getSession(r *http.Request) *sessions.Session {
+	session, _ := server.sessionStore.Get(r, "session")
+	return session
+}
+
+func separateRoute(url string) []string {
+	path := strings.Split(url, "/")
+	if path[0] == "" {
+		path = path[1:]
+	}
+	if path[len(path)-1] == "" {
+		path = path[:len(path)-1]
+	}
+	return path
+}
+
+type routeData struct {
+	Request       *http.Request
+	Store         storage.Store
+	Tokens        *auth.TokenSigner
+	Limiter       *limits.RateLimiter
+	Quotas        limits.Quotas
+	Payloads      limits.PayloadLimits
+	Session       *sessions.Session
+	AuthID        string
+	DynamicParams map[string]string
+}
+
+type route struct {
+	RoutePattern string
+	routeParts   []string
+	Method       string
+	Authenticate bool
+	Admin        bool   //called by an operator with the admin secret rather than by a client
+	KeyScope     string //scope an API key needs to call the route, {param}s are replaced by the route's params. empty if keys can't call it
+	Func         func(routeData, chan routeResponse)
+}
+
+//what a route responds with, Err is set when the call was refused by a rate limit, quota or the payload checks so
+//it's sent with the matching status
+type routeResponse struct {
+	Body []byte
+	Err  error
+}
+
+//scope an API key needs to call the route with the params
+func (route *route) keyScope(dynamicParams map[string]string) string {
+	scope := route.KeyScope
+	for name, value := range dynamicParams {
+		scope = strings.ReplaceAll(scope, "{"+name+"}", value)
+	}
+	return scope
+}
+
+//id of the client whose API key was sent with a request, errKeyScope if the key is valid but doesn't have the
+//route's scope
+func (server *Server) authenticateKey(route route, token string, dynamicParams map[string]string) (string, error) {
+	key, err := auth.AuthenticateAPIKey(server.store, token, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if route.KeyScope == "" || !key.HasScope(route.keyScope(dynamicParams)) {
+		return "", errKeyScope
+	}
+	return key.ClientID, nil
+}
+
+//id of the client an access token was issued to, if the token is valid
+func (server *Server) authenticateToken(token string) (string, bool) {
+	if server.tokens == nil {
+		return "", false
+	}
+	client, err := server.tokens.Authenticate(server.store, token, time.Now())
+	if err != nil {
+		return "", false
+	}
+	return client.ID, true
+}
+
+func (route *route) GetDynamicParams(url string) map[string]string {
+	urlParts := separateRoute(url)
+	dynamicParams := make(map[string]string)
+	if len(urlParts) != len(route.routeParts) {
+		return dynamicParams
+	}
+	for i, part := range urlParts {
+		correPart := route.routeParts[i]
+		if string(correPart[0]) == "{" && string(correPart[len(correPart)-1]) == "}" {
+			dynamicUrlParamName := correPart[1 : len(correPart)-1]
+			dynamicParams[dynamicUrlParamName] = part
+		}
+	}
+	return dynamicParams
+}
+
+func (route *route) Match(url string, method string) bool {
+	if route.Method != method && method != "OPTIONS" {
+		return false
+	}
+	urlParts := separateRoute(url)
+	if route.routeParts == nil {
+		route.routeParts = separateRoute(route.RoutePattern)
+	}
+	if len(route.routeParts) != len(urlParts) {
+		return false
+	}
+	for i, part := range urlParts {
+		correPart := route.routeParts[i]
+		if string(correPart[0]) == "{" && string(correPart[len(correPart)-1]) == "}" {
+			continue
+		} else if correPart != part {
+			return false
+		}
+	}
+	return true
+
+}
+
+func createResponseChannel() chan routeResponse {
+	return make(chan routeResponse)
+}
+
+//HTTP status a route refused with the error is sent with, 200 if it wasn't refused by a limit or the payload checks
+func refusalStatus(err error) int {
+	limited := &limits.RateLimitError{}
+	exceeded := &limits.QuotaError{}
+	rejected := &limits.PayloadError{}
+	switch {
+	case errors.As(err, &limited):
+		return http.StatusTooManyRequests
+	case errors.As(err, &exceeded):
+		return http.StatusInsufficientStorage
+	case errors.As(err, &rejected) && rejected.Limit > 0:
+		return http.StatusRequestEntityTooLarge
+	case errors.As(err, &rejected) && rejected.SchemaVersion > 0:
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusOK
+}
+
+func (server *Server) matchRoute(url string, method string) (route, bool) {
+	for _, route := range server.routes {
+		if route.Match(url, method) {
+			return route, true
+		}
+	}
+	return route{}, false
+}
+
+func allRoutes() []route {
+	routes := []route{}
+
+	routes = append(routes, authRoutes()...)
+
+	routes = append(routes, registerRoutes()...)
+
+	routes = append(routes, organizationRoutes()...)
+
+	routes = append(routes, apiKeyRoutes()...)
+
+	routes = append(routes, publicationRoutes()...)
+
+	routes = append(routes, groupRoutes()...)
+
+	routes = append(routes, messageRoutes()...)
+
+	routes = append(routes, subscriberRoutes()...)
+
+	routes = append(routes, subscriptionRequestRoutes()...)
+
+	routes = append(routes, rateLimitRoutes()...)
+
+	routes = append(routes, usageRoutes()...)
+
+	return routes
+}
+
+func (server *Server) handleRequest(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Access-Control-Allow-Origin", server.allowedOrigin)
+	rw.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-API-Key, Authorization")
+	rw.Header().Set("Access-Control-Allow-Credentials", "true")
+	rw.Header().Set("Access-Control-Expose-Headers", "Retry-After")
+	route, found := server.matchRoute(r.URL.Path, r.Method)
+
+	if !found {
+		http.NotFound(rw, r)
+		return
+	}
+
+	if r.Method == "OPTIONS" {
+		(rw).Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
+		rw.Write(createMessageResponse(true, ""))
+		return
+	}
+	h := rw.Header()
+	h.Add("Content-Type", "application/json")
+	maxBody := server.payloadLimits.MaxRequestBytes
+	if r.ContentLength > int64(maxBody) {
+		rw.WriteHeader(http.StatusRequestEntityTooLarge)
+		rw.Write(createBodyTooLargeResponse(maxBody))
+		return
+	}
+	r.Body = http.MaxBytesReader(rw, r.Body, int64(maxBody))
+	session := server.getSession(r)
+
+	rd := routeData{
+		Request:       r,
+		Store:         server.store,
+		Tokens:        server.tokens,
+		Limiter:       server.limiter,
+		Quotas:        server.quotas,
+		Payloads:      server.payloadLimits,
+		Session:       session,
+		DynamicParams: route.GetDynamicParams(r.URL.Path),
+	}
+
+	if route.Admin && !server.checkAdminSecret(r.Header.Get("X-Admin-Secret")) {
+		rw.WriteHeader(http.StatusForbidden)
+		rw.Write(createMessageResponse(false, "Forbidden >:("))
+		return
+	}
+	if route.Authenticate {
+		id, authed := checkAuth(session, server.store)
+		if token := r.Header.Get("X-API-Key"); token != "" {
+			var err error
+			id, err = server.authenticateKey(route, token, rd.DynamicParams)
+			authed = err == nil
+		} else if token := auth.BearerToken(r.Header.Get("Authorization")); token != "" {
+			id, authed = server.authenticateToken(token)
+		}
+		if !authed {
+			rw.WriteHeader(http.StatusForbidden)
+			rw.Write(createMessageResponse(false, "Forbidden >:("))
+			return
+		}
+		rd.AuthID = id
+	}
+
+	var response routeResponse
+	if limited := server.checkRequestRate(route, rd.AuthID); limited != nil {
+		response = routeResponse{Body: createRateLimitResponse(limited), Err: limited}
+	} else {
+		channel := createResponseChannel()
+
+		go route.Func(rd, channel)
+
+		response = <-channel
+	}
+	session.Save(r, rw)
+	limited := &limits.RateLimitError{}
+	if errors.As(response.Err, &limited) {
+		rw.Header().Set("Retry-After", strconv.Itoa(limited.RetryAfterSeconds()))
+	}
+	if status := refusalStatus(response.Err); status != http.StatusOK {
+		rw.WriteHeader(status)
+	}
+	rw.Write(response.Body)
+}
@@ -0,0 +1,168 @@
+package management
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/schema"
+)
+
+type jsonSchemaVersion struct {
+	Version       int       `json:"version"`
+	Schema        string    `json:"schema"` //the JSON Schema document
+	Compatibility string    `json:"compatibility"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type schemaResult struct {
+	Success bool              `json:"success"`
+	Message string            `json:"message"`
+	Schema  jsonSchemaVersion `json:"schema"`
+}
+
+type schemasResult struct {
+	Success bool                `json:"success"`
+	Schemas []jsonSchemaVersion `json:"schemas"`
+}
+
+type registerSchemaRequest struct {
+	Schema        json.RawMessage `json:"schema"`        //the JSON Schema document, either as an object or a string
+	Compatibility string          `json:"compatibility"` //backward, forward, full or none. empty keeps the latest version's
+}
+
+func createSchemaVersion(schema storage.Schema) jsonSchemaVersion {
+	return jsonSchemaVersion{
+		Version:       schema.Version,
+		Schema:        schema.Definition,
+		Compatibility: schema.Compatibility,
+		CreatedAt:     schema.CreatedAt,
+	}
+}
+
+func createSchemaResponse(schema storage.Schema, message string, failedMessage string) []byte {
+	response, err := json.Marshal(schemaResult{Success: true, Message: message, Schema: createSchemaVersion(schema)})
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	return response
+}
+
+//find a publisher whose schemas the client can read, as someone who manages it or can subscribe to it
+func findSchemaPublisher(pubId string, clientId string, store storage.Store) (*storage.Publisher, error) {
+	publisher, client, err := findVisiblePublisher(pubId, clientId, store)
+	if err != nil || publisher == nil {
+		return nil, err
+	}
+	manages, err := publisher.ManagedBy(clientId, store)
+	if err != nil {
+		return nil, err
+	}
+	if manages {
+		return publisher, nil
+	}
+	allowed, err := publisher.Allows(client, store)
+	if err != nil || !allowed {
+		return nil, err
+	}
+	return publisher, nil
+}
+
+func handleGetPublisherSchemas(pubId string, clientId string, store storage.Store) []byte {
+	failedMessage := "get publisher schemas failed"
+	publisher, err := findSchemaPublisher(pubId, clientId, store)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	if publisher == nil {
+		return createRefusalResponse(refusalNotFound, "publisher not found")
+	}
+	schemas, err := store.ListSchemas(pubId)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	result := schemasResult{Success: true, Schemas: []jsonSchemaVersion{}}
+	for _, schema := range schemas {
+		result.Schemas = append(result.Schemas, createSchemaVersion(schema))
+	}
+	response, err := json.Marshal(result)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	return response
+}
+
+//get a version of the publisher's schema, a number or latest
+func handleGetPublisherSchema(pubId string, version string, clientId string, store storage.Store) []byte {
+	failedMessage := "get publisher schema failed"
+	number := 0
+	if version != "latest" {
+		parsed, err := strconv.Atoi(version)
+		if err != nil || parsed < 1 {
+			return createMessageResponse(false, "version should be a number from 1 or latest")
+		}
+		number = parsed
+	}
+	publisher, err := findSchemaPublisher(pubId, clientId, store)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	if publisher == nil {
+		return createRefusalResponse(refusalNotFound, "publisher not found")
+	}
+	schema, err := store.FindSchema(pubId, number)
+	if errors.Is(err, storage.ErrNotFound) {
+		return createRefusalResponse(refusalNotFound, "schema not found")
+	}
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	return createSchemaResponse(*schema, "", failedMessage)
+}
+
+//register the next version of the publisher's schema, payloads published after it are validated against it
+func handleRegisterPublisherSchema(pubId string, body io.ReadCloser, ownerId string, store storage.Store) []byte {
+	failedMessage := "register publisher schema failed"
+	bytes, err := readBody(body)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	request := registerSchemaRequest{}
+	err = json.Unmarshal(bytes, &request)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	definition := string(request.Schema)
+	if len(request.Schema) > 0 && request.Schema[0] == '"' {
+		err = json.Unmarshal(request.Schema, &definition)
+		if err != nil {
+			return createMessageResponse(false, failedMessage)
+		}
+	}
+	if definition == "" || definition == "null" {
+		return createMessageResponse(false, "schema is required")
+	}
+	publisher, err := findOwnedPublisher(pubId, ownerId, store)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	if publisher == nil {
+		return createRefusalResponse(refusalNotFound, "publisher not found")
+	}
+	registered, err := schema.RegisterSchema(store, pubId, definition, request.Compatibility, time.Now())
+	invalid := &schema.SchemaError{}
+	incompatible := &schema.CompatibilityError{}
+	if errors.As(err, &invalid) || errors.As(err, &incompatible) {
+		return createMessageResponse(false, err.Error())
+	}
+	if errors.Is(err, storage.ErrConflict) {
+		return createMessageResponse(false, "another version of the schema was registered at the same time, try again")
+	}
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	return createSchemaResponse(*registered, "schema registered", failedMessage)
+}
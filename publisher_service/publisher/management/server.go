@@ -0,0 +1,306 @@
+//Package management is the publisher service's API for registering clients, managing publishers and
+//subscriptions and publishing messages, over REST and gRPC. It can be embedded in another process, e.g. for
+//integration tests, by creating a Server against a store shared with the message broker.
+package management
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/auth"
+	"bezberr.com/messagebrokerstorage/limits"
+	"github.com/gorilla/sessions"
+	"google.golang.org/grpc"
+)
+
+const (
+	DefaultHTTPAddress = ":8081"
+	DefaultGRPCAddress = ":8082"
+
+	DefaultAllowedOrigin = "http://localhost:8080"
+
+	generatedSessionSecretBytes = 32
+)
+
+//Option configures a Server
+type Option func(*Server)
+
+//WithStore sets the store clients, publishers and messages are kept in, required
+func WithStore(store storage.Store) Option {
+	return func(server *Server) {
+		server.store = store
+	}
+}
+
+//WithHTTPAddress sets the address to serve the REST API on, DefaultHTTPAddress if not set
+func WithHTTPAddress(address string) Option {
+	return func(server *Server) {
+		server.httpAddress = address
+	}
+}
+
+//WithHTTPListener serves the REST API on a listener which is already open
+func WithHTTPListener(listener net.Listener) Option {
+	return func(server *Server) {
+		server.httpListener = listener
+	}
+}
+
+//WithGRPCAddress sets the address to serve the gRPC API on, DefaultGRPCAddress if not set. empty disables gRPC
+func WithGRPCAddress(address string) Option {
+	return func(server *Server) {
+		server.grpcAddress = address
+	}
+}
+
+//WithGRPCListener serves the gRPC API on a listener which is already open
+func WithGRPCListener(listener net.Listener) Option {
+	return func(server *Server) {
+		server.grpcListener = listener
+	}
+}
+
+//WithSessionSecret sets the key the session cookies which keep clients logged in are signed with. without one a
+//random key is generated, so sessions don't outlive the process or carry over to other instances
+func WithSessionSecret(secret []byte) Option {
+	return func(server *Server) {
+		server.sessionSecret = secret
+	}
+}
+
+//WithTokenSecret enables issuing access and refresh tokens signed with the secret, the message broker accepts
+//them when given the same secret. tokens aren't issued or accepted if it isn't set
+func WithTokenSecret(secret []byte) Option {
+	return func(server *Server) {
+		server.tokenSecret = secret
+	}
+}
+
+//WithTokenTTLs sets how long access and refresh tokens last, auth.DefaultAccessTokenTTL and
+//auth.DefaultRefreshTokenTTL if not set
+func WithTokenTTLs(accessTTL time.Duration, refreshTTL time.Duration) Option {
+	return func(server *Server) {
+		server.accessTokenTTL = accessTTL
+		server.refreshTokenTTL = refreshTTL
+	}
+}
+
+//WithRateLimits limits how fast clients can call the API and publish, and how fast messages can be published to
+//each publisher. nothing is limited if it isn't set, unless a client has an override
+func WithRateLimits(limits limits.RateLimitConfig) Option {
+	return func(server *Server) {
+		server.rateLimits = limits
+	}
+}
+
+//WithQuotas limits the messages which can be stored for each publisher and client, nothing is limited if it isn't
+//set
+func WithQuotas(quotas limits.Quotas) Option {
+	return func(server *Server) {
+		server.quotas = quotas
+	}
+}
+
+//WithPayloadLimits sets the largest payload which can be published and the largest request body read,
+//limits.DefaultPayloadLimits if not set
+func WithPayloadLimits(payloadLimits limits.PayloadLimits) Option {
+	return func(server *Server) {
+		server.payloadLimits = payloadLimits
+	}
+}
+
+//WithAdminSecret enables the admin routes for operators, such as overriding a client's rate limits, called with the
+//secret in the X-Admin-Secret header. they're disabled if it isn't set
+func WithAdminSecret(secret []byte) Option {
+	return func(server *Server) {
+		server.adminSecret = secret
+	}
+}
+
+//WithAllowedOrigin sets the origin of the web frontend which may call the REST API from a browser,
+//DefaultAllowedOrigin if not set
+func WithAllowedOrigin(origin string) Option {
+	return func(server *Server) {
+		server.allowedOrigin = origin
+	}
+}
+
+//Server is the publisher service's API, which can be started and shut down inside another process
+type Server struct {
+	store         storage.Store
+	httpAddress   string
+	httpListener  net.Listener
+	grpcAddress   string
+	grpcListener  net.Listener
+	sessionSecret []byte
+	allowedOrigin string
+
+	tokenSecret     []byte
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+
+	rateLimits    limits.RateLimitConfig
+	quotas        limits.Quotas
+	payloadLimits limits.PayloadLimits
+	adminSecret   []byte
+
+	routes       []route
+	sessionStore *sessions.CookieStore
+	tokens       *auth.TokenSigner //nil if tokens aren't enabled
+	limiter      *limits.RateLimiter
+
+	lock       sync.Mutex
+	started    bool
+	stopped    bool //a server can't be started again once it's been shut down
+	httpServer *http.Server
+	grpcServer *grpc.Server
+}
+
+//New creates the API, it doesn't accept requests until it's started
+func New(options ...Option) (*Server, error) {
+	server := &Server{
+		httpAddress:   DefaultHTTPAddress,
+		grpcAddress:   DefaultGRPCAddress,
+		allowedOrigin: DefaultAllowedOrigin,
+	}
+	for _, option := range options {
+		option(server)
+	}
+	if server.store == nil {
+		return nil, errors.New("a store is required")
+	}
+	if len(server.sessionSecret) == 0 {
+		server.sessionSecret = make([]byte, generatedSessionSecretBytes)
+		_, err := rand.Read(server.sessionSecret)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Println("Warning: no session secret is set, using a random one so sessions end when the process stops and aren't shared with other instances")
+	}
+	if server.allowedOrigin == "" {
+		return nil, errors.New("an allowed origin is required")
+	}
+	server.routes = allRoutes()
+	server.sessionStore = sessions.NewCookieStore(server.sessionSecret)
+	if len(server.tokenSecret) > 0 {
+		server.tokens = auth.NewTokenSigner(server.tokenSecret, server.accessTokenTTL, server.refreshTokenTTL)
+	}
+	err := server.rateLimits.Validate()
+	if err == nil {
+		err = server.quotas.Validate()
+	}
+	if err == nil {
+		err = server.payloadLimits.Validate()
+	}
+	if err != nil {
+		return nil, err
+	}
+	server.payloadLimits = server.payloadLimits.WithDefaults()
+	server.limiter = limits.NewRateLimiter(server.rateLimits)
+	return server, nil
+}
+
+//open a listener for the address unless one was supplied, returning nil if neither was
+func listen(ctx context.Context, listener net.Listener, address string) (net.Listener, error) {
+	if listener != nil || address == "" {
+		return listener, nil
+	}
+	config := net.ListenConfig{}
+	return config.Listen(ctx, "tcp", address)
+}
+
+//Start opens the listeners and starts serving requests, returning once the API is ready. ctx only covers
+//starting up, the API runs until it's shut down
+func (server *Server) Start(ctx context.Context) error {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	if server.started {
+		return errors.New("already started")
+	}
+
+	var err error
+	server.httpListener, err = listen(ctx, server.httpListener, server.httpAddress)
+	if err == nil && server.httpListener == nil {
+		err = errors.New("an HTTP address is required")
+	}
+	if err == nil {
+		server.grpcListener, err = listen(ctx, server.grpcListener, server.grpcAddress)
+	}
+	if err != nil {
+		if server.httpListener != nil {
+			server.httpListener.Close()
+		}
+		return err
+	}
+	server.started = true
+
+	server.httpServer = &http.Server{Handler: http.HandlerFunc(server.handleRequest)}
+	go func() {
+		if err := server.httpServer.Serve(server.httpListener); err != http.ErrServerClosed {
+			fmt.Println(err.Error())
+		}
+	}()
+
+	//gRPC version of the routes runs alongside the REST server
+	if server.grpcListener != nil {
+		server.grpcServer = newGRPCServer(server)
+		go func() {
+			if err := server.grpcServer.Serve(server.grpcListener); err != nil {
+				fmt.Println(err.Error())
+			}
+		}()
+	}
+	return nil
+}
+
+//HTTPAddr is the address the REST API is served on, nil until the API has started
+func (server *Server) HTTPAddr() net.Addr {
+	return listenerAddr(server.httpListener)
+}
+
+//GRPCAddr is the address the gRPC API is served on, nil until the API has started or if gRPC is disabled
+func (server *Server) GRPCAddr() net.Addr {
+	return listenerAddr(server.grpcListener)
+}
+
+func listenerAddr(listener net.Listener) net.Addr {
+	if listener == nil {
+		return nil
+	}
+	return listener.Addr()
+}
+
+//Shutdown stops accepting requests and waits for the ones in progress to finish until ctx is done. the store is
+//left open for the caller to close
+func (server *Server) Shutdown(ctx context.Context) error {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	if !server.started || server.stopped {
+		return errors.New("not running")
+	}
+	server.stopped = true
+
+	err := server.httpServer.Shutdown(ctx)
+	if server.grpcServer != nil {
+		grpcStopped := make(chan bool)
+		go func() {
+			server.grpcServer.GracefulStop()
+			close(grpcStopped)
+		}()
+		select {
+		case <-grpcStopped:
+		case <-ctx.Done():
+			server.grpcServer.Stop()
+		case <-time.After(5 * time.Second):
+			server.grpcServer.Stop()
+		}
+	}
+	return err
+}
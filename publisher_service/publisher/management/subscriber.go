@@ -0,0 +1,214 @@
+package management
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"github.com/google/uuid"
+)
+
+//stream subscriptions are consumed by the client's websocket, event stream or pull requests
+const subscriptionTypeStream = "stream"
+
+type jsonSubscriptionResultPublisher struct {
+	Id      string `json:"id"`
+	Name    string `json:"name"`
+	OwnerID string `json:"owner_id"`
+}
+type jsonWebhookStatus struct {
+	URL                 string     `json:"url"`
+	Status              string     `json:"status"` //healthy, or unhealthy after repeated failed deliveries
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt       *time.Time `json:"last_failure_at,omitempty"`
+	NextAttemptAt       *time.Time `json:"next_attempt_at,omitempty"` //when a failed delivery will be retried
+}
+type jsonSubscriptionResult struct {
+	Id        string                          `json:"id"`
+	Type      string                          `json:"type"`
+	Publisher jsonSubscriptionResultPublisher `json:"publisher"`
+	Webhook   *jsonWebhookStatus              `json:"webhook,omitempty"`
+}
+
+//optional time fields are left out of the response if they have never been set
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+type subscriptionsResult struct {
+	Success       bool                     `json:"success"`
+	Subscriptions []jsonSubscriptionResult `json:"subscriptions"`
+}
+
+func findPublisherInList(id string, publishers []storage.Publisher) (bool, storage.Publisher) {
+	for _, publisher := range publishers {
+		if publisher.ID == id {
+			return true, publisher
+		}
+	}
+	return false, storage.Publisher{}
+}
+
+func handleGetSubscriptions(id string, store storage.Store) []byte {
+	client, err := store.FindClient(id)
+	if err != nil {
+		return createMessageResponse(false, "failed fetching subscriptions")
+	}
+	publisherIDs := []string{}
+	for _, subscription := range client.Subscriptions {
+		publisherIDs = append(publisherIDs, subscription.PublisherID)
+	}
+	publishers, err := store.FindPublishers(publisherIDs)
+	if err != nil {
+		return createMessageResponse(false, "failed fetching subscriptions")
+	}
+
+	subscriptions := []jsonSubscriptionResult{}
+	for _, subscription := range client.Subscriptions {
+		found, publisherDetails := findPublisherInList(subscription.PublisherID, publishers)
+		if !found {
+			continue
+		}
+		result := jsonSubscriptionResult{
+			Id:   subscription.ID,
+			Type: subscriptionTypeStream,
+			Publisher: jsonSubscriptionResultPublisher{
+				Id:      publisherDetails.ID,
+				Name:    publisherDetails.Name,
+				OwnerID: publisherDetails.OwnerID,
+			},
+		}
+		if subscription.Type == storage.SubscriptionTypeWebhook && subscription.Webhook != nil {
+			webhook := subscription.Webhook
+			result.Type = storage.SubscriptionTypeWebhook
+			result.Webhook = &jsonWebhookStatus{
+				URL:                 webhook.URL,
+				Status:              webhook.Status,
+				ConsecutiveFailures: webhook.ConsecutiveFailures,
+				LastError:           webhook.LastError,
+				LastSuccessAt:       optionalTime(webhook.LastSuccessAt),
+				LastFailureAt:       optionalTime(webhook.LastFailureAt),
+				NextAttemptAt:       optionalTime(webhook.NextAttemptAt),
+			}
+		}
+		subscriptions = append(subscriptions, result)
+	}
+
+	result, err := json.Marshal(subscriptionsResult{
+		Success:       true,
+		Subscriptions: subscriptions,
+	})
+	if err != nil {
+		return createMessageResponse(false, "failed fetching subscriptions")
+	}
+	return result
+}
+
+type subscribeRequest struct {
+	PublisherID string `json:"publisher_id"`
+	Type        string `json:"type"`   //stream (the default) or webhook
+	URL         string `json:"url"`    //URL webhook deliveries are POSTed to
+	Secret      string `json:"secret"` //secret webhook deliveries are signed with
+}
+
+//check the target of a webhook subscription
+func validateWebhook(request subscribeRequest) error {
+	target, err := url.Parse(request.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return errors.New("webhook url must be an absolute http or https url")
+	}
+	if request.Secret == "" {
+		return errors.New("webhook secret required")
+	}
+	return nil
+}
+
+//subscription a request to subscribe would add, checking its type and webhook
+func newSubscription(request subscribeRequest) (storage.Subscription, error) {
+	if request.Type == "" {
+		request.Type = subscriptionTypeStream
+	}
+	if request.Type != subscriptionTypeStream && request.Type != storage.SubscriptionTypeWebhook {
+		return storage.Subscription{}, errors.New("unknown subscription type")
+	}
+	subscription := storage.Subscription{
+		ID:          uuid.New().String(),
+		PublisherID: request.PublisherID,
+	}
+	if request.Type == storage.SubscriptionTypeWebhook {
+		err := validateWebhook(request)
+		if err != nil {
+			return storage.Subscription{}, err
+		}
+		subscription.Type = storage.SubscriptionTypeWebhook
+		subscription.Webhook = &storage.Webhook{
+			URL:    request.URL,
+			Secret: request.Secret,
+			Status: storage.WebhookStatusHealthy,
+		}
+	}
+	return subscription, nil
+}
+
+func handleSubscribe(body io.ReadCloser, id string, store storage.Store) []byte {
+	failMessage := "failed to subscribe"
+	bytes, err := readBody(body)
+	if err != nil {
+		return createMessageResponse(false, failMessage)
+	}
+	request := subscribeRequest{}
+	err = json.Unmarshal(bytes, &request)
+	if err != nil {
+		return createMessageResponse(false, failMessage)
+	}
+
+	subscription, err := newSubscription(request)
+	if err != nil {
+		return createMessageResponse(false, err.Error())
+	}
+
+	publisher, client, err := findVisiblePublisher(request.PublisherID, id, store)
+	if err != nil {
+		return createMessageResponse(false, failMessage)
+	}
+	if publisher == nil {
+		return createRefusalResponse(refusalNotFound, "publisher not found")
+	}
+	allowed, err := publisher.Allows(client, store)
+	if err != nil {
+		return createMessageResponse(false, failMessage)
+	}
+	if !allowed {
+		return createRefusalResponse(refusalPermissionDenied, "not allowed to subscribe to the publisher, send a subscription request instead")
+	}
+
+	err = store.AddSubscription(id, subscription)
+
+	if errors.Is(err, storage.ErrConflict) {
+		return createMessageResponse(false, "already subscribed")
+	}
+	if err != nil {
+		return createMessageResponse(false, failMessage)
+	}
+
+	return createMessageResponse(true, "subscribed")
+}
+
+func handleDeleteSubscription(subscriptionId string, body io.ReadCloser, id string, store storage.Store) []byte {
+	err := store.RemoveSubscription(id, subscriptionId)
+	if errors.Is(err, storage.ErrNotFound) {
+		return createRefusalResponse(refusalNotFound, "subscription not found")
+	}
+	if err != nil {
+		return createMessageResponse(false, "delete subscription failed")
+	}
+	return createMessageResponse(true, "unsubscribed")
+}
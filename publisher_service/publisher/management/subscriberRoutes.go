@@ -0,0 +1,35 @@
+package management
+
+import "bezberr.com/messagebrokerstorage/auth"
+
+func subscriberRoutes() []route {
+	return []route{
+		{
+			RoutePattern: "/subscriptions",
+			Method:       "GET",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleGetSubscriptions(rd.AuthID, rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/subscriptions",
+			Method:       "POST",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleSubscribe(rd.Request.Body, rd.AuthID, rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/subscriptions/{subscription_id}",
+			Method:       "DELETE",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleDeleteSubscription(rd.DynamicParams["subscription_id"], rd.Request.Body, rd.AuthID, rd.Store)}
+			},
+		},
+	}
+}
@@ -0,0 +1,57 @@
+package management
+
+import (
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/auth"
+)
+
+//requests to subscribe to publishers a client can't subscribe to directly, decided by the publishers' owners
+func subscriptionRequestRoutes() []route {
+	return []route{
+		{
+			RoutePattern: "/subscription-requests",
+			Method:       "GET",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleGetSubscriptionRequests(rd.AuthID, rd.Request.URL.Query(), rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/subscription-requests",
+			Method:       "POST",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleCreateSubscriptionRequest(rd.Request.Body, rd.AuthID, rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/subscription-requests/incoming",
+			Method:       "GET",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleGetIncomingSubscriptionRequests(rd.AuthID, rd.Request.URL.Query(), rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/subscription-requests/{request_id}/approve",
+			Method:       "POST",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleDecideSubscriptionRequest(rd.DynamicParams["request_id"], storage.RequestApproved, rd.Request.Body, rd.AuthID, rd.Store)}
+			},
+		},
+		{
+			RoutePattern: "/subscription-requests/{request_id}/deny",
+			Method:       "POST",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleDecideSubscriptionRequest(rd.DynamicParams["request_id"], storage.RequestDenied, rd.Request.Body, rd.AuthID, rd.Store)}
+			},
+		},
+	}
+}
@@ -0,0 +1,279 @@
+package management
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"github.com/google/uuid"
+)
+
+type createSubscriptionRequestRequest struct {
+	subscribeRequest
+	Message string `json:"message"` //to the owner of the publisher
+}
+
+type decideSubscriptionRequestRequest struct {
+	Reason string `json:"reason"` //optional, passed on to the client
+}
+
+type jsonSubscriptionRequest struct {
+	Id         string                          `json:"id"`
+	ClientID   string                          `json:"client_id"`
+	Publisher  jsonSubscriptionResultPublisher `json:"publisher"`
+	Message    string                          `json:"message"`
+	Type       string                          `json:"type"` //stream or webhook, the type of subscription added once it's approved
+	WebhookURL string                          `json:"webhook_url,omitempty"`
+	Status     string                          `json:"status"` //pending, approved or denied
+	Reason     string                          `json:"reason,omitempty"`
+	CreatedAt  time.Time                       `json:"created_at"`
+	DecidedAt  *time.Time                      `json:"decided_at,omitempty"`
+}
+
+type subscriptionRequestResult struct {
+	Success bool                    `json:"success"`
+	Request jsonSubscriptionRequest `json:"request"`
+}
+
+type subscriptionRequestsResult struct {
+	Success  bool                      `json:"success"`
+	Requests []jsonSubscriptionRequest `json:"requests"`
+}
+
+func newJSONSubscriptionRequest(request storage.SubscriptionRequest, publisherName string) jsonSubscriptionRequest {
+	result := jsonSubscriptionRequest{
+		Id:       request.ID,
+		ClientID: request.ClientID,
+		Publisher: jsonSubscriptionResultPublisher{
+			Id:      request.PublisherID,
+			Name:    publisherName,
+			OwnerID: request.OwnerID,
+		},
+		Message:   request.Message,
+		Type:      subscriptionTypeStream,
+		Status:    request.Status,
+		Reason:    request.Reason,
+		CreatedAt: request.CreatedAt,
+		DecidedAt: optionalTime(request.DecidedAt),
+	}
+	if request.Subscription.Webhook != nil {
+		result.Type = storage.SubscriptionTypeWebhook
+		result.WebhookURL = request.Subscription.Webhook.URL
+	}
+	return result
+}
+
+func createSubscriptionRequestResponse(request storage.SubscriptionRequest, publisherName string, failedMessage string) []byte {
+	response, err := json.Marshal(subscriptionRequestResult{
+		Success: true,
+		Request: newJSONSubscriptionRequest(request, publisherName),
+	})
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	return response
+}
+
+//ask the owner of a publisher the client can't subscribe to directly to let it subscribe
+func handleCreateSubscriptionRequest(body io.ReadCloser, id string, store storage.Store) []byte {
+	failedMessage := "subscription request failed"
+	bytes, err := readBody(body)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	request := createSubscriptionRequestRequest{}
+	err = json.Unmarshal(bytes, &request)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	subscription, err := newSubscription(request.subscribeRequest)
+	if err != nil {
+		return createMessageResponse(false, err.Error())
+	}
+	publisher, client, err := findVisiblePublisher(request.PublisherID, id, store)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	if publisher == nil {
+		return createRefusalResponse(refusalNotFound, "publisher not found")
+	}
+	allowed, err := publisher.Allows(client, store)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	if allowed {
+		return createMessageResponse(false, "already allowed to subscribe to the publisher")
+	}
+
+	subscriptionRequest := storage.SubscriptionRequest{
+		ID:           uuid.New().String(),
+		ClientID:     id,
+		PublisherID:  publisher.ID,
+		OwnerID:      publisher.OwnerID,
+		Message:      request.Message,
+		Subscription: subscription,
+		Status:       storage.RequestPending,
+		CreatedAt:    time.Now(),
+	}
+	err = store.CreateSubscriptionRequest(subscriptionRequest)
+	if errors.Is(err, storage.ErrConflict) {
+		return createMessageResponse(false, "already waiting on a request to subscribe to the publisher")
+	}
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	return createSubscriptionRequestResponse(subscriptionRequest, publisher.Name, failedMessage)
+}
+
+//list requests along with the names of their publishers, optionally filtered by status
+func listSubscriptionRequests(query storage.SubscriptionRequestQuery, values url.Values, store storage.Store) []byte {
+	failedMessage := "failed fetching subscription requests"
+	query.Status = values.Get("status")
+	requests, err := store.ListSubscriptionRequests(query)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	publisherIDs := []string{}
+	for _, request := range requests {
+		publisherIDs = append(publisherIDs, request.PublisherID)
+	}
+	publishers, err := store.FindPublishers(publisherIDs)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	results := []jsonSubscriptionRequest{}
+	for _, request := range requests {
+		_, publisher := findPublisherInList(request.PublisherID, publishers)
+		results = append(results, newJSONSubscriptionRequest(request, publisher.Name))
+	}
+	response, err := json.Marshal(subscriptionRequestsResult{Success: true, Requests: results})
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	return response
+}
+
+//requests the client has made
+func handleGetSubscriptionRequests(id string, values url.Values, store storage.Store) []byte {
+	return listSubscriptionRequests(storage.SubscriptionRequestQuery{ClientID: id}, values, store)
+}
+
+//requests to subscribe to the client's publishers
+func handleGetIncomingSubscriptionRequests(id string, values url.Values, store storage.Store) []byte {
+	return listSubscriptionRequests(storage.SubscriptionRequestQuery{OwnerID: id}, values, store)
+}
+
+//requests to subscribe to one of the client's publishers
+func handleGetPublisherSubscriptionRequests(pubId string, ownerId string, values url.Values, store storage.Store) []byte {
+	owned, err := checkOwnsPublisher(pubId, ownerId, store)
+	if err != nil {
+		return createMessageResponse(false, "failed fetching subscription requests")
+	}
+	if !owned {
+		return createRefusalResponse(refusalNotFound, "publisher not found")
+	}
+	return listSubscriptionRequests(storage.SubscriptionRequestQuery{PublisherID: pubId}, values, store)
+}
+
+//approve or deny a pending request to subscribe to one of the client's publishers. an approved client is added to
+//the publisher's approved clients so changes to the allowlist don't take its subscription away
+func handleDecideSubscriptionRequest(requestId string, status string, body io.ReadCloser, ownerId string, store storage.Store) []byte {
+	failedMessage := "deciding subscription request failed"
+	bytes, err := readBody(body)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	decision := decideSubscriptionRequestRequest{}
+	if len(bytes) > 0 {
+		err = json.Unmarshal(bytes, &decision)
+		if err != nil {
+			return createMessageResponse(false, failedMessage)
+		}
+	}
+	request, err := store.FindSubscriptionRequest(requestId)
+	if errors.Is(err, storage.ErrNotFound) {
+		return createRefusalResponse(refusalNotFound, "subscription request not found")
+	}
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	publisher, err := findOwnedPublisher(request.PublisherID, ownerId, store)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	if publisher == nil {
+		return createRefusalResponse(refusalNotFound, "subscription request not found")
+	}
+
+	now := time.Now()
+	err = store.DecideSubscriptionRequest(requestId, status, decision.Reason, now)
+	if errors.Is(err, storage.ErrConflict) {
+		return createMessageResponse(false, "subscription request already "+request.Status)
+	}
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	request.Status = status
+	request.Reason = decision.Reason
+	request.DecidedAt = now
+
+	if status == storage.RequestApproved {
+		access := publisher.Access
+		access.Approved = uniqueIDs(append(access.Approved, request.ClientID))
+		err = store.SetPublisherAccess(publisher.ID, access)
+		if err == nil {
+			err = store.AddSubscription(request.ClientID, request.Subscription)
+		}
+		if err != nil && !errors.Is(err, storage.ErrConflict) {
+			fmt.Println(err)
+			return createMessageResponse(false, "request approved but adding the subscription failed")
+		}
+	}
+	return createSubscriptionRequestResponse(*request, publisher.Name, failedMessage)
+}
+
+//remove a client's subscriptions to one of the client's publishers, taking it off the approved clients and the
+//allowlist so it can't subscribe again unless the publisher is public or it's in one of the allowed groups
+func handleRemoveSubscriber(pubId string, clientId string, ownerId string, store storage.Store) []byte {
+	failedMessage := "removing subscriber failed"
+	publisher, err := findOwnedPublisher(pubId, ownerId, store)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	if publisher == nil {
+		return createRefusalResponse(refusalNotFound, "publisher not found")
+	}
+	client, err := store.FindClient(clientId)
+	if errors.Is(err, storage.ErrNotFound) {
+		return createRefusalResponse(refusalNotFound, "subscriber not found")
+	}
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	access := publisher.Access
+	access.Approved, _ = withoutID(access.Approved, clientId)
+	access.Clients, _ = withoutID(access.Clients, clientId)
+	err = store.SetPublisherAccess(pubId, access)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	removed := 0
+	for _, subscription := range client.Subscriptions {
+		if subscription.PublisherID != pubId {
+			continue
+		}
+		err = store.RemoveSubscription(clientId, subscription.ID)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return createMessageResponse(false, failedMessage)
+		}
+		removed++
+	}
+	if removed == 0 {
+		return createRefusalResponse(refusalNotFound, "subscriber not found")
+	}
+	return createMessageResponse(true, "subscriber removed")
+}
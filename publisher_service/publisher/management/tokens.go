@@ -0,0 +1,97 @@
+package management
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/auth"
+)
+
+type refreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type jsonTokens struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`         //seconds until the access token expires
+	RefreshExpiresIn int64  `json:"refresh_expires_in"` //seconds until the refresh token expires
+}
+
+type tokenResponse struct {
+	Success bool       `json:"success"`
+	Data    jsonTokens `json:"data"`
+}
+
+func createTokenResponse(pair *auth.TokenPair, now time.Time) []byte {
+	response, err := json.Marshal(tokenResponse{
+		Success: true,
+		Data: jsonTokens{
+			AccessToken:      pair.AccessToken,
+			RefreshToken:     pair.RefreshToken,
+			TokenType:        "Bearer",
+			ExpiresIn:        int64(pair.AccessExpiresAt.Sub(now).Seconds()),
+			RefreshExpiresIn: int64(pair.RefreshExpiresAt.Sub(now).Seconds()),
+		},
+	})
+	if err != nil {
+		return createMessageResponse(false, "failed issuing tokens")
+	}
+	return response
+}
+
+//issue a pair of tokens to a client logging in with its id and secret
+func handleIssueToken(body io.ReadCloser, store storage.Store, tokens *auth.TokenSigner) []byte {
+	failedMessage := "Authentication failed"
+	if tokens == nil {
+		return createMessageResponse(false, "tokens aren't enabled")
+	}
+	bytes, err := readBody(body)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	requestBody := authRequest{}
+	err = json.Unmarshal(bytes, &requestBody)
+	if err != nil {
+		fmt.Println(err)
+		return createMessageResponse(false, failedMessage)
+	}
+	client, err := authenticateClient(requestBody.UniqueId, requestBody.Secret, store)
+	if err != nil {
+		return createRefusalResponse(refusalUnauthenticated, failedMessage)
+	}
+	now := time.Now()
+	pair, err := tokens.Issue(client, now)
+	if err != nil {
+		fmt.Println(err)
+		return createMessageResponse(false, failedMessage)
+	}
+	return createTokenResponse(pair, now)
+}
+
+//exchange a refresh token for a new pair, refused once the client's secret has been rotated
+func handleRefreshToken(body io.ReadCloser, store storage.Store, tokens *auth.TokenSigner) []byte {
+	failedMessage := "refresh failed"
+	if tokens == nil {
+		return createMessageResponse(false, "tokens aren't enabled")
+	}
+	bytes, err := readBody(body)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	requestBody := refreshTokenRequest{}
+	err = json.Unmarshal(bytes, &requestBody)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	now := time.Now()
+	pair, err := tokens.Refresh(store, requestBody.RefreshToken, now)
+	if err != nil {
+		return createRefusalResponse(refusalUnauthenticated, failedMessage)
+	}
+	return createTokenResponse(pair, now)
+}
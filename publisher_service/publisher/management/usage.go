@@ -0,0 +1,101 @@
+package management
+
+import (
+	"encoding/json"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/limits"
+)
+
+type jsonUsage struct {
+	Messages    int64 `json:"messages"`
+	Bytes       int64 `json:"bytes"`
+	MaxMessages int64 `json:"max_messages"` //0 if there isn't a quota
+	MaxBytes    int64 `json:"max_bytes"`
+}
+
+type jsonPublisherUsage struct {
+	Id    string    `json:"id"`
+	Name  string    `json:"name"`
+	Usage jsonUsage `json:"usage"`
+}
+
+type publisherUsageResult struct {
+	Success bool      `json:"success"`
+	Usage   jsonUsage `json:"usage"`
+}
+
+type clientUsageResult struct {
+	Success    bool                 `json:"success"`
+	Usage      jsonUsage            `json:"usage"`
+	Publishers []jsonPublisherUsage `json:"publishers"`
+}
+
+func newJSONUsage(usage storage.Usage, maxMessages int, maxBytes int) jsonUsage {
+	return jsonUsage{
+		Messages:    usage.Messages,
+		Bytes:       usage.Bytes,
+		MaxMessages: int64(maxMessages),
+		MaxBytes:    int64(maxBytes),
+	}
+}
+
+//response to a message refused as it would go over a quota, sent with 507 Insufficient Storage
+func createQuotaResponse(exceeded *limits.QuotaError) []byte {
+	res, _ := json.Marshal(messageResponse{
+		Success: false,
+		Message: exceeded.Error(),
+		Quota:   exceeded.Quota,
+	})
+	return res
+}
+
+//messages stored for one of the client's publishers against the publisher's quota
+func handleGetPublisherUsage(pubId string, authId string, store storage.Store, quotas limits.Quotas) []byte {
+	failedMessage := "failed fetching usage"
+	publisher, err := findOwnedPublisher(pubId, authId, store)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	if publisher == nil {
+		return createRefusalResponse(refusalNotFound, "publisher not found")
+	}
+	usage, err := limits.PublisherUsage(store, publisher.ID)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	response, err := json.Marshal(publisherUsageResult{
+		Success: true,
+		Usage:   newJSONUsage(usage, quotas.PublisherMessages, quotas.PublisherBytes),
+	})
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	return response
+}
+
+//messages stored for all of the client's publishers against its quota, along with each publisher's usage
+func handleGetClientUsage(authId string, store storage.Store, quotas limits.Quotas) []byte {
+	failedMessage := "failed fetching usage"
+	total, publishers, usage, err := limits.ClientUsage(store, authId)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	result := clientUsageResult{
+		Success:    true,
+		Usage:      newJSONUsage(total, quotas.ClientMessages, quotas.ClientBytes),
+		Publishers: []jsonPublisherUsage{},
+	}
+	for _, publisher := range publishers {
+		result.Publishers = append(result.Publishers, jsonPublisherUsage{
+			Id:    publisher.ID,
+			Name:  publisher.Name,
+			Usage: newJSONUsage(usage[publisher.ID], quotas.PublisherMessages, quotas.PublisherBytes),
+		})
+	}
+	response, err := json.Marshal(result)
+	if err != nil {
+		return createMessageResponse(false, failedMessage)
+	}
+	return response
+}
@@ -0,0 +1,27 @@
+package management
+
+import "bezberr.com/messagebrokerstorage/auth"
+
+//messages stored for the client and its publishers against their quotas
+func usageRoutes() []route {
+	return []route{
+		{
+			RoutePattern: "/usage",
+			Method:       "GET",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleGetClientUsage(rd.AuthID, rd.Store, rd.Quotas)}
+			},
+		},
+		{
+			RoutePattern: "/publishers/{publisher_id}/usage",
+			Method:       "GET",
+			Authenticate: true,
+			KeyScope:     auth.ScopeManage,
+			Func: func(rd routeData, c chan routeResponse) {
+				c <- routeResponse{Body: handleGetPublisherUsage(rd.DynamicParams["publisher_id"], rd.AuthID, rd.Store, rd.Quotas)}
+			},
+		},
+	}
+}
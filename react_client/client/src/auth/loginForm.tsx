@@ -10,6 +10,7 @@ const LoginForm = (props:loginFormProps) => {
 
     
     const [loginId, setLoginId] = React.useState("");
+    const [loginSecret, setLoginSecret] = React.useState("");
 
     const attemptLogin = async () => {
         const id = loginId;
@@ -17,7 +18,8 @@ const LoginForm = (props:loginFormProps) => {
                         .setRoute("/auth")
                         .setMethod("POST")
                         .setData({
-                            id: id
+                            id: id,
+                            secret: loginSecret
                         })
                         .send();
         return res;
@@ -27,7 +29,7 @@ const LoginForm = (props:loginFormProps) => {
         event.preventDefault();
         attemptLogin().then((res)=>{
             if(res.success) {
-                props.onLoggedIn(res.data)
+                props.onLoggedIn({...res.data, secret: loginSecret})
             }
         }).catch(err=>{
             console.log(err);
@@ -47,6 +49,14 @@ const LoginForm = (props:loginFormProps) => {
                             }}></input>
                         </div>
                     </div>
+                    <div className="field">
+                        <label className="label is-small" htmlFor="login_secret_input">Secret</label>
+                        <div className="control">
+                            <input className="input is-small" id="login_secret_input" type="password" onChange={(e)=>{
+                                setLoginSecret(e.target.value);
+                            }}></input>
+                        </div>
+                    </div>
                     <div className="control">
                         <button className="button is-primary" type="submit">Login</button>
                     </div>
@@ -18,6 +18,7 @@ const RegisterForm = (props:registerFormProps) => {
 
     const [statusMessage,setStatusMessage] = useState<statusMessage|null>(null)
     const [isLoading,setIsLoading] = useState(false);
+    const [registered,setRegistered] = useState<authedUser|null>(null);
     let form = document.getElementById("register_form") as HTMLFormElement;
     useEffect(()=>{
         form = document.getElementById("register_form") as HTMLFormElement
@@ -40,9 +41,11 @@ const RegisterForm = (props:registerFormProps) => {
         if(!registerResult) return;
 
         if(registerResult.success) {
-            props.onRegistered({
+            //the secret can't be shown again so it's displayed until the user continues
+            setRegistered({
                 id: registerResult.row.id,
-                name: data.name
+                name: data.name,
+                secret: registerResult.row.secret
             });
         } else {
             setStatusMessage({
@@ -106,6 +109,23 @@ const RegisterForm = (props:registerFormProps) => {
         return classes.join(" ");
     }
 
+    if(registered) {
+        return (
+            <section className="section">
+                <div className="container">
+                    <h2 className="title is-3">Registered</h2>
+                    <div className="notification is-warning">
+                        <p>Your ID is <strong>{registered.id}</strong> and your secret is <strong>{registered.secret}</strong></p>
+                        <p>Keep the secret somewhere safe, it's needed to log in again and can't be shown later.</p>
+                    </div>
+                    <div className="control">
+                        <button className="button is-primary" onClick={()=>props.onRegistered(registered)}>Continue</button>
+                    </div>
+                </div>
+            </section>
+        )
+    }
+
     return (
         <section className="section">
             <div className="container">
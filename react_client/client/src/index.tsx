@@ -10,6 +10,7 @@ import { appPage, authedUser } from "./types/types";
 import MessageFeed from "./message-feed/messageFeed";
 import RegisterForm from "./auth/registerForm";
 import SubscriptionManager from "./subscription/subscriptionManager";
+import { storeTokens } from "./modules/tokens";
 
 
 const appPages:appPage[] = [
@@ -51,7 +52,8 @@ const App = () =>{
                 setIsAuthed(true);
                 setAuthedUser({
                     id: r.data.id,
-                    name: r.data.name
+                    name: r.data.name,
+                    secret: sessionStorage.getItem("client_secret") ?? undefined
                 });
             } else {
                 setIsAuthed(false);
@@ -68,6 +70,20 @@ const App = () =>{
 
     
     const onLoggedIn = (authedUser:authedUser) => {
+        //the message feed authenticates with the broker using a token, or the secret kept for the tab if the
+        //publisher service doesn't issue tokens
+        const secret = authedUser.secret;
+        if(secret) {
+            storeTokens(authedUser.id, secret).then((issued)=>{
+                if(issued) {
+                    sessionStorage.removeItem("client_secret");
+                } else {
+                    sessionStorage.setItem("client_secret", secret);
+                }
+            }).catch(err=>{
+                console.log(err);
+            });
+        }
         setAuthedUser(authedUser);
         setIsAuthed(true);
     }
@@ -1,5 +1,6 @@
 import React, { useEffect, useReducer, useState } from "react";
 import { authedUser } from "../types/types";
+import { accessToken } from "../modules/tokens";
 
 type messageFeedProps = {
     authedUser: authedUser
@@ -92,8 +93,13 @@ const MessageFeed = (props:messageFeedProps) => {
         let newMessageList:message[];
         switch(content.action) {
             case "authenticate":
-                send({
-                    id: props.authedUser.id
+                accessToken().then((token)=>{
+                    send(token ? {token: token} : {
+                        id: props.authedUser.id,
+                        secret: props.authedUser.secret
+                    });
+                }).catch(err=>{
+                    console.log(err);
                 });
                 break;
             case "authentication_successful":
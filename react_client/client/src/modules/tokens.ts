@@ -0,0 +1,38 @@
+import APIRequest from "./APIRequest";
+
+//kept for the tab instead of the secret when the publisher service issues tokens
+const refreshTokenKey = "refresh_token";
+
+//log in for tokens, false if the publisher service doesn't issue them
+export const storeTokens = async (id:string, secret:string) => {
+    const res = await (new APIRequest)
+        .setRoute("/auth/token")
+        .setMethod("POST")
+        .setData({
+            id: id,
+            secret: secret
+        })
+        .send();
+    if(!res.success) return false;
+    sessionStorage.setItem(refreshTokenKey, res.data.refresh_token);
+    return true;
+}
+
+//swap the stored refresh token for an access token to connect to the broker with, null if there isn't one or it has stopped working
+export const accessToken = async () => {
+    const refreshToken = sessionStorage.getItem(refreshTokenKey);
+    if(!refreshToken) return null;
+    const res = await (new APIRequest)
+        .setRoute("/auth/token/refresh")
+        .setMethod("POST")
+        .setData({
+            refresh_token: refreshToken
+        })
+        .send();
+    if(!res.success) {
+        sessionStorage.removeItem(refreshTokenKey);
+        return null;
+    }
+    sessionStorage.setItem(refreshTokenKey, res.data.refresh_token);
+    return res.data.access_token as string;
+}
@@ -11,6 +11,7 @@ export type appPage = {
 export type authedUser = {
     id: string
     name: string
+    secret?: string
 }
 
 export type statusMessage = {
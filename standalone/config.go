@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+
+	"bezberr.com/messagebroker/broker"
+	config "bezberr.com/messagebrokerconfig"
+	"bezberr.com/messagebrokerpublisherservice/management"
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/limits"
+)
+
+const envPrefix = "MESSAGE_BROKER_STANDALONE"
+
+//settings of the message broker and publisher service running together, see config.Load for where they're read
+//from. the secrets and limits are shared by both
+type standaloneConfig struct {
+	Broker           brokerConfig           `config:"broker"`
+	PublisherService serviceConfig          `config:"publisher_service"`
+	AllowedOrigin    string                 `config:"allowed_origin" flag:"allowed-origin" usage:"origin of the web frontend allowed to call both services from a browser"`
+	TokenSecret      string                 `config:"token_secret" flag:"token-secret" secret:"true" usage:"key access and refresh tokens are signed with, tokens aren't issued or accepted if empty"`
+	RateLimits       limits.RateLimitConfig `config:"rate_limits"`
+	Quotas           limits.Quotas          `config:"quotas"`
+	Payloads         limits.PayloadLimits   `config:"payloads"`
+	Storage          storage.Config         `config:"storage"`
+}
+
+type brokerConfig struct {
+	HTTPAddress     string `config:"http_address" flag:"broker-http-address" usage:"address the message broker accepts websocket and HTTP connections on"`
+	GRPCAddress     string `config:"grpc_address" flag:"broker-grpc-address" usage:"address the message broker accepts gRPC streams on, empty to disable gRPC"`
+	MQTTAddress     string `config:"mqtt_address" flag:"broker-mqtt-address" usage:"address to accept MQTT connections on, empty to disable MQTT"`
+	PrivateWebhooks bool   `config:"private_webhooks" flag:"private-webhooks" usage:"deliver to webhooks on loopback, private and link-local addresses, for local development"`
+}
+
+type serviceConfig struct {
+	HTTPAddress   string `config:"http_address" flag:"service-http-address" usage:"address the publisher service serves the REST API on"`
+	GRPCAddress   string `config:"grpc_address" flag:"service-grpc-address" usage:"address the publisher service serves the gRPC API on, empty to disable gRPC"`
+	SessionSecret string `config:"session_secret" flag:"session-secret" secret:"true" usage:"key the session cookies are signed with, a random one is generated each time the service starts if empty"`
+	AdminSecret   string `config:"admin_secret" flag:"admin-secret" secret:"true" usage:"secret sent in the X-Admin-Secret header to call the admin routes, they're disabled if empty"`
+}
+
+//keeps everything in memory unless told otherwise, as running both in one process is mostly for development
+func defaultConfig() *standaloneConfig {
+	storageConfig := storage.DefaultConfig()
+	storageConfig.Backend = storage.BackendMemory
+	return &standaloneConfig{
+		Broker: brokerConfig{
+			HTTPAddress: broker.DefaultHTTPAddress,
+			GRPCAddress: broker.DefaultGRPCAddress,
+			MQTTAddress: broker.DefaultMQTTAddress,
+		},
+		PublisherService: serviceConfig{
+			HTTPAddress: management.DefaultHTTPAddress,
+			GRPCAddress: management.DefaultGRPCAddress,
+		},
+		AllowedOrigin: management.DefaultAllowedOrigin,
+		Payloads:      limits.DefaultPayloadLimits(),
+		Storage:       storageConfig,
+	}
+}
+
+func (standaloneConfig *standaloneConfig) Validate() error {
+	addresses := map[string]string{
+		"broker.http_address":            standaloneConfig.Broker.HTTPAddress,
+		"broker.grpc_address":            standaloneConfig.Broker.GRPCAddress,
+		"broker.mqtt_address":            standaloneConfig.Broker.MQTTAddress,
+		"publisher_service.http_address": standaloneConfig.PublisherService.HTTPAddress,
+		"publisher_service.grpc_address": standaloneConfig.PublisherService.GRPCAddress,
+	}
+	for name, address := range addresses {
+		if address == "" && name != "broker.http_address" && name != "publisher_service.http_address" {
+			continue
+		}
+		err := config.ValidateAddress(name, address)
+		if err != nil {
+			return err
+		}
+	}
+	if standaloneConfig.AllowedOrigin == "" {
+		return errors.New("allowed_origin is required")
+	}
+	err := standaloneConfig.RateLimits.Validate()
+	if err == nil {
+		err = standaloneConfig.Quotas.Validate()
+	}
+	if err == nil {
+		err = standaloneConfig.Payloads.Validate()
+	}
+	if err != nil {
+		return err
+	}
+	return standaloneConfig.Storage.Validate()
+}
+
+//options for the message broker set by the config
+func (standaloneConfig *standaloneConfig) brokerOptions() []broker.Option {
+	return []broker.Option{
+		broker.WithHTTPAddress(standaloneConfig.Broker.HTTPAddress),
+		broker.WithGRPCAddress(standaloneConfig.Broker.GRPCAddress),
+		broker.WithMQTTAddress(standaloneConfig.Broker.MQTTAddress),
+		broker.WithAllowedOrigin(standaloneConfig.AllowedOrigin),
+		broker.WithPrivateWebhooks(standaloneConfig.Broker.PrivateWebhooks),
+		broker.WithTokenSecret([]byte(standaloneConfig.TokenSecret)),
+		broker.WithRateLimits(standaloneConfig.RateLimits),
+		broker.WithQuotas(standaloneConfig.Quotas),
+		broker.WithPayloadLimits(standaloneConfig.Payloads),
+	}
+}
+
+//options for the publisher service set by the config
+func (standaloneConfig *standaloneConfig) serviceOptions() []management.Option {
+	return []management.Option{
+		management.WithHTTPAddress(standaloneConfig.PublisherService.HTTPAddress),
+		management.WithGRPCAddress(standaloneConfig.PublisherService.GRPCAddress),
+		management.WithSessionSecret([]byte(standaloneConfig.PublisherService.SessionSecret)),
+		management.WithAllowedOrigin(standaloneConfig.AllowedOrigin),
+		management.WithTokenSecret([]byte(standaloneConfig.TokenSecret)),
+		management.WithAdminSecret([]byte(standaloneConfig.PublisherService.AdminSecret)),
+		management.WithRateLimits(standaloneConfig.RateLimits),
+		management.WithQuotas(standaloneConfig.Quotas),
+		management.WithPayloadLimits(standaloneConfig.Payloads),
+	}
+}
@@ -0,0 +1,80 @@
+//Command standalone runs the message broker and the publisher service in a single process sharing one store,
+//so the memory and disk storage backends can be used by both. it's meant for development, tests and small
+//deployments which don't need the services to scale separately
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"bezberr.com/messagebroker/broker"
+	config "bezberr.com/messagebrokerconfig"
+	"bezberr.com/messagebrokerpublisherservice/management"
+	storage "bezberr.com/messagebrokerstorage"
+)
+
+func main() {
+	standaloneConfig := defaultConfig()
+	err := config.Load(standaloneConfig, config.Options{Name: "message-broker-standalone", EnvPrefix: envPrefix})
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	} else if err != nil {
+		log.Fatalf("Failed loading config, %s", err.Error())
+	}
+	fmt.Println("Config:")
+	config.Print(os.Stdout, standaloneConfig)
+
+	store, err := storage.Open(standaloneConfig.Storage)
+	if err != nil {
+		log.Fatalf("Failed opening storage, %s", err.Error())
+	}
+	api, err := management.New(append(standaloneConfig.serviceOptions(), management.WithStore(store))...)
+	if err != nil {
+		log.Fatalf("Failed creating the publisher service, %s", err.Error())
+	}
+	messageBroker, err := broker.New(append(standaloneConfig.brokerOptions(), broker.WithStore(store))...)
+	if err != nil {
+		log.Fatalf("Failed creating the broker, %s", err.Error())
+	}
+	err = api.Start(context.Background())
+	if err != nil {
+		log.Fatalf("Failed starting the publisher service, %s", err.Error())
+	}
+	err = messageBroker.Start(context.Background())
+	if err != nil {
+		api.Shutdown(context.Background())
+		log.Fatalf("Failed starting the broker, %s", err.Error())
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	//wait for SIGINT or SIGTERM
+	<-stop
+
+	//stop taking new messages first, then drain the broker's sessions
+	ctx, cancel := context.WithTimeout(context.Background(), 40*time.Second)
+	defer cancel()
+	err = api.Shutdown(ctx)
+	if err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println("Draining")
+	err = messageBroker.Shutdown(ctx)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	err = store.Close()
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	fmt.Println("Close")
+}
@@ -0,0 +1,107 @@
+package storage
+
+import "errors"
+
+const (
+	VisibilityPublic    = "public"    //any client which can see the publisher can subscribe, the default
+	VisibilityPrivate   = "private"   //only the owner and clients whose subscription requests it approved
+	VisibilityAllowlist = "allowlist" //as private, along with the clients and groups on the publisher's allowlist
+)
+
+//Access decides which clients can subscribe to a publisher
+type Access struct {
+	Visibility string   //VisibilityPublic, VisibilityPrivate or VisibilityAllowlist, empty is public
+	Clients    []string //ids of the clients on the allowlist
+	Groups     []string //ids of the owner's groups on the allowlist
+	Approved   []string //ids of the clients whose subscription requests the owner approved, whatever the visibility
+	//ids of the organizations the publisher is shared with, their members are treated as the visibility says as
+	//though they were in the publisher's organization
+	Organizations []string
+}
+
+//Group of clients a client defines so they can be put on its publishers' allowlists together
+type Group struct {
+	ID      string
+	OwnerID string
+	Name    string
+	Members []string //ids of the clients in the group
+}
+
+//GroupStore keeps the groups of clients
+type GroupStore interface {
+	//CreateGroup for a client, returning ErrConflict if the client already has a group with the name
+	CreateGroup(ownerID string, name string, members []string) (*Group, error)
+	//FindGroup returns ErrNotFound if there isn't one
+	FindGroup(id string) (*Group, error)
+	//ListGroups owned by a client
+	ListGroups(ownerID string) ([]Group, error)
+	//SetGroupMembers replaces the members of a group, returning ErrNotFound if there isn't one
+	SetGroupMembers(id string, members []string) error
+	//DeleteGroup returns ErrNotFound if there isn't one
+	DeleteGroup(id string) error
+}
+
+//ValidateVisibility checks the visibility is one of the known ones
+func ValidateVisibility(visibility string) error {
+	switch visibility {
+	case "", VisibilityPublic, VisibilityPrivate, VisibilityAllowlist:
+		return nil
+	}
+	return errors.New("visibility " + visibility + " should be public, private or allowlist")
+}
+
+//Allows reports whether the client can subscribe to the publisher. it never can if the publisher isn't visible to
+//it. groups on the allowlist which have gone or belong to someone other than the publisher's owner are ignored
+func (publisher *Publisher) Allows(client *Client, groups GroupStore) (bool, error) {
+	clientID := client.ID
+	if clientID == publisher.OwnerID {
+		return true, nil
+	}
+	if !publisher.VisibleTo(client) {
+		return false, nil
+	}
+	if publisher.Access.Visibility == "" || publisher.Access.Visibility == VisibilityPublic {
+		return true, nil
+	}
+	for _, id := range publisher.Access.Approved {
+		if id == clientID {
+			return true, nil
+		}
+	}
+	if publisher.Access.Visibility != VisibilityAllowlist {
+		return false, nil
+	}
+	for _, id := range publisher.Access.Clients {
+		if id == clientID {
+			return true, nil
+		}
+	}
+	for _, id := range publisher.Access.Groups {
+		group, err := groups.FindGroup(id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if group.OwnerID != publisher.OwnerID {
+			continue
+		}
+		for _, member := range group.Members {
+			if member == clientID {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func copyAccess(access Access) Access {
+	return Access{
+		Visibility:    access.Visibility,
+		Clients:       append([]string{}, access.Clients...),
+		Groups:        append([]string{}, access.Groups...),
+		Approved:      append([]string{}, access.Approved...),
+		Organizations: append([]string{}, access.Organizations...),
+	}
+}
@@ -0,0 +1,40 @@
+package storage
+
+import "time"
+
+//APIKey lets a service act as a client with a limited set of scopes, rather than with the client's secret
+type APIKey struct {
+	ID         string
+	ClientID   string
+	Name       string
+	SecretHash string //from auth.HashSecret
+	Scopes     []string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time //zero if the key doesn't expire
+}
+
+//APIKeyStore keeps the API keys of clients
+type APIKeyStore interface {
+	CreateAPIKey(key APIKey) error
+	//FindAPIKey returns ErrNotFound if there isn't one
+	FindAPIKey(id string) (*APIKey, error)
+	//ListAPIKeys of a client, oldest first
+	ListAPIKeys(clientID string) ([]APIKey, error)
+	//DeleteAPIKey returns ErrNotFound if the client doesn't have the key
+	DeleteAPIKey(clientID string, id string) error
+}
+
+//HasScope reports whether the key was granted the scope, e.g. publish:<publisher id>, see auth.ValidateScope
+func (key *APIKey) HasScope(scope string) bool {
+	for _, granted := range key.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+//Expired reports whether the key has passed its expiry
+func (key *APIKey) Expired(now time.Time) bool {
+	return !key.ExpiresAt.IsZero() && !now.Before(key.ExpiresAt)
+}
@@ -0,0 +1,52 @@
+//Package auth checks the credentials clients present to the message broker and the publisher service, against
+//what's kept for them in storage.
+package auth
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+)
+
+const (
+	ScopeManage          = "manage"     //manage the client's publishers and subscriptions
+	ScopePublishPrefix   = "publish:"   //followed by the id of a publisher the key can publish to
+	ScopeSubscribePrefix = "subscribe:" //followed by the id of a subscription the key can consume
+)
+
+//ValidateScope checks a scope is manage, publish:<publisher id> or subscribe:<subscription id>
+func ValidateScope(scope string) error {
+	if scope == ScopeManage {
+		return nil
+	}
+	for _, prefix := range []string{ScopePublishPrefix, ScopeSubscribePrefix} {
+		if strings.HasPrefix(scope, prefix) && len(scope) > len(prefix) {
+			return nil
+		}
+	}
+	return errors.New("scope " + scope + " should be manage, publish:<publisher id> or subscribe:<subscription id>")
+}
+
+//APIKeyToken is what the holder of a key presents, the key's id and secret joined by a dot
+func APIKeyToken(id string, secret string) string {
+	return id + "." + secret
+}
+
+//AuthenticateAPIKey finds the key presented as a token from APIKeyToken, returning storage.ErrNotFound if the token
+//is malformed, the secret is wrong or the key has expired
+func AuthenticateAPIKey(store storage.APIKeyStore, token string, now time.Time) (*storage.APIKey, error) {
+	id, secret, found := strings.Cut(token, ".")
+	if !found || id == "" {
+		return nil, storage.ErrNotFound
+	}
+	key, err := store.FindAPIKey(id)
+	if err != nil {
+		return nil, err
+	}
+	if !CheckSecret(key.SecretHash, secret) || key.Expired(now) {
+		return nil, storage.ErrNotFound
+	}
+	return key, nil
+}
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	secretBytes = 32
+	saltBytes   = 16
+
+	secretHashScheme = "sha256"
+)
+
+//NewSecret generates a random client secret, only its hash should be kept
+func NewSecret() (string, error) {
+	secret := make([]byte, secretBytes)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+//HashSecret salts and hashes a secret for storing, as sha256$<salt>$<hash>. secrets are random rather than picked
+//by people so a fast hash is enough, the salt stops two hashes being compared
+func HashSecret(secret string) (string, error) {
+	salt := make([]byte, saltBytes)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", err
+	}
+	return secretHashScheme + "$" + hex.EncodeToString(salt) + "$" + hex.EncodeToString(saltedHash(salt, secret)), nil
+}
+
+func saltedHash(salt []byte, secret string) []byte {
+	hash := sha256.New()
+	hash.Write(salt)
+	hash.Write([]byte(secret))
+	return hash.Sum(nil)
+}
+
+//CheckSecret reports whether secret matches a hash from HashSecret. an empty hash, e.g. of a client registered
+//before secrets were issued, matches nothing
+func CheckSecret(secretHash string, secret string) bool {
+	parts := strings.Split(secretHash, "$")
+	if len(parts) != 3 || parts[0] != secretHashScheme || secret == "" {
+		return false
+	}
+	salt, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+	expected, err := hex.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(saltedHash(salt, secret), expected) == 1
+}
+
+//SecretVersion fingerprints a client's secret hash, tokens and sessions carry it so rotating the secret stops
+//them working. empty for a client without a secret
+func SecretVersion(secretHash string) string {
+	if secretHash == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(secretHash))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+)
+
+const (
+	TokenAccess  = "access"  //authenticates requests to either service
+	TokenRefresh = "refresh" //only exchanges for a new pair of tokens
+
+	DefaultAccessTokenTTL  = 15 * time.Minute
+	DefaultRefreshTokenTTL = 7 * 24 * time.Hour
+)
+
+//header of every token, they're JWTs signed with HMAC-SHA256
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+//TokenClaims are the contents of a bearer token
+type TokenClaims struct {
+	Subject       string `json:"sub"` //id of the client
+	Type          string `json:"typ"` //TokenAccess or TokenRefresh
+	IssuedAt      int64  `json:"iat"`
+	ExpiresAt     int64  `json:"exp"`
+	SecretVersion string `json:"sv,omitempty"` //see SecretVersion
+}
+
+//TokenPair is what a client gets when it logs in or refreshes
+type TokenPair struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+//TokenSigner issues and checks the bearer tokens shared by the publisher service and the message broker. the
+//tokens are checked without a lookup so both services only need the same secret
+type TokenSigner struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+//NewTokenSigner with the secret the tokens are signed with, zero TTLs use the defaults
+func NewTokenSigner(secret []byte, accessTTL time.Duration, refreshTTL time.Duration) *TokenSigner {
+	if accessTTL <= 0 {
+		accessTTL = DefaultAccessTokenTTL
+	}
+	if refreshTTL <= 0 {
+		refreshTTL = DefaultRefreshTokenTTL
+	}
+	return &TokenSigner{secret: secret, accessTTL: accessTTL, refreshTTL: refreshTTL}
+}
+
+//Issue a pair of tokens for the client
+func (signer *TokenSigner) Issue(client *storage.Client, now time.Time) (*TokenPair, error) {
+	pair := TokenPair{
+		AccessExpiresAt:  now.Add(signer.accessTTL),
+		RefreshExpiresAt: now.Add(signer.refreshTTL),
+	}
+	var err error
+	pair.AccessToken, err = signer.sign(TokenClaims{
+		Subject:       client.ID,
+		Type:          TokenAccess,
+		IssuedAt:      now.Unix(),
+		ExpiresAt:     pair.AccessExpiresAt.Unix(),
+		SecretVersion: SecretVersion(client.SecretHash),
+	})
+	if err != nil {
+		return nil, err
+	}
+	pair.RefreshToken, err = signer.sign(TokenClaims{
+		Subject:       client.ID,
+		Type:          TokenRefresh,
+		IssuedAt:      now.Unix(),
+		ExpiresAt:     pair.RefreshExpiresAt.Unix(),
+		SecretVersion: SecretVersion(client.SecretHash),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pair, nil
+}
+
+//Refresh exchanges a refresh token for a new pair, returning storage.ErrNotFound if the token isn't valid, the
+//client has gone or its secret has been rotated since the token was issued
+func (signer *TokenSigner) Refresh(store storage.Store, refreshToken string, now time.Time) (*TokenPair, error) {
+	client, err := signer.client(store, refreshToken, TokenRefresh, now)
+	if err != nil {
+		return nil, err
+	}
+	return signer.Issue(client, now)
+}
+
+//Authenticate returns the client an access token was issued to, or storage.ErrNotFound if the token isn't valid,
+//the client has gone or its secret has been rotated since the token was issued
+func (signer *TokenSigner) Authenticate(store storage.Store, accessToken string, now time.Time) (*storage.Client, error) {
+	return signer.client(store, accessToken, TokenAccess, now)
+}
+
+//client a token was issued to, as long as its secret hasn't been rotated since
+func (signer *TokenSigner) client(store storage.Store, token string, tokenType string, now time.Time) (*storage.Client, error) {
+	claims, err := signer.Verify(token, tokenType, now)
+	if err != nil {
+		return nil, err
+	}
+	client, err := store.FindClient(claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if claims.SecretVersion != SecretVersion(client.SecretHash) {
+		return nil, storage.ErrNotFound
+	}
+	return client, nil
+}
+
+func (signer *TokenSigner) sign(claims TokenClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	unsigned := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signer.signature(unsigned)), nil
+}
+
+func (signer *TokenSigner) signature(unsigned string) []byte {
+	mac := hmac.New(sha256.New, signer.secret)
+	mac.Write([]byte(unsigned))
+	return mac.Sum(nil)
+}
+
+//Verify checks a token's signature, type and expiry, returning storage.ErrNotFound if any of them are wrong
+func (signer *TokenSigner) Verify(token string, tokenType string, now time.Time) (*TokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != tokenHeader {
+		return nil, storage.ErrNotFound
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || subtle.ConstantTimeCompare(signature, signer.signature(parts[0]+"."+parts[1])) != 1 {
+		return nil, storage.ErrNotFound
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, storage.ErrNotFound
+	}
+	claims := TokenClaims{}
+	err = json.Unmarshal(payload, &claims)
+	if err != nil || claims.Type != tokenType || claims.Subject == "" || now.Unix() >= claims.ExpiresAt {
+		return nil, storage.ErrNotFound
+	}
+	return &claims, nil
+}
+
+//BearerToken takes the token from an Authorization header, empty if it isn't a bearer token
+func BearerToken(header string) string {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
@@ -0,0 +1,819 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultDataDir = "data"
+
+	clientKeyPrefix    = "client/"
+	publisherKeyPrefix = "publisher/"
+	apiKeyKeyPrefix    = "apikey/"
+	groupKeyPrefix     = "group/"
+	requestKeyPrefix   = "request/"
+	orgKeyPrefix       = "organization/"
+	schemaKeyPrefix    = "schema/"
+)
+
+//Disk keeps everything in files under a data directory, for running without a database. clients, publishers and
+//subscriptions are kept in a key value store and each publisher's messages in a log of their own, see messageLog.
+//
+//only one process can use a data directory at a time. the cluster's leases and the leases on messages handed out
+//to clients are only kept in memory, so after a restart messages which weren't confirmed are delivered again
+type Disk struct {
+	lock     sync.Mutex
+	dir      string
+	lockFile *os.File
+	memory   *Memory //clients, organizations, API keys, publishers, groups, subscription requests, schemas and the cluster's leases
+	kv       *kvStore
+	logs     map[string]*messageLog //publisher id to its messages
+}
+
+func clientKey(id string) string {
+	return clientKeyPrefix + id
+}
+
+func publisherKey(id string) string {
+	return publisherKeyPrefix + id
+}
+
+func apiKeyKey(id string) string {
+	return apiKeyKeyPrefix + id
+}
+
+func groupKey(id string) string {
+	return groupKeyPrefix + id
+}
+
+func requestKey(id string) string {
+	return requestKeyPrefix + id
+}
+
+func orgKey(id string) string {
+	return orgKeyPrefix + id
+}
+
+func schemaKey(publisherID string, version int) string {
+	return schemaKeyPrefix + publisherID + "/" + strconv.Itoa(version)
+}
+
+func (store *Disk) messagesDir() string {
+	return filepath.Join(store.dir, "messages")
+}
+
+//OpenDisk opens the store in dir, creating it if it doesn't exist. anything left half written by a process which
+//stopped part way through a write is discarded
+func OpenDisk(dir string) (*Disk, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	lockFile, err := lockDirectory(filepath.Join(dir, "LOCK"))
+	if err != nil {
+		return nil, err
+	}
+	store := &Disk{dir: dir, lockFile: lockFile, memory: NewMemory(), logs: make(map[string]*messageLog)}
+	err = store.load()
+	if err != nil {
+		store.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func (store *Disk) load() error {
+	var err error
+	store.kv, err = openKVStore(filepath.Join(store.dir, "store.kv"))
+	if err != nil {
+		return err
+	}
+	err = store.kv.each(clientKeyPrefix, func(value []byte) error {
+		client := Client{}
+		err := json.Unmarshal(value, &client)
+		if err == nil {
+			store.memory.putClient(&client)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	err = store.kv.each(orgKeyPrefix, func(value []byte) error {
+		organization := Organization{}
+		err := json.Unmarshal(value, &organization)
+		if err == nil {
+			store.memory.putOrganization(organization)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	err = store.kv.each(publisherKeyPrefix, func(value []byte) error {
+		publisher := Publisher{}
+		err := json.Unmarshal(value, &publisher)
+		if err == nil {
+			store.memory.putPublisher(publisher)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	err = store.kv.each(apiKeyKeyPrefix, func(value []byte) error {
+		key := APIKey{}
+		err := json.Unmarshal(value, &key)
+		if err == nil {
+			store.memory.putAPIKey(key)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	err = store.kv.each(groupKeyPrefix, func(value []byte) error {
+		group := Group{}
+		err := json.Unmarshal(value, &group)
+		if err == nil {
+			store.memory.putGroup(group)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	err = store.kv.each(requestKeyPrefix, func(value []byte) error {
+		request := SubscriptionRequest{}
+		err := json.Unmarshal(value, &request)
+		if err == nil {
+			store.memory.putRequest(request)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	err = store.kv.each(schemaKeyPrefix, func(value []byte) error {
+		schema := Schema{}
+		err := json.Unmarshal(value, &schema)
+		if err == nil {
+			store.memory.putSchema(schema)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(store.messagesDir(), 0755)
+	if err != nil {
+		return err
+	}
+	items, err := os.ReadDir(store.messagesDir())
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		dir := filepath.Join(store.messagesDir(), item.Name())
+		if _, err := store.memory.FindPublisher(item.Name()); err != nil {
+			//the publisher was deleted but the process stopped before its messages were
+			os.RemoveAll(dir)
+			continue
+		}
+		log, err := openMessageLog(dir)
+		if err != nil {
+			return err
+		}
+		store.logs[item.Name()] = log
+	}
+	return nil
+}
+
+//log of a publisher's messages, opening it if it doesn't have one yet
+func (store *Disk) messageLog(publisherID string) (*messageLog, error) {
+	if log := store.logs[publisherID]; log != nil {
+		return log, nil
+	}
+	if publisherID == "" || strings.ContainsAny(publisherID, `/\.`) {
+		return nil, ErrNotFound
+	}
+	log, err := openMessageLog(filepath.Join(store.messagesDir(), publisherID))
+	if err != nil {
+		return nil, err
+	}
+	store.logs[publisherID] = log
+	return log, nil
+}
+
+//apply a change to a client and write the result, putting the client back the way it was if it can't be written
+func (store *Disk) updateClient(clientID string, update func() error) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	previous, err := store.memory.FindClient(clientID)
+	if err != nil {
+		return err
+	}
+	err = update()
+	if err != nil {
+		return err
+	}
+	client, err := store.memory.FindClient(clientID)
+	if err == nil {
+		err = store.kv.put(clientKey(clientID), client)
+	}
+	if err != nil {
+		store.memory.putClient(previous)
+	}
+	return err
+}
+
+func (store *Disk) CreateClient(organizationID string, name string, secretHash string) (*Client, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	client, err := store.memory.CreateClient(organizationID, name, secretHash)
+	if err != nil {
+		return nil, err
+	}
+	err = store.kv.put(clientKey(client.ID), client)
+	if err != nil {
+		store.memory.removeClient(client.ID)
+		return nil, err
+	}
+	return client, nil
+}
+
+func (store *Disk) FindClient(id string) (*Client, error) {
+	return store.memory.FindClient(id)
+}
+
+func (store *Disk) SetClientSecret(clientID string, secretHash string) error {
+	return store.updateClient(clientID, func() error {
+		return store.memory.SetClientSecret(clientID, secretHash)
+	})
+}
+
+func (store *Disk) SetClientRateLimits(clientID string, limits *RateLimits) error {
+	return store.updateClient(clientID, func() error {
+		return store.memory.SetClientRateLimits(clientID, limits)
+	})
+}
+
+func (store *Disk) AddSubscription(clientID string, subscription Subscription) error {
+	return store.updateClient(clientID, func() error {
+		return store.memory.AddSubscription(clientID, subscription)
+	})
+}
+
+func (store *Disk) RemoveSubscription(clientID string, subscriptionID string) error {
+	return store.updateClient(clientID, func() error {
+		return store.memory.RemoveSubscription(clientID, subscriptionID)
+	})
+}
+
+func (store *Disk) ListWebhooks() ([]WebhookSubscription, error) {
+	return store.memory.ListWebhooks()
+}
+
+func (store *Disk) RecordWebhookDelivery(clientID string, subscriptionID string, delivery WebhookDelivery) error {
+	return store.updateClient(clientID, func() error {
+		return store.memory.RecordWebhookDelivery(clientID, subscriptionID, delivery)
+	})
+}
+
+func (store *Disk) CreateAPIKey(key APIKey) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	err := store.memory.CreateAPIKey(key)
+	if err != nil {
+		return err
+	}
+	err = store.kv.put(apiKeyKey(key.ID), key)
+	if err != nil {
+		store.memory.DeleteAPIKey(key.ClientID, key.ID)
+	}
+	return err
+}
+
+func (store *Disk) FindAPIKey(id string) (*APIKey, error) {
+	return store.memory.FindAPIKey(id)
+}
+
+func (store *Disk) ListAPIKeys(clientID string) ([]APIKey, error) {
+	return store.memory.ListAPIKeys(clientID)
+}
+
+func (store *Disk) DeleteAPIKey(clientID string, id string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	key, err := store.memory.FindAPIKey(id)
+	if err != nil {
+		return err
+	}
+	if key.ClientID != clientID {
+		return ErrNotFound
+	}
+	err = store.kv.delete(apiKeyKey(id))
+	if err != nil {
+		return err
+	}
+	return store.memory.DeleteAPIKey(clientID, id)
+}
+
+func (store *Disk) CreatePublisher(ownerID string, organizationID string, name string) (*Publisher, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	publisher, err := store.memory.CreatePublisher(ownerID, organizationID, name)
+	if err != nil {
+		return nil, err
+	}
+	err = store.kv.put(publisherKey(publisher.ID), publisher)
+	if err != nil {
+		store.memory.removePublisher(publisher.ID)
+		return nil, err
+	}
+	return publisher, nil
+}
+
+func (store *Disk) FindPublisher(id string) (*Publisher, error) {
+	return store.memory.FindPublisher(id)
+}
+
+func (store *Disk) FindPublishers(ids []string) ([]Publisher, error) {
+	return store.memory.FindPublishers(ids)
+}
+
+func (store *Disk) ListPublishers(ownerID string) ([]Publisher, error) {
+	return store.memory.ListPublishers(ownerID)
+}
+
+func (store *Disk) ListSubscribers(publisherID string) ([]Client, error) {
+	return store.memory.ListSubscribers(publisherID)
+}
+
+func (store *Disk) CreateOrganization(name string) (*Organization, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	organization, err := store.memory.CreateOrganization(name)
+	if err != nil {
+		return nil, err
+	}
+	err = store.kv.put(orgKey(organization.ID), organization)
+	if err != nil {
+		store.memory.removeOrganization(organization.ID)
+		return nil, err
+	}
+	return organization, nil
+}
+
+func (store *Disk) FindOrganization(id string) (*Organization, error) {
+	return store.memory.FindOrganization(id)
+}
+
+func (store *Disk) SetOrganizationAdmins(id string, admins []string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	organization, err := store.memory.FindOrganization(id)
+	if err != nil {
+		return err
+	}
+	organization.Admins = append([]string{}, admins...)
+	err = store.kv.put(orgKey(id), organization)
+	if err != nil {
+		return err
+	}
+	return store.memory.SetOrganizationAdmins(id, admins)
+}
+
+func (store *Disk) ListMembers(organizationID string) ([]Client, error) {
+	return store.memory.ListMembers(organizationID)
+}
+
+func (store *Disk) ListOrganizationPublishers(organizationID string) ([]Publisher, error) {
+	return store.memory.ListOrganizationPublishers(organizationID)
+}
+
+func (store *Disk) SetPublisherAccess(id string, access Access) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	previous, err := store.memory.FindPublisher(id)
+	if err != nil {
+		return err
+	}
+	err = store.memory.SetPublisherAccess(id, access)
+	if err != nil {
+		return err
+	}
+	publisher, err := store.memory.FindPublisher(id)
+	if err == nil {
+		err = store.kv.put(publisherKey(id), publisher)
+	}
+	if err != nil {
+		store.memory.putPublisher(*previous)
+	}
+	return err
+}
+
+func (store *Disk) SetPublisherPayload(id string, policy PayloadPolicy) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	previous, err := store.memory.FindPublisher(id)
+	if err != nil {
+		return err
+	}
+	err = store.memory.SetPublisherPayload(id, policy)
+	if err != nil {
+		return err
+	}
+	publisher, err := store.memory.FindPublisher(id)
+	if err == nil {
+		err = store.kv.put(publisherKey(id), publisher)
+	}
+	if err != nil {
+		store.memory.putPublisher(*previous)
+	}
+	return err
+}
+
+func (store *Disk) CreateGroup(ownerID string, name string, members []string) (*Group, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	group, err := store.memory.CreateGroup(ownerID, name, members)
+	if err != nil {
+		return nil, err
+	}
+	err = store.kv.put(groupKey(group.ID), group)
+	if err != nil {
+		store.memory.DeleteGroup(group.ID)
+		return nil, err
+	}
+	return group, nil
+}
+
+func (store *Disk) FindGroup(id string) (*Group, error) {
+	return store.memory.FindGroup(id)
+}
+
+func (store *Disk) ListGroups(ownerID string) ([]Group, error) {
+	return store.memory.ListGroups(ownerID)
+}
+
+func (store *Disk) SetGroupMembers(id string, members []string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	group, err := store.memory.FindGroup(id)
+	if err != nil {
+		return err
+	}
+	updated := *group
+	updated.Members = members
+	err = store.kv.put(groupKey(id), updated)
+	if err != nil {
+		return err
+	}
+	return store.memory.SetGroupMembers(id, members)
+}
+
+func (store *Disk) DeleteGroup(id string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	_, err := store.memory.FindGroup(id)
+	if err != nil {
+		return err
+	}
+	err = store.kv.delete(groupKey(id))
+	if err != nil {
+		return err
+	}
+	return store.memory.DeleteGroup(id)
+}
+
+func (store *Disk) CreateSubscriptionRequest(request SubscriptionRequest) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	err := store.memory.CreateSubscriptionRequest(request)
+	if err != nil {
+		return err
+	}
+	err = store.kv.put(requestKey(request.ID), request)
+	if err != nil {
+		store.memory.removeRequest(request.ID)
+	}
+	return err
+}
+
+func (store *Disk) AddSchema(schema Schema) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	err := store.memory.AddSchema(schema)
+	if err != nil {
+		return err
+	}
+	err = store.kv.put(schemaKey(schema.PublisherID, schema.Version), schema)
+	if err != nil {
+		store.memory.removeSchema(schema.PublisherID, schema.Version)
+	}
+	return err
+}
+
+func (store *Disk) FindSchema(publisherID string, version int) (*Schema, error) {
+	return store.memory.FindSchema(publisherID, version)
+}
+
+func (store *Disk) ListSchemas(publisherID string) ([]Schema, error) {
+	return store.memory.ListSchemas(publisherID)
+}
+
+func (store *Disk) FindSubscriptionRequest(id string) (*SubscriptionRequest, error) {
+	return store.memory.FindSubscriptionRequest(id)
+}
+
+func (store *Disk) ListSubscriptionRequests(query SubscriptionRequestQuery) ([]SubscriptionRequest, error) {
+	return store.memory.ListSubscriptionRequests(query)
+}
+
+func (store *Disk) DecideSubscriptionRequest(id string, status string, reason string, decidedAt time.Time) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	request, err := store.memory.FindSubscriptionRequest(id)
+	if err != nil {
+		return err
+	}
+	if request.Status != RequestPending {
+		return ErrConflict
+	}
+	decided := *request
+	decided.Status = status
+	decided.Reason = reason
+	decided.DecidedAt = decidedAt
+	err = store.kv.put(requestKey(id), decided)
+	if err != nil {
+		return err
+	}
+	return store.memory.DecideSubscriptionRequest(id, status, reason, decidedAt)
+}
+
+//the subscriptions are removed before the publisher, so if the process stops part way through the publisher is
+//left with fewer subscribers rather than clients with subscriptions to a publisher which doesn't exist
+func (store *Disk) DeletePublisher(id string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	_, err := store.memory.FindPublisher(id)
+	if err != nil {
+		return err
+	}
+	subscribers, err := store.memory.ListSubscribers(id)
+	if err != nil {
+		return err
+	}
+	for _, subscriber := range subscribers {
+		client, err := store.memory.FindClient(subscriber.ID)
+		if err != nil {
+			continue
+		}
+		remaining := []Subscription{}
+		for _, subscription := range client.Subscriptions {
+			if subscription.PublisherID != id {
+				remaining = append(remaining, subscription)
+			}
+		}
+		client.Subscriptions = remaining
+		err = store.kv.put(clientKey(client.ID), client)
+		if err != nil {
+			return err
+		}
+		store.memory.putClient(client)
+	}
+	requests, err := store.memory.ListSubscriptionRequests(SubscriptionRequestQuery{PublisherID: id})
+	if err != nil {
+		return err
+	}
+	for _, request := range requests {
+		err = store.kv.delete(requestKey(request.ID))
+		if err != nil {
+			return err
+		}
+	}
+	schemas, err := store.memory.ListSchemas(id)
+	if err != nil {
+		return err
+	}
+	for _, schema := range schemas {
+		err = store.kv.delete(schemaKey(id, schema.Version))
+		if err != nil {
+			return err
+		}
+	}
+	err = store.kv.delete(publisherKey(id))
+	if err != nil {
+		return err
+	}
+	store.memory.DeletePublisher(id)
+	if log := store.logs[id]; log != nil {
+		log.close()
+		delete(store.logs, id)
+	}
+	//anything left behind is removed the next time the store is opened
+	os.RemoveAll(filepath.Join(store.messagesDir(), id))
+	return nil
+}
+
+func (store *Disk) InsertMessage(message Message) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	log, err := store.messageLog(message.PublisherID)
+	if err != nil {
+		return err
+	}
+	return log.insert(message)
+}
+
+func (store *Disk) LeaseMessages(publisherID string, clientID string, max int, expires time.Time) ([]Message, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	messages := []Message{}
+	log := store.logs[publisherID]
+	if log == nil {
+		return messages, nil
+	}
+	client, err := store.memory.FindClient(clientID)
+	if err != nil || !hasSubscription(client, publisherID) {
+		return messages, nil
+	}
+	now := time.Now()
+	for _, entry := range log.entries {
+		if len(messages) >= max {
+			break
+		}
+		if !entry.availableTo(clientID, now) {
+			continue
+		}
+		message, err := log.read(entry)
+		if err != nil {
+			//hand back what's been leased so far, the rest will be tried again on the next lease
+			if len(messages) > 0 {
+				break
+			}
+			return nil, err
+		}
+		entry.leases[clientID] = expires
+		messages = append(messages, Message{
+			ID:            message.ID,
+			PublisherID:   publisherID,
+			Payload:       message.Payload,
+			CreatedAt:     message.CreatedAt,
+			ExpiresAt:     message.ExpiresAt,
+			SchemaVersion: message.SchemaVersion,
+		})
+	}
+	return messages, nil
+}
+
+func (store *Disk) RenewLeases(clientID string, messageIDs []string, expires time.Time) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for _, log := range store.logs {
+		for _, id := range messageIDs {
+			if entry := log.byID[id]; entry != nil {
+				if _, leased := entry.leases[clientID]; leased {
+					entry.leases[clientID] = expires
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (store *Disk) ReleaseLeases(publisherID string, clientID string, messageIDs []string, availableAt time.Time) (int, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	log := store.logs[publisherID]
+	if log == nil {
+		return 0, nil
+	}
+	released := 0
+	for _, id := range messageIDs {
+		entry := log.byID[id]
+		if entry == nil {
+			continue
+		}
+		if _, leased := entry.leases[clientID]; !leased {
+			continue
+		}
+		if availableAt.IsZero() {
+			delete(entry.leases, clientID)
+		} else {
+			entry.leases[clientID] = availableAt
+		}
+		released++
+	}
+	return released, nil
+}
+
+func (store *Disk) ConfirmMessages(publisherID string, clientID string, messageIDs []string) (int, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	log := store.logs[publisherID]
+	if log == nil {
+		return 0, nil
+	}
+	confirmed := []string{}
+	for _, id := range messageIDs {
+		if entry := log.byID[id]; entry != nil && !entry.receivedBy[clientID] {
+			confirmed = append(confirmed, id)
+		}
+	}
+	if len(confirmed) == 0 {
+		return 0, nil
+	}
+	err := log.append(logRecord{Type: logRecordConfirm, ClientID: clientID, MessageIDs: confirmed})
+	if err != nil {
+		return 0, err
+	}
+	return len(confirmed), nil
+}
+
+func (store *Disk) ConfirmMessagesThrough(publisherID string, clientID string, messageID string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	log := store.logs[publisherID]
+	if log == nil || log.byID[messageID] == nil {
+		return ErrNotFound
+	}
+	return log.append(logRecord{Type: logRecordConfirmThrough, ClientID: clientID, MessageIDs: []string{messageID}})
+}
+
+//also where the logs are compacted, as it's run regularly and is when most of their garbage is made
+func (store *Disk) DeleteExpiredMessages(now time.Time) (int, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	deleted := 0
+	for _, log := range store.logs {
+		deleted += log.deleteExpired(now)
+		err := log.dropDeadSegments()
+		if err == nil {
+			err = log.compactIfNeeded()
+		}
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+func (store *Disk) MessageUsage(publisherIDs []string) (map[string]Usage, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	usage := make(map[string]Usage)
+	for _, id := range publisherIDs {
+		if log, exists := store.logs[id]; exists {
+			usage[id] = Usage{Messages: int64(len(log.entries)), Bytes: log.payloads}
+		}
+	}
+	return usage, nil
+}
+
+func (store *Disk) AcquireLease(name string, holder string, expires time.Time) (bool, error) {
+	return store.memory.AcquireLease(name, holder, expires)
+}
+
+func (store *Disk) ReleaseLease(name string, holder string) error {
+	return store.memory.ReleaseLease(name, holder)
+}
+
+func (store *Disk) RegisterInstance(id string, address string, expires time.Time) error {
+	return store.memory.RegisterInstance(id, address, expires)
+}
+
+func (store *Disk) RemoveInstance(id string) error {
+	return store.memory.RemoveInstance(id)
+}
+
+func (store *Disk) RemoveExpiredInstances(now time.Time) error {
+	return store.memory.RemoveExpiredInstances(now)
+}
+
+func (store *Disk) Close() error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for _, log := range store.logs {
+		log.close()
+	}
+	store.logs = make(map[string]*messageLog)
+	var err error
+	if store.kv != nil {
+		err = store.kv.close()
+		store.kv = nil
+	}
+	if store.lockFile != nil {
+		unlockDirectory(store.lockFile)
+		store.lockFile = nil
+	}
+	return err
+}
@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+//open a disk store with a client subscribed to a publisher
+func openSubscribedDisk(t *testing.T, dir string) (*Disk, *Client, *Publisher) {
+	t.Helper()
+	store, err := OpenDisk(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := store.CreateClient("", "client", "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	publisher, err := store.CreatePublisher(client.ID, "", "publisher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.AddSubscription(client.ID, Subscription{ID: "subscription", PublisherID: publisher.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store, client, publisher
+}
+
+func reopenDisk(t *testing.T, store *Disk, dir string) *Disk {
+	t.Helper()
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := OpenDisk(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { reopened.Close() })
+	return reopened
+}
+
+//ids of the messages a client can lease from a publisher
+func leaseIDs(t *testing.T, store *Disk, publisherID string, clientID string) []string {
+	t.Helper()
+	messages, err := store.LeaseMessages(publisherID, clientID, 100, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := []string{}
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+	}
+	return ids
+}
+
+func TestDiskRecoversTornSegment(t *testing.T) {
+	dir := t.TempDir()
+	store, client, publisher := openSubscribedDisk(t, dir)
+	for _, id := range []string{"m1", "m2", "m3"} {
+		err := store.InsertMessage(Message{ID: id, PublisherID: publisher.ID, Payload: id, CreatedAt: time.Now()})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := store.ConfirmMessages(publisher.ID, client.ID, []string{"m1"}); err != nil {
+		t.Fatal(err)
+	}
+	entry := store.logs[publisher.ID].byID["m3"]
+	truncate(t, entry.segment.file, entry.offset+entry.size-1)
+
+	//the confirmation was written after m3, so it's lost along with it
+	reopened := reopenDisk(t, store, dir)
+	if ids := leaseIDs(t, reopened, publisher.ID, client.ID); !reflect.DeepEqual(ids, []string{"m1", "m2"}) {
+		t.Fatalf("leased %v", ids)
+	}
+}
+
+func TestDiskCompactionKeepsUnconfirmedMessages(t *testing.T) {
+	dir := t.TempDir()
+	store, client, publisher := openSubscribedDisk(t, dir)
+	now := time.Now()
+	insert := func(id string, payload string, expiresAt time.Time) {
+		t.Helper()
+		err := store.InsertMessage(Message{ID: id, PublisherID: publisher.ID, Payload: payload, CreatedAt: now, ExpiresAt: expiresAt})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	insert("confirmed", "confirmed", time.Time{})
+	insert("unconfirmed", "unconfirmed", time.Time{})
+	//enough expired payload for the log to be worth compacting
+	large := strings.Repeat("x", logCompactMinGarbage/4)
+	for _, id := range []string{"e1", "e2", "e3", "e4", "e5"} {
+		insert(id, large, now.Add(time.Hour))
+	}
+	if _, err := store.ConfirmMessages(publisher.ID, client.ID, []string{"confirmed"}); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := store.DeleteExpiredMessages(now.Add(2 * time.Hour))
+	if err != nil || deleted != 5 {
+		t.Fatalf("deleted %d, %v", deleted, err)
+	}
+	log := store.logs[publisher.ID]
+	if log.generation != 1 {
+		t.Fatalf("log is at generation %d, it wasn't compacted", log.generation)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "messages", publisher.ID, "0")); !os.IsNotExist(err) {
+		t.Fatalf("old generation left behind, %v", err)
+	}
+
+	reopened := reopenDisk(t, store, dir)
+	if ids := leaseIDs(t, reopened, publisher.ID, client.ID); !reflect.DeepEqual(ids, []string{"unconfirmed"}) {
+		t.Fatalf("leased %v after compacting", ids)
+	}
+	//the confirmed message is kept for the publisher's other subscribers
+	if entry := reopened.logs[publisher.ID].byID["confirmed"]; entry == nil || !entry.receivedBy[client.ID] {
+		t.Fatalf("confirmed message compacted to %v", entry)
+	}
+}
@@ -1,8 +1,11 @@
-module github.com/sberridge/bezmongo
+module bezberr.com/messagebrokerstorage
 
-go 1.17
+go 1.24.0
 
-require go.mongodb.org/mongo-driver v1.8.4
+require (
+	github.com/google/uuid v1.6.0
+	go.mongodb.org/mongo-driver v1.8.4
+)
 
 require (
 	github.com/go-stack/stack v1.8.0 // indirect
@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const kvCompactMinRecords = 1000 //the log isn't compacted until it has at least this many records
+
+//kvStore is a small key value store kept as a log of the changes made to it. every value is held in memory,
+//the log is read back when the store is opened and rewritten with just the current values once it's mostly
+//overwritten or deleted entries
+type kvStore struct {
+	path    string
+	file    *os.File
+	size    int64 //where the next record is written
+	records int   //records in the log, live or not
+	values  map[string][]byte
+	keys    []string //in the order they were first written
+}
+
+type kvRecord struct {
+	Key     string          `json:"key"`
+	Value   json.RawMessage `json:"value,omitempty"`
+	Deleted bool            `json:"deleted,omitempty"`
+}
+
+//open the store at path, creating it if it doesn't exist
+func openKVStore(path string) (*kvStore, error) {
+	//left behind if the process stopped part way through compacting, the log it would have replaced is intact
+	os.Remove(path + ".compact")
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	store := &kvStore{path: path, file: file, values: make(map[string][]byte)}
+	validSize, err := scanRecords(file, func(offset int64, size int64, data []byte) error {
+		record := kvRecord{}
+		err := json.Unmarshal(data, &record)
+		if err != nil {
+			return err
+		}
+		store.apply(record)
+		store.records++
+		return nil
+	})
+	if err == nil {
+		err = truncateAfter(file, validSize)
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	store.size = validSize
+	err = store.compactIfNeeded()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func (store *kvStore) apply(record kvRecord) {
+	_, exists := store.values[record.Key]
+	if record.Deleted {
+		if exists {
+			delete(store.values, record.Key)
+			for i, key := range store.keys {
+				if key == record.Key {
+					store.keys = append(store.keys[:i:i], store.keys[i+1:]...)
+					break
+				}
+			}
+		}
+		return
+	}
+	if !exists {
+		store.keys = append(store.keys, record.Key)
+	}
+	store.values[record.Key] = record.Value
+}
+
+func (store *kvStore) write(record kvRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	size, err := writeRecord(store.file, store.size, data)
+	if err == nil {
+		err = store.file.Sync()
+	}
+	if err != nil {
+		return err
+	}
+	store.size += size
+	store.records++
+	store.apply(record)
+	//the record is already written, a compaction which fails leaves the old log intact and is tried again on
+	//the next write
+	store.compactIfNeeded()
+	return nil
+}
+
+//put the value, encoded as json, under key
+func (store *kvStore) put(key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return store.write(kvRecord{Key: key, Value: data})
+}
+
+func (store *kvStore) delete(key string) error {
+	if _, exists := store.values[key]; !exists {
+		return nil
+	}
+	return store.write(kvRecord{Key: key, Deleted: true})
+}
+
+//call handle with the value of each key starting with prefix, in the order the keys were first written
+func (store *kvStore) each(prefix string, handle func(value []byte) error) error {
+	for _, key := range store.keys {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		err := handle(store.values[key])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (store *kvStore) compactIfNeeded() error {
+	if store.records < kvCompactMinRecords || store.records < 2*len(store.keys) {
+		return nil
+	}
+	return store.compact()
+}
+
+//rewrite the log with just the current values. the new log is written alongside the old one and renamed over
+//it, so a crash part way through leaves the old log in place
+func (store *kvStore) compact() error {
+	compactPath := store.path + ".compact"
+	file, err := os.OpenFile(compactPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	size := int64(0)
+	for _, key := range store.keys {
+		data, err := json.Marshal(kvRecord{Key: key, Value: store.values[key]})
+		recordSize := int64(0)
+		if err == nil {
+			recordSize, err = writeRecord(file, size, data)
+		}
+		if err != nil {
+			file.Close()
+			os.Remove(compactPath)
+			return err
+		}
+		size += recordSize
+	}
+	err = file.Sync()
+	if err == nil {
+		err = os.Rename(compactPath, store.path)
+	}
+	if err != nil {
+		file.Close()
+		os.Remove(compactPath)
+		return err
+	}
+	syncDirectory(filepath.Dir(store.path))
+	store.file.Close()
+	store.file = file
+	store.size = size
+	store.records = len(store.keys)
+	return nil
+}
+
+func (store *kvStore) close() error {
+	return store.file.Close()
+}
@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func openKV(t *testing.T, path string) *kvStore {
+	t.Helper()
+	store, err := openKVStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.close() })
+	return store
+}
+
+//the values of a store decoded as strings, in the order the keys were first written
+func kvContents(t *testing.T, store *kvStore) map[string]string {
+	t.Helper()
+	contents := map[string]string{}
+	for _, key := range store.keys {
+		value := ""
+		if err := json.Unmarshal(store.values[key], &value); err != nil {
+			t.Fatal(err)
+		}
+		contents[key] = value
+	}
+	return contents
+}
+
+func mustPut(t *testing.T, store *kvStore, key string, value string) {
+	t.Helper()
+	if err := store.put(key, value); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func fileSize(t *testing.T, path string) int64 {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.Size()
+}
+
+func TestKVStoreReplaysLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.kv")
+	store := openKV(t, path)
+	mustPut(t, store, "a", "1")
+	mustPut(t, store, "b", "2")
+	mustPut(t, store, "c", "3")
+	mustPut(t, store, "a", "4")
+	if err := store.delete("b"); err != nil {
+		t.Fatal(err)
+	}
+	store.close()
+
+	reopened := openKV(t, path)
+	if contents := kvContents(t, reopened); !reflect.DeepEqual(contents, map[string]string{"a": "4", "c": "3"}) {
+		t.Fatalf("reopened with %v", contents)
+	}
+	if !reflect.DeepEqual(reopened.keys, []string{"a", "c"}) {
+		t.Fatalf("keys in the order %v", reopened.keys)
+	}
+	if reopened.records != 5 {
+		t.Fatalf("replayed %d records", reopened.records)
+	}
+}
+
+func TestKVStoreRecovers(t *testing.T) {
+	tests := []struct {
+		name     string
+		damage   func(t *testing.T, file *os.File, size int64)
+		expected map[string]string
+	}{
+		{
+			name: "torn write",
+			damage: func(t *testing.T, file *os.File, size int64) {
+				//the start of a record which didn't finish being written
+				if _, err := file.WriteAt(encodeRecord([]byte(`{"key":"c","value":"3"}`))[:12], size); err != nil {
+					t.Fatal(err)
+				}
+			},
+			expected: map[string]string{"a": "1", "b": "2"},
+		},
+		{
+			name: "corrupt last record",
+			damage: func(t *testing.T, file *os.File, size int64) {
+				flipByte(t, file, size-2)
+			},
+			expected: map[string]string{"a": "1"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "store.kv")
+			store := openKV(t, path)
+			mustPut(t, store, "a", "1")
+			mustPut(t, store, "b", "2")
+			size := store.size
+			test.damage(t, store.file, size)
+			store.close()
+
+			reopened := openKV(t, path)
+			if contents := kvContents(t, reopened); !reflect.DeepEqual(contents, test.expected) {
+				t.Fatalf("reopened with %v", contents)
+			}
+			if fileSize(t, path) != reopened.size {
+				t.Fatalf("file is %d bytes, the valid records end at %d", fileSize(t, path), reopened.size)
+			}
+
+			//writes after the recovered records survive the next reopen
+			mustPut(t, reopened, "d", "4")
+			reopened.close()
+			expected := map[string]string{"d": "4"}
+			for key, value := range test.expected {
+				expected[key] = value
+			}
+			if contents := kvContents(t, openKV(t, path)); !reflect.DeepEqual(contents, expected) {
+				t.Fatalf("reopened with %v after writing past the recovered records", contents)
+			}
+		})
+	}
+}
+
+func TestKVStoreCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.kv")
+	store := openKV(t, path)
+	for i := 0; i < kvCompactMinRecords; i++ {
+		mustPut(t, store, fmt.Sprintf("key%d", i%3), fmt.Sprint(i))
+	}
+	mustPut(t, store, "kept", "value")
+	if store.records != len(store.keys) {
+		t.Fatalf("%d records for %d keys, the log wasn't compacted", store.records, len(store.keys))
+	}
+	expected := map[string]string{"key0": "999", "key1": "997", "key2": "998", "kept": "value"}
+	if contents := kvContents(t, store); !reflect.DeepEqual(contents, expected) {
+		t.Fatalf("compacted to %v", contents)
+	}
+	if fileSize(t, path) != store.size {
+		t.Fatalf("compacted log is %d bytes, expected %d", fileSize(t, path), store.size)
+	}
+	store.close()
+
+	reopened := openKV(t, path)
+	if contents := kvContents(t, reopened); !reflect.DeepEqual(contents, expected) {
+		t.Fatalf("reopened the compacted log with %v", contents)
+	}
+}
+
+func TestKVStoreInterruptedCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.kv")
+	store := openKV(t, path)
+	mustPut(t, store, "a", "1")
+	store.close()
+	//left by a process which stopped before renaming the compacted log over the old one
+	if err := os.WriteFile(path+".compact", encodeRecord([]byte(`{"key":"a","value":"stale"}`)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := openKV(t, path)
+	if contents := kvContents(t, reopened); !reflect.DeepEqual(contents, map[string]string{"a": "1"}) {
+		t.Fatalf("reopened with %v", contents)
+	}
+	if _, err := os.Stat(path + ".compact"); !os.IsNotExist(err) {
+		t.Fatalf("unfinished compaction left behind, %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package limits
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"unicode/utf8"
+
+	storage "bezberr.com/messagebrokerstorage"
+)
+
+const (
+	DefaultMaxPayloadBytes = 256 * 1024  //largest payload which can be published unless the services are configured otherwise
+	DefaultMaxRequestBytes = 1024 * 1024 //largest request body, frame or packet the services read unless configured otherwise
+)
+
+//PayloadLimits on what the services read from clients. 0 uses the defaults
+type PayloadLimits struct {
+	MaxPayloadBytes int `config:"max_payload_bytes" usage:"largest payload which can be published, publishers can lower it for themselves"`
+	MaxRequestBytes int `config:"max_request_bytes" usage:"largest request body, frame or packet read from a client"`
+}
+
+//DefaultPayloadLimits are DefaultMaxPayloadBytes and DefaultMaxRequestBytes
+func DefaultPayloadLimits() PayloadLimits {
+	return PayloadLimits{MaxPayloadBytes: DefaultMaxPayloadBytes, MaxRequestBytes: DefaultMaxRequestBytes}
+}
+
+//WithDefaults fills in the limits which aren't set
+func (limits PayloadLimits) WithDefaults() PayloadLimits {
+	if limits.MaxPayloadBytes == 0 {
+		limits.MaxPayloadBytes = DefaultMaxPayloadBytes
+	}
+	if limits.MaxRequestBytes == 0 {
+		limits.MaxRequestBytes = DefaultMaxRequestBytes
+	}
+	return limits
+}
+
+//Validate checks the limits aren't negative and a payload of the largest size fits in a request
+func (limits PayloadLimits) Validate() error {
+	if limits.MaxPayloadBytes < 0 || limits.MaxRequestBytes < 0 {
+		return errors.New("payload limits can't be negative")
+	}
+	limits = limits.WithDefaults()
+	if limits.MaxPayloadBytes > limits.MaxRequestBytes {
+		return errors.New("max_payload_bytes can't be over max_request_bytes")
+	}
+	return nil
+}
+
+//PayloadError is returned when a payload is rejected by its publisher's policy or schema, or the service's limit
+type PayloadError struct {
+	Limit         int    //size limit the payload was over, 0 if it was rejected for something else
+	SchemaVersion int    //version of the publisher's schema the payload didn't match, 0 if it was rejected for something else
+	Reason        string //why it was rejected
+}
+
+func (err *PayloadError) Error() string {
+	return "payload rejected, " + err.Reason
+}
+
+//ValidateContentType checks a declared content type parses as a media type without parameters other than charset
+func ValidateContentType(contentType string) error {
+	if contentType == "" {
+		return nil
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("invalid content type %q: %v", contentType, err)
+	}
+	if !strings.Contains(mediaType, "/") {
+		return fmt.Errorf("invalid content type %q, should be a type and subtype like application/json", contentType)
+	}
+	for name := range params {
+		if name != "charset" {
+			return fmt.Errorf("invalid content type %q, only the charset parameter is supported", contentType)
+		}
+	}
+	if charset, ok := params["charset"]; ok && !strings.EqualFold(charset, "utf-8") {
+		return fmt.Errorf("invalid content type %q, payloads are always utf-8", contentType)
+	}
+	return nil
+}
+
+//ValidatePolicy checks a publisher's content type and that its size limit is within the service's
+func ValidatePolicy(policy storage.PayloadPolicy, serviceMax int) error {
+	if policy.MaxBytes < 0 {
+		return errors.New("max payload bytes can't be negative")
+	}
+	if serviceMax > 0 && policy.MaxBytes > serviceMax {
+		return fmt.Errorf("max payload bytes can't be over the service's limit of %d", serviceMax)
+	}
+	return ValidateContentType(policy.ContentType)
+}
+
+//PolicyLimit on the size of payloads, the publisher's own if it has one otherwise the service's. 0 for no limit
+func PolicyLimit(policy storage.PayloadPolicy, serviceMax int) int {
+	if policy.MaxBytes > 0 && (serviceMax <= 0 || policy.MaxBytes < serviceMax) {
+		return policy.MaxBytes
+	}
+	return serviceMax
+}
+
+//CheckPayload against the publisher's policy and the service's size limit, returning a *PayloadError if it's
+//rejected. JSON content types have to be well formed JSON and text ones valid UTF-8, other content types only have
+//their size checked
+func CheckPayload(policy storage.PayloadPolicy, payload string, serviceMax int) error {
+	if limit := PolicyLimit(policy, serviceMax); limit > 0 && len(payload) > limit {
+		return &PayloadError{Limit: limit, Reason: fmt.Sprintf("%d bytes is over the limit of %d", len(payload), limit)}
+	}
+	if policy.ContentType == "" {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(policy.ContentType)
+	if err != nil {
+		return nil
+	}
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		if !json.Valid([]byte(payload)) {
+			return &PayloadError{Reason: jsonSyntaxReason(payload, mediaType)}
+		}
+	case strings.HasPrefix(mediaType, "text/"):
+		if !utf8.ValidString(payload) {
+			return &PayloadError{Reason: fmt.Sprintf("not valid utf-8 for content type %s", mediaType)}
+		}
+	}
+	return nil
+}
+
+//where a payload which isn't valid JSON goes wrong
+func jsonSyntaxReason(payload string, mediaType string) string {
+	var value interface{}
+	err := json.Unmarshal([]byte(payload), &value)
+	syntaxErr := &json.SyntaxError{}
+	if errors.As(err, &syntaxErr) {
+		return fmt.Sprintf("not valid JSON for content type %s, %v at offset %d", mediaType, syntaxErr, syntaxErr.Offset)
+	}
+	if err != nil {
+		return fmt.Sprintf("not valid JSON for content type %s, %v", mediaType, err)
+	}
+	return fmt.Sprintf("not valid JSON for content type %s", mediaType)
+}
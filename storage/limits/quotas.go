@@ -0,0 +1,117 @@
+package limits
+
+import (
+	"errors"
+	"fmt"
+
+	storage "bezberr.com/messagebrokerstorage"
+)
+
+const (
+	QuotaPublisherMessages = "publisher_messages" //messages stored for a publisher
+	QuotaPublisherBytes    = "publisher_bytes"    //bytes of the payloads stored for a publisher
+	QuotaClientMessages    = "client_messages"    //messages stored for all of a client's publishers
+	QuotaClientBytes       = "client_bytes"       //bytes of the payloads stored for all of a client's publishers
+)
+
+//Quotas on the messages stored for each publisher and client, the client's usage being that of the publishers it
+//owns. 0 leaves a quota off
+type Quotas struct {
+	PublisherMessages int `config:"publisher_messages" usage:"messages which can be stored for a publisher, 0 for no quota"`
+	PublisherBytes    int `config:"publisher_bytes" usage:"bytes of payloads which can be stored for a publisher, 0 for no quota"`
+	ClientMessages    int `config:"client_messages" usage:"messages which can be stored for all of a client's publishers, 0 for no quota"`
+	ClientBytes       int `config:"client_bytes" usage:"bytes of payloads which can be stored for all of a client's publishers, 0 for no quota"`
+}
+
+//Validate checks none of the quotas are negative
+func (quotas Quotas) Validate() error {
+	if quotas.PublisherMessages < 0 || quotas.PublisherBytes < 0 || quotas.ClientMessages < 0 || quotas.ClientBytes < 0 {
+		return errors.New("quotas can't be negative")
+	}
+	return nil
+}
+
+//QuotaError is returned when storing a message would take a publisher or its owner over one of its quotas
+type QuotaError struct {
+	Quota string //one of QuotaPublisherMessages, QuotaPublisherBytes, QuotaClientMessages or QuotaClientBytes
+	Used  int64  //before the message
+	Limit int64
+}
+
+func (err *QuotaError) Error() string {
+	switch err.Quota {
+	case QuotaPublisherMessages:
+		return fmt.Sprintf("publisher storage quota exceeded, %d of %d messages stored", err.Used, err.Limit)
+	case QuotaPublisherBytes:
+		return fmt.Sprintf("publisher storage quota exceeded, %d of %d bytes stored", err.Used, err.Limit)
+	case QuotaClientMessages:
+		return fmt.Sprintf("client storage quota exceeded, %d of %d messages stored", err.Used, err.Limit)
+	}
+	return fmt.Sprintf("client storage quota exceeded, %d of %d bytes stored", err.Used, err.Limit)
+}
+
+//PublisherUsage of a single publisher
+func PublisherUsage(store storage.Store, publisherID string) (storage.Usage, error) {
+	usage, err := store.MessageUsage([]string{publisherID})
+	if err != nil {
+		return storage.Usage{}, err
+	}
+	return usage[publisherID], nil
+}
+
+//ClientUsage of every publisher a client owns, along with the usage of each of them
+func ClientUsage(store storage.Store, clientID string) (storage.Usage, []storage.Publisher, map[string]storage.Usage, error) {
+	publishers, err := store.ListPublishers(clientID)
+	if err != nil {
+		return storage.Usage{}, nil, nil, err
+	}
+	ids := []string{}
+	for _, publisher := range publishers {
+		ids = append(ids, publisher.ID)
+	}
+	usage, err := store.MessageUsage(ids)
+	if err != nil {
+		return storage.Usage{}, nil, nil, err
+	}
+	total := storage.Usage{}
+	for _, id := range ids {
+		total.Messages += usage[id].Messages
+		total.Bytes += usage[id].Bytes
+	}
+	return total, publishers, usage, nil
+}
+
+//check a usage plus the message being stored fits in a quota on messages and one on bytes
+func checkQuota(usage storage.Usage, size int, maxMessages int, maxBytes int, messagesQuota string, bytesQuota string) error {
+	if maxMessages > 0 && usage.Messages+1 > int64(maxMessages) {
+		return &QuotaError{Quota: messagesQuota, Used: usage.Messages, Limit: int64(maxMessages)}
+	}
+	if maxBytes > 0 && usage.Bytes+int64(size) > int64(maxBytes) {
+		return &QuotaError{Quota: bytesQuota, Used: usage.Bytes, Limit: int64(maxBytes)}
+	}
+	return nil
+}
+
+//CheckQuotas returns a *QuotaError if storing a message of size bytes would take the publisher or its owner over
+//one of the quotas. the usage is read before the message is stored, so messages published at the same time can
+//take a publisher slightly over its quota
+func (quotas Quotas) CheckQuotas(store storage.Store, publisher *storage.Publisher, size int) error {
+	if quotas.PublisherMessages > 0 || quotas.PublisherBytes > 0 {
+		usage, err := PublisherUsage(store, publisher.ID)
+		if err != nil {
+			return err
+		}
+		err = checkQuota(usage, size, quotas.PublisherMessages, quotas.PublisherBytes, QuotaPublisherMessages, QuotaPublisherBytes)
+		if err != nil {
+			return err
+		}
+	}
+	if quotas.ClientMessages > 0 || quotas.ClientBytes > 0 {
+		usage, _, _, err := ClientUsage(store, publisher.OwnerID)
+		if err != nil {
+			return err
+		}
+		return checkQuota(usage, size, quotas.ClientMessages, quotas.ClientBytes, QuotaClientMessages, QuotaClientBytes)
+	}
+	return nil
+}
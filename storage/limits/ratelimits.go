@@ -0,0 +1,211 @@
+//Package limits applies the rate limits, payload limits and storage quotas the message broker and the publisher service
+//enforce on clients, along with the overrides kept for them in storage.
+package limits
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+)
+
+const (
+	LimitPublish     = "publish"     //messages a client publishes
+	LimitPublisher   = "publisher"   //messages published to a publisher, whoever publishes them
+	LimitRequests    = "requests"    //calls a client makes to the REST and gRPC APIs
+	LimitConnections = "connections" //sessions a client has open to the message broker at once
+
+	//how often buckets which have refilled are forgotten
+	clearBucketsInterval = 10 * time.Minute
+)
+
+//RateLimitConfig is the limits a service applies to every client and publisher
+type RateLimitConfig struct {
+	Client                    storage.RateLimits `config:"client"`
+	PublisherPublishPerMinute int                `config:"publisher_publish_per_minute" usage:"messages which can be published to a publisher a minute, 0 for no limit"`
+	PublisherPublishBurst     int                `config:"publisher_publish_burst" usage:"messages which can be published to a publisher at once after being idle, defaults to a second's worth"`
+}
+
+//Validate checks none of the limits are negative
+func (config RateLimitConfig) Validate() error {
+	for _, value := range []int{
+		config.Client.PublishPerMinute, config.Client.PublishBurst, config.Client.RequestsPerMinute,
+		config.Client.RequestsBurst, config.Client.Connections, config.PublisherPublishPerMinute,
+		config.PublisherPublishBurst,
+	} {
+		if value < 0 {
+			return errors.New("rate limits can't be negative")
+		}
+	}
+	return nil
+}
+
+//RateLimitError is returned when a client or publisher is over one of its limits
+type RateLimitError struct {
+	Limit      string        //LimitPublish, LimitPublisher, LimitRequests or LimitConnections
+	RetryAfter time.Duration //how long until the same call would be allowed
+}
+
+func (err *RateLimitError) Error() string {
+	if err.Limit == LimitConnections {
+		return fmt.Sprintf("too many connections open, retry after %ds", err.RetryAfterSeconds())
+	}
+	return fmt.Sprintf("%s rate limit exceeded, retry after %ds", err.Limit, err.RetryAfterSeconds())
+}
+
+//RetryAfterSeconds rounded up, as sent in a Retry-After header
+func (err *RateLimitError) RetryAfterSeconds() int {
+	return int(math.Ceil(err.RetryAfter.Seconds()))
+}
+
+//token bucket holding up to burst tokens, refilled at perMinute tokens a minute
+type bucket struct {
+	tokens    float64
+	perMinute int
+	burst     int
+	updated   time.Time
+}
+
+//refill the bucket up to now, returning how long until it has a token if it's empty
+func (bucket *bucket) refill(now time.Time) time.Duration {
+	perSecond := float64(bucket.perMinute) / 60
+	bucket.tokens = math.Min(float64(bucket.burst), bucket.tokens+now.Sub(bucket.updated).Seconds()*perSecond)
+	bucket.updated = now
+	if bucket.tokens >= 1 {
+		return 0
+	}
+	return time.Duration(math.Ceil((1 - bucket.tokens) / perSecond * float64(time.Second)))
+}
+
+//burst of a limit, a second's worth of tokens if it isn't set
+func burstOf(perMinute int, burst int) int {
+	if burst > 0 {
+		return burst
+	}
+	return int(math.Max(1, math.Ceil(float64(perMinute)/60)))
+}
+
+//RateLimiter keeps a token bucket for each client and publisher. the buckets are in memory, so each instance of a
+//service enforces the limits on its own. a nil limiter doesn't limit anything
+type RateLimiter struct {
+	config RateLimitConfig
+
+	lock        sync.Mutex
+	buckets     map[string]*bucket
+	lastCleared time.Time
+}
+
+//NewRateLimiter applying the config's limits
+func NewRateLimiter(config RateLimitConfig) *RateLimiter {
+	return &RateLimiter{config: config, buckets: map[string]*bucket{}, lastCleared: time.Now()}
+}
+
+//Limits of a client, its override applied over the defaults
+func (limiter *RateLimiter) Limits(override *storage.RateLimits) storage.RateLimits {
+	if limiter == nil {
+		return storage.RateLimits{}
+	}
+	return apply(limiter.config.Client, override)
+}
+
+//apply a client's override to the default limits
+func apply(defaults storage.RateLimits, override *storage.RateLimits) storage.RateLimits {
+	if override == nil {
+		return defaults
+	}
+	pick := func(value int, overridden int) int {
+		switch {
+		case overridden < 0:
+			return 0
+		case overridden > 0:
+			return overridden
+		}
+		return value
+	}
+	return storage.RateLimits{
+		PublishPerMinute:  pick(defaults.PublishPerMinute, override.PublishPerMinute),
+		PublishBurst:      pick(defaults.PublishBurst, override.PublishBurst),
+		RequestsPerMinute: pick(defaults.RequestsPerMinute, override.RequestsPerMinute),
+		RequestsBurst:     pick(defaults.RequestsBurst, override.RequestsBurst),
+		Connections:       pick(defaults.Connections, override.Connections),
+	}
+}
+
+//limit is a bucket to take a token from
+type limit struct {
+	name      string
+	key       string
+	perMinute int
+	burst     int
+}
+
+//take a token from each of the limits which are on, or none of them if any of them are empty
+func (limiter *RateLimiter) take(limits ...limit) error {
+	limiter.lock.Lock()
+	defer limiter.lock.Unlock()
+	now := time.Now()
+	limiter.clearFull(now)
+	taking := []*bucket{}
+	for _, limit := range limits {
+		if limit.perMinute <= 0 {
+			continue
+		}
+		burst := burstOf(limit.perMinute, limit.burst)
+		tokens, exists := limiter.buckets[limit.key]
+		if !exists {
+			tokens = &bucket{tokens: float64(burst), updated: now}
+			limiter.buckets[limit.key] = tokens
+		}
+		//the limits can change between calls as overrides are set
+		tokens.perMinute, tokens.burst = limit.perMinute, burst
+		wait := tokens.refill(now)
+		if wait > 0 {
+			return &RateLimitError{Limit: limit.name, RetryAfter: wait}
+		}
+		taking = append(taking, tokens)
+	}
+	for _, tokens := range taking {
+		tokens.tokens--
+	}
+	return nil
+}
+
+//forget buckets which have refilled, a new bucket starts full so clients and publishers which have gone quiet
+//don't need to be kept
+func (limiter *RateLimiter) clearFull(now time.Time) {
+	if now.Sub(limiter.lastCleared) < clearBucketsInterval {
+		return
+	}
+	limiter.lastCleared = now
+	for key, tokens := range limiter.buckets {
+		tokens.refill(now)
+		if tokens.tokens >= float64(tokens.burst) {
+			delete(limiter.buckets, key)
+		}
+	}
+}
+
+//AllowRequest takes a token from the client's request bucket, returning a *RateLimitError if it's empty
+func (limiter *RateLimiter) AllowRequest(clientID string, override *storage.RateLimits) error {
+	if limiter == nil {
+		return nil
+	}
+	limits := limiter.Limits(override)
+	return limiter.take(limit{LimitRequests, "requests/" + clientID, limits.RequestsPerMinute, limits.RequestsBurst})
+}
+
+//AllowPublish takes a token from the client's publish bucket and the publisher's, returning a *RateLimitError
+//without taking either if one of them is empty
+func (limiter *RateLimiter) AllowPublish(clientID string, override *storage.RateLimits, publisherID string) error {
+	if limiter == nil {
+		return nil
+	}
+	limits := limiter.Limits(override)
+	return limiter.take(
+		limit{LimitPublish, "publish/" + clientID, limits.PublishPerMinute, limits.PublishBurst},
+		limit{LimitPublisher, "publisher/" + publisherID, limiter.config.PublisherPublishPerMinute, limiter.config.PublisherPublishBurst},
+	)
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package storage
+
+import "os"
+
+//locking the data directory is only supported on unix, elsewhere it's up to whoever runs the process to make
+//sure only one uses it
+func lockDirectory(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+}
+
+func unlockDirectory(file *os.File) {
+	file.Close()
+}
@@ -0,0 +1,29 @@
+//go:build unix
+
+package storage
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+//take an exclusive lock on a data directory so two processes can't use it at once. the lock is released by the
+//operating system if the process exits without closing the store
+func lockDirectory(path string) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("data directory is in use by another process, run the broker and publisher service together with the standalone binary to share it: %w", err)
+	}
+	return file, nil
+}
+
+func unlockDirectory(file *os.File) {
+	syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+	file.Close()
+}
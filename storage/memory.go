@@ -0,0 +1,987 @@
+package storage
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//Memory keeps everything in the process, it's lost when the process exits
+type Memory struct {
+	lock       sync.Mutex
+	clients    []*Client              //in the order they registered
+	publishers []*Publisher           //in the order they were created
+	apiKeys    []*APIKey              //in the order they were created
+	groups     []*Group               //in the order they were created
+	orgs       []*Organization        //in the order they were created
+	requests   []*SubscriptionRequest //in the order they were made
+	schemas    map[string][]Schema    //each publisher's versions, oldest first
+	messages   map[string][]*memoryMessage
+	leases     map[string]memoryLease
+	instances  map[string]time.Time //when each instance's registration expires
+}
+
+type memoryMessage struct {
+	message    Message
+	receivedBy map[string]bool
+	leases     map[string]time.Time //client id to the time its lease on the message expires
+}
+
+type memoryLease struct {
+	holder  string
+	expires time.Time
+}
+
+//NewMemory creates an empty store
+func NewMemory() *Memory {
+	return &Memory{
+		schemas:   make(map[string][]Schema),
+		messages:  make(map[string][]*memoryMessage),
+		leases:    make(map[string]memoryLease),
+		instances: make(map[string]time.Time),
+	}
+}
+
+//copy of a subscription so callers can't change the stored one
+func copySubscription(subscription Subscription) Subscription {
+	if subscription.Webhook != nil {
+		webhook := *subscription.Webhook
+		subscription.Webhook = &webhook
+	}
+	return subscription
+}
+
+func copyClient(client *Client) *Client {
+	result := Client{
+		ID:             client.ID,
+		OrganizationID: client.OrganizationID,
+		Name:           client.Name,
+		SecretHash:     client.SecretHash,
+		RateLimits:     copyRateLimits(client.RateLimits),
+		Subscriptions:  []Subscription{},
+	}
+	for _, subscription := range client.Subscriptions {
+		result.Subscriptions = append(result.Subscriptions, copySubscription(subscription))
+	}
+	return &result
+}
+
+func copyPublisher(publisher *Publisher) *Publisher {
+	result := *publisher
+	result.Access = copyAccess(publisher.Access)
+	return &result
+}
+
+func copyGroup(group *Group) *Group {
+	result := *group
+	result.Members = append([]string{}, group.Members...)
+	return &result
+}
+
+func (store *Memory) findClient(id string) *Client {
+	for _, client := range store.clients {
+		if client.ID == id {
+			return client
+		}
+	}
+	return nil
+}
+
+func (store *Memory) findPublisher(id string) *Publisher {
+	for _, publisher := range store.publishers {
+		if publisher.ID == id {
+			return publisher
+		}
+	}
+	return nil
+}
+
+func (store *Memory) CreateClient(organizationID string, name string, secretHash string) (*Client, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for _, client := range store.clients {
+		if client.OrganizationID == organizationID && client.Name == name {
+			return nil, ErrConflict
+		}
+	}
+	client := &Client{ID: uuid.New().String(), OrganizationID: organizationID, Name: name, SecretHash: secretHash}
+	store.clients = append(store.clients, client)
+	return copyClient(client), nil
+}
+
+func (store *Memory) FindClient(id string) (*Client, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	client := store.findClient(id)
+	if client == nil {
+		return nil, ErrNotFound
+	}
+	return copyClient(client), nil
+}
+
+func (store *Memory) SetClientSecret(clientID string, secretHash string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	client := store.findClient(clientID)
+	if client == nil {
+		return ErrNotFound
+	}
+	client.SecretHash = secretHash
+	return nil
+}
+
+func (store *Memory) SetClientRateLimits(clientID string, limits *RateLimits) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	client := store.findClient(clientID)
+	if client == nil {
+		return ErrNotFound
+	}
+	client.RateLimits = copyRateLimits(limits)
+	return nil
+}
+
+func (store *Memory) AddSubscription(clientID string, subscription Subscription) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	client := store.findClient(clientID)
+	if client == nil {
+		return ErrNotFound
+	}
+	for _, existing := range client.Subscriptions {
+		if existing.PublisherID == subscription.PublisherID {
+			return ErrConflict
+		}
+	}
+	client.Subscriptions = append(client.Subscriptions, copySubscription(subscription))
+	return nil
+}
+
+func (store *Memory) RemoveSubscription(clientID string, subscriptionID string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	client := store.findClient(clientID)
+	if client == nil {
+		return ErrNotFound
+	}
+	for i, subscription := range client.Subscriptions {
+		if subscription.ID == subscriptionID {
+			client.Subscriptions = append(client.Subscriptions[:i:i], client.Subscriptions[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (store *Memory) ListWebhooks() ([]WebhookSubscription, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	webhooks := []WebhookSubscription{}
+	for _, client := range store.clients {
+		for _, subscription := range client.Subscriptions {
+			if subscription.Type == SubscriptionTypeWebhook && subscription.Webhook != nil {
+				webhooks = append(webhooks, WebhookSubscription{ClientID: client.ID, Subscription: copySubscription(subscription)})
+			}
+		}
+	}
+	return webhooks, nil
+}
+
+func (store *Memory) RecordWebhookDelivery(clientID string, subscriptionID string, delivery WebhookDelivery) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	client := store.findClient(clientID)
+	if client == nil {
+		return ErrNotFound
+	}
+	for _, subscription := range client.Subscriptions {
+		if subscription.ID != subscriptionID || subscription.Webhook == nil {
+			continue
+		}
+		webhook := subscription.Webhook
+		webhook.Status = delivery.Status
+		webhook.ConsecutiveFailures = delivery.ConsecutiveFailures
+		webhook.LastError = delivery.Error
+		if delivery.Error == "" {
+			webhook.LastSuccessAt = time.Now()
+		} else {
+			webhook.LastFailureAt = time.Now()
+			webhook.NextAttemptAt = delivery.NextAttemptAt
+		}
+		return nil
+	}
+	return ErrNotFound
+}
+
+func (store *Memory) CreatePublisher(ownerID string, organizationID string, name string) (*Publisher, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for _, publisher := range store.publishers {
+		sameSpace := publisher.OwnerID == ownerID
+		if organizationID != "" {
+			sameSpace = publisher.OrganizationID == organizationID
+		}
+		if sameSpace && publisher.Name == name {
+			return nil, ErrConflict
+		}
+	}
+	publisher := &Publisher{ID: uuid.New().String(), Name: name, OwnerID: ownerID, OrganizationID: organizationID}
+	store.publishers = append(store.publishers, publisher)
+	return copyPublisher(publisher), nil
+}
+
+func (store *Memory) FindPublisher(id string) (*Publisher, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for _, publisher := range store.publishers {
+		if publisher.ID == id {
+			return copyPublisher(publisher), nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (store *Memory) FindPublishers(ids []string) ([]Publisher, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	wanted := make(map[string]bool)
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	publishers := []Publisher{}
+	for _, publisher := range store.publishers {
+		if wanted[publisher.ID] {
+			publishers = append(publishers, *copyPublisher(publisher))
+		}
+	}
+	return publishers, nil
+}
+
+func (store *Memory) ListPublishers(ownerID string) ([]Publisher, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	publishers := []Publisher{}
+	for _, publisher := range store.publishers {
+		if publisher.OwnerID == ownerID {
+			publishers = append(publishers, *copyPublisher(publisher))
+		}
+	}
+	return publishers, nil
+}
+
+func (store *Memory) ListSubscribers(publisherID string) ([]Client, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	subscribers := []Client{}
+	for _, client := range store.clients {
+		for _, subscription := range client.Subscriptions {
+			if subscription.PublisherID == publisherID {
+				subscribers = append(subscribers, Client{ID: client.ID, Name: client.Name})
+				break
+			}
+		}
+	}
+	return subscribers, nil
+}
+
+func (store *Memory) SetPublisherAccess(id string, access Access) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for _, publisher := range store.publishers {
+		if publisher.ID == id {
+			publisher.Access = copyAccess(access)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (store *Memory) SetPublisherPayload(id string, policy PayloadPolicy) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for _, publisher := range store.publishers {
+		if publisher.ID == id {
+			publisher.Payload = policy
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (store *Memory) DeletePublisher(id string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	found := false
+	for i, publisher := range store.publishers {
+		if publisher.ID == id {
+			store.publishers = append(store.publishers[:i:i], store.publishers[i+1:]...)
+			found = true
+			break
+		}
+	}
+	if !found {
+		return ErrNotFound
+	}
+	delete(store.messages, id)
+	delete(store.schemas, id)
+	requests := []*SubscriptionRequest{}
+	for _, request := range store.requests {
+		if request.PublisherID != id {
+			requests = append(requests, request)
+		}
+	}
+	store.requests = requests
+	for _, client := range store.clients {
+		remaining := []Subscription{}
+		for _, subscription := range client.Subscriptions {
+			if subscription.PublisherID != id {
+				remaining = append(remaining, subscription)
+			}
+		}
+		client.Subscriptions = remaining
+	}
+	return nil
+}
+
+func (store *Memory) InsertMessage(message Message) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	store.messages[message.PublisherID] = append(store.messages[message.PublisherID], &memoryMessage{
+		message:    message,
+		receivedBy: make(map[string]bool),
+		leases:     make(map[string]time.Time),
+	})
+	return nil
+}
+
+//whether the client hasn't received the message and doesn't currently have it leased
+func (message *memoryMessage) availableTo(clientID string, now time.Time) bool {
+	if message.receivedBy[clientID] {
+		return false
+	}
+	expires, leased := message.leases[clientID]
+	return !leased || !expires.After(now)
+}
+
+//messages of a publisher with the given ids
+func (store *Memory) findMessages(publisherID string, messageIDs []string) []*memoryMessage {
+	wanted := make(map[string]bool)
+	for _, id := range messageIDs {
+		wanted[id] = true
+	}
+	found := []*memoryMessage{}
+	for _, message := range store.messages[publisherID] {
+		if wanted[message.message.ID] {
+			found = append(found, message)
+		}
+	}
+	return found
+}
+
+//whether the client has a subscription to the publisher
+func hasSubscription(client *Client, publisherID string) bool {
+	if client == nil {
+		return false
+	}
+	for _, subscription := range client.Subscriptions {
+		if subscription.PublisherID == publisherID {
+			return true
+		}
+	}
+	return false
+}
+
+func (store *Memory) LeaseMessages(publisherID string, clientID string, max int, expires time.Time) ([]Message, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	now := time.Now()
+	messages := []Message{}
+	if !hasSubscription(store.findClient(clientID), publisherID) {
+		return messages, nil
+	}
+	for _, message := range store.messages[publisherID] {
+		if len(messages) >= max {
+			break
+		}
+		if message.availableTo(clientID, now) {
+			message.leases[clientID] = expires
+			messages = append(messages, message.message)
+		}
+	}
+	return messages, nil
+}
+
+func (store *Memory) RenewLeases(clientID string, messageIDs []string, expires time.Time) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for publisherID := range store.messages {
+		for _, message := range store.findMessages(publisherID, messageIDs) {
+			if _, leased := message.leases[clientID]; leased {
+				message.leases[clientID] = expires
+			}
+		}
+	}
+	return nil
+}
+
+func (store *Memory) ReleaseLeases(publisherID string, clientID string, messageIDs []string, availableAt time.Time) (int, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	released := 0
+	for _, message := range store.findMessages(publisherID, messageIDs) {
+		if _, leased := message.leases[clientID]; !leased {
+			continue
+		}
+		if availableAt.IsZero() {
+			delete(message.leases, clientID)
+		} else {
+			message.leases[clientID] = availableAt
+		}
+		released++
+	}
+	return released, nil
+}
+
+func (store *Memory) ConfirmMessages(publisherID string, clientID string, messageIDs []string) (int, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	confirmed := 0
+	for _, message := range store.findMessages(publisherID, messageIDs) {
+		if !message.receivedBy[clientID] {
+			message.receivedBy[clientID] = true
+			delete(message.leases, clientID)
+			confirmed++
+		}
+	}
+	return confirmed, nil
+}
+
+func (store *Memory) ConfirmMessagesThrough(publisherID string, clientID string, messageID string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	messages := store.messages[publisherID]
+	for i, message := range messages {
+		if message.message.ID != messageID {
+			continue
+		}
+		for _, earlier := range messages[:i+1] {
+			earlier.receivedBy[clientID] = true
+			delete(earlier.leases, clientID)
+		}
+		return nil
+	}
+	return ErrNotFound
+}
+
+func (store *Memory) DeleteExpiredMessages(now time.Time) (int, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	deleted := 0
+	for publisherID, messages := range store.messages {
+		remaining := []*memoryMessage{}
+		for _, message := range messages {
+			if !message.message.ExpiresAt.IsZero() && message.message.ExpiresAt.Before(now) {
+				deleted++
+				continue
+			}
+			remaining = append(remaining, message)
+		}
+		store.messages[publisherID] = remaining
+	}
+	return deleted, nil
+}
+
+func (store *Memory) MessageUsage(publisherIDs []string) (map[string]Usage, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	usage := make(map[string]Usage)
+	for _, id := range publisherIDs {
+		publisherUsage := Usage{}
+		for _, message := range store.messages[id] {
+			publisherUsage.Messages++
+			publisherUsage.Bytes += int64(len(message.message.Payload))
+		}
+		usage[id] = publisherUsage
+	}
+	return usage, nil
+}
+
+func (store *Memory) AcquireLease(name string, holder string, expires time.Time) (bool, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	lease, exists := store.leases[name]
+	if exists && lease.holder != holder && lease.expires.After(time.Now()) {
+		return false, nil
+	}
+	store.leases[name] = memoryLease{holder: holder, expires: expires}
+	return true, nil
+}
+
+func (store *Memory) ReleaseLease(name string, holder string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	if lease, exists := store.leases[name]; exists && lease.holder == holder {
+		delete(store.leases, name)
+	}
+	return nil
+}
+
+func (store *Memory) RegisterInstance(id string, address string, expires time.Time) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	store.instances[id] = expires
+	return nil
+}
+
+func (store *Memory) RemoveInstance(id string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	delete(store.instances, id)
+	return nil
+}
+
+func (store *Memory) RemoveExpiredInstances(now time.Time) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for id, expires := range store.instances {
+		if expires.Before(now) {
+			delete(store.instances, id)
+		}
+	}
+	return nil
+}
+
+func (store *Memory) Close() error {
+	return nil
+}
+
+func copyAPIKey(key *APIKey) *APIKey {
+	result := *key
+	result.Scopes = append([]string{}, key.Scopes...)
+	return &result
+}
+
+func (store *Memory) CreateAPIKey(key APIKey) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	if store.findClient(key.ClientID) == nil {
+		return ErrNotFound
+	}
+	for _, existing := range store.apiKeys {
+		if existing.ID == key.ID {
+			return ErrConflict
+		}
+	}
+	store.apiKeys = append(store.apiKeys, copyAPIKey(&key))
+	return nil
+}
+
+func (store *Memory) FindAPIKey(id string) (*APIKey, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for _, key := range store.apiKeys {
+		if key.ID == id {
+			return copyAPIKey(key), nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (store *Memory) ListAPIKeys(clientID string) ([]APIKey, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	keys := []APIKey{}
+	for _, key := range store.apiKeys {
+		if key.ClientID == clientID {
+			keys = append(keys, *copyAPIKey(key))
+		}
+	}
+	return keys, nil
+}
+
+func (store *Memory) DeleteAPIKey(clientID string, id string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for i, key := range store.apiKeys {
+		if key.ID == id && key.ClientID == clientID {
+			store.apiKeys = append(store.apiKeys[:i:i], store.apiKeys[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (store *Memory) CreateGroup(ownerID string, name string, members []string) (*Group, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for _, group := range store.groups {
+		if group.OwnerID == ownerID && group.Name == name {
+			return nil, ErrConflict
+		}
+	}
+	group := &Group{ID: uuid.New().String(), OwnerID: ownerID, Name: name, Members: append([]string{}, members...)}
+	store.groups = append(store.groups, group)
+	return copyGroup(group), nil
+}
+
+func (store *Memory) FindGroup(id string) (*Group, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for _, group := range store.groups {
+		if group.ID == id {
+			return copyGroup(group), nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (store *Memory) ListGroups(ownerID string) ([]Group, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	groups := []Group{}
+	for _, group := range store.groups {
+		if group.OwnerID == ownerID {
+			groups = append(groups, *copyGroup(group))
+		}
+	}
+	return groups, nil
+}
+
+func (store *Memory) SetGroupMembers(id string, members []string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for _, group := range store.groups {
+		if group.ID == id {
+			group.Members = append([]string{}, members...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (store *Memory) DeleteGroup(id string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for i, group := range store.groups {
+		if group.ID == id {
+			store.groups = append(store.groups[:i:i], store.groups[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func copyRequest(request *SubscriptionRequest) *SubscriptionRequest {
+	result := *request
+	result.Subscription = copySubscription(request.Subscription)
+	return &result
+}
+
+func (store *Memory) CreateSubscriptionRequest(request SubscriptionRequest) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for _, existing := range store.requests {
+		if existing.ID == request.ID || (existing.ClientID == request.ClientID &&
+			existing.PublisherID == request.PublisherID && existing.Status == RequestPending) {
+			return ErrConflict
+		}
+	}
+	store.requests = append(store.requests, copyRequest(&request))
+	return nil
+}
+
+func (store *Memory) FindSubscriptionRequest(id string) (*SubscriptionRequest, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for _, request := range store.requests {
+		if request.ID == id {
+			return copyRequest(request), nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (store *Memory) ListSubscriptionRequests(query SubscriptionRequestQuery) ([]SubscriptionRequest, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	requests := []SubscriptionRequest{}
+	for _, request := range store.requests {
+		if query.matches(request) {
+			requests = append(requests, *copyRequest(request))
+		}
+	}
+	//requests loaded from disk aren't in the order they were made
+	sort.SliceStable(requests, func(i, j int) bool {
+		return requests[i].CreatedAt.Before(requests[j].CreatedAt)
+	})
+	return requests, nil
+}
+
+func (store *Memory) DecideSubscriptionRequest(id string, status string, reason string, decidedAt time.Time) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for _, request := range store.requests {
+		if request.ID != id {
+			continue
+		}
+		if request.Status != RequestPending {
+			return ErrConflict
+		}
+		request.Status = status
+		request.Reason = reason
+		request.DecidedAt = decidedAt
+		return nil
+	}
+	return ErrNotFound
+}
+
+func (store *Memory) AddSchema(schema Schema) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	if store.findPublisher(schema.PublisherID) == nil {
+		return ErrNotFound
+	}
+	versions := store.schemas[schema.PublisherID]
+	if schema.Version != len(versions)+1 {
+		return ErrConflict
+	}
+	store.schemas[schema.PublisherID] = append(versions, schema)
+	return nil
+}
+
+func (store *Memory) FindSchema(publisherID string, version int) (*Schema, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	versions := store.schemas[publisherID]
+	if version == 0 {
+		version = len(versions)
+	}
+	for _, schema := range versions {
+		if schema.Version == version {
+			return &schema, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (store *Memory) ListSchemas(publisherID string) ([]Schema, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	return append([]Schema{}, store.schemas[publisherID]...), nil
+}
+
+//putClient replaces the client with the same id or adds it if there isn't one, used to restore the state kept
+//by other backends
+func (store *Memory) putClient(client *Client) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	stored := copyClient(client)
+	for i, existing := range store.clients {
+		if existing.ID == client.ID {
+			store.clients[i] = stored
+			return
+		}
+	}
+	store.clients = append(store.clients, stored)
+}
+
+func (store *Memory) removeClient(id string) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for i, client := range store.clients {
+		if client.ID == id {
+			store.clients = append(store.clients[:i:i], store.clients[i+1:]...)
+			return
+		}
+	}
+}
+
+func (store *Memory) CreateOrganization(name string) (*Organization, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for _, organization := range store.orgs {
+		if organization.Name == name {
+			return nil, ErrConflict
+		}
+	}
+	organization := &Organization{ID: uuid.New().String(), Name: name, Admins: []string{}}
+	store.orgs = append(store.orgs, organization)
+	return copyOrganization(organization), nil
+}
+
+func (store *Memory) FindOrganization(id string) (*Organization, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for _, organization := range store.orgs {
+		if organization.ID == id {
+			return copyOrganization(organization), nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (store *Memory) SetOrganizationAdmins(id string, admins []string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for _, organization := range store.orgs {
+		if organization.ID == id {
+			organization.Admins = append([]string{}, admins...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (store *Memory) ListMembers(organizationID string) ([]Client, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	members := []Client{}
+	for _, client := range store.clients {
+		if client.OrganizationID == organizationID {
+			members = append(members, Client{ID: client.ID, OrganizationID: client.OrganizationID, Name: client.Name})
+		}
+	}
+	return members, nil
+}
+
+func (store *Memory) ListOrganizationPublishers(organizationID string) ([]Publisher, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	publishers := []Publisher{}
+	for _, publisher := range store.publishers {
+		if publisher.OrganizationID == organizationID {
+			publishers = append(publishers, *copyPublisher(publisher))
+		}
+	}
+	return publishers, nil
+}
+
+//putOrganization replaces the organization with the same id or adds it if there isn't one
+func (store *Memory) putOrganization(organization Organization) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for i, existing := range store.orgs {
+		if existing.ID == organization.ID {
+			store.orgs[i] = copyOrganization(&organization)
+			return
+		}
+	}
+	store.orgs = append(store.orgs, copyOrganization(&organization))
+}
+
+func (store *Memory) removeOrganization(id string) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for i, organization := range store.orgs {
+		if organization.ID == id {
+			store.orgs = append(store.orgs[:i:i], store.orgs[i+1:]...)
+			return
+		}
+	}
+}
+
+//putPublisher replaces the publisher with the same id or adds it if there isn't one
+func (store *Memory) putPublisher(publisher Publisher) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for i, existing := range store.publishers {
+		if existing.ID == publisher.ID {
+			store.publishers[i] = copyPublisher(&publisher)
+			return
+		}
+	}
+	store.publishers = append(store.publishers, copyPublisher(&publisher))
+}
+
+func (store *Memory) removePublisher(id string) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for i, publisher := range store.publishers {
+		if publisher.ID == id {
+			store.publishers = append(store.publishers[:i:i], store.publishers[i+1:]...)
+			return
+		}
+	}
+}
+
+//putAPIKey replaces the key with the same id or adds it if there isn't one
+func (store *Memory) putAPIKey(key APIKey) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for i, existing := range store.apiKeys {
+		if existing.ID == key.ID {
+			store.apiKeys[i] = copyAPIKey(&key)
+			return
+		}
+	}
+	store.apiKeys = append(store.apiKeys, copyAPIKey(&key))
+}
+
+//putGroup replaces the group with the same id or adds it if there isn't one
+func (store *Memory) putGroup(group Group) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for i, existing := range store.groups {
+		if existing.ID == group.ID {
+			store.groups[i] = copyGroup(&group)
+			return
+		}
+	}
+	store.groups = append(store.groups, copyGroup(&group))
+}
+
+//putRequest replaces the subscription request with the same id or adds it if there isn't one
+func (store *Memory) putRequest(request SubscriptionRequest) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for i, existing := range store.requests {
+		if existing.ID == request.ID {
+			store.requests[i] = copyRequest(&request)
+			return
+		}
+	}
+	store.requests = append(store.requests, copyRequest(&request))
+}
+
+func (store *Memory) removeRequest(id string) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	for i, request := range store.requests {
+		if request.ID == id {
+			store.requests = append(store.requests[:i:i], store.requests[i+1:]...)
+			return
+		}
+	}
+}
+
+//putSchema adds a version of a publisher's schema, keeping the versions in order
+func (store *Memory) putSchema(schema Schema) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	versions := store.schemas[schema.PublisherID]
+	for i, existing := range versions {
+		if existing.Version == schema.Version {
+			versions[i] = schema
+			return
+		}
+	}
+	versions = append(versions, schema)
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version < versions[j].Version
+	})
+	store.schemas[schema.PublisherID] = versions
+}
+
+func (store *Memory) removeSchema(publisherID string, version int) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	versions := store.schemas[publisherID]
+	for i, schema := range versions {
+		if schema.Version == version {
+			store.schemas[publisherID] = append(versions[:i:i], versions[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,433 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	segmentSize             = 16 << 20 //a new segment is started once the current one reaches this size
+	logCompactMinGarbage    = 4 << 20  //a log isn't rewritten until it has at least this many bytes of garbage
+	currentGenerationFile   = "CURRENT"
+	logRecordMessage        = "message"
+	logRecordConfirm        = "confirm"         //messages received by a client
+	logRecordConfirmThrough = "confirm_through" //every message up to and including one received by a client
+)
+
+//messageLog keeps the messages of a single publisher as records appended to a series of segment files. an index
+//of the messages still in the log is held in memory, along with whether each client has received them, while
+//the payloads are read back from the segments when they're needed.
+//
+//the segments live in a directory per generation, named in CURRENT. old segments are dropped once every message
+//in them has expired, and when too much of the log is garbage it's rewritten as a new generation which replaces
+//the old one by updating CURRENT
+type messageLog struct {
+	dir        string
+	generation int
+	segments   []*logSegment
+	entries    []*logEntry //messages in the log, oldest first
+	byID       map[string]*logEntry
+	live       int64 //bytes of message records still in use
+	garbage    int64 //bytes of records which would be left out if the log was rewritten
+	payloads   int64 //bytes of the payloads of the messages in the log, for quotas
+	noSync     bool  //leave syncing the segments to the caller, used while rewriting the log
+}
+
+type logSegment struct {
+	number int
+	file   *os.File
+	size   int64
+	live   int //messages in the segment which haven't been deleted
+}
+
+//logEntry indexes a message in the log
+type logEntry struct {
+	id         string
+	createdAt  time.Time
+	expiresAt  time.Time
+	segment    *logSegment
+	offset     int64
+	size       int64
+	payload    int64 //bytes of the message's payload
+	receivedBy map[string]bool
+	leases     map[string]time.Time //client id to the time its lease on the message expires, not kept on disk
+}
+
+type logRecord struct {
+	Type       string      `json:"type"`
+	Message    *logMessage `json:"message,omitempty"`
+	ClientID   string      `json:"client_id,omitempty"`
+	MessageIDs []string    `json:"message_ids,omitempty"`
+}
+
+type logMessage struct {
+	ID            string    `json:"id"`
+	Payload       string    `json:"payload"`
+	CreatedAt     time.Time `json:"created_at"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	SchemaVersion int       `json:"schema_version,omitempty"`
+	ReceivedBy    []string  `json:"received_by,omitempty"` //clients which had received it when the log was rewritten
+}
+
+func segmentName(number int) string {
+	return fmt.Sprintf("%020d.log", number)
+}
+
+func (log *messageLog) generationDir(generation int) string {
+	return filepath.Join(log.dir, strconv.Itoa(generation))
+}
+
+//open the log in dir, creating it if it doesn't exist, and rebuild the index from its segments. anything after
+//the last complete record of a segment was left by a write which didn't finish and is truncated
+func openMessageLog(dir string) (*messageLog, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	log := &messageLog{dir: dir, byID: make(map[string]*logEntry)}
+	current, err := os.ReadFile(filepath.Join(dir, currentGenerationFile))
+	if err == nil {
+		log.generation, err = strconv.Atoi(strings.TrimSpace(string(current)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s in %s", currentGenerationFile, dir)
+		}
+	} else if os.IsNotExist(err) {
+		err = log.writeCurrent(0)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, err
+	}
+
+	//remove generations left behind by a rewrite which didn't finish or whose old generation wasn't removed
+	items, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if item.Name() != currentGenerationFile && item.Name() != strconv.Itoa(log.generation) {
+			os.RemoveAll(filepath.Join(dir, item.Name()))
+		}
+	}
+
+	generationDir := log.generationDir(log.generation)
+	err = os.MkdirAll(generationDir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	items, err = os.ReadDir(generationDir)
+	if err != nil {
+		return nil, err
+	}
+	numbers := []int{}
+	for _, item := range items {
+		number, err := strconv.Atoi(strings.TrimSuffix(item.Name(), ".log"))
+		if err == nil && strings.HasSuffix(item.Name(), ".log") {
+			numbers = append(numbers, number)
+		}
+	}
+	sort.Ints(numbers)
+	for _, number := range numbers {
+		err = log.loadSegment(number)
+		if err != nil {
+			log.close()
+			return nil, err
+		}
+	}
+	//deleting expired messages isn't written to the log, so take out any which expired before the restart. done
+	//once everything is loaded so records confirming them still apply to the messages around them
+	log.deleteExpired(time.Now())
+	return log, nil
+}
+
+func (log *messageLog) loadSegment(number int) error {
+	file, err := os.OpenFile(filepath.Join(log.generationDir(log.generation), segmentName(number)), os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+	segment := &logSegment{number: number, file: file}
+	log.segments = append(log.segments, segment)
+	validSize, err := scanRecords(file, func(offset int64, size int64, data []byte) error {
+		record := logRecord{}
+		err := json.Unmarshal(data, &record)
+		if err != nil {
+			return err
+		}
+		log.apply(record, segment, offset, size)
+		return nil
+	})
+	if err == nil {
+		err = truncateAfter(file, validSize)
+	}
+	segment.size = validSize
+	return err
+}
+
+//update the index with a record
+func (log *messageLog) apply(record logRecord, segment *logSegment, offset int64, size int64) {
+	switch record.Type {
+	case logRecordMessage:
+		if record.Message == nil || log.byID[record.Message.ID] != nil {
+			log.garbage += size
+			return
+		}
+		entry := &logEntry{
+			id:         record.Message.ID,
+			createdAt:  record.Message.CreatedAt,
+			expiresAt:  record.Message.ExpiresAt,
+			segment:    segment,
+			offset:     offset,
+			size:       size,
+			payload:    int64(len(record.Message.Payload)),
+			receivedBy: make(map[string]bool),
+			leases:     make(map[string]time.Time),
+		}
+		for _, clientID := range record.Message.ReceivedBy {
+			entry.receivedBy[clientID] = true
+		}
+		log.entries = append(log.entries, entry)
+		log.byID[entry.id] = entry
+		segment.live++
+		log.live += size
+		log.payloads += entry.payload
+	case logRecordConfirm:
+		for _, id := range record.MessageIDs {
+			if entry := log.byID[id]; entry != nil {
+				entry.receive(record.ClientID)
+			}
+		}
+		log.garbage += size
+	case logRecordConfirmThrough:
+		if len(record.MessageIDs) == 1 && log.byID[record.MessageIDs[0]] != nil {
+			for _, entry := range log.entries {
+				entry.receive(record.ClientID)
+				if entry.id == record.MessageIDs[0] {
+					break
+				}
+			}
+		}
+		log.garbage += size
+	default:
+		log.garbage += size
+	}
+}
+
+func (entry *logEntry) receive(clientID string) {
+	entry.receivedBy[clientID] = true
+	delete(entry.leases, clientID)
+}
+
+//whether the client hasn't received the message and doesn't currently have it leased
+func (entry *logEntry) availableTo(clientID string, now time.Time) bool {
+	if entry.receivedBy[clientID] {
+		return false
+	}
+	expires, leased := entry.leases[clientID]
+	return !leased || !expires.After(now)
+}
+
+//point CURRENT at a generation, written alongside and renamed over the old one so it's replaced in one step
+func (log *messageLog) writeCurrent(generation int) error {
+	path := filepath.Join(log.dir, currentGenerationFile)
+	file, err := os.Create(path + ".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = file.WriteString(strconv.Itoa(generation))
+	if err == nil {
+		err = file.Sync()
+	}
+	file.Close()
+	if err == nil {
+		err = os.Rename(path+".tmp", path)
+	}
+	if err != nil {
+		os.Remove(path + ".tmp")
+		return err
+	}
+	syncDirectory(log.dir)
+	return nil
+}
+
+//the segment new records are appended to, starting a new one if the last is full
+func (log *messageLog) activeSegment() (*logSegment, error) {
+	if len(log.segments) > 0 && log.segments[len(log.segments)-1].size < segmentSize {
+		return log.segments[len(log.segments)-1], nil
+	}
+	number := 0
+	if len(log.segments) > 0 {
+		number = log.segments[len(log.segments)-1].number + 1
+	}
+	file, err := os.OpenFile(filepath.Join(log.generationDir(log.generation), segmentName(number)), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, err
+	}
+	syncDirectory(log.generationDir(log.generation))
+	segment := &logSegment{number: number, file: file}
+	log.segments = append(log.segments, segment)
+	return segment, nil
+}
+
+//append a record to the log and apply it to the index once it's on disk
+func (log *messageLog) append(record logRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	segment, err := log.activeSegment()
+	if err != nil {
+		return err
+	}
+	offset := segment.size
+	size, err := writeRecord(segment.file, offset, data)
+	if err == nil && !log.noSync {
+		err = segment.file.Sync()
+	}
+	if err != nil {
+		//anything partly written is overwritten by the next record or truncated when the log is next opened
+		return err
+	}
+	segment.size += size
+	log.apply(record, segment, offset, size)
+	return nil
+}
+
+func (log *messageLog) insert(message Message) error {
+	return log.append(logRecord{Type: logRecordMessage, Message: &logMessage{
+		ID:            message.ID,
+		Payload:       message.Payload,
+		CreatedAt:     message.CreatedAt,
+		ExpiresAt:     message.ExpiresAt,
+		SchemaVersion: message.SchemaVersion,
+	}})
+}
+
+//read a message back from its segment
+func (log *messageLog) read(entry *logEntry) (logMessage, error) {
+	data, err := readRecord(entry.segment.file, entry.offset, entry.size)
+	if err != nil {
+		return logMessage{}, err
+	}
+	record := logRecord{}
+	err = json.Unmarshal(data, &record)
+	if err != nil {
+		return logMessage{}, err
+	}
+	if record.Message == nil || record.Message.ID != entry.id {
+		return logMessage{}, errCorruptRecord
+	}
+	return *record.Message, nil
+}
+
+//remove messages which have expired from the index, returning the number removed. their records become garbage
+func (log *messageLog) deleteExpired(now time.Time) int {
+	remaining := []*logEntry{}
+	deleted := 0
+	for _, entry := range log.entries {
+		if entry.expiresAt.IsZero() || !entry.expiresAt.Before(now) {
+			remaining = append(remaining, entry)
+			continue
+		}
+		delete(log.byID, entry.id)
+		entry.segment.live--
+		log.live -= entry.size
+		log.payloads -= entry.payload
+		log.garbage += entry.size
+		deleted++
+	}
+	log.entries = remaining
+	return deleted
+}
+
+//remove the oldest segments while none of their messages are left. any records confirming their messages are in
+//the same or later segments and are ignored once the messages are gone, so the log stays valid whenever this stops
+func (log *messageLog) dropDeadSegments() error {
+	for len(log.segments) > 1 && log.segments[0].live == 0 {
+		segment := log.segments[0]
+		segment.file.Close()
+		err := os.Remove(filepath.Join(log.generationDir(log.generation), segmentName(segment.number)))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		log.garbage -= segment.size
+		if log.garbage < 0 {
+			log.garbage = 0
+		}
+		log.segments = log.segments[1:]
+	}
+	return nil
+}
+
+//rewrite the log as a new generation once it's mostly garbage, folding the confirmations into the message records
+func (log *messageLog) compactIfNeeded() error {
+	if log.garbage < logCompactMinGarbage || log.garbage < log.live {
+		return nil
+	}
+	return log.compact()
+}
+
+func (log *messageLog) compact() error {
+	generation := log.generation + 1
+	generationDir := log.generationDir(generation)
+	os.RemoveAll(generationDir)
+	err := os.MkdirAll(generationDir, 0755)
+	if err != nil {
+		return err
+	}
+	rewritten := &messageLog{dir: log.dir, generation: generation, byID: make(map[string]*logEntry), noSync: true}
+	abandon := func(err error) error {
+		rewritten.close()
+		os.RemoveAll(generationDir)
+		return err
+	}
+	for _, entry := range log.entries {
+		message, err := log.read(entry)
+		if err != nil {
+			return abandon(err)
+		}
+		message.ReceivedBy = []string{}
+		for clientID := range entry.receivedBy {
+			message.ReceivedBy = append(message.ReceivedBy, clientID)
+		}
+		sort.Strings(message.ReceivedBy)
+		err = rewritten.append(logRecord{Type: logRecordMessage, Message: &message})
+		if err != nil {
+			return abandon(err)
+		}
+	}
+	for _, segment := range rewritten.segments {
+		err = segment.file.Sync()
+		if err != nil {
+			return abandon(err)
+		}
+	}
+	syncDirectory(generationDir)
+	err = log.writeCurrent(generation)
+	if err != nil {
+		return abandon(err)
+	}
+
+	//the new generation is in use, carry over the leases and drop the old one
+	for _, entry := range rewritten.entries {
+		entry.leases = log.byID[entry.id].leases
+	}
+	oldDir := log.generationDir(log.generation)
+	log.close()
+	os.RemoveAll(oldDir)
+	rewritten.noSync = false
+	*log = *rewritten
+	return nil
+}
+
+func (log *messageLog) close() {
+	for _, segment := range log.segments {
+		segment.file.Close()
+	}
+}
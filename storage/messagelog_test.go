@@ -0,0 +1,398 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func openLog(t *testing.T, dir string) *messageLog {
+	t.Helper()
+	log, err := openMessageLog(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(log.close)
+	return log
+}
+
+func insertMessages(t *testing.T, log *messageLog, ids ...string) {
+	t.Helper()
+	for _, id := range ids {
+		err := log.insert(Message{ID: id, Payload: "payload of " + id, CreatedAt: time.Now()})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+//ids of the messages in the log, oldest first
+func logIDs(log *messageLog) []string {
+	ids := []string{}
+	for _, entry := range log.entries {
+		ids = append(ids, entry.id)
+	}
+	return ids
+}
+
+//clients which have received each message in the log
+func logReceived(log *messageLog) map[string][]string {
+	received := map[string][]string{}
+	for _, entry := range log.entries {
+		clients := []string{}
+		for clientID := range entry.receivedBy {
+			clients = append(clients, clientID)
+		}
+		sort.Strings(clients)
+		received[entry.id] = clients
+	}
+	return received
+}
+
+//path of a segment in the log's current generation
+func segmentPath(log *messageLog, number int) string {
+	return filepath.Join(log.generationDir(log.generation), segmentName(number))
+}
+
+//write a segment of the first generation of the log in dir directly
+func writeSegment(t *testing.T, dir string, number int, records ...logRecord) {
+	t.Helper()
+	generationDir := filepath.Join(dir, "0")
+	if err := os.MkdirAll(generationDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Create(filepath.Join(generationDir, segmentName(number)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	offset := int64(0)
+	for _, record := range records {
+		data, err := json.Marshal(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		size, err := writeRecord(file, offset, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		offset += size
+	}
+}
+
+func TestMessageLogReplaysConfirmations(t *testing.T) {
+	dir := t.TempDir()
+	log := openLog(t, dir)
+	insertMessages(t, log, "m1", "m2", "m3")
+	for _, record := range []logRecord{
+		{Type: logRecordConfirm, ClientID: "c1", MessageIDs: []string{"m2"}},
+		{Type: logRecordConfirmThrough, ClientID: "c2", MessageIDs: []string{"m2"}},
+		//a message which isn't in the log confirms nothing
+		{Type: logRecordConfirmThrough, ClientID: "c3", MessageIDs: []string{"missing"}},
+	} {
+		if err := log.append(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	log.close()
+
+	reopened := openLog(t, dir)
+	if ids := logIDs(reopened); !reflect.DeepEqual(ids, []string{"m1", "m2", "m3"}) {
+		t.Fatalf("reopened with %v", ids)
+	}
+	expected := map[string][]string{"m1": {"c2"}, "m2": {"c1", "c2"}, "m3": {}}
+	if received := logReceived(reopened); !reflect.DeepEqual(received, expected) {
+		t.Fatalf("received %v", received)
+	}
+	message, err := reopened.read(reopened.byID["m3"])
+	if err != nil || message.Payload != "payload of m3" {
+		t.Fatalf("read %v, %v", message, err)
+	}
+}
+
+func TestMessageLogRecovers(t *testing.T) {
+	tests := []struct {
+		name     string
+		damage   func(t *testing.T, log *messageLog)
+		expected []string
+	}{
+		{
+			name: "torn message",
+			damage: func(t *testing.T, log *messageLog) {
+				entry := log.byID["m3"]
+				truncate(t, entry.segment.file, entry.offset+entry.size/2)
+			},
+			expected: []string{"m1", "m2"},
+		},
+		{
+			name: "torn header",
+			damage: func(t *testing.T, log *messageLog) {
+				entry := log.byID["m3"]
+				truncate(t, entry.segment.file, entry.offset+recordHeaderSize-1)
+			},
+			expected: []string{"m1", "m2"},
+		},
+		{
+			name: "corrupt message",
+			damage: func(t *testing.T, log *messageLog) {
+				entry := log.byID["m2"]
+				flipByte(t, entry.segment.file, entry.offset+entry.size-3)
+			},
+			expected: []string{"m1"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			log := openLog(t, dir)
+			insertMessages(t, log, "m1", "m2", "m3")
+			test.damage(t, log)
+			log.close()
+
+			reopened := openLog(t, dir)
+			if ids := logIDs(reopened); !reflect.DeepEqual(ids, test.expected) {
+				t.Fatalf("reopened with %v", ids)
+			}
+			if fileSize(t, segmentPath(reopened, 0)) != reopened.segments[0].size {
+				t.Fatalf("segment is %d bytes, the valid records end at %d", fileSize(t, segmentPath(reopened, 0)), reopened.segments[0].size)
+			}
+
+			//messages written after the recovered records survive the next reopen
+			insertMessages(t, reopened, "m4")
+			reopened.close()
+			if ids := logIDs(openLog(t, dir)); !reflect.DeepEqual(ids, append(test.expected, "m4")) {
+				t.Fatalf("reopened with %v after writing past the recovered records", ids)
+			}
+		})
+	}
+}
+
+func TestMessageLogTornConfirmation(t *testing.T) {
+	dir := t.TempDir()
+	log := openLog(t, dir)
+	insertMessages(t, log, "m1", "m2")
+	if err := log.append(logRecord{Type: logRecordConfirm, ClientID: "c1", MessageIDs: []string{"m1"}}); err != nil {
+		t.Fatal(err)
+	}
+	size := log.segments[0].size
+	if err := log.append(logRecord{Type: logRecordConfirmThrough, ClientID: "c1", MessageIDs: []string{"m2"}}); err != nil {
+		t.Fatal(err)
+	}
+	truncate(t, log.segments[0].file, size+recordHeaderSize+1)
+	log.close()
+
+	//the confirmation which was cut short is lost, so m2 is delivered to c1 again
+	reopened := openLog(t, dir)
+	expected := map[string][]string{"m1": {"c1"}, "m2": {}}
+	if received := logReceived(reopened); !reflect.DeepEqual(received, expected) {
+		t.Fatalf("received %v", received)
+	}
+}
+
+func TestMessageLogCompaction(t *testing.T) {
+	dir := t.TempDir()
+	log := openLog(t, dir)
+	insertMessages(t, log, "m1", "m2", "m3")
+	expiring := Message{ID: "expired", Payload: "gone", CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)}
+	if err := log.insert(expiring); err != nil {
+		t.Fatal(err)
+	}
+	if err := log.append(logRecord{Type: logRecordConfirm, ClientID: "c1", MessageIDs: []string{"m1", "m2"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := log.append(logRecord{Type: logRecordConfirm, ClientID: "c2", MessageIDs: []string{"m1"}}); err != nil {
+		t.Fatal(err)
+	}
+	log.deleteExpired(time.Now().Add(2 * time.Hour))
+	log.byID["m3"].leases["c1"] = time.Now().Add(time.Minute)
+	if err := log.compact(); err != nil {
+		t.Fatal(err)
+	}
+
+	//every message which hasn't expired is kept, whether or not it's been received, along with who received it
+	expectedIDs := []string{"m1", "m2", "m3"}
+	expectedReceived := map[string][]string{"m1": {"c1", "c2"}, "m2": {"c1"}, "m3": {}}
+	if ids := logIDs(log); !reflect.DeepEqual(ids, expectedIDs) {
+		t.Fatalf("compacted to %v", ids)
+	}
+	if received := logReceived(log); !reflect.DeepEqual(received, expectedReceived) {
+		t.Fatalf("compacted with %v received", received)
+	}
+	if _, leased := log.byID["m3"].leases["c1"]; !leased {
+		t.Fatal("lease lost by the compaction")
+	}
+	if log.generation != 1 || log.garbage != 0 {
+		t.Fatalf("compacted to generation %d with %d bytes of garbage", log.generation, log.garbage)
+	}
+	current, err := os.ReadFile(filepath.Join(dir, currentGenerationFile))
+	if err != nil || string(current) != "1" {
+		t.Fatalf("CURRENT is %q, %v", current, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "0")); !os.IsNotExist(err) {
+		t.Fatalf("old generation left behind, %v", err)
+	}
+	message, err := log.read(log.byID["m2"])
+	if err != nil || message.Payload != "payload of m2" {
+		t.Fatalf("read %v, %v", message, err)
+	}
+
+	//new records go to the new generation and the confirmations folded into the messages survive a reopen
+	insertMessages(t, log, "m4")
+	log.close()
+	reopened := openLog(t, dir)
+	if ids := logIDs(reopened); !reflect.DeepEqual(ids, append(expectedIDs, "m4")) {
+		t.Fatalf("reopened with %v", ids)
+	}
+	expectedReceived["m4"] = []string{}
+	if received := logReceived(reopened); !reflect.DeepEqual(received, expectedReceived) {
+		t.Fatalf("reopened with %v received", received)
+	}
+}
+
+func TestMessageLogInterruptedCompaction(t *testing.T) {
+	tests := []struct {
+		name  string
+		crash func(t *testing.T, dir string)
+	}{
+		{
+			//stopped while writing the new generation, before CURRENT was updated
+			name: "before switching generation",
+			crash: func(t *testing.T, dir string) {
+				writeSegment(t, filepath.Join(dir, "next"), 0)
+				if err := os.Rename(filepath.Join(dir, "next", "0"), filepath.Join(dir, "1")); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			//stopped after CURRENT was updated, before the old generation was removed
+			name: "after switching generation",
+			crash: func(t *testing.T, dir string) {
+				log := openLog(t, dir)
+				if err := log.compact(); err != nil {
+					t.Fatal(err)
+				}
+				log.close()
+				writeSegment(t, dir, 0, logRecord{Type: logRecordMessage, Message: &logMessage{ID: "stale"}})
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			log := openLog(t, dir)
+			insertMessages(t, log, "m1", "m2")
+			if err := log.append(logRecord{Type: logRecordConfirm, ClientID: "c1", MessageIDs: []string{"m1"}}); err != nil {
+				t.Fatal(err)
+			}
+			log.close()
+			test.crash(t, dir)
+
+			reopened := openLog(t, dir)
+			expected := map[string][]string{"m1": {"c1"}, "m2": {}}
+			if received := logReceived(reopened); !reflect.DeepEqual(received, expected) {
+				t.Fatalf("reopened with %v", received)
+			}
+			items, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names := []string{}
+			for _, item := range items {
+				names = append(names, item.Name())
+			}
+			expectedNames := []string{currentGenerationFile, strings.TrimSpace(readFile(t, filepath.Join(dir, currentGenerationFile)))}
+			sort.Strings(expectedNames)
+			if !reflect.DeepEqual(names, expectedNames) {
+				t.Fatalf("left %v in the log's directory", names)
+			}
+		})
+	}
+}
+
+func TestMessageLogInvalidCurrent(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, currentGenerationFile), []byte("garbage"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := openMessageLog(dir); err == nil {
+		t.Fatal("opened a log with an invalid CURRENT")
+	}
+}
+
+func TestMessageLogDropDeadSegments(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	expired := now.Add(-time.Hour)
+	message := func(id string, expiresAt time.Time) logRecord {
+		return logRecord{Type: logRecordMessage, Message: &logMessage{ID: id, Payload: id, CreatedAt: now, ExpiresAt: expiresAt}}
+	}
+	writeSegment(t, dir, 0, message("m1", expired), message("m2", expired))
+	writeSegment(t, dir, 1,
+		logRecord{Type: logRecordConfirm, ClientID: "c1", MessageIDs: []string{"m1"}},
+		message("m3", expired),
+		message("m4", time.Time{}),
+	)
+	writeSegment(t, dir, 2, logRecord{Type: logRecordConfirmThrough, ClientID: "c2", MessageIDs: []string{"m2"}})
+
+	//the expired messages are deleted as the log is opened
+	log := openLog(t, dir)
+	if ids := logIDs(log); !reflect.DeepEqual(ids, []string{"m4"}) {
+		t.Fatalf("opened with %v", ids)
+	}
+	if err := log.dropDeadSegments(); err != nil {
+		t.Fatal(err)
+	}
+
+	//segment 1 still holds m4 so it and every segment after it are kept
+	if len(log.segments) != 2 || log.segments[0].number != 1 {
+		t.Fatalf("%d segments left starting at %d", len(log.segments), log.segments[0].number)
+	}
+	if _, err := os.Stat(segmentPath(log, 0)); !os.IsNotExist(err) {
+		t.Fatalf("dead segment left behind, %v", err)
+	}
+	log.close()
+
+	//the confirmations of the dropped messages are ignored when the log is read back
+	reopened := openLog(t, dir)
+	if received := logReceived(reopened); !reflect.DeepEqual(received, map[string][]string{"m4": {}}) {
+		t.Fatalf("reopened with %v", received)
+	}
+}
+
+func TestMessageLogKeepsLastSegment(t *testing.T) {
+	dir := t.TempDir()
+	log := openLog(t, dir)
+	if err := log.insert(Message{ID: "m1", CreatedAt: time.Now(), ExpiresAt: time.Now().Add(-time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if deleted := log.deleteExpired(time.Now()); deleted != 1 {
+		t.Fatalf("deleted %d expired messages", deleted)
+	}
+	if err := log.dropDeadSegments(); err != nil {
+		t.Fatal(err)
+	}
+	//the segment being appended to is kept even once it's empty
+	if len(log.segments) != 1 {
+		t.Fatalf("%d segments left", len(log.segments))
+	}
+	insertMessages(t, log, "m2")
+	log.close()
+	if ids := logIDs(openLog(t, dir)); !reflect.DeepEqual(ids, []string{"m2"}) {
+		t.Fatalf("reopened with %v", ids)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
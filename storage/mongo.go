@@ -0,0 +1,1300 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	mongoDatabase = "message-broker"
+
+	clientsCollection    = "clients"
+	publishersCollection = "publishers"
+	apiKeysCollection    = "api_keys"
+	orgsCollection       = "organizations"
+	groupsCollection     = "client_groups"
+	requestsCollection   = "subscription_requests"
+	schemasCollection    = "publisher_schemas"
+	messagesCollection   = "publisher_messages"
+	usageCollection      = "publisher_usage"  //messages stored for each publisher, for quotas
+	instancesCollection  = "broker_instances" //instances currently running in the cluster
+	leasesCollection     = "broker_leases"    //leases held by instances, for leadership and subscription ownership
+
+	mongoTimeout = 2 * time.Second
+)
+
+//Mongo stores everything in the message-broker database of a MongoDB server
+type Mongo struct {
+	connection *mongo.Client
+}
+
+type mongoWebhook struct {
+	URL                 string    `bson:"url"`
+	Secret              string    `bson:"secret"`
+	Status              string    `bson:"status"`
+	ConsecutiveFailures int       `bson:"consecutive_failures"`
+	LastError           string    `bson:"last_error,omitempty"`
+	LastSuccessAt       time.Time `bson:"last_success_at,omitempty"`
+	LastFailureAt       time.Time `bson:"last_failure_at,omitempty"`
+	NextAttemptAt       time.Time `bson:"next_attempt_at,omitempty"`
+}
+
+type mongoSubscription struct {
+	Id          string        `bson:"_id"`
+	PublisherId string        `bson:"publisher_id"`
+	Type        string        `bson:"type,omitempty"`
+	Webhook     *mongoWebhook `bson:"webhook,omitempty"`
+}
+
+type mongoClient struct {
+	Id             string              `bson:"_id"`
+	OrganizationID string              `bson:"organization_id,omitempty"`
+	Name           string              `bson:"name"`
+	SecretHash     string              `bson:"secret_hash,omitempty"`
+	RateLimits     *mongoRateLimits    `bson:"rate_limits,omitempty"`
+	Subscriptions  []mongoSubscription `bson:"subscriptions"`
+}
+
+type mongoRateLimits struct {
+	PublishPerMinute  int `bson:"publish_per_minute,omitempty"`
+	PublishBurst      int `bson:"publish_burst,omitempty"`
+	RequestsPerMinute int `bson:"requests_per_minute,omitempty"`
+	RequestsBurst     int `bson:"requests_burst,omitempty"`
+	Connections       int `bson:"connections,omitempty"`
+}
+
+type mongoAPIKey struct {
+	ID         string    `bson:"_id"`
+	ClientID   string    `bson:"client_id"`
+	Name       string    `bson:"name"`
+	SecretHash string    `bson:"secret_hash"`
+	Scopes     []string  `bson:"scopes"`
+	CreatedAt  time.Time `bson:"created_at"`
+	ExpiresAt  time.Time `bson:"expires_at,omitempty"`
+}
+
+type mongoAccess struct {
+	Visibility    string   `bson:"visibility,omitempty"`
+	Clients       []string `bson:"clients,omitempty"`
+	Groups        []string `bson:"groups,omitempty"`
+	Approved      []string `bson:"approved,omitempty"`
+	Organizations []string `bson:"organizations,omitempty"`
+}
+
+type mongoPayloadPolicy struct {
+	MaxBytes    int    `bson:"max_bytes,omitempty"`
+	ContentType string `bson:"content_type,omitempty"`
+}
+
+type mongoPublisher struct {
+	ID             string             `bson:"_id"`
+	Name           string             `bson:"name"`
+	OwnerID        string             `bson:"owner_id"`
+	OrganizationID string             `bson:"organization_id,omitempty"`
+	Access         mongoAccess        `bson:"access"`
+	Payload        mongoPayloadPolicy `bson:"payload"`
+}
+
+type mongoOrganization struct {
+	ID     string   `bson:"_id"`
+	Name   string   `bson:"name"`
+	Admins []string `bson:"admins"`
+}
+
+type mongoGroup struct {
+	ID      string   `bson:"_id"`
+	OwnerID string   `bson:"owner_id"`
+	Name    string   `bson:"name"`
+	Members []string `bson:"members"`
+}
+
+type mongoSubscriptionRequest struct {
+	ID           string            `bson:"_id"`
+	ClientID     string            `bson:"client_id"`
+	PublisherID  string            `bson:"publisher_id"`
+	OwnerID      string            `bson:"owner_id"`
+	Message      string            `bson:"message"`
+	Subscription mongoSubscription `bson:"subscription"`
+	Status       string            `bson:"status"`
+	Reason       string            `bson:"reason,omitempty"`
+	CreatedAt    time.Time         `bson:"created_at"`
+	DecidedAt    time.Time         `bson:"decided_at,omitempty"`
+}
+
+type mongoSchema struct {
+	ID            string    `bson:"_id"` //publisher id and version, so versions can't be registered twice
+	PublisherID   string    `bson:"publisher_id"`
+	Version       int       `bson:"version"`
+	Definition    string    `bson:"definition"`
+	Compatibility string    `bson:"compatibility"`
+	CreatedAt     time.Time `bson:"created_at"`
+}
+
+type mongoMessage struct {
+	Id            string    `bson:"_id"`
+	PublisherID   string    `bson:"publisher_id"`
+	Payload       string    `bson:"payload"`
+	DateCreated   time.Time `bson:"date_created"`
+	Ttl           int64     `bson:"ttl"` //unix time the message expires, 0 if it doesn't
+	SchemaVersion int       `bson:"schema_version,omitempty"`
+}
+
+func (sub mongoSubscription) subscription() Subscription {
+	subscription := Subscription{
+		ID:          sub.Id,
+		PublisherID: sub.PublisherId,
+		Type:        sub.Type,
+	}
+	if sub.Webhook != nil {
+		webhook := Webhook(*sub.Webhook)
+		subscription.Webhook = &webhook
+	}
+	return subscription
+}
+
+func (publisher mongoPublisher) publisher() Publisher {
+	return Publisher{
+		ID:             publisher.ID,
+		Name:           publisher.Name,
+		OwnerID:        publisher.OwnerID,
+		OrganizationID: publisher.OrganizationID,
+		Access:         copyAccess(Access(publisher.Access)),
+		Payload:        PayloadPolicy(publisher.Payload),
+	}
+}
+
+func newMongoSubscription(subscription Subscription) mongoSubscription {
+	result := mongoSubscription{
+		Id:          subscription.ID,
+		PublisherId: subscription.PublisherID,
+		Type:        subscription.Type,
+	}
+	if subscription.Webhook != nil {
+		webhook := mongoWebhook(*subscription.Webhook)
+		result.Webhook = &webhook
+	}
+	return result
+}
+
+func (request mongoSubscriptionRequest) request() SubscriptionRequest {
+	return SubscriptionRequest{
+		ID:           request.ID,
+		ClientID:     request.ClientID,
+		PublisherID:  request.PublisherID,
+		OwnerID:      request.OwnerID,
+		Message:      request.Message,
+		Subscription: request.Subscription.subscription(),
+		Status:       request.Status,
+		Reason:       request.Reason,
+		CreatedAt:    request.CreatedAt,
+		DecidedAt:    request.DecidedAt,
+	}
+}
+
+func (client mongoClient) client() *Client {
+	result := Client{
+		ID:             client.Id,
+		OrganizationID: client.OrganizationID,
+		Name:           client.Name,
+		SecretHash:     client.SecretHash,
+		Subscriptions:  []Subscription{},
+	}
+	if client.RateLimits != nil {
+		result.RateLimits = &RateLimits{
+			PublishPerMinute:  client.RateLimits.PublishPerMinute,
+			PublishBurst:      client.RateLimits.PublishBurst,
+			RequestsPerMinute: client.RateLimits.RequestsPerMinute,
+			RequestsBurst:     client.RateLimits.RequestsBurst,
+			Connections:       client.RateLimits.Connections,
+		}
+	}
+	for _, sub := range client.Subscriptions {
+		result.Subscriptions = append(result.Subscriptions, sub.subscription())
+	}
+	return &result
+}
+
+//NewMongo connects to the MongoDB server at uri
+func NewMongo(uri string) (*Mongo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), mongoTimeout)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, err
+	}
+	store := &Mongo{connection: client}
+	err = store.createIndexes()
+	if err != nil {
+		client.Disconnect(ctx)
+		return nil, err
+	}
+	return store, nil
+}
+
+//create the indexes uniqueness is enforced with, so it holds when documents are inserted at the same time
+func (store *Mongo) createIndexes() error {
+	ctx, cancel := queryContext()
+	defer cancel()
+	//clients outside of an organization don't have an organization_id, which the index treats as null
+	_, err := store.collection(clientsCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "organization_id", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+func (store *Mongo) collection(name string) *mongo.Collection {
+	return store.connection.Database(mongoDatabase).Collection(name)
+}
+
+//context for a single query
+func queryContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), mongoTimeout)
+}
+
+//decode every result of a query
+func findAll(collection *mongo.Collection, findOptions *options.FindOptions, filter bson.D, results interface{}) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+	cursor, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return err
+	}
+	return cursor.All(ctx, results)
+}
+
+func count(collection *mongo.Collection, filter bson.D) (int64, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+	return collection.CountDocuments(ctx, filter)
+}
+
+func updateOne(collection *mongo.Collection, filter bson.D, update bson.D) (*mongo.UpdateResult, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+	return collection.UpdateOne(ctx, filter, update)
+}
+
+func updateMany(collection *mongo.Collection, filter bson.D, update bson.D) (*mongo.UpdateResult, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+	return collection.UpdateMany(ctx, filter, update)
+}
+
+func upsertOne(collection *mongo.Collection, filter bson.D, update bson.D) (*mongo.UpdateResult, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+	return collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+}
+
+func insertOne(collection *mongo.Collection, document interface{}) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+	_, err := collection.InsertOne(ctx, document)
+	return err
+}
+
+func deleteOne(collection *mongo.Collection, filter bson.D) (*mongo.DeleteResult, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+	return collection.DeleteOne(ctx, filter)
+}
+
+func deleteMany(collection *mongo.Collection, filter bson.D) (*mongo.DeleteResult, error) {
+	ctx, cancel := queryContext()
+	defer cancel()
+	return collection.DeleteMany(ctx, filter)
+}
+
+//find a single document, ErrNotFound is returned if there isn't one
+func findOne(collection *mongo.Collection, projection bson.D, filter bson.D, result interface{}) error {
+	ctx, cancel := queryContext()
+	defer cancel()
+	err := collection.FindOne(ctx, filter, options.FindOne().SetProjection(projection)).Decode(result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrNotFound
+	}
+	return err
+}
+
+//match documents in an organization, documents outside of one don't have the field
+func organizationFilter(organizationID string) bson.E {
+	if organizationID == "" {
+		return bson.E{Key: "organization_id", Value: nil}
+	}
+	return bson.E{Key: "organization_id", Value: organizationID}
+}
+
+func (store *Mongo) CreateClient(organizationID string, name string, secretHash string) (*Client, error) {
+	client := Client{
+		ID:             uuid.New().String(),
+		OrganizationID: organizationID,
+		Name:           name,
+		SecretHash:     secretHash,
+		Subscriptions:  []Subscription{},
+	}
+	document := bson.D{
+		{Key: "_id", Value: client.ID},
+		{Key: "name", Value: client.Name},
+		{Key: "secret_hash", Value: client.SecretHash},
+	}
+	if organizationID != "" {
+		document = append(document, bson.E{Key: "organization_id", Value: organizationID})
+	}
+	//the name is unique in the organization through the clients index
+	err := insertOne(store.collection(clientsCollection), document)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, ErrConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+func (store *Mongo) FindClient(id string) (*Client, error) {
+	projection := bson.D{
+		{Key: "_id", Value: 1},
+		{Key: "organization_id", Value: 1},
+		{Key: "name", Value: 1},
+		{Key: "secret_hash", Value: 1},
+		{Key: "rate_limits", Value: 1},
+		{Key: "subscriptions", Value: 1},
+	}
+	client := mongoClient{}
+	err := findOne(store.collection(clientsCollection), projection, bson.D{{Key: "_id", Value: id}}, &client)
+	if err != nil {
+		return nil, err
+	}
+	return client.client(), nil
+}
+
+func (store *Mongo) SetClientSecret(clientID string, secretHash string) error {
+	filter := bson.D{{Key: "_id", Value: clientID}}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "secret_hash", Value: secretHash}}}}
+	result, err := updateOne(store.collection(clientsCollection), filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (store *Mongo) SetClientRateLimits(clientID string, limits *RateLimits) error {
+	filter := bson.D{{Key: "_id", Value: clientID}}
+	update := bson.D{{Key: "$unset", Value: bson.D{{Key: "rate_limits", Value: ""}}}}
+	if limits != nil {
+		update = bson.D{{Key: "$set", Value: bson.D{{Key: "rate_limits", Value: mongoRateLimits{
+			PublishPerMinute:  limits.PublishPerMinute,
+			PublishBurst:      limits.PublishBurst,
+			RequestsPerMinute: limits.RequestsPerMinute,
+			RequestsBurst:     limits.RequestsBurst,
+			Connections:       limits.Connections,
+		}}}}}
+	}
+	result, err := updateOne(store.collection(clientsCollection), filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (store *Mongo) AddSubscription(clientID string, subscription Subscription) error {
+	collection := store.collection(clientsCollection)
+	document := bson.D{
+		{Key: "_id", Value: subscription.ID},
+		{Key: "publisher_id", Value: subscription.PublisherID},
+	}
+	if subscription.Type != "" {
+		document = append(document, bson.E{Key: "type", Value: subscription.Type})
+	}
+	if subscription.Webhook != nil {
+		document = append(document, bson.E{Key: "webhook", Value: mongoWebhook(*subscription.Webhook)})
+	}
+	//only push the subscription if the client isn't already subscribed to the publisher
+	filter := bson.D{
+		{Key: "_id", Value: clientID},
+		{Key: "subscriptions.publisher_id", Value: bson.D{{Key: "$ne", Value: subscription.PublisherID}}},
+	}
+	update := bson.D{{Key: "$push", Value: bson.D{{Key: "subscriptions", Value: document}}}}
+	result, err := updateOne(collection, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+	clients, err := count(collection, bson.D{{Key: "_id", Value: clientID}})
+	if err != nil {
+		return err
+	}
+	if clients == 0 {
+		return ErrNotFound
+	}
+	return ErrConflict
+}
+
+func (store *Mongo) RemoveSubscription(clientID string, subscriptionID string) error {
+	filter := bson.D{
+		{Key: "_id", Value: clientID},
+		{Key: "subscriptions._id", Value: subscriptionID},
+	}
+	update := bson.D{{Key: "$pull", Value: bson.D{
+		{Key: "subscriptions", Value: bson.D{{Key: "_id", Value: subscriptionID}}},
+	}}}
+	result, err := updateOne(store.collection(clientsCollection), filter, update)
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (store *Mongo) ListWebhooks() ([]WebhookSubscription, error) {
+	filter := bson.D{{Key: "subscriptions.type", Value: SubscriptionTypeWebhook}}
+	projection := bson.D{{Key: "_id", Value: 1}, {Key: "subscriptions", Value: 1}}
+	clients := []mongoClient{}
+	err := findAll(store.collection(clientsCollection), options.Find().SetProjection(projection), filter, &clients)
+	if err != nil {
+		return nil, err
+	}
+	webhooks := []WebhookSubscription{}
+	for _, client := range clients {
+		for _, sub := range client.Subscriptions {
+			if sub.Type == SubscriptionTypeWebhook && sub.Webhook != nil {
+				webhooks = append(webhooks, WebhookSubscription{ClientID: client.Id, Subscription: sub.subscription()})
+			}
+		}
+	}
+	return webhooks, nil
+}
+
+func (store *Mongo) RecordWebhookDelivery(clientID string, subscriptionID string, delivery WebhookDelivery) error {
+	fields := bson.D{
+		{Key: "subscriptions.$.webhook.status", Value: delivery.Status},
+		{Key: "subscriptions.$.webhook.consecutive_failures", Value: delivery.ConsecutiveFailures},
+		{Key: "subscriptions.$.webhook.last_error", Value: delivery.Error},
+	}
+	if delivery.Error == "" {
+		fields = append(fields, bson.E{Key: "subscriptions.$.webhook.last_success_at", Value: time.Now()})
+	} else {
+		fields = append(fields,
+			bson.E{Key: "subscriptions.$.webhook.last_failure_at", Value: time.Now()},
+			bson.E{Key: "subscriptions.$.webhook.next_attempt_at", Value: delivery.NextAttemptAt},
+		)
+	}
+	filter := bson.D{
+		{Key: "_id", Value: clientID},
+		{Key: "subscriptions._id", Value: subscriptionID},
+	}
+	_, err := updateOne(store.collection(clientsCollection), filter, bson.D{{Key: "$set", Value: fields}})
+	return err
+}
+
+func (store *Mongo) CreateAPIKey(key APIKey) error {
+	existing, err := count(store.collection(clientsCollection), bson.D{{Key: "_id", Value: key.ClientID}})
+	if err != nil {
+		return err
+	}
+	if existing == 0 {
+		return ErrNotFound
+	}
+	err = insertOne(store.collection(apiKeysCollection), mongoAPIKey(key))
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrConflict
+	}
+	return err
+}
+
+func (store *Mongo) FindAPIKey(id string) (*APIKey, error) {
+	key := mongoAPIKey{}
+	err := findOne(store.collection(apiKeysCollection), bson.D{}, bson.D{{Key: "_id", Value: id}}, &key)
+	if err != nil {
+		return nil, err
+	}
+	result := APIKey(key)
+	return &result, nil
+}
+
+func (store *Mongo) ListAPIKeys(clientID string) ([]APIKey, error) {
+	found := []mongoAPIKey{}
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	err := findAll(store.collection(apiKeysCollection), findOptions, bson.D{{Key: "client_id", Value: clientID}}, &found)
+	if err != nil {
+		return nil, err
+	}
+	keys := []APIKey{}
+	for _, key := range found {
+		keys = append(keys, APIKey(key))
+	}
+	return keys, nil
+}
+
+func (store *Mongo) DeleteAPIKey(clientID string, id string) error {
+	filter := bson.D{{Key: "_id", Value: id}, {Key: "client_id", Value: clientID}}
+	result, err := deleteOne(store.collection(apiKeysCollection), filter)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (store *Mongo) CreatePublisher(ownerID string, organizationID string, name string) (*Publisher, error) {
+	collection := store.collection(publishersCollection)
+	filter := bson.D{{Key: "owner_id", Value: ownerID}, {Key: "name", Value: name}}
+	if organizationID != "" {
+		filter = bson.D{{Key: "organization_id", Value: organizationID}, {Key: "name", Value: name}}
+	}
+	existing, err := count(collection, filter)
+	if err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		return nil, ErrConflict
+	}
+	publisher := Publisher{ID: uuid.New().String(), Name: name, OwnerID: ownerID, OrganizationID: organizationID}
+	err = insertOne(collection, mongoPublisher{
+		ID:             publisher.ID,
+		Name:           publisher.Name,
+		OwnerID:        publisher.OwnerID,
+		OrganizationID: publisher.OrganizationID,
+		Payload:        mongoPayloadPolicy(publisher.Payload),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &publisher, nil
+}
+
+func (store *Mongo) FindPublisher(id string) (*Publisher, error) {
+	publisher := mongoPublisher{}
+	err := findOne(store.collection(publishersCollection), bson.D{}, bson.D{{Key: "_id", Value: id}}, &publisher)
+	if err != nil {
+		return nil, err
+	}
+	result := publisher.publisher()
+	return &result, nil
+}
+
+//find publishers matching the filter
+func (store *Mongo) findPublishers(filter bson.D) ([]Publisher, error) {
+	found := []mongoPublisher{}
+	err := findAll(store.collection(publishersCollection), options.Find(), filter, &found)
+	if err != nil {
+		return nil, err
+	}
+	publishers := []Publisher{}
+	for _, publisher := range found {
+		publishers = append(publishers, publisher.publisher())
+	}
+	return publishers, nil
+}
+
+func (store *Mongo) FindPublishers(ids []string) ([]Publisher, error) {
+	return store.findPublishers(bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}})
+}
+
+func (store *Mongo) ListPublishers(ownerID string) ([]Publisher, error) {
+	return store.findPublishers(bson.D{{Key: "owner_id", Value: ownerID}})
+}
+
+func (store *Mongo) ListSubscribers(publisherID string) ([]Client, error) {
+	filter := bson.D{{Key: "subscriptions.publisher_id", Value: publisherID}}
+	projection := bson.D{{Key: "_id", Value: 1}, {Key: "name", Value: 1}}
+	found := []mongoClient{}
+	err := findAll(store.collection(clientsCollection), options.Find().SetProjection(projection), filter, &found)
+	if err != nil {
+		return nil, err
+	}
+	clients := []Client{}
+	for _, client := range found {
+		clients = append(clients, Client{ID: client.Id, Name: client.Name})
+	}
+	return clients, nil
+}
+
+func (store *Mongo) CreateOrganization(name string) (*Organization, error) {
+	collection := store.collection(orgsCollection)
+	existing, err := count(collection, bson.D{{Key: "name", Value: name}})
+	if err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		return nil, ErrConflict
+	}
+	organization := Organization{ID: uuid.New().String(), Name: name, Admins: []string{}}
+	err = insertOne(collection, mongoOrganization(organization))
+	if err != nil {
+		return nil, err
+	}
+	return &organization, nil
+}
+
+func (store *Mongo) FindOrganization(id string) (*Organization, error) {
+	organization := mongoOrganization{}
+	err := findOne(store.collection(orgsCollection), bson.D{}, bson.D{{Key: "_id", Value: id}}, &organization)
+	if err != nil {
+		return nil, err
+	}
+	result := Organization(organization)
+	return &result, nil
+}
+
+func (store *Mongo) SetOrganizationAdmins(id string, admins []string) error {
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "admins", Value: append([]string{}, admins...)}}}}
+	result, err := updateOne(store.collection(orgsCollection), bson.D{{Key: "_id", Value: id}}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (store *Mongo) ListMembers(organizationID string) ([]Client, error) {
+	projection := bson.D{{Key: "_id", Value: 1}, {Key: "organization_id", Value: 1}, {Key: "name", Value: 1}}
+	found := []mongoClient{}
+	err := findAll(store.collection(clientsCollection), options.Find().SetProjection(projection), bson.D{organizationFilter(organizationID)}, &found)
+	if err != nil {
+		return nil, err
+	}
+	members := []Client{}
+	for _, client := range found {
+		members = append(members, Client{ID: client.Id, OrganizationID: client.OrganizationID, Name: client.Name})
+	}
+	return members, nil
+}
+
+func (store *Mongo) ListOrganizationPublishers(organizationID string) ([]Publisher, error) {
+	return store.findPublishers(bson.D{organizationFilter(organizationID)})
+}
+
+func (store *Mongo) SetPublisherAccess(id string, access Access) error {
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "access", Value: mongoAccess(access)}}}}
+	result, err := updateOne(store.collection(publishersCollection), bson.D{{Key: "_id", Value: id}}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (store *Mongo) SetPublisherPayload(id string, policy PayloadPolicy) error {
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "payload", Value: mongoPayloadPolicy(policy)}}}}
+	result, err := updateOne(store.collection(publishersCollection), bson.D{{Key: "_id", Value: id}}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (store *Mongo) CreateGroup(ownerID string, name string, members []string) (*Group, error) {
+	collection := store.collection(groupsCollection)
+	existing, err := count(collection, bson.D{{Key: "owner_id", Value: ownerID}, {Key: "name", Value: name}})
+	if err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		return nil, ErrConflict
+	}
+	group := Group{ID: uuid.New().String(), OwnerID: ownerID, Name: name, Members: append([]string{}, members...)}
+	err = insertOne(collection, mongoGroup(group))
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (store *Mongo) FindGroup(id string) (*Group, error) {
+	group := mongoGroup{}
+	err := findOne(store.collection(groupsCollection), bson.D{}, bson.D{{Key: "_id", Value: id}}, &group)
+	if err != nil {
+		return nil, err
+	}
+	result := Group(group)
+	return &result, nil
+}
+
+func (store *Mongo) ListGroups(ownerID string) ([]Group, error) {
+	found := []mongoGroup{}
+	err := findAll(store.collection(groupsCollection), options.Find(), bson.D{{Key: "owner_id", Value: ownerID}}, &found)
+	if err != nil {
+		return nil, err
+	}
+	groups := []Group{}
+	for _, group := range found {
+		groups = append(groups, Group(group))
+	}
+	return groups, nil
+}
+
+func (store *Mongo) SetGroupMembers(id string, members []string) error {
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "members", Value: append([]string{}, members...)}}}}
+	result, err := updateOne(store.collection(groupsCollection), bson.D{{Key: "_id", Value: id}}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (store *Mongo) DeleteGroup(id string) error {
+	result, err := deleteOne(store.collection(groupsCollection), bson.D{{Key: "_id", Value: id}})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (store *Mongo) CreateSubscriptionRequest(request SubscriptionRequest) error {
+	collection := store.collection(requestsCollection)
+	pending, err := count(collection, bson.D{
+		{Key: "client_id", Value: request.ClientID},
+		{Key: "publisher_id", Value: request.PublisherID},
+		{Key: "status", Value: RequestPending},
+	})
+	if err != nil {
+		return err
+	}
+	if pending > 0 {
+		return ErrConflict
+	}
+	err = insertOne(collection, mongoSubscriptionRequest{
+		ID:           request.ID,
+		ClientID:     request.ClientID,
+		PublisherID:  request.PublisherID,
+		OwnerID:      request.OwnerID,
+		Message:      request.Message,
+		Subscription: newMongoSubscription(request.Subscription),
+		Status:       request.Status,
+		Reason:       request.Reason,
+		CreatedAt:    request.CreatedAt,
+		DecidedAt:    request.DecidedAt,
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrConflict
+	}
+	return err
+}
+
+func (schema mongoSchema) schema() Schema {
+	return Schema{
+		PublisherID:   schema.PublisherID,
+		Version:       schema.Version,
+		Definition:    schema.Definition,
+		Compatibility: schema.Compatibility,
+		CreatedAt:     schema.CreatedAt,
+	}
+}
+
+func (store *Mongo) AddSchema(schema Schema) error {
+	publisher := mongoPublisher{}
+	err := findOne(store.collection(publishersCollection), bson.D{{Key: "_id", Value: 1}},
+		bson.D{{Key: "_id", Value: schema.PublisherID}}, &publisher)
+	if err != nil {
+		return err
+	}
+	latest, err := store.FindSchema(schema.PublisherID, 0)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	previous := 0
+	if latest != nil {
+		previous = latest.Version
+	}
+	if schema.Version != previous+1 {
+		return ErrConflict
+	}
+	//two versions registered at the same time get the same id, so the second is refused
+	err = insertOne(store.collection(schemasCollection), mongoSchema{
+		ID:            schema.PublisherID + "/" + strconv.Itoa(schema.Version),
+		PublisherID:   schema.PublisherID,
+		Version:       schema.Version,
+		Definition:    schema.Definition,
+		Compatibility: schema.Compatibility,
+		CreatedAt:     schema.CreatedAt,
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrConflict
+	}
+	return err
+}
+
+func (store *Mongo) FindSchema(publisherID string, version int) (*Schema, error) {
+	filter := bson.D{{Key: "publisher_id", Value: publisherID}}
+	if version != 0 {
+		filter = append(filter, bson.E{Key: "version", Value: version})
+	}
+	found := []mongoSchema{}
+	findOptions := options.Find().SetSort(bson.D{{Key: "version", Value: -1}}).SetLimit(1)
+	err := findAll(store.collection(schemasCollection), findOptions, filter, &found)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, ErrNotFound
+	}
+	schema := found[0].schema()
+	return &schema, nil
+}
+
+func (store *Mongo) ListSchemas(publisherID string) ([]Schema, error) {
+	found := []mongoSchema{}
+	findOptions := options.Find().SetSort(bson.D{{Key: "version", Value: 1}})
+	err := findAll(store.collection(schemasCollection), findOptions, bson.D{{Key: "publisher_id", Value: publisherID}}, &found)
+	if err != nil {
+		return nil, err
+	}
+	schemas := []Schema{}
+	for _, schema := range found {
+		schemas = append(schemas, schema.schema())
+	}
+	return schemas, nil
+}
+
+func (store *Mongo) FindSubscriptionRequest(id string) (*SubscriptionRequest, error) {
+	request := mongoSubscriptionRequest{}
+	err := findOne(store.collection(requestsCollection), bson.D{}, bson.D{{Key: "_id", Value: id}}, &request)
+	if err != nil {
+		return nil, err
+	}
+	result := request.request()
+	return &result, nil
+}
+
+func (store *Mongo) ListSubscriptionRequests(query SubscriptionRequestQuery) ([]SubscriptionRequest, error) {
+	filter := bson.D{}
+	for _, field := range []bson.E{
+		{Key: "client_id", Value: query.ClientID},
+		{Key: "owner_id", Value: query.OwnerID},
+		{Key: "publisher_id", Value: query.PublisherID},
+		{Key: "status", Value: query.Status},
+	} {
+		if field.Value != "" {
+			filter = append(filter, field)
+		}
+	}
+	if !query.CreatedAfter.IsZero() {
+		filter = append(filter, bson.E{Key: "created_at", Value: bson.D{{Key: "$gte", Value: query.CreatedAfter}}})
+	}
+	if !query.DecidedAfter.IsZero() {
+		filter = append(filter, bson.E{Key: "decided_at", Value: bson.D{{Key: "$gte", Value: query.DecidedAfter}}})
+	}
+	found := []mongoSubscriptionRequest{}
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	err := findAll(store.collection(requestsCollection), findOptions, filter, &found)
+	if err != nil {
+		return nil, err
+	}
+	requests := []SubscriptionRequest{}
+	for _, request := range found {
+		requests = append(requests, request.request())
+	}
+	return requests, nil
+}
+
+func (store *Mongo) DecideSubscriptionRequest(id string, status string, reason string, decidedAt time.Time) error {
+	collection := store.collection(requestsCollection)
+	filter := bson.D{{Key: "_id", Value: id}, {Key: "status", Value: RequestPending}}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: status},
+		{Key: "reason", Value: reason},
+		{Key: "decided_at", Value: decidedAt},
+	}}}
+	result, err := updateOne(collection, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+	existing, err := count(collection, bson.D{{Key: "_id", Value: id}})
+	if err != nil {
+		return err
+	}
+	if existing == 0 {
+		return ErrNotFound
+	}
+	return ErrConflict
+}
+
+func (store *Mongo) DeletePublisher(id string) error {
+	result, err := deleteOne(store.collection(publishersCollection), bson.D{{Key: "_id", Value: id}})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	_, err = deleteMany(store.collection(messagesCollection), bson.D{{Key: "publisher_id", Value: id}})
+	if err != nil {
+		return err
+	}
+	_, err = deleteOne(store.collection(usageCollection), bson.D{{Key: "_id", Value: id}})
+	if err != nil {
+		return err
+	}
+	_, err = deleteMany(store.collection(requestsCollection), bson.D{{Key: "publisher_id", Value: id}})
+	if err != nil {
+		return err
+	}
+	_, err = deleteMany(store.collection(schemasCollection), bson.D{{Key: "publisher_id", Value: id}})
+	if err != nil {
+		return err
+	}
+	filter := bson.D{{Key: "subscriptions.publisher_id", Value: id}}
+	update := bson.D{{Key: "$pull", Value: bson.D{
+		{Key: "subscriptions", Value: bson.D{{Key: "publisher_id", Value: id}}},
+	}}}
+	_, err = updateMany(store.collection(clientsCollection), filter, update)
+	return err
+}
+
+func (store *Mongo) InsertMessage(message Message) error {
+	ttl := int64(0)
+	if !message.ExpiresAt.IsZero() {
+		ttl = message.ExpiresAt.Unix()
+	}
+	err := insertOne(store.collection(messagesCollection), mongoMessage{
+		Id:            message.ID,
+		PublisherID:   message.PublisherID,
+		Payload:       message.Payload,
+		DateCreated:   message.CreatedAt,
+		Ttl:           ttl,
+		SchemaVersion: message.SchemaVersion,
+	})
+	if err != nil {
+		return err
+	}
+	return store.addUsage(message.PublisherID, 1, int64(len(message.Payload)))
+}
+
+//usage of a publisher, kept up to date as messages are inserted and deleted so it doesn't need counting for
+//every publish
+type mongoUsage struct {
+	PublisherID string `bson:"_id"`
+	Messages    int64  `bson:"messages"`
+	Bytes       int64  `bson:"bytes"`
+}
+
+//add to the usage of a publisher. publishers whose usage hasn't been counted yet are left alone, their messages
+//are counted the first time the usage is needed
+func (store *Mongo) addUsage(publisherID string, messages int64, bytes int64) error {
+	update := bson.D{{Key: "$inc", Value: bson.D{
+		{Key: "messages", Value: messages},
+		{Key: "bytes", Value: bytes},
+	}}}
+	_, err := updateOne(store.collection(usageCollection), bson.D{{Key: "_id", Value: publisherID}}, update)
+	return err
+}
+
+//count the messages matching the filter for each publisher
+func (store *Mongo) countUsage(filter bson.D) ([]mongoUsage, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$publisher_id"},
+			{Key: "messages", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "bytes", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$strLenBytes", Value: "$payload"}}}}},
+		}}},
+	}
+	ctx, cancel := queryContext()
+	defer cancel()
+	cursor, err := store.collection(messagesCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	counted := []mongoUsage{}
+	err = cursor.All(ctx, &counted)
+	return counted, err
+}
+
+func (store *Mongo) MessageUsage(publisherIDs []string) (map[string]Usage, error) {
+	found := []mongoUsage{}
+	filter := bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: publisherIDs}}}}
+	err := findAll(store.collection(usageCollection), options.Find(), filter, &found)
+	if err != nil {
+		return nil, err
+	}
+	usage := make(map[string]Usage)
+	for _, publisherUsage := range found {
+		usage[publisherUsage.PublisherID] = Usage{Messages: publisherUsage.Messages, Bytes: publisherUsage.Bytes}
+	}
+	uncounted := []string{}
+	for _, id := range publisherIDs {
+		if _, counted := usage[id]; !counted {
+			uncounted = append(uncounted, id)
+		}
+	}
+	if len(uncounted) == 0 {
+		return usage, nil
+	}
+
+	//publishers with messages from before usage was tracked, count them once and keep the count up to date from
+	//then on
+	counted, err := store.countUsage(bson.D{{Key: "publisher_id", Value: bson.D{{Key: "$in", Value: uncounted}}}})
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range uncounted {
+		publisherUsage := mongoUsage{PublisherID: id}
+		for _, count := range counted {
+			if count.PublisherID == id {
+				publisherUsage = count
+			}
+		}
+		err = insertOne(store.collection(usageCollection), publisherUsage)
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return nil, err
+		}
+		usage[id] = Usage{Messages: publisherUsage.Messages, Bytes: publisherUsage.Bytes}
+	}
+	return usage, nil
+}
+
+//leases are stored against the message as leases.{client id}: expiry time
+func leaseField(clientID string) string {
+	return "leases." + clientID
+}
+
+//filter for messages from a publisher the client hasn't received and doesn't currently have leased
+func availableMessagesFilter(publisherID string, clientID string) bson.D {
+	return bson.D{
+		{Key: "publisher_id", Value: publisherID},
+		{Key: "received_by", Value: bson.D{
+			{Key: "$nin", Value: []string{clientID}},
+		}},
+		{Key: leaseField(clientID), Value: bson.D{
+			{Key: "$not", Value: bson.D{{Key: "$gt", Value: time.Now()}}},
+		}},
+	}
+}
+
+func (store *Mongo) LeaseMessages(publisherID string, clientID string, max int, expires time.Time) ([]Message, error) {
+	collection := store.collection(messagesCollection)
+	projection := bson.D{
+		{Key: "publisher_id", Value: 1},
+		{Key: "payload", Value: 1},
+		{Key: "date_created", Value: 1},
+		{Key: "schema_version", Value: 1},
+		{Key: "ttl", Value: 1},
+	}
+	findOptions := options.Find().SetProjection(projection).SetSort(bson.D{{Key: "date_created", Value: 1}}).SetLimit(int64(max))
+	found := []mongoMessage{}
+	subscribed, err := count(store.collection(clientsCollection), bson.D{
+		{Key: "_id", Value: clientID},
+		{Key: "subscriptions.publisher_id", Value: publisherID},
+	})
+	if err != nil || subscribed == 0 {
+		return []Message{}, err
+	}
+	err = findAll(collection, findOptions, availableMessagesFilter(publisherID, clientID), &found)
+	if err != nil {
+		return nil, err
+	}
+	messages := []Message{}
+	for _, message := range found {
+		//only take the lease if nothing else has taken it since the messages were found
+		filter := append(availableMessagesFilter(publisherID, clientID), bson.E{Key: "_id", Value: message.Id})
+		update := bson.D{{Key: "$set", Value: bson.D{{Key: leaseField(clientID), Value: expires}}}}
+		result, err := updateOne(collection, filter, update)
+		if err != nil {
+			return messages, err
+		}
+		if result.ModifiedCount == 0 {
+			continue
+		}
+		messages = append(messages, message.message())
+	}
+	return messages, nil
+}
+
+func (message mongoMessage) message() Message {
+	result := Message{
+		ID:            message.Id,
+		PublisherID:   message.PublisherID,
+		Payload:       message.Payload,
+		CreatedAt:     message.DateCreated,
+		SchemaVersion: message.SchemaVersion,
+	}
+	if message.Ttl != 0 {
+		result.ExpiresAt = time.Unix(message.Ttl, 0)
+	}
+	return result
+}
+
+func (store *Mongo) RenewLeases(clientID string, messageIDs []string, expires time.Time) error {
+	filter := bson.D{
+		{Key: "_id", Value: bson.D{{Key: "$in", Value: messageIDs}}},
+		{Key: leaseField(clientID), Value: bson.D{{Key: "$exists", Value: true}}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: leaseField(clientID), Value: expires}}}}
+	_, err := updateMany(store.collection(messagesCollection), filter, update)
+	return err
+}
+
+func (store *Mongo) ReleaseLeases(publisherID string, clientID string, messageIDs []string, availableAt time.Time) (int, error) {
+	filter := bson.D{
+		{Key: "_id", Value: bson.D{{Key: "$in", Value: messageIDs}}},
+		{Key: "publisher_id", Value: publisherID},
+		{Key: leaseField(clientID), Value: bson.D{{Key: "$exists", Value: true}}},
+	}
+	update := bson.D{{Key: "$unset", Value: bson.D{{Key: leaseField(clientID), Value: ""}}}}
+	if !availableAt.IsZero() {
+		//keep the lease until the message is available again
+		update = bson.D{{Key: "$set", Value: bson.D{{Key: leaseField(clientID), Value: availableAt}}}}
+	}
+	result, err := updateMany(store.collection(messagesCollection), filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return int(result.ModifiedCount), nil
+}
+
+func (store *Mongo) ConfirmMessages(publisherID string, clientID string, messageIDs []string) (int, error) {
+	filter := bson.D{
+		{Key: "_id", Value: bson.D{{Key: "$in", Value: messageIDs}}},
+		{Key: "publisher_id", Value: publisherID},
+		{Key: "received_by", Value: bson.D{{Key: "$nin", Value: []string{clientID}}}},
+	}
+	update := bson.D{
+		{Key: "$push", Value: bson.D{{Key: "received_by", Value: clientID}}},
+		{Key: "$unset", Value: bson.D{{Key: leaseField(clientID), Value: ""}}},
+	}
+	result, err := updateMany(store.collection(messagesCollection), filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return int(result.ModifiedCount), nil
+}
+
+func (store *Mongo) ConfirmMessagesThrough(publisherID string, clientID string, messageID string) error {
+	collection := store.collection(messagesCollection)
+	last := mongoMessage{}
+	err := findOne(collection, bson.D{{Key: "date_created", Value: 1}}, bson.D{
+		{Key: "_id", Value: messageID},
+		{Key: "publisher_id", Value: publisherID},
+	}, &last)
+	if err != nil {
+		return err
+	}
+	filter := bson.D{
+		{Key: "publisher_id", Value: publisherID},
+		{Key: "date_created", Value: bson.D{{Key: "$lte", Value: last.DateCreated}}},
+		{Key: "received_by", Value: bson.D{{Key: "$nin", Value: []string{clientID}}}},
+	}
+	update := bson.D{
+		{Key: "$push", Value: bson.D{{Key: "received_by", Value: clientID}}},
+		{Key: "$unset", Value: bson.D{{Key: leaseField(clientID), Value: ""}}},
+	}
+	_, err = updateMany(collection, filter, update)
+	return err
+}
+
+func (store *Mongo) DeleteExpiredMessages(now time.Time) (int, error) {
+	filter := bson.D{{Key: "ttl", Value: bson.D{
+		{Key: "$lt", Value: now.Unix()},
+		{Key: "$ne", Value: 0},
+	}}}
+	expired, err := store.countUsage(filter)
+	if err != nil {
+		return 0, err
+	}
+	result, err := deleteMany(store.collection(messagesCollection), filter)
+	if err != nil {
+		return 0, err
+	}
+	for _, usage := range expired {
+		err = store.addUsage(usage.PublisherID, -usage.Messages, -usage.Bytes)
+		if err != nil {
+			return int(result.DeletedCount), err
+		}
+	}
+	return int(result.DeletedCount), nil
+}
+
+func (store *Mongo) AcquireLease(name string, holder string, expires time.Time) (bool, error) {
+	filter := bson.D{
+		{Key: "_id", Value: name},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "holder", Value: holder}},
+			bson.D{{Key: "expires", Value: bson.D{{Key: "$lt", Value: time.Now()}}}},
+		}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "holder", Value: holder},
+		{Key: "expires", Value: expires},
+	}}}
+	_, err := upsertOne(store.collection(leasesCollection), filter, update)
+	if mongo.IsDuplicateKeyError(err) {
+		//lease exists and belongs to someone else
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (store *Mongo) ReleaseLease(name string, holder string) error {
+	_, err := deleteOne(store.collection(leasesCollection), bson.D{{Key: "_id", Value: name}, {Key: "holder", Value: holder}})
+	return err
+}
+
+func (store *Mongo) RegisterInstance(id string, address string, expires time.Time) error {
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "address", Value: address},
+			{Key: "lease_expires", Value: expires},
+		}},
+		{Key: "$setOnInsert", Value: bson.D{
+			{Key: "started", Value: time.Now()},
+		}},
+	}
+	_, err := upsertOne(store.collection(instancesCollection), bson.D{{Key: "_id", Value: id}}, update)
+	return err
+}
+
+func (store *Mongo) RemoveInstance(id string) error {
+	_, err := deleteOne(store.collection(instancesCollection), bson.D{{Key: "_id", Value: id}})
+	return err
+}
+
+func (store *Mongo) RemoveExpiredInstances(now time.Time) error {
+	filter := bson.D{{Key: "lease_expires", Value: bson.D{{Key: "$lt", Value: now}}}}
+	_, err := deleteMany(store.collection(instancesCollection), filter)
+	return err
+}
+
+func (store *Mongo) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return store.connection.Disconnect(ctx)
+}
@@ -0,0 +1,76 @@
+package storage
+
+import "errors"
+
+//Organization is a tenant. its members are the clients registered in it, and they can only see and subscribe to the
+//organization's publishers along with those other organizations have shared with it
+type Organization struct {
+	ID     string
+	Name   string
+	Admins []string //ids of the members who manage the organization and every one of its publishers
+}
+
+//OrganizationStore keeps the organizations
+type OrganizationStore interface {
+	//CreateOrganization returns ErrConflict if there's already an organization with the name
+	CreateOrganization(name string) (*Organization, error)
+	//FindOrganization returns ErrNotFound if there isn't one
+	FindOrganization(id string) (*Organization, error)
+	//SetOrganizationAdmins replaces the admins of an organization, returning ErrNotFound if there isn't one
+	SetOrganizationAdmins(id string, admins []string) error
+	//ListMembers of an organization, without their subscriptions
+	ListMembers(organizationID string) ([]Client, error)
+	//ListOrganizationPublishers owned by the members of an organization
+	ListOrganizationPublishers(organizationID string) ([]Publisher, error)
+}
+
+//IsAdmin reports whether the client manages the organization
+func (organization *Organization) IsAdmin(clientID string) bool {
+	for _, id := range organization.Admins {
+		if id == clientID {
+			return true
+		}
+	}
+	return false
+}
+
+//VisibleTo reports whether the client can see the publisher at all, it has to be in the publisher's organization
+//or one the publisher is shared with. clients outside of any organization only see publishers outside of one
+func (publisher *Publisher) VisibleTo(client *Client) bool {
+	if client.OrganizationID == publisher.OrganizationID {
+		return true
+	}
+	if client.OrganizationID == "" {
+		return false
+	}
+	for _, id := range publisher.Access.Organizations {
+		if id == client.OrganizationID {
+			return true
+		}
+	}
+	return false
+}
+
+//ManagedBy reports whether the client manages the publisher, as its owner or an admin of its organization
+func (publisher *Publisher) ManagedBy(clientID string, organizations OrganizationStore) (bool, error) {
+	if publisher.OwnerID == clientID {
+		return true, nil
+	}
+	if publisher.OrganizationID == "" {
+		return false, nil
+	}
+	organization, err := organizations.FindOrganization(publisher.OrganizationID)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return organization.IsAdmin(clientID), nil
+}
+
+func copyOrganization(organization *Organization) *Organization {
+	result := *organization
+	result.Admins = append([]string{}, organization.Admins...)
+	return &result
+}
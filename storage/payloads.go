@@ -0,0 +1,7 @@
+package storage
+
+//PayloadPolicy a publisher declares for the payloads published to it, see limits.CheckPayload
+type PayloadPolicy struct {
+	MaxBytes    int    //largest payload, 0 uses the service's limit. can't be over the service's limit
+	ContentType string //media type payloads have to be, e.g. application/json. empty accepts anything
+}
@@ -0,0 +1,19 @@
+package storage
+
+//RateLimits of a client. as defaults zero leaves a limit off, as a client's override zero falls back to the
+//default and a negative value leaves the limit off for the client
+type RateLimits struct {
+	PublishPerMinute  int `config:"publish_per_minute" usage:"messages a client can publish a minute, 0 for no limit"`
+	PublishBurst      int `config:"publish_burst" usage:"messages a client can publish at once after being idle, defaults to a second's worth"`
+	RequestsPerMinute int `config:"requests_per_minute" usage:"API calls a client can make a minute, 0 for no limit"`
+	RequestsBurst     int `config:"requests_burst" usage:"API calls a client can make at once after being idle, defaults to a second's worth"`
+	Connections       int `config:"connections" usage:"connections a client can have open to the message broker at once, 0 for no limit"`
+}
+
+func copyRateLimits(limits *RateLimits) *RateLimits {
+	if limits == nil {
+		return nil
+	}
+	result := *limits
+	return &result
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+)
+
+const (
+	recordHeaderSize = 8        //length and checksum in front of each record
+	maxRecordSize    = 64 << 20 //anything claiming to be bigger is treated as corrupt
+)
+
+var errCorruptRecord = errors.New("corrupt record")
+
+//frame data as a record, its length and checksum followed by the data itself
+func encodeRecord(data []byte) []byte {
+	record := make([]byte, recordHeaderSize+len(data))
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(data)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(data))
+	copy(record[recordHeaderSize:], data)
+	return record
+}
+
+//write a record at offset, returning its size. it isn't on disk until the file is synced
+func writeRecord(file *os.File, offset int64, data []byte) (int64, error) {
+	record := encodeRecord(data)
+	_, err := file.WriteAt(record, offset)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(record)), nil
+}
+
+//read the record starting at offset
+func readRecord(file *os.File, offset int64, size int64) ([]byte, error) {
+	record := make([]byte, size)
+	_, err := file.ReadAt(record, offset)
+	if err != nil {
+		return nil, err
+	}
+	length := binary.BigEndian.Uint32(record[0:4])
+	data := record[recordHeaderSize:]
+	if int64(length) != size-recordHeaderSize || crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(record[4:8]) {
+		return nil, errCorruptRecord
+	}
+	return data, nil
+}
+
+//read the records of a file in order, stopping at the first one which is incomplete or doesn't match its
+//checksum. returns the offset the valid records end at, anything after it was left by a write which didn't
+//finish and should be truncated
+func scanRecords(file *os.File, handle func(offset int64, size int64, data []byte) error) (int64, error) {
+	_, err := file.Seek(0, io.SeekStart)
+	if err != nil {
+		return 0, err
+	}
+	reader := bufio.NewReader(file)
+	offset := int64(0)
+	header := make([]byte, recordHeaderSize)
+	for {
+		_, err = io.ReadFull(reader, header)
+		if err != nil {
+			//clean end of the file or a torn header
+			return offset, nil
+		}
+		length := binary.BigEndian.Uint32(header[0:4])
+		if length > maxRecordSize {
+			return offset, nil
+		}
+		data := make([]byte, length)
+		_, err = io.ReadFull(reader, data)
+		if err != nil || crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(header[4:8]) {
+			return offset, nil
+		}
+		size := int64(recordHeaderSize + len(data))
+		err = handle(offset, size, data)
+		if err != nil {
+			return offset, err
+		}
+		offset += size
+	}
+}
+
+//drop anything after the valid records left by a write which didn't finish
+func truncateAfter(file *os.File, validSize int64) error {
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() == validSize {
+		return nil
+	}
+	err = file.Truncate(validSize)
+	if err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+//flush a directory so files created, renamed or removed in it survive a crash. best effort as not every
+//platform supports syncing a directory
+func syncDirectory(path string) {
+	directory, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	directory.Sync()
+	directory.Close()
+}
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+//write records to a new file, returning it along with the offset each record starts at
+func writeRecords(t *testing.T, records ...string) (*os.File, []int64) {
+	t.Helper()
+	file, err := os.OpenFile(filepath.Join(t.TempDir(), "records"), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+	offsets := []int64{}
+	offset := int64(0)
+	for _, record := range records {
+		offsets = append(offsets, offset)
+		size, err := writeRecord(file, offset, []byte(record))
+		if err != nil {
+			t.Fatal(err)
+		}
+		offset += size
+	}
+	return file, append(offsets, offset)
+}
+
+//scan a file's records, returning their data and the offset the valid records end at
+func scanAll(t *testing.T, file *os.File) ([]string, int64) {
+	t.Helper()
+	scanned := []string{}
+	validSize, err := scanRecords(file, func(offset int64, size int64, data []byte) error {
+		scanned = append(scanned, string(data))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return scanned, validSize
+}
+
+func TestScanRecords(t *testing.T) {
+	tests := []struct {
+		name    string
+		damage  func(t *testing.T, file *os.File, offsets []int64)
+		scanned int
+	}{
+		{
+			name:    "intact",
+			damage:  func(t *testing.T, file *os.File, offsets []int64) {},
+			scanned: 3,
+		},
+		{
+			name: "torn header",
+			damage: func(t *testing.T, file *os.File, offsets []int64) {
+				truncate(t, file, offsets[2]+recordHeaderSize/2)
+			},
+			scanned: 2,
+		},
+		{
+			name: "torn data",
+			damage: func(t *testing.T, file *os.File, offsets []int64) {
+				truncate(t, file, offsets[3]-1)
+			},
+			scanned: 2,
+		},
+		{
+			name: "checksum mismatch",
+			damage: func(t *testing.T, file *os.File, offsets []int64) {
+				flipByte(t, file, offsets[1]+recordHeaderSize)
+			},
+			scanned: 1,
+		},
+		{
+			name: "oversized length",
+			damage: func(t *testing.T, file *os.File, offsets []int64) {
+				header := binary.BigEndian.AppendUint32(nil, maxRecordSize+1)
+				if _, err := file.WriteAt(header, offsets[1]); err != nil {
+					t.Fatal(err)
+				}
+			},
+			scanned: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			file, offsets := writeRecords(t, "first", "second", "third")
+			test.damage(t, file, offsets)
+			scanned, validSize := scanAll(t, file)
+			if len(scanned) != test.scanned {
+				t.Fatalf("scanned %v, expected %d records", scanned, test.scanned)
+			}
+			if validSize != offsets[test.scanned] {
+				t.Fatalf("valid records end at %d, expected %d", validSize, offsets[test.scanned])
+			}
+
+			//the damaged tail is dropped and the file can be written after the valid records
+			if err := truncateAfter(file, validSize); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := writeRecord(file, validSize, []byte("after")); err != nil {
+				t.Fatal(err)
+			}
+			scanned, _ = scanAll(t, file)
+			if len(scanned) != test.scanned+1 || scanned[test.scanned] != "after" {
+				t.Fatalf("scanned %v after writing past the valid records", scanned)
+			}
+		})
+	}
+}
+
+func TestReadRecordChecksum(t *testing.T) {
+	file, offsets := writeRecords(t, "first", "second")
+	data, err := readRecord(file, offsets[1], offsets[2]-offsets[1])
+	if err != nil || string(data) != "second" {
+		t.Fatalf("read %q, %v", data, err)
+	}
+	flipByte(t, file, offsets[2]-1)
+	if _, err := readRecord(file, offsets[1], offsets[2]-offsets[1]); err != errCorruptRecord {
+		t.Fatalf("read a corrupt record with %v", err)
+	}
+}
+
+func truncate(t *testing.T, file *os.File, size int64) {
+	t.Helper()
+	if err := file.Truncate(size); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func flipByte(t *testing.T, file *os.File, offset int64) {
+	t.Helper()
+	b := make([]byte, 1)
+	if _, err := file.ReadAt(b, offset); err != nil {
+		t.Fatal(err)
+	}
+	b[0] ^= 0xff
+	if _, err := file.WriteAt(b, offset); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,57 @@
+package storage
+
+import "time"
+
+const (
+	RequestPending  = "pending"
+	RequestApproved = "approved"
+	RequestDenied   = "denied"
+)
+
+//SubscriptionRequest from a client to subscribe to a publisher it can't subscribe to directly, which the owner of
+//the publisher approves or denies
+type SubscriptionRequest struct {
+	ID           string
+	ClientID     string //client asking to subscribe
+	PublisherID  string
+	OwnerID      string       //owner of the publisher when the request was made
+	Message      string       //from the client to the owner
+	Subscription Subscription //added to the client once the request is approved
+	Status       string       //RequestPending, RequestApproved or RequestDenied
+	Reason       string       //from the owner when deciding
+	CreatedAt    time.Time
+	DecidedAt    time.Time //zero while the request is pending
+}
+
+//SubscriptionRequestQuery selects subscription requests, empty fields match every request
+type SubscriptionRequestQuery struct {
+	ClientID     string
+	OwnerID      string
+	PublisherID  string
+	Status       string
+	CreatedAfter time.Time //requests made at or after the time
+	DecidedAfter time.Time //requests decided at or after the time, leaving out pending requests unless it's zero
+}
+
+//SubscriptionRequestStore keeps the requests to subscribe to publishers
+type SubscriptionRequestStore interface {
+	//CreateSubscriptionRequest returns ErrConflict if the client already has a pending request for the publisher
+	CreateSubscriptionRequest(request SubscriptionRequest) error
+	//FindSubscriptionRequest returns ErrNotFound if there isn't one
+	FindSubscriptionRequest(id string) (*SubscriptionRequest, error)
+	//ListSubscriptionRequests matching the query, oldest first
+	ListSubscriptionRequests(query SubscriptionRequestQuery) ([]SubscriptionRequest, error)
+	//DecideSubscriptionRequest approves or denies a pending request, returning ErrNotFound if there isn't one or
+	//ErrConflict if it has already been decided
+	DecideSubscriptionRequest(id string, status string, reason string, decidedAt time.Time) error
+}
+
+//whether the request is selected by the query
+func (query SubscriptionRequestQuery) matches(request *SubscriptionRequest) bool {
+	return (query.ClientID == "" || request.ClientID == query.ClientID) &&
+		(query.OwnerID == "" || request.OwnerID == query.OwnerID) &&
+		(query.PublisherID == "" || request.PublisherID == query.PublisherID) &&
+		(query.Status == "" || request.Status == query.Status) &&
+		!request.CreatedAt.Before(query.CreatedAfter) &&
+		(query.DecidedAfter.IsZero() || !request.DecidedAt.Before(query.DecidedAfter))
+}
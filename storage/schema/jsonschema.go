@@ -0,0 +1,832 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxSchemaErrors = 5  //most problems listed when a payload doesn't match its schema
+	maxSchemaDepth  = 64 //deepest $refs are followed when checking compatibility
+	//most schemas a payload is checked against, so a schema whose $refs loop or branch out can't hold up publishing
+	maxValidateSteps = 100000
+)
+
+var jsonSchemaKeywords = map[string]bool{
+	"type": true, "enum": true, "const": true, "minimum": true, "maximum": true, "exclusiveMinimum": true,
+	"exclusiveMaximum": true, "multipleOf": true, "minLength": true, "maxLength": true, "pattern": true,
+	"properties": true, "required": true, "additionalProperties": true, "minProperties": true, "maxProperties": true,
+	"items": true, "minItems": true, "maxItems": true, "uniqueItems": true, "allOf": true, "anyOf": true, "oneOf": true,
+	"not": true, "$ref": true,
+}
+
+var jsonSchemaTypes = map[string]bool{
+	"null": true, "boolean": true, "object": true, "array": true, "number": true, "integer": true, "string": true,
+}
+
+//jsonSchema is a compiled JSON Schema. the validation keywords of draft 2020-12 which don't need the payload's
+//format or the network are supported, others such as format are ignored as the spec allows
+type jsonSchema struct {
+	canonical string //the schema encoded with its keys sorted, for telling whether two schemas are the same
+	always    *bool  //set for the true and false schemas
+	keywords  int    //supported keywords the schema has, 0 if it accepts anything
+
+	types      []string
+	enum       []interface{}
+	constValue interface{}
+	hasConst   bool
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	multipleOf       *float64
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+
+	properties           map[string]*jsonSchema
+	required             []string
+	additionalProperties *jsonSchema //nil allows anything
+	minProperties        *int
+	maxProperties        *int
+
+	items       *jsonSchema //nil allows anything
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+
+	allOf []*jsonSchema
+	anyOf []*jsonSchema
+	oneOf []*jsonSchema
+	not   *jsonSchema
+
+	ref  string                 //#/$defs/name or #/definitions/name
+	defs map[string]*jsonSchema //of the root schema, shared by every schema in it to resolve $ref
+}
+
+//decode JSON keeping numbers exact so integers can be told apart
+func decodeJSON(data string) (interface{}, error) {
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	err := decoder.Decode(&value)
+	if err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected data after the top-level value")
+	}
+	return value, nil
+}
+
+//compileJSONSchema parses a JSON Schema document, returning a descriptive error if it isn't a valid schema
+func compileJSONSchema(definition string) (*jsonSchema, error) {
+	value, err := decodeJSON(definition)
+	if err != nil {
+		return nil, fmt.Errorf("schema isn't valid JSON: %v", err)
+	}
+	defs := make(map[string]*jsonSchema)
+	schema, err := compileSchemaValue(value, "", defs)
+	if err != nil {
+		return nil, err
+	}
+	if object, ok := value.(map[string]interface{}); ok {
+		for _, keyword := range []string{"$defs", "definitions"} {
+			definitions, ok := object[keyword].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for name, definition := range definitions {
+				path := "/" + keyword + "/" + name
+				compiled, err := compileSchemaValue(definition, path, defs)
+				if err != nil {
+					return nil, err
+				}
+				defs["#"+path] = compiled
+			}
+		}
+	}
+	err = schema.checkRefs(make(map[*jsonSchema]bool))
+	if err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+func compileSchemaValue(value interface{}, path string, defs map[string]*jsonSchema) (*jsonSchema, error) {
+	canonical, _ := json.Marshal(value)
+	schema := &jsonSchema{canonical: string(canonical), defs: defs}
+	if always, ok := value.(bool); ok {
+		schema.always = &always
+		return schema, nil
+	}
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, schemaError(path, "a schema must be an object or a boolean")
+	}
+	var err error
+	for keyword, value := range object {
+		keywordPath := path + "/" + keyword
+		switch keyword {
+		case "type":
+			schema.types, err = compileTypes(value, keywordPath)
+		case "enum":
+			values, ok := value.([]interface{})
+			if !ok {
+				return nil, schemaError(keywordPath, "must be an array")
+			}
+			schema.enum = values
+		case "const":
+			schema.constValue, schema.hasConst = value, true
+		case "minimum":
+			schema.minimum, err = compileNumber(value, keywordPath)
+		case "maximum":
+			schema.maximum, err = compileNumber(value, keywordPath)
+		case "exclusiveMinimum":
+			schema.exclusiveMinimum, err = compileNumber(value, keywordPath)
+		case "exclusiveMaximum":
+			schema.exclusiveMaximum, err = compileNumber(value, keywordPath)
+		case "multipleOf":
+			schema.multipleOf, err = compileNumber(value, keywordPath)
+			if err == nil && *schema.multipleOf <= 0 {
+				err = schemaError(keywordPath, "must be greater than 0")
+			}
+		case "minLength":
+			schema.minLength, err = compileCount(value, keywordPath)
+		case "maxLength":
+			schema.maxLength, err = compileCount(value, keywordPath)
+		case "pattern":
+			pattern, ok := value.(string)
+			if !ok {
+				return nil, schemaError(keywordPath, "must be a string")
+			}
+			schema.pattern, err = regexp.Compile(pattern)
+			if err != nil {
+				err = schemaError(keywordPath, err.Error())
+			}
+		case "properties":
+			properties, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, schemaError(keywordPath, "must be an object")
+			}
+			schema.properties = make(map[string]*jsonSchema)
+			for name, property := range properties {
+				schema.properties[name], err = compileSchemaValue(property, keywordPath+"/"+name, defs)
+				if err != nil {
+					return nil, err
+				}
+			}
+		case "required":
+			names, ok := value.([]interface{})
+			if !ok {
+				return nil, schemaError(keywordPath, "must be an array of property names")
+			}
+			for _, name := range names {
+				name, ok := name.(string)
+				if !ok {
+					return nil, schemaError(keywordPath, "must be an array of property names")
+				}
+				schema.required = append(schema.required, name)
+			}
+		case "additionalProperties":
+			schema.additionalProperties, err = compileSchemaValue(value, keywordPath, defs)
+		case "minProperties":
+			schema.minProperties, err = compileCount(value, keywordPath)
+		case "maxProperties":
+			schema.maxProperties, err = compileCount(value, keywordPath)
+		case "items":
+			schema.items, err = compileSchemaValue(value, keywordPath, defs)
+		case "minItems":
+			schema.minItems, err = compileCount(value, keywordPath)
+		case "maxItems":
+			schema.maxItems, err = compileCount(value, keywordPath)
+		case "uniqueItems":
+			unique, ok := value.(bool)
+			if !ok {
+				return nil, schemaError(keywordPath, "must be a boolean")
+			}
+			schema.uniqueItems = unique
+		case "allOf", "anyOf", "oneOf":
+			var schemas []*jsonSchema
+			schemas, err = compileSchemaList(value, keywordPath, defs)
+			switch keyword {
+			case "allOf":
+				schema.allOf = schemas
+			case "anyOf":
+				schema.anyOf = schemas
+			default:
+				schema.oneOf = schemas
+			}
+		case "not":
+			schema.not, err = compileSchemaValue(value, keywordPath, defs)
+		case "$ref":
+			ref, ok := value.(string)
+			if !ok || !(strings.HasPrefix(ref, "#/$defs/") || strings.HasPrefix(ref, "#/definitions/")) {
+				return nil, schemaError(keywordPath, "only references to #/$defs/ and #/definitions/ are supported")
+			}
+			schema.ref = ref
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, supported := jsonSchemaKeywords[keyword]; supported {
+			schema.keywords++
+		}
+	}
+	return schema, nil
+}
+
+func schemaError(path string, message string) error {
+	if path == "" {
+		return errors.New("invalid schema, " + message)
+	}
+	return fmt.Errorf("invalid schema at %s, %s", path, message)
+}
+
+func compileTypes(value interface{}, path string) ([]string, error) {
+	names := []interface{}{value}
+	if list, ok := value.([]interface{}); ok {
+		names = list
+	}
+	types := []string{}
+	for _, value := range names {
+		name, ok := value.(string)
+		if !ok || !jsonSchemaTypes[name] {
+			return nil, schemaError(path, fmt.Sprintf("unknown type %v", value))
+		}
+		types = append(types, name)
+	}
+	return types, nil
+}
+
+func compileNumber(value interface{}, path string) (*float64, error) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return nil, schemaError(path, "must be a number")
+	}
+	parsed, err := number.Float64()
+	if err != nil {
+		return nil, schemaError(path, err.Error())
+	}
+	return &parsed, nil
+}
+
+func compileCount(value interface{}, path string) (*int, error) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return nil, schemaError(path, "must be a non-negative integer")
+	}
+	parsed, err := strconv.Atoi(number.String())
+	if err != nil || parsed < 0 {
+		return nil, schemaError(path, "must be a non-negative integer")
+	}
+	return &parsed, nil
+}
+
+func compileSchemaList(value interface{}, path string, defs map[string]*jsonSchema) ([]*jsonSchema, error) {
+	values, ok := value.([]interface{})
+	if !ok || len(values) == 0 {
+		return nil, schemaError(path, "must be a non-empty array of schemas")
+	}
+	schemas := []*jsonSchema{}
+	for i, value := range values {
+		schema, err := compileSchemaValue(value, path+"/"+strconv.Itoa(i), defs)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, schema)
+	}
+	return schemas, nil
+}
+
+//check every $ref in the schema points at a definition
+func (schema *jsonSchema) checkRefs(seen map[*jsonSchema]bool) error {
+	if schema == nil || seen[schema] {
+		return nil
+	}
+	seen[schema] = true
+	if schema.ref != "" && schema.defs[schema.ref] == nil {
+		return fmt.Errorf("invalid schema, %s isn't defined", schema.ref)
+	}
+	children := []*jsonSchema{schema.additionalProperties, schema.items, schema.not}
+	children = append(children, schema.allOf...)
+	children = append(children, schema.anyOf...)
+	children = append(children, schema.oneOf...)
+	for _, property := range schema.properties {
+		children = append(children, property)
+	}
+	for _, definition := range schema.defs {
+		children = append(children, definition)
+	}
+	for _, child := range children {
+		err := child.checkRefs(seen)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//jsonType of a decoded value, integer for numbers without a fractional part
+func jsonType(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		number, err := value.Float64()
+		if err == nil && number == math.Trunc(number) && !strings.ContainsAny(value.String(), ".eE") {
+			return "integer"
+		}
+		return "number"
+	}
+	return "unknown"
+}
+
+func typeAllowed(types []string, actual string) bool {
+	for _, allowed := range types {
+		if allowed == actual || (allowed == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+//canonicalJSON encodes a decoded value with its keys sorted and numbers in one form, so 1 and 1.0 are the same
+func canonicalJSON(value interface{}) string {
+	switch value := value.(type) {
+	case json.Number:
+		number, err := value.Float64()
+		if err != nil {
+			return value.String()
+		}
+		return strconv.FormatFloat(number, 'g', -1, 64)
+	case map[string]interface{}:
+		names := []string{}
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		parts := []string{}
+		for _, name := range names {
+			encoded, _ := json.Marshal(name)
+			parts = append(parts, string(encoded)+":"+canonicalJSON(value[name]))
+		}
+		return "{" + strings.Join(parts, ",") + "}"
+	case []interface{}:
+		parts := []string{}
+		for _, item := range value {
+			parts = append(parts, canonicalJSON(item))
+		}
+		return "[" + strings.Join(parts, ",") + "]"
+	}
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
+
+//jsonEqual compares decoded JSON values, numbers by value
+func jsonEqual(a interface{}, b interface{}) bool {
+	return canonicalJSON(a) == canonicalJSON(b)
+}
+
+func pointerPath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+//validate a decoded value against the schema, adding what's wrong with it to problems
+//validation of a payload in progress
+type validation struct {
+	problems   []string
+	steps      int  //schemas checked so far
+	tooComplex bool //set once the steps run out
+}
+
+func (schema *jsonSchema) validate(value interface{}, path string, state *validation) {
+	if len(state.problems) >= maxSchemaErrors {
+		return
+	}
+	report := func(format string, args ...interface{}) {
+		if len(state.problems) < maxSchemaErrors {
+			state.problems = append(state.problems, pointerPath(path)+": "+fmt.Sprintf(format, args...))
+		}
+	}
+	state.steps++
+	if state.steps > maxValidateSteps {
+		if !state.tooComplex {
+			state.tooComplex = true
+			report("too complex to validate against the schema")
+		}
+		return
+	}
+	if schema.always != nil {
+		if !*schema.always {
+			report("not allowed")
+		}
+		return
+	}
+	if schema.ref != "" {
+		schema.defs[schema.ref].validate(value, path, state)
+	}
+	actual := jsonType(value)
+	if len(schema.types) > 0 && !typeAllowed(schema.types, actual) {
+		report("expected %s, got %s", strings.Join(schema.types, " or "), actual)
+		return
+	}
+	if len(schema.enum) > 0 {
+		found := false
+		for _, allowed := range schema.enum {
+			found = found || jsonEqual(allowed, value)
+		}
+		if !found {
+			encoded, _ := json.Marshal(schema.enum)
+			report("must be one of %s", encoded)
+		}
+	}
+	if schema.hasConst && !jsonEqual(schema.constValue, value) {
+		encoded, _ := json.Marshal(schema.constValue)
+		report("must be %s", encoded)
+	}
+
+	switch value := value.(type) {
+	case json.Number:
+		number, _ := value.Float64()
+		if schema.minimum != nil && number < *schema.minimum {
+			report("%s is less than the minimum of %v", value, *schema.minimum)
+		}
+		if schema.maximum != nil && number > *schema.maximum {
+			report("%s is more than the maximum of %v", value, *schema.maximum)
+		}
+		if schema.exclusiveMinimum != nil && number <= *schema.exclusiveMinimum {
+			report("%s must be more than %v", value, *schema.exclusiveMinimum)
+		}
+		if schema.exclusiveMaximum != nil && number >= *schema.exclusiveMaximum {
+			report("%s must be less than %v", value, *schema.exclusiveMaximum)
+		}
+		if schema.multipleOf != nil {
+			quotient := number / *schema.multipleOf
+			if math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+				report("%s isn't a multiple of %v", value, *schema.multipleOf)
+			}
+		}
+	case string:
+		length := utf8.RuneCountInString(value)
+		if schema.minLength != nil && length < *schema.minLength {
+			report("must be at least %d characters, got %d", *schema.minLength, length)
+		}
+		if schema.maxLength != nil && length > *schema.maxLength {
+			report("must be at most %d characters, got %d", *schema.maxLength, length)
+		}
+		if schema.pattern != nil && !schema.pattern.MatchString(value) {
+			report("doesn't match the pattern %s", schema.pattern.String())
+		}
+	case map[string]interface{}:
+		for _, name := range schema.required {
+			if _, ok := value[name]; !ok {
+				report("missing required property %q", name)
+			}
+		}
+		if schema.minProperties != nil && len(value) < *schema.minProperties {
+			report("must have at least %d properties, got %d", *schema.minProperties, len(value))
+		}
+		if schema.maxProperties != nil && len(value) > *schema.maxProperties {
+			report("must have at most %d properties, got %d", *schema.maxProperties, len(value))
+		}
+		names := []string{}
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			propertyPath := path + "/" + escapePointer(name)
+			if property, ok := schema.properties[name]; ok {
+				property.validate(value[name], propertyPath, state)
+			} else if schema.additionalProperties != nil {
+				if schema.additionalProperties.always != nil && !*schema.additionalProperties.always {
+					report("property %q isn't allowed", name)
+				} else {
+					schema.additionalProperties.validate(value[name], propertyPath, state)
+				}
+			}
+		}
+	case []interface{}:
+		if schema.minItems != nil && len(value) < *schema.minItems {
+			report("must have at least %d items, got %d", *schema.minItems, len(value))
+		}
+		if schema.maxItems != nil && len(value) > *schema.maxItems {
+			report("must have at most %d items, got %d", *schema.maxItems, len(value))
+		}
+		if schema.uniqueItems {
+			seen := make(map[string]int)
+			for i, item := range value {
+				key := canonicalJSON(item)
+				if first, duplicate := seen[key]; duplicate {
+					report("items %d and %d are the same", first, i)
+				} else {
+					seen[key] = i
+				}
+			}
+		}
+		if schema.items != nil {
+			for i, item := range value {
+				schema.items.validate(item, path+"/"+strconv.Itoa(i), state)
+			}
+		}
+	}
+
+	for _, sub := range schema.allOf {
+		sub.validate(value, path, state)
+	}
+	if len(schema.anyOf) > 0 {
+		matched := 0
+		for _, sub := range schema.anyOf {
+			if sub.matches(value, state) {
+				matched++
+			}
+		}
+		if matched == 0 {
+			report("doesn't match any of the schemas in anyOf")
+		}
+	}
+	if len(schema.oneOf) > 0 {
+		matched := 0
+		for _, sub := range schema.oneOf {
+			if sub.matches(value, state) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			report("must match exactly one of the schemas in oneOf, matched %d", matched)
+		}
+	}
+	if schema.not != nil && schema.not.matches(value, state) {
+		report("must not match the schema in not")
+	}
+}
+
+//whether a value is valid against the schema
+func (schema *jsonSchema) matches(value interface{}, state *validation) bool {
+	branch := &validation{steps: state.steps, tooComplex: state.tooComplex}
+	schema.validate(value, "", branch)
+	state.steps = branch.steps
+	if branch.tooComplex && !state.tooComplex {
+		state.tooComplex = true
+		state.problems = append(state.problems, pointerPath("")+": too complex to validate against the schema")
+	}
+	return len(branch.problems) == 0
+}
+
+func escapePointer(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
+
+//resolve a schema which is just a $ref to the schema it points at, following references to references
+func (schema *jsonSchema) resolve() *jsonSchema {
+	for i := 0; schema.ref != "" && schema.keywords == 1 && i < maxSchemaDepth; i++ {
+		schema = schema.defs[schema.ref]
+	}
+	return schema
+}
+
+//whether the schema accepts every value
+func (schema *jsonSchema) acceptsAnything() bool {
+	return schema == nil || (schema.always != nil && *schema.always) || (schema.always == nil && schema.keywords == 0)
+}
+
+//whether the schema accepts no value
+func (schema *jsonSchema) rejectsEverything() bool {
+	return schema != nil && schema.always != nil && !*schema.always
+}
+
+//every type the schema allows, all of them if it doesn't say
+func (schema *jsonSchema) allowedTypes() []string {
+	if len(schema.types) > 0 {
+		return schema.types
+	}
+	return []string{"null", "boolean", "object", "array", "number", "string"}
+}
+
+//checkCompatible adds to issues the ways data valid against writer might not be valid against reader. the check is
+//conservative, if it can't tell that reader accepts what writer allows it says so. properties writer doesn't
+//declare are taken not to be sent, so adding an optional property doesn't break compatibility
+func checkCompatible(reader *jsonSchema, writer *jsonSchema, path string, issues *[]string, depth int) {
+	report := func(format string, args ...interface{}) {
+		*issues = append(*issues, pointerPath(path)+": "+fmt.Sprintf(format, args...))
+	}
+	if reader.acceptsAnything() || writer.rejectsEverything() {
+		return
+	}
+	if writer != nil {
+		writer = writer.resolve()
+	}
+	reader = reader.resolve()
+	if writer != nil && reader.canonical == writer.canonical {
+		return
+	}
+	if depth > maxSchemaDepth {
+		report("schemas are nested too deeply to compare")
+		return
+	}
+	if writer == nil || writer.acceptsAnything() {
+		report("was unconstrained and is now restricted")
+		return
+	}
+	if reader.rejectsEverything() {
+		report("is no longer allowed")
+		return
+	}
+	if reader.ref != "" || writer.ref != "" {
+		report("$ref changed, references have to stay the same")
+		return
+	}
+	for _, keyword := range []struct {
+		name   string
+		reader []*jsonSchema
+		writer []*jsonSchema
+	}{{"allOf", reader.allOf, writer.allOf}, {"anyOf", reader.anyOf, writer.anyOf}, {"oneOf", reader.oneOf, writer.oneOf}} {
+		if !sameSchemas(keyword.reader, keyword.writer) {
+			report("%s changed, it has to stay the same", keyword.name)
+		}
+	}
+	if (reader.not == nil) != (writer.not == nil) || (reader.not != nil && reader.not.canonical != writer.not.canonical) {
+		report("not changed, it has to stay the same")
+	}
+
+	writerTypes := writer.allowedTypes()
+	if len(writer.types) == 0 && len(reader.types) > 0 {
+		report("type %s added, any type was allowed", strings.Join(reader.types, " or "))
+	} else {
+		for _, writerType := range writerTypes {
+			if !typeAllowed(reader.allowedTypes(), writerType) {
+				report("type %s is no longer allowed", writerType)
+			}
+		}
+	}
+	if len(reader.enum) > 0 {
+		if len(writer.enum) == 0 && !writer.hasConst {
+			report("enum added, values were unrestricted")
+		}
+		values := writer.enum
+		if writer.hasConst {
+			values = []interface{}{writer.constValue}
+		}
+		for _, value := range values {
+			found := false
+			for _, allowed := range reader.enum {
+				found = found || jsonEqual(allowed, value)
+			}
+			if !found {
+				encoded, _ := json.Marshal(value)
+				report("enum value %s was removed", encoded)
+			}
+		}
+	}
+	if reader.hasConst && !(writer.hasConst && jsonEqual(reader.constValue, writer.constValue)) {
+		report("const changed")
+	}
+
+	if typeAllowed(writerTypes, "integer") {
+		checkLowerBound(reader.minimum, writer.minimum, writer.exclusiveMinimum, "minimum", report)
+		checkLowerBound(reader.exclusiveMinimum, writer.exclusiveMinimum, nil, "exclusiveMinimum", report)
+		checkUpperBound(reader.maximum, writer.maximum, writer.exclusiveMaximum, "maximum", report)
+		checkUpperBound(reader.exclusiveMaximum, writer.exclusiveMaximum, nil, "exclusiveMaximum", report)
+		if reader.multipleOf != nil && (writer.multipleOf == nil || math.Mod(*writer.multipleOf, *reader.multipleOf) != 0) {
+			report("multipleOf is stricter")
+		}
+	}
+	if typeAllowed(writerTypes, "string") {
+		checkMinCount(reader.minLength, writer.minLength, "minLength", report)
+		checkMaxCount(reader.maxLength, writer.maxLength, "maxLength", report)
+		if reader.pattern != nil && (writer.pattern == nil || writer.pattern.String() != reader.pattern.String()) {
+			report("pattern changed")
+		}
+	}
+	if typeAllowed(writerTypes, "array") {
+		checkMinCount(reader.minItems, writer.minItems, "minItems", report)
+		checkMaxCount(reader.maxItems, writer.maxItems, "maxItems", report)
+		if reader.uniqueItems && !writer.uniqueItems {
+			report("uniqueItems added")
+		}
+		if reader.items != nil {
+			checkCompatible(reader.items, writer.items, path+"/items", issues, depth+1)
+		}
+	}
+	if typeAllowed(writerTypes, "object") {
+		checkMinCount(reader.minProperties, writer.minProperties, "minProperties", report)
+		checkMaxCount(reader.maxProperties, writer.maxProperties, "maxProperties", report)
+		checkCompatibleProperties(reader, writer, path, issues, depth, report)
+	}
+}
+
+//properties writer doesn't declare are taken not to be sent, unless it allows additional properties
+func checkCompatibleProperties(reader *jsonSchema, writer *jsonSchema, path string, issues *[]string, depth int, report func(string, ...interface{})) {
+	writerRequired := make(map[string]bool)
+	for _, name := range writer.required {
+		writerRequired[name] = true
+	}
+	for _, name := range reader.required {
+		if !writerRequired[name] {
+			report("property %q is required but may be missing", name)
+		}
+	}
+	names := []string{}
+	for name := range reader.properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writerProperty, declared := writer.properties[name]
+		if !declared {
+			writerProperty = writer.additionalProperties
+		}
+		if declared || writerProperty != nil {
+			checkCompatible(reader.properties[name], writerProperty, path+"/"+escapePointer(name), issues, depth+1)
+		}
+	}
+	if reader.additionalProperties == nil {
+		return
+	}
+	names = []string{}
+	for name := range writer.properties {
+		if _, declared := reader.properties[name]; !declared {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if reader.additionalProperties.rejectsEverything() {
+			report("property %q is no longer allowed", name)
+		} else {
+			checkCompatible(reader.additionalProperties, writer.properties[name], path+"/"+escapePointer(name), issues, depth+1)
+		}
+	}
+	if writer.additionalProperties == nil {
+		return
+	}
+	if reader.additionalProperties.rejectsEverything() && !writer.additionalProperties.rejectsEverything() {
+		report("additional properties are no longer allowed")
+	} else if !reader.additionalProperties.rejectsEverything() {
+		checkCompatible(reader.additionalProperties, writer.additionalProperties, path+"/additionalProperties", issues, depth+1)
+	}
+}
+
+func sameSchemas(a []*jsonSchema, b []*jsonSchema) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].canonical != b[i].canonical {
+			return false
+		}
+	}
+	return true
+}
+
+//the writer's lower bound, inclusive or exclusive, has to be at or above the reader's
+func checkLowerBound(reader *float64, writer *float64, writerExclusive *float64, keyword string, report func(string, ...interface{})) {
+	if reader == nil {
+		return
+	}
+	if (writer == nil || *writer < *reader) && (writerExclusive == nil || *writerExclusive < *reader) {
+		report("%s is stricter", keyword)
+	}
+}
+
+func checkUpperBound(reader *float64, writer *float64, writerExclusive *float64, keyword string, report func(string, ...interface{})) {
+	if reader == nil {
+		return
+	}
+	if (writer == nil || *writer > *reader) && (writerExclusive == nil || *writerExclusive > *reader) {
+		report("%s is stricter", keyword)
+	}
+}
+
+func checkMinCount(reader *int, writer *int, keyword string, report func(string, ...interface{})) {
+	if reader != nil && *reader > 0 && (writer == nil || *writer < *reader) {
+		report("%s is stricter", keyword)
+	}
+}
+
+func checkMaxCount(reader *int, writer *int, keyword string, report func(string, ...interface{})) {
+	if reader != nil && (writer == nil || *writer > *reader) {
+		report("%s is stricter", keyword)
+	}
+}
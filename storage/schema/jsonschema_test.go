@@ -0,0 +1,364 @@
+package schema
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func compile(t *testing.T, definition string) *jsonSchema {
+	t.Helper()
+	schema, err := compileJSONSchema(definition)
+	if err != nil {
+		t.Fatalf("compiling %s: %v", definition, err)
+	}
+	return schema
+}
+
+//what's wrong with a payload according to a schema
+func problems(t *testing.T, definition string, payload string) []string {
+	t.Helper()
+	value, err := decodeJSON(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := &validation{}
+	compile(t, definition).validate(value, "", state)
+	return state.problems
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		payload  string
+		problems []string
+	}{
+		{"true schema", `true`, `{"a":1}`, nil},
+		{"false schema", `false`, `1`, []string{"/: not allowed"}},
+		{"empty schema", `{}`, `[1,"a"]`, nil},
+
+		{"type", `{"type":"string"}`, `"a"`, nil},
+		{"type mismatch", `{"type":"string"}`, `1`, []string{"/: expected string, got integer"}},
+		{"type list", `{"type":["string","null"]}`, `null`, nil},
+		{"type list mismatch", `{"type":["string","null"]}`, `true`, []string{"/: expected string or null, got boolean"}},
+		{"integer is a number", `{"type":"number"}`, `3`, nil},
+		{"number isn't an integer", `{"type":"integer"}`, `3.5`, []string{"/: expected integer, got number"}},
+		{"integer written with a fraction", `{"type":"integer"}`, `3.0`, []string{"/: expected integer, got number"}},
+
+		{"enum", `{"enum":["a",1,null]}`, `1.0`, nil},
+		{"enum mismatch", `{"enum":["a",1]}`, `"b"`, []string{`/: must be one of ["a",1]`}},
+		{"enum object", `{"enum":[{"a":1,"b":2}]}`, `{"b":2,"a":1}`, nil},
+		{"const", `{"const":"a"}`, `"b"`, []string{`/: must be "a"`}},
+
+		{"minimum", `{"minimum":1}`, `1`, nil},
+		{"below minimum", `{"minimum":1}`, `0.5`, []string{"/: 0.5 is less than the minimum of 1"}},
+		{"above maximum", `{"maximum":10}`, `11`, []string{"/: 11 is more than the maximum of 10"}},
+		{"exclusive minimum", `{"exclusiveMinimum":1}`, `1`, []string{"/: 1 must be more than 1"}},
+		{"exclusive maximum", `{"exclusiveMaximum":1}`, `1`, []string{"/: 1 must be less than 1"}},
+		{"multipleOf", `{"multipleOf":0.1}`, `0.3`, nil},
+		{"not a multipleOf", `{"multipleOf":3}`, `10`, []string{"/: 10 isn't a multiple of 3"}},
+		{"number keywords ignore strings", `{"minimum":1}`, `"0"`, nil},
+
+		{"minLength counts characters", `{"minLength":2}`, `"é"`, []string{"/: must be at least 2 characters, got 1"}},
+		{"maxLength", `{"maxLength":2}`, `"abc"`, []string{"/: must be at most 2 characters, got 3"}},
+		{"pattern", `{"pattern":"^[a-z]+$"}`, `"abc"`, nil},
+		{"pattern mismatch", `{"pattern":"^[a-z]+$"}`, `"ab1"`, []string{"/: doesn't match the pattern ^[a-z]+$"}},
+		{"unanchored pattern", `{"pattern":"b"}`, `"abc"`, nil},
+
+		{"properties", `{"properties":{"a":{"type":"string"}}}`, `{"a":"x","b":1}`, nil},
+		{
+			"property mismatch",
+			`{"properties":{"a":{"type":"string"},"b/c":{"type":"integer"}}}`,
+			`{"a":1,"b/c":"x"}`,
+			[]string{"/a: expected string, got integer", "/b~1c: expected integer, got string"},
+		},
+		{"required", `{"required":["a","b"]}`, `{"a":1}`, []string{`/: missing required property "b"`}},
+		{"required ignores non-objects", `{"required":["a"]}`, `[]`, nil},
+		{"additionalProperties false", `{"properties":{"a":{}},"additionalProperties":false}`, `{"a":1,"b":2}`, []string{`/: property "b" isn't allowed`}},
+		{"additionalProperties schema", `{"properties":{"a":{}},"additionalProperties":{"type":"integer"}}`, `{"a":"x","b":"y"}`, []string{"/b: expected integer, got string"}},
+		{"minProperties", `{"minProperties":2}`, `{"a":1}`, []string{"/: must have at least 2 properties, got 1"}},
+		{"maxProperties", `{"maxProperties":1}`, `{"a":1,"b":2}`, []string{"/: must have at most 1 properties, got 2"}},
+
+		{"items", `{"items":{"type":"integer"}}`, `[1,2]`, nil},
+		{"item mismatch", `{"items":{"type":"integer"}}`, `[1,"a",2,true]`, []string{"/1: expected integer, got string", "/3: expected integer, got boolean"}},
+		{"minItems", `{"minItems":1}`, `[]`, []string{"/: must have at least 1 items, got 0"}},
+		{"maxItems", `{"maxItems":1}`, `[1,2]`, []string{"/: must have at most 1 items, got 2"}},
+		{"uniqueItems", `{"uniqueItems":true}`, `[1,{"a":1},1.0]`, []string{"/: items 0 and 2 are the same"}},
+
+		{"allOf", `{"allOf":[{"minimum":1},{"maximum":2}]}`, `3`, []string{"/: 3 is more than the maximum of 2"}},
+		{"anyOf", `{"anyOf":[{"type":"string"},{"minimum":5}]}`, `6`, nil},
+		{"anyOf mismatch", `{"anyOf":[{"type":"string"},{"minimum":5}]}`, `4`, []string{"/: doesn't match any of the schemas in anyOf"}},
+		{"oneOf matching both", `{"oneOf":[{"type":"integer"},{"minimum":5}]}`, `6`, []string{"/: must match exactly one of the schemas in oneOf, matched 2"}},
+		{"not", `{"not":{"type":"null"}}`, `null`, []string{"/: must not match the schema in not"}},
+
+		{"$ref", `{"$ref":"#/$defs/name","$defs":{"name":{"type":"string"}}}`, `1`, []string{"/: expected string, got integer"}},
+		{"$ref to definitions", `{"properties":{"a":{"$ref":"#/definitions/n"}},"definitions":{"n":{"minimum":0}}}`, `{"a":-1}`, []string{"/a: -1 is less than the minimum of 0"}},
+		{
+			"recursive $ref",
+			`{"$ref":"#/$defs/node","$defs":{"node":{"type":"object","properties":{"next":{"$ref":"#/$defs/node"}},"additionalProperties":false}}}`,
+			`{"next":{"next":{"value":1}}}`,
+			[]string{`/next/next: property "value" isn't allowed`},
+		},
+
+		{"unknown keywords ignored", `{"format":"email","title":"x"}`, `"not an email"`, nil},
+		{
+			"problems are capped",
+			`{"items":{"type":"string"}}`,
+			`[1,2,3,4,5,6,7]`,
+			[]string{"/0: expected string, got integer", "/1: expected string, got integer", "/2: expected string, got integer", "/3: expected string, got integer", "/4: expected string, got integer"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			found := problems(t, test.schema, test.payload)
+			if len(found) == 0 && len(test.problems) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(found, test.problems) {
+				t.Fatalf("%s against %s: got %q, expected %q", test.payload, test.schema, found, test.problems)
+			}
+		})
+	}
+}
+
+func TestValidateTooComplex(t *testing.T) {
+	//every level doubles the schemas checked, so the steps run out long before it's done
+	definition := `{"$ref":"#/$defs/d0","$defs":{`
+	for i := 0; i < 30; i++ {
+		if i > 0 {
+			definition += ","
+		}
+		next := `{"$ref":"#/$defs/d` + strconv.Itoa(i+1) + `"}`
+		definition += `"d` + strconv.Itoa(i) + `":{"anyOf":[` + next + `,` + next + `]}`
+	}
+	definition += `,"d30":{"type":"string"}}}`
+	found := problems(t, definition, `1`)
+	if len(found) == 0 || !strings.HasSuffix(found[0], "too complex to validate against the schema") {
+		t.Fatalf("got %q", found)
+	}
+}
+
+func TestCompileJSONSchemaErrors(t *testing.T) {
+	tests := []struct {
+		schema string
+		err    string
+	}{
+		{`{`, "schema isn't valid JSON"},
+		{`{} {}`, "schema isn't valid JSON: unexpected data after the top-level value"},
+		{`"string"`, "invalid schema, a schema must be an object or a boolean"},
+		{`{"type":"text"}`, "invalid schema at /type, unknown type text"},
+		{`{"type":["string",1]}`, "invalid schema at /type, unknown type 1"},
+		{`{"enum":"a"}`, "invalid schema at /enum, must be an array"},
+		{`{"minimum":"1"}`, "invalid schema at /minimum, must be a number"},
+		{`{"multipleOf":0}`, "invalid schema at /multipleOf, must be greater than 0"},
+		{`{"minLength":-1}`, "invalid schema at /minLength, must be a non-negative integer"},
+		{`{"maxItems":1.5}`, "invalid schema at /maxItems, must be a non-negative integer"},
+		{`{"pattern":"("}`, "invalid schema at /pattern, error parsing regexp"},
+		{`{"properties":[]}`, "invalid schema at /properties, must be an object"},
+		{`{"properties":{"a":1}}`, "invalid schema at /properties/a, a schema must be an object or a boolean"},
+		{`{"required":["a",1]}`, "invalid schema at /required, must be an array of property names"},
+		{`{"additionalProperties":"no"}`, "invalid schema at /additionalProperties, a schema must be an object or a boolean"},
+		{`{"items":{"type":"list"}}`, "invalid schema at /items/type, unknown type list"},
+		{`{"uniqueItems":1}`, "invalid schema at /uniqueItems, must be a boolean"},
+		{`{"anyOf":[]}`, "invalid schema at /anyOf, must be a non-empty array of schemas"},
+		{`{"$ref":"http://example.com/schema"}`, "invalid schema at /$ref, only references to #/$defs/ and #/definitions/ are supported"},
+		{`{"$ref":"#/$defs/missing"}`, "invalid schema, #/$defs/missing isn't defined"},
+		{`{"$defs":{"a":{"maximum":true}}}`, "invalid schema at /$defs/a/maximum, must be a number"},
+	}
+	for _, test := range tests {
+		t.Run(test.schema, func(t *testing.T) {
+			_, err := compileJSONSchema(test.schema)
+			if err == nil || !strings.HasPrefix(err.Error(), test.err) {
+				t.Fatalf("got %v, expected %s", err, test.err)
+			}
+		})
+	}
+}
+
+func TestSchemaIssues(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous string
+		next     string
+		//issues under each compatibility rule, none is never checked
+		backward []string
+		forward  []string
+	}{
+		{
+			name:     "same schema",
+			previous: `{"type":"object","properties":{"a":{"type":"string"}}}`,
+			next:     `{"properties":{"a":{"type":"string"}},"type":"object"}`,
+		},
+		{
+			name:     "optional property added",
+			previous: `{"type":"object","properties":{"a":{"type":"string"}}}`,
+			next:     `{"type":"object","properties":{"a":{"type":"string"},"b":{"type":"integer"}}}`,
+		},
+		{
+			name:     "required property added",
+			previous: `{"type":"object","properties":{"a":{"type":"string"}}}`,
+			next:     `{"type":"object","properties":{"a":{"type":"string"},"b":{"type":"integer"}},"required":["b"]}`,
+			backward: []string{`/: property "b" is required but may be missing`},
+		},
+		{
+			name:     "required property removed",
+			previous: `{"type":"object","required":["a"]}`,
+			next:     `{"type":"object"}`,
+			forward:  []string{`/: property "a" is required but may be missing (forward)`},
+		},
+		{
+			name:     "type widened",
+			previous: `{"type":"integer"}`,
+			next:     `{"type":["integer","string"]}`,
+			forward:  []string{"/: type string is no longer allowed (forward)"},
+		},
+		{
+			name:     "type added",
+			previous: `{}`,
+			next:     `{"type":"string"}`,
+			backward: []string{"/: was unconstrained and is now restricted"},
+		},
+		{
+			name:     "integer to number",
+			previous: `{"type":"integer"}`,
+			next:     `{"type":"number"}`,
+			forward:  []string{"/: type number is no longer allowed (forward)"},
+		},
+		{
+			name:     "enum value added",
+			previous: `{"enum":["a","b"]}`,
+			next:     `{"enum":["a","b","c"]}`,
+			forward:  []string{`/: enum value "c" was removed (forward)`},
+		},
+		{
+			name:     "const to enum",
+			previous: `{"const":"a"}`,
+			next:     `{"enum":["a","b"]}`,
+			forward:  []string{"/: const changed (forward)"},
+		},
+		{
+			name:     "minimum raised",
+			previous: `{"type":"integer","minimum":0}`,
+			next:     `{"type":"integer","minimum":1}`,
+			backward: []string{"/: minimum is stricter"},
+		},
+		{
+			name:     "exclusive minimum covers minimum",
+			previous: `{"type":"number","exclusiveMinimum":1}`,
+			next:     `{"type":"number","minimum":1}`,
+			forward:  []string{"/: exclusiveMinimum is stricter (forward)"},
+		},
+		{
+			name:     "maximum lowered",
+			previous: `{"type":"number","maximum":10}`,
+			next:     `{"type":"number","maximum":5}`,
+			backward: []string{"/: maximum is stricter"},
+		},
+		{
+			name:     "multipleOf",
+			previous: `{"type":"integer","multipleOf":4}`,
+			next:     `{"type":"integer","multipleOf":2}`,
+			forward:  []string{"/: multipleOf is stricter (forward)"},
+		},
+		{
+			name:     "number keywords on strings",
+			previous: `{"type":"string"}`,
+			next:     `{"type":"string","minimum":5}`,
+		},
+		{
+			name:     "maxLength lowered",
+			previous: `{"type":"string","maxLength":10}`,
+			next:     `{"type":"string","maxLength":5,"minLength":0}`,
+			backward: []string{"/: maxLength is stricter"},
+		},
+		{
+			name:     "pattern changed",
+			previous: `{"type":"string","pattern":"^a"}`,
+			next:     `{"type":"string","pattern":"^b"}`,
+			backward: []string{"/: pattern changed"},
+			forward:  []string{"/: pattern changed (forward)"},
+		},
+		{
+			name:     "items narrowed",
+			previous: `{"type":"array","items":{"type":["string","integer"]}}`,
+			next:     `{"type":"array","items":{"type":"string"},"minItems":1,"uniqueItems":true}`,
+			backward: []string{"/: minItems is stricter", "/: uniqueItems added", "/items: type integer is no longer allowed"},
+		},
+		{
+			name:     "additional properties closed",
+			previous: `{"type":"object","properties":{"a":{}}}`,
+			next:     `{"type":"object","properties":{"a":{}},"additionalProperties":false}`,
+		},
+		{
+			name:     "additional properties closed when allowed before",
+			previous: `{"type":"object","properties":{"a":{}},"additionalProperties":{"type":"string"}}`,
+			next:     `{"type":"object","properties":{"a":{}},"additionalProperties":false}`,
+			backward: []string{"/: additional properties are no longer allowed"},
+		},
+		{
+			name:     "declared property disallowed",
+			previous: `{"type":"object","properties":{"a":{},"b":{"type":"string"}}}`,
+			next:     `{"type":"object","properties":{"a":{}},"additionalProperties":false}`,
+			backward: []string{`/: property "b" is no longer allowed`},
+		},
+		{
+			name:     "property narrowed",
+			previous: `{"type":"object","properties":{"a":{"type":"number"}}}`,
+			next:     `{"type":"object","properties":{"a":{"type":"integer"}}}`,
+			backward: []string{"/a: type number is no longer allowed"},
+		},
+		{
+			name:     "$ref resolved on both sides",
+			previous: `{"$ref":"#/$defs/a","$defs":{"a":{"type":"string"}}}`,
+			next:     `{"$ref":"#/$defs/b","$defs":{"b":{"type":"string"}}}`,
+		},
+		{
+			name:     "$ref target narrowed",
+			previous: `{"properties":{"a":{"$ref":"#/$defs/a"}},"$defs":{"a":{"type":"number"}}}`,
+			next:     `{"properties":{"a":{"$ref":"#/$defs/a"}},"$defs":{"a":{"type":"integer"}}}`,
+			backward: []string{"/a: type number is no longer allowed"},
+		},
+		{
+			name:     "$ref replaced",
+			previous: `{"$ref":"#/$defs/a","minLength":1,"$defs":{"a":{"type":"string"}}}`,
+			next:     `{"type":"string","minLength":1}`,
+			backward: []string{"/: $ref changed, references have to stay the same"},
+			forward:  []string{"/: $ref changed, references have to stay the same (forward)"},
+		},
+		{
+			name:     "anyOf changed",
+			previous: `{"anyOf":[{"type":"string"},{"type":"integer"}]}`,
+			next:     `{"anyOf":[{"type":"string"}]}`,
+			backward: []string{"/: anyOf changed, it has to stay the same"},
+			forward:  []string{"/: anyOf changed, it has to stay the same (forward)"},
+		},
+		{
+			name:     "false schema",
+			previous: `{"type":"string"}`,
+			next:     `false`,
+			backward: []string{"/: is no longer allowed"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			previous, next := compile(t, test.previous), compile(t, test.next)
+			expected := map[string][]string{
+				CompatibilityBackward: test.backward,
+				CompatibilityForward:  test.forward,
+				CompatibilityFull:     append(append([]string{}, test.backward...), test.forward...),
+				CompatibilityNone:     nil,
+			}
+			for compatibility, issues := range expected {
+				found := schemaIssues(previous, next, compatibility)
+				if len(found) == 0 && len(issues) == 0 {
+					continue
+				}
+				if !reflect.DeepEqual(found, issues) {
+					t.Errorf("%s: got %q, expected %q", compatibility, found, issues)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+//Package schema is the registry of the JSON Schemas publishers validate their payloads against, checking new
+//versions are compatible with the previous one and that published payloads match the latest.
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/limits"
+)
+
+const (
+	CompatibilityBackward = "backward" //consumers on the new version can read messages published with the previous one
+	CompatibilityForward  = "forward"  //consumers still on the previous version can read messages published with the new one
+	CompatibilityFull     = "full"     //both backward and forward
+	CompatibilityNone     = "none"     //new versions aren't checked against the previous one
+
+	maxCompiledSchemas = 1000 //compiled schemas kept for validating payloads before the cache is cleared
+)
+
+//SchemaError is returned when registering a schema which isn't a valid JSON Schema
+type SchemaError struct {
+	Reason string
+}
+
+func (err *SchemaError) Error() string {
+	return err.Reason
+}
+
+//CompatibilityError is returned when a new version of a schema breaks the compatibility rule with the previous one
+type CompatibilityError struct {
+	Compatibility string
+	Version       int      //of the previous version
+	Issues        []string //what breaks compatibility, each prefixed with the JSON pointer of the part of the schema
+}
+
+func (err *CompatibilityError) Error() string {
+	return fmt.Sprintf("schema isn't %s compatible with version %d: %s", err.Compatibility, err.Version,
+		strings.Join(err.Issues, "; "))
+}
+
+//ValidateCompatibility checks the rule is one of the Compatibility constants, empty is allowed
+func ValidateCompatibility(compatibility string) error {
+	switch compatibility {
+	case "", CompatibilityBackward, CompatibilityForward, CompatibilityFull, CompatibilityNone:
+		return nil
+	}
+	return fmt.Errorf("unknown compatibility %q, should be backward, forward, full or none", compatibility)
+}
+
+//RegisterSchema adds the next version of a publisher's schema, after checking it's a valid JSON Schema and
+//compatible with the latest version under the compatibility rule. an empty rule keeps the latest version's, or is
+//backward for the first version. returns a *SchemaError or *CompatibilityError if the schema is refused
+func RegisterSchema(store storage.Store, publisherID string, definition string, compatibility string, now time.Time) (*storage.Schema, error) {
+	err := ValidateCompatibility(compatibility)
+	if err != nil {
+		return nil, &SchemaError{Reason: err.Error()}
+	}
+	compiled, err := compileJSONSchema(definition)
+	if err != nil {
+		return nil, &SchemaError{Reason: err.Error()}
+	}
+	schema := storage.Schema{PublisherID: publisherID, Version: 1, Definition: definition, Compatibility: compatibility, CreatedAt: now}
+	latest, err := store.FindSchema(publisherID, 0)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, err
+	}
+	if latest != nil {
+		schema.Version = latest.Version + 1
+		if schema.Compatibility == "" {
+			schema.Compatibility = latest.Compatibility
+		}
+	}
+	if schema.Compatibility == "" {
+		schema.Compatibility = CompatibilityBackward
+	}
+	if latest != nil {
+		previous, err := compiledSchema(latest)
+		if err != nil {
+			return nil, err
+		}
+		issues := schemaIssues(previous, compiled, schema.Compatibility)
+		if len(issues) > 0 {
+			return nil, &CompatibilityError{Compatibility: schema.Compatibility, Version: latest.Version, Issues: issues}
+		}
+	}
+	err = store.AddSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+//what breaks the compatibility rule between two versions of a schema
+func schemaIssues(previous *jsonSchema, next *jsonSchema, compatibility string) []string {
+	issues := []string{}
+	if compatibility == CompatibilityBackward || compatibility == CompatibilityFull {
+		//messages published with the previous version have to be valid against the new one
+		checkCompatible(next, previous, "", &issues, 0)
+	}
+	if compatibility == CompatibilityForward || compatibility == CompatibilityFull {
+		forward := []string{}
+		checkCompatible(previous, next, "", &forward, 0)
+		for _, issue := range forward {
+			issues = append(issues, issue+" (forward)")
+		}
+	}
+	return issues
+}
+
+//schemas already compiled for validating payloads, versions can't change so they're kept until the cache fills up
+var compiledSchemas = struct {
+	sync.Mutex
+	schemas map[string]*jsonSchema
+}{schemas: make(map[string]*jsonSchema)}
+
+func compiledSchema(schema *storage.Schema) (*jsonSchema, error) {
+	key := schema.PublisherID + "/" + strconv.Itoa(schema.Version)
+	compiledSchemas.Lock()
+	compiled := compiledSchemas.schemas[key]
+	compiledSchemas.Unlock()
+	if compiled != nil {
+		return compiled, nil
+	}
+	compiled, err := compileJSONSchema(schema.Definition)
+	if err != nil {
+		return nil, err
+	}
+	compiledSchemas.Lock()
+	if len(compiledSchemas.schemas) >= maxCompiledSchemas {
+		compiledSchemas.schemas = make(map[string]*jsonSchema)
+	}
+	compiledSchemas.schemas[key] = compiled
+	compiledSchemas.Unlock()
+	return compiled, nil
+}
+
+//CheckSchema validates a payload against the latest version of a publisher's schema, returning the version it
+//matched or 0 if the publisher doesn't have a schema. a payload which doesn't match returns a *limits.PayloadError
+//listing what's wrong with it
+func CheckSchema(store storage.SchemaStore, publisherID string, payload string) (int, error) {
+	schema, err := store.FindSchema(publisherID, 0)
+	if errors.Is(err, storage.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	compiled, err := compiledSchema(schema)
+	if err != nil {
+		return 0, err
+	}
+	value, err := decodeJSON(payload)
+	if err != nil {
+		return 0, &limits.PayloadError{
+			SchemaVersion: schema.Version,
+			Reason:        fmt.Sprintf("not valid JSON for schema version %d, %v", schema.Version, err),
+		}
+	}
+	state := &validation{}
+	compiled.validate(value, "", state)
+	if len(state.problems) > 0 {
+		return 0, &limits.PayloadError{
+			SchemaVersion: schema.Version,
+			Reason:        fmt.Sprintf("doesn't match schema version %d, %s", schema.Version, strings.Join(state.problems, "; ")),
+		}
+	}
+	return schema.Version, nil
+}
@@ -0,0 +1,87 @@
+package schema
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	storage "bezberr.com/messagebrokerstorage"
+	"bezberr.com/messagebrokerstorage/limits"
+)
+
+func TestRegisterSchema(t *testing.T) {
+	store := storage.NewMemory()
+	publisher, err := store.CreatePublisher("owner", "", "publisher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	register := func(definition string, compatibility string) (*storage.Schema, error) {
+		return RegisterSchema(store, publisher.ID, definition, compatibility, time.Now())
+	}
+
+	//the first version is backward unless it says otherwise, and later versions keep the rule
+	first, err := register(`{"type":"object"}`, "")
+	if err != nil || first.Version != 1 || first.Compatibility != CompatibilityBackward {
+		t.Fatalf("registered %+v, %v", first, err)
+	}
+	_, err = register(`{"type":"object","required":["a"]}`, "")
+	compatibilityErr := &CompatibilityError{}
+	if !errors.As(err, &compatibilityErr) || compatibilityErr.Version != 1 || compatibilityErr.Compatibility != CompatibilityBackward {
+		t.Fatalf("registered a breaking version with %v", err)
+	}
+	second, err := register(`{"type":"object","required":["a"]}`, CompatibilityNone)
+	if err != nil || second.Version != 2 {
+		t.Fatalf("registered %+v, %v", second, err)
+	}
+	third, err := register(`{"type":"string"}`, "")
+	if err != nil || third.Version != 3 || third.Compatibility != CompatibilityNone {
+		t.Fatalf("registered %+v, %v", third, err)
+	}
+
+	schemaErr := &SchemaError{}
+	if _, err := register(`{"type":"text"}`, ""); !errors.As(err, &schemaErr) {
+		t.Fatalf("registered an invalid schema with %v", err)
+	}
+	if _, err := register(`{}`, "sideways"); !errors.As(err, &schemaErr) {
+		t.Fatalf("registered an unknown compatibility with %v", err)
+	}
+}
+
+func TestCheckSchema(t *testing.T) {
+	store := storage.NewMemory()
+	publisher, err := store.CreatePublisher("owner", "", "publisher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version, err := CheckSchema(store, publisher.ID, "anything"); version != 0 || err != nil {
+		t.Fatalf("checked against no schema with %d, %v", version, err)
+	}
+	for _, definition := range []string{`{"type":"object"}`, `{"type":"object","properties":{"a":{"type":"integer"}}}`} {
+		if _, err := RegisterSchema(store, publisher.ID, definition, "", time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		payload string
+		version int
+		reason  string
+	}{
+		{`{"a":1}`, 2, ""},
+		{`{"a":"x"}`, 0, "doesn't match schema version 2, /a: expected integer, got string"},
+		{`{"a":`, 0, "not valid JSON for schema version 2, unexpected EOF"},
+	}
+	for _, test := range tests {
+		version, err := CheckSchema(store, publisher.ID, test.payload)
+		if test.reason == "" {
+			if version != test.version || err != nil {
+				t.Errorf("%s: checked with %d, %v", test.payload, version, err)
+			}
+			continue
+		}
+		payloadErr := &limits.PayloadError{}
+		if !errors.As(err, &payloadErr) || payloadErr.SchemaVersion != 2 || payloadErr.Reason != test.reason {
+			t.Errorf("%s: checked with %d, %v", test.payload, version, err)
+		}
+	}
+}
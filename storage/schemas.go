@@ -0,0 +1,24 @@
+package storage
+
+import "time"
+
+//Schema is a version of the JSON Schema the payloads published to a publisher are validated against. versions
+//can't be changed once registered
+type Schema struct {
+	PublisherID   string
+	Version       int    //1 for the first version, going up by one with each version registered after it
+	Definition    string //the JSON Schema document
+	Compatibility string //rule the version was checked against the previous one with, one of the schema package's Compatibility constants
+	CreatedAt     time.Time
+}
+
+//SchemaStore keeps the versions of publishers' schemas
+type SchemaStore interface {
+	//AddSchema registers a version of a publisher's schema, returning ErrNotFound if there isn't a publisher or
+	//ErrConflict if the version isn't one after the latest, as another version was registered first
+	AddSchema(schema Schema) error
+	//FindSchema returns a version of a publisher's schema or the latest if version is 0, ErrNotFound if there isn't one
+	FindSchema(publisherID string, version int) (*Schema, error)
+	//ListSchemas returns every version of a publisher's schema, oldest first
+	ListSchemas(publisherID string) ([]Schema, error)
+}
@@ -0,0 +1,245 @@
+//Package storage is the persistence layer shared by the message broker and the publisher service. Store covers
+//clients, publishers, subscriptions, messages and the cluster's leases, with a MongoDB implementation for
+//deployments, an embedded one keeping its files on local disk for running without a database, and an in-memory
+//one for tests and running everything in a single process.
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	BackendMongo  = "mongo"
+	BackendMemory = "memory"
+	BackendDisk   = "disk"
+
+	SubscriptionTypeWebhook = "webhook" //pushed to a URL rather than consumed by the client's sessions
+
+	WebhookStatusHealthy   = "healthy"
+	WebhookStatusUnhealthy = "unhealthy"
+
+	DefaultMongoURI = "mongodb://message_broker_db:27017"
+)
+
+var (
+	ErrNotFound = errors.New("not found")
+	ErrConflict = errors.New("already exists")
+)
+
+//Client registered with the publisher service
+type Client struct {
+	ID             string
+	OrganizationID string //organization the client is a member of, empty if it isn't in one
+	Name           string
+	SecretHash     string      //salted hash of the client's secret from auth.HashSecret, empty for clients registered without one
+	RateLimits     *RateLimits //override of the default rate limits, nil if the client uses the defaults
+	Subscriptions  []Subscription
+}
+
+//Subscription of a client to a publisher
+type Subscription struct {
+	ID          string
+	PublisherID string
+	Type        string   //empty for subscriptions consumed by the client's sessions, or SubscriptionTypeWebhook
+	Webhook     *Webhook //target of a webhook subscription
+}
+
+//Webhook a subscription's messages are pushed to, along with the status of its deliveries
+type Webhook struct {
+	URL                 string
+	Secret              string
+	Status              string
+	ConsecutiveFailures int
+	LastError           string
+	LastSuccessAt       time.Time
+	LastFailureAt       time.Time
+	NextAttemptAt       time.Time
+}
+
+//WebhookSubscription is a webhook subscription along with the client it belongs to
+type WebhookSubscription struct {
+	ClientID     string
+	Subscription Subscription
+}
+
+//WebhookDelivery is the outcome of delivering to a webhook
+type WebhookDelivery struct {
+	Status              string
+	ConsecutiveFailures int
+	Error               string    //empty when the delivery succeeded
+	NextAttemptAt       time.Time //when a failed delivery will be retried
+}
+
+//Publisher owned by a client, and by its organization if it's in one
+type Publisher struct {
+	ID             string
+	Name           string
+	OwnerID        string
+	OrganizationID string        //organization of the owner when the publisher was created, empty if it wasn't in one
+	Access         Access        //who can subscribe
+	Payload        PayloadPolicy //size limit and content type of the payloads published to it
+}
+
+//Message published to a publisher
+type Message struct {
+	ID          string
+	PublisherID string
+	Payload     string
+	CreatedAt   time.Time
+	ExpiresAt   time.Time //zero if the message is kept until the publisher is deleted
+	//version of the publisher's schema the payload was validated against, 0 if the publisher didn't have one
+	SchemaVersion int
+}
+
+//Usage of storage by messages which haven't expired or been deleted along with their publisher
+type Usage struct {
+	Messages int64
+	Bytes    int64 //of the messages' payloads
+}
+
+//ClientStore keeps clients and their subscriptions
+type ClientStore interface {
+	//CreateClient registers a client in an organization, or outside of any if organizationID is empty, with the hash
+	//of its secret. returns ErrConflict if the name is taken in the organization
+	CreateClient(organizationID string, name string, secretHash string) (*Client, error)
+	//FindClient along with its subscriptions, returning ErrNotFound if there isn't one
+	FindClient(id string) (*Client, error)
+	//SetClientSecret replaces the hash of a client's secret, returning ErrNotFound if there isn't a client
+	SetClientSecret(clientID string, secretHash string) error
+	//SetClientRateLimits overrides the default rate limits for a client, nil goes back to the defaults. returns
+	//ErrNotFound if there isn't a client
+	SetClientRateLimits(clientID string, limits *RateLimits) error
+	//AddSubscription to a client, returning ErrConflict if it's already subscribed to the publisher
+	AddSubscription(clientID string, subscription Subscription) error
+	//RemoveSubscription from a client, returning ErrNotFound if the client doesn't have it
+	RemoveSubscription(clientID string, subscriptionID string) error
+	//ListWebhooks returns every webhook subscription
+	ListWebhooks() ([]WebhookSubscription, error)
+	//RecordWebhookDelivery updates the status of a webhook subscription
+	RecordWebhookDelivery(clientID string, subscriptionID string, delivery WebhookDelivery) error
+}
+
+//PublisherStore keeps publishers
+type PublisherStore interface {
+	//CreatePublisher for a client in its organization, returning ErrConflict if the organization already has a
+	//publisher with the name. outside of an organization the name only has to be unique amongst the client's publishers
+	CreatePublisher(ownerID string, organizationID string, name string) (*Publisher, error)
+	//FindPublisher returns ErrNotFound if there isn't one
+	FindPublisher(id string) (*Publisher, error)
+	//FindPublishers returns the publishers out of ids which exist
+	FindPublishers(ids []string) ([]Publisher, error)
+	//ListPublishers owned by a client
+	ListPublishers(ownerID string) ([]Publisher, error)
+	//ListSubscribers of a publisher, without their subscriptions
+	ListSubscribers(publisherID string) ([]Client, error)
+	//SetPublisherAccess replaces who can subscribe to a publisher, returning ErrNotFound if there isn't one.
+	//subscriptions the change takes access away from are left for the caller to remove
+	SetPublisherAccess(id string, access Access) error
+	//SetPublisherPayload replaces the policy for a publisher's payloads, returning ErrNotFound if there isn't one
+	SetPublisherPayload(id string, policy PayloadPolicy) error
+	//DeletePublisher along with its messages, schemas, subscriptions and subscription requests, returning
+	//ErrNotFound if there isn't one
+	DeletePublisher(id string) error
+}
+
+//MessageStore keeps published messages and tracks which clients have received them. Messages handed out to a
+//client are leased to it until they're confirmed, so the same message isn't handed to two of its sessions at once.
+type MessageStore interface {
+	InsertMessage(message Message) error
+	//LeaseMessages leases up to max of the oldest messages from the publisher the client hasn't received
+	//and doesn't already have leased. nothing is leased once the client's subscription to the publisher has
+	//been removed, so it stops receiving messages straight away
+	LeaseMessages(publisherID string, clientID string, max int, expires time.Time) ([]Message, error)
+	//RenewLeases the client still holds
+	RenewLeases(clientID string, messageIDs []string, expires time.Time) error
+	//ReleaseLeases so the messages can be leased again from availableAt, or straight away if it's zero.
+	//Returns the number released
+	ReleaseLeases(publisherID string, clientID string, messageIDs []string, availableAt time.Time) (int, error)
+	//ConfirmMessages as received by the client, returning the number confirmed
+	ConfirmMessages(publisherID string, clientID string, messageIDs []string) (int, error)
+	//ConfirmMessagesThrough confirms every message from the publisher up to and including messageID,
+	//returning ErrNotFound if there isn't one
+	ConfirmMessagesThrough(publisherID string, clientID string, messageID string) error
+	//DeleteExpiredMessages returns the number deleted
+	DeleteExpiredMessages(now time.Time) (int, error)
+	//MessageUsage returns the messages stored for each of the publishers, publishers without any can be left out
+	MessageUsage(publisherIDs []string) (map[string]Usage, error)
+}
+
+//ClusterStore keeps the instances of the message broker running against the store and the leases they hold
+type ClusterStore interface {
+	//AcquireLease or renew it, returning false if it's held by another holder and hasn't expired
+	AcquireLease(name string, holder string, expires time.Time) (bool, error)
+	ReleaseLease(name string, holder string) error
+	//RegisterInstance or renew its registration
+	RegisterInstance(id string, address string, expires time.Time) error
+	RemoveInstance(id string) error
+	RemoveExpiredInstances(now time.Time) error
+}
+
+//Store is everything the message broker and publisher service persist
+type Store interface {
+	ClientStore
+	APIKeyStore
+	PublisherStore
+	OrganizationStore
+	GroupStore
+	SubscriptionRequestStore
+	SchemaStore
+	MessageStore
+	ClusterStore
+	Close() error
+}
+
+//Config selects and configures a storage backend, tagged to be loaded as the storage section of the services' config
+type Config struct {
+	Backend  string `config:"backend" flag:"storage" usage:"storage backend, mongo, disk or memory"` //BackendMongo (the default), BackendDisk or BackendMemory
+	MongoURI string `config:"mongo_uri" flag:"mongo-uri" usage:"MongoDB connection string for the mongo storage backend"`
+	DataDir  string `config:"data_dir" flag:"data-dir" usage:"directory the disk storage backend keeps its files in"`
+}
+
+//DefaultConfig uses MongoDB at DefaultMongoURI
+func DefaultConfig() Config {
+	return Config{Backend: BackendMongo, MongoURI: DefaultMongoURI, DataDir: DefaultDataDir}
+}
+
+//Validate checks the backend is known and has what it needs
+func (config Config) Validate() error {
+	switch config.Backend {
+	case "", BackendMongo:
+		if config.MongoURI == "" {
+			return errors.New("storage.mongo_uri is required for the mongo backend")
+		}
+	case BackendDisk:
+		if config.DataDir == "" {
+			return errors.New("storage.data_dir is required for the disk backend")
+		}
+	case BackendMemory:
+	default:
+		return fmt.Errorf("unknown storage backend %q, should be mongo, disk or memory", config.Backend)
+	}
+	return nil
+}
+
+//Open the backend selected by the config
+func Open(config Config) (Store, error) {
+	switch config.Backend {
+	case "", BackendMongo:
+		uri := config.MongoURI
+		if uri == "" {
+			uri = DefaultMongoURI
+		}
+		return NewMongo(uri)
+	case BackendDisk:
+		dir := config.DataDir
+		if dir == "" {
+			dir = DefaultDataDir
+		}
+		return OpenDisk(dir)
+	case BackendMemory:
+		return NewMemory(), nil
+	}
+	return nil, fmt.Errorf("unknown storage backend %q", config.Backend)
+}